@@ -51,7 +51,6 @@ func (c *TeeConn) ResetBuffer() {
 }
 
 func main() {
-	var serverResponse []byte
 	targetURL := "www.example.com"
 	targetPort := "443"
 
@@ -104,13 +103,14 @@ func main() {
 	teeConn := NewTeeConn(conn)
 
 	config := &utls.Config{
-		ServerName:     targetURL,
-		ServerResponse: &serverResponse,
-		KeyLogWriter:   os.Stderr,
-		MinVersion:     utls.VersionTLS13,
-		MaxVersion:     utls.VersionTLS13,
+		ServerName:   targetURL,
+		KeyLogWriter: os.Stderr,
+		MinVersion:   utls.VersionTLS13,
+		MaxVersion:   utls.VersionTLS13,
 	}
+	recorder := utls.NewHandshakeRecorder()
 	uconn := utls.UClient(teeConn, config, utls.HelloCustom)
+	uconn.SetHandshakeRecorder(recorder)
 	if err := uconn.ApplyPreset(&spec); err != nil {
 		fmt.Printf("ApplyPreset error: %v\n", err)
 		return
@@ -135,7 +135,7 @@ func main() {
 
 	if err := uconn.Handshake(); err != nil {
 		fmt.Printf("uconn.Handshake() error: %v\n", err)
-		if serverResponse != nil {
+		if serverResponse := recorder.RawRecords(utls.RecordReceived); serverResponse != nil {
 			fmt.Println("--- ServerResponse bytes on error ---")
 			fmt.Print(hex.Dump(serverResponse))
 			fmt.Println("-------------------------------------")
@@ -147,10 +147,10 @@ func main() {
 	// reads that happen from this point forward (i.e., application data).
 	teeConn.ResetBuffer()
 
-	fmt.Print(hex.Dump(tls.ClientHelloRaw))
+	fmt.Print(hex.Dump(recorder.Message(tls.RecordSent, tls.HandshakeTypeClientHello)))
 	fmt.Println("✅ TLS Handshake successful")
 
-	if serverResponse != nil {
+	if serverResponse := recorder.RawRecords(utls.RecordReceived); serverResponse != nil {
 		fmt.Println("--- ServerResponse bytes ---")
 		fmt.Print(hex.Dump(serverResponse))
 		fmt.Println("--------------------------")
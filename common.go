@@ -263,6 +263,12 @@ type ConnectionState struct {
 	// provided by peer.
 	PeerApplicationSettings []byte // [uTLS]
 
+	// JA3S and JA4S are the fingerprints of the ServerHello, and JA4X those of
+	// PeerCertificates in the same order. They are set on the client side only.
+	JA3S JA3S   // [uTLS]
+	JA4S JA4S   // [uTLS]
+	JA4X []JA4X // [uTLS]
+
 	// ServerName is the value of the Server Name Indication extension sent by
 	// the client. It's available both on the server and on the client side.
 	ServerName string
@@ -850,10 +856,6 @@ type Config struct {
 	// used for debugging.
 	KeyLogWriter io.Writer
 
-	// ServerResponse is a pointer to a byte slice that will be filled with the raw
-	// server response records.
-	ServerResponse *[]byte // [uTLS]
-
 	// EncryptedClientHelloConfigList is a serialized ECHConfigList. If
 	// provided, clients will attempt to connect to servers using Encrypted
 	// Client Hello (ECH) using one of the provided ECHConfigs.
@@ -688,14 +688,20 @@ func (c *Conn) readRecordOrCCS(expectChangeCipherSpec bool) error {
 	// Process message.
 	record := c.rawInput.Next(recordHeaderLen + n)
 
-	if c.isClient && c.config.ServerResponse != nil {
-		*c.config.ServerResponse = append(*c.config.ServerResponse, record...)
+	// [uTLS] decryption happens in place, keep the ciphertext for the recorder
+	var rawRecord []byte
+	seq := c.in.seq // [uTLS]
+	if c.utls.recorder != nil {
+		rawRecord = append(rawRecord, record...)
 	}
 
 	data, typ, err := c.in.decrypt(record)
 	if err != nil {
 		return c.in.setErrorLocked(c.sendAlert(err.(alert)))
 	}
+	if c.utls.recorder != nil { // [uTLS]
+		c.utls.recorder.addRecord(RecordReceived, typ, rawRecord, data, c.in.recordProtection(seq, rawRecord, len(data)))
+	}
 	if len(data) > maxPlaintext {
 		return c.in.setErrorLocked(c.sendAlert(alertRecordOverflow))
 	}
@@ -726,6 +732,7 @@ func (c *Conn) readRecordOrCCS(expectChangeCipherSpec bool) error {
 		if len(data) != 2 {
 			return c.in.setErrorLocked(c.sendAlert(alertUnexpectedMessage))
 		}
+		c.utls.receivedAlert = &HandshakeAlert{Level: data[0], Description: data[1]} // [uTLS]
 		if alert(data[1]) == alertCloseNotify {
 			return c.in.setErrorLocked(io.EOF)
 		}
@@ -851,6 +858,7 @@ func (c *Conn) sendAlertLocked(err alert) error {
 		c.tmp[0] = alertLevelError
 	}
 	c.tmp[1] = byte(err)
+	c.utls.sentAlert = &HandshakeAlert{Level: c.tmp[0], Description: c.tmp[1]} // [uTLS]
 
 	_, writeErr := c.writeRecordLocked(recordTypeAlert, c.tmp[0:2])
 	if err == alertCloseNotify {
@@ -1028,10 +1036,14 @@ func (c *Conn) writeRecordLocked(typ recordType, data []byte) (int, error) {
 		outBuf[4] = byte(m)
 
 		var err error
+		seq := c.out.seq // [uTLS]
 		outBuf, err = c.out.encrypt(outBuf, data[:m], c.config.rand())
 		if err != nil {
 			return n, err
 		}
+		if c.utls.recorder != nil { // [uTLS]
+			c.utls.recorder.addRecord(RecordSent, typ, append([]byte(nil), outBuf...), data[:m], c.out.recordProtection(seq, outBuf, m))
+		}
 		if _, err := c.write(outBuf); err != nil {
 			return n, err
 		}
@@ -1062,6 +1074,7 @@ func (c *Conn) writeHandshakeRecord(msg handshakeMessage, transcript transcriptH
 	if transcript != nil {
 		transcript.Write(data)
 	}
+	c.utls.addMessage(RecordSent, data, c.out.cipher != nil) // [uTLS]
 
 	return c.writeRecordLocked(recordTypeHandshake, data)
 }
@@ -1117,6 +1130,7 @@ func (c *Conn) readHandshake(transcript transcriptHash) (any, error) {
 		return nil, err
 	}
 	data = c.hand.Next(4 + n)
+	c.utls.addMessage(RecordReceived, data, c.in.cipher != nil) // [uTLS]
 	return c.unmarshalHandshakeMessage(data, transcript)
 }
 
@@ -1305,6 +1319,7 @@ func (c *Conn) handleRenegotiation() error {
 	c.isHandshakeComplete.Store(false)
 	if c.handshakeErr = c.clientHandshake(context.Background()); c.handshakeErr == nil {
 		c.handshakes++
+		c.utlsServerFingerprints() // [uTLS]
 	}
 	return c.handshakeErr
 }
@@ -1363,6 +1378,7 @@ func (c *Conn) handleKeyUpdate(keyUpdate *keyUpdateMsg) error {
 		if err != nil {
 			return err
 		}
+		c.utls.addMessage(RecordSent, msgBytes, true) // [uTLS]
 		_, err = c.writeRecordLocked(recordTypeHandshake, msgBytes)
 		if err != nil {
 			// Surface the error at the next write.
@@ -1583,6 +1599,7 @@ func (c *Conn) handshakeContext(ctx context.Context) (ret error) {
 	c.handshakeErr = c.handshakeFn(handshakeCtx)
 	if c.handshakeErr == nil {
 		c.handshakes++
+		c.utlsServerFingerprints() // [uTLS]
 	} else {
 		// If an error occurred during the handshake try to flush the
 		// alert that might be left in the buffer.
@@ -1703,4 +1720,4 @@ func (c *Conn) VerifyHostname(host string) error {
 		return errors.New("tls: handshake did not verify certificate chain")
 	}
 	return c.peerCertificates[0].VerifyHostname(host)
-}
\ No newline at end of file
+}
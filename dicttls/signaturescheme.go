@@ -5,14 +5,18 @@ package dicttls
 
 const (
 	SigScheme_rsa_pkcs1_sha1                    uint16 = 0x0201
+	SigScheme_dsa_sha1_RESERVED                 uint16 = 0x0202
 	SigScheme_ecdsa_sha1                        uint16 = 0x0203
 	SigScheme_rsa_pkcs1_sha256                  uint16 = 0x0401
+	SigScheme_dsa_sha256_RESERVED               uint16 = 0x0402
 	SigScheme_ecdsa_secp256r1_sha256            uint16 = 0x0403
 	SigScheme_rsa_pkcs1_sha256_legacy           uint16 = 0x0420
 	SigScheme_rsa_pkcs1_sha384                  uint16 = 0x0501
+	SigScheme_dsa_sha384_RESERVED               uint16 = 0x0502
 	SigScheme_ecdsa_secp384r1_sha384            uint16 = 0x0503
 	SigScheme_rsa_pkcs1_sha384_legacy           uint16 = 0x0520
 	SigScheme_rsa_pkcs1_sha512                  uint16 = 0x0601
+	SigScheme_dsa_sha512_RESERVED               uint16 = 0x0602
 	SigScheme_ecdsa_secp521r1_sha512            uint16 = 0x0603
 	SigScheme_rsa_pkcs1_sha512_legacy           uint16 = 0x0620
 	SigScheme_eccsi_sha256                      uint16 = 0x0704
@@ -42,14 +46,18 @@ const (
 
 var DictSignatureSchemeValueIndexed = map[uint16]string{
 	0x0201: "rsa_pkcs1_sha1",
+	0x0202: "dsa_sha1_RESERVED",
 	0x0203: "ecdsa_sha1",
 	0x0401: "rsa_pkcs1_sha256",
+	0x0402: "dsa_sha256_RESERVED",
 	0x0403: "ecdsa_secp256r1_sha256",
 	0x0420: "rsa_pkcs1_sha256_legacy",
 	0x0501: "rsa_pkcs1_sha384",
+	0x0502: "dsa_sha384_RESERVED",
 	0x0503: "ecdsa_secp384r1_sha384",
 	0x0520: "rsa_pkcs1_sha384_legacy",
 	0x0601: "rsa_pkcs1_sha512",
+	0x0602: "dsa_sha512_RESERVED",
 	0x0603: "ecdsa_secp521r1_sha512",
 	0x0620: "rsa_pkcs1_sha512_legacy",
 	0x0704: "eccsi_sha256",
@@ -79,15 +87,19 @@ var DictSignatureSchemeValueIndexed = map[uint16]string{
 
 var DictSignatureSchemeNameIndexed = map[string]uint16{
 	"rsa_pkcs1_sha1":                      0x0201,
+	"dsa_sha1_RESERVED":                   0x0202,
 	"Reserved for backward compatibility": 0x0202,
 	"ecdsa_sha1":                          0x0203,
 	"rsa_pkcs1_sha256":                    0x0401,
+	"dsa_sha256_RESERVED":                 0x0402,
 	"ecdsa_secp256r1_sha256":              0x0403,
 	"rsa_pkcs1_sha256_legacy":             0x0420,
 	"rsa_pkcs1_sha384":                    0x0501,
+	"dsa_sha384_RESERVED":                 0x0502,
 	"ecdsa_secp384r1_sha384":              0x0503,
 	"rsa_pkcs1_sha384_legacy":             0x0520,
 	"rsa_pkcs1_sha512":                    0x0601,
+	"dsa_sha512_RESERVED":                 0x0602,
 	"ecdsa_secp521r1_sha512":              0x0603,
 	"rsa_pkcs1_sha512_legacy":             0x0620,
 	"eccsi_sha256":                        0x0704,
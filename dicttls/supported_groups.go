@@ -50,6 +50,8 @@ const (
 	SupportedGroups_ffdhe4096                       uint16 = 258
 	SupportedGroups_ffdhe6144                       uint16 = 259
 	SupportedGroups_ffdhe8192                       uint16 = 260
+	SupportedGroups_X25519MLKEM768                  uint16 = 4588  // draft-kwiatkowski-tls-ecdhe-mlkem
+	SupportedGroups_X25519Kyber768Draft00           uint16 = 25497 // draft-tls-westerbaan-xyber768d00
 	SupportedGroups_arbitrary_explicit_prime_curves uint16 = 65281
 	SupportedGroups_arbitrary_explicit_char2_curves uint16 = 65282
 )
@@ -101,6 +103,8 @@ var DictSupportedGroupsValueIndexed = map[uint16]string{
 	258:   "ffdhe4096",
 	259:   "ffdhe6144",
 	260:   "ffdhe8192",
+	4588:  "X25519MLKEM768",        // draft-kwiatkowski-tls-ecdhe-mlkem
+	25497: "X25519Kyber768Draft00", // draft-tls-westerbaan-xyber768d00
 	65281: "arbitrary_explicit_prime_curves",
 	65282: "arbitrary_explicit_char2_curves",
 }
@@ -152,6 +156,8 @@ var DictSupportedGroupsNameIndexed = map[string]uint16{
 	"ffdhe4096":                       258,
 	"ffdhe6144":                       259,
 	"ffdhe8192":                       260,
+	"X25519MLKEM768":                  4588,  // draft-kwiatkowski-tls-ecdhe-mlkem
+	"X25519Kyber768Draft00":           25497, // draft-tls-westerbaan-xyber768d00
 	"arbitrary_explicit_prime_curves": 65281,
 	"arbitrary_explicit_char2_curves": 65282,
 }
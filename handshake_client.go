@@ -807,13 +807,16 @@ func (hs *clientHandshakeState) doFullHandshake() error {
 		}
 	}
 
+	c.observeKeySchedule(KeySchedulePreMasterSecret, preMasterSecret, nil) // [uTLS]
 	if hs.serverHello.extendedMasterSecret {
 		c.extMasterSecret = true
 		hs.masterSecret = extMasterFromPreMasterSecret(c.vers, hs.suite, preMasterSecret,
 			hs.finishedHash.Sum())
+		c.observeKeyScheduleSum(KeyScheduleMasterSecret, hs.masterSecret, hs.finishedHash.Sum()) // [uTLS]
 	} else {
 		hs.masterSecret = masterFromPreMasterSecret(c.vers, hs.suite, preMasterSecret,
 			hs.hello.random, hs.serverHello.random)
+		c.observeKeySchedule(KeyScheduleMasterSecret, hs.masterSecret, nil) // [uTLS]
 	}
 	if err := c.config.writeKeyLog(keyLogLabelTLS12, hs.hello.random, hs.masterSecret); err != nil {
 		c.sendAlert(alertInternalError)
@@ -877,6 +880,7 @@ func (hs *clientHandshakeState) establishKeys() error {
 
 	clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV :=
 		keysFromMasterSecret(c.vers, hs.suite, hs.masterSecret, hs.hello.random, hs.serverHello.random, hs.suite.macLen, hs.suite.keyLen, hs.suite.ivLen)
+	c.observeKeyBlock(clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV) // [uTLS]
 	var clientCipher, serverCipher any
 	var clientHash, serverHash hash.Hash
 	if hs.suite.cipher != nil {
@@ -961,6 +965,7 @@ func (hs *clientHandshakeState) processServerHello() (bool, error) {
 
 	// Restore master secret and certificates from previous state
 	hs.masterSecret = hs.session.secret
+	c.observeKeySchedule(KeyScheduleMasterSecret, hs.masterSecret, nil) // [uTLS]
 	c.extMasterSecret = hs.session.extMasterSecret
 	c.peerCertificates = hs.session.peerCertificates
 	c.activeCertHandles = hs.c.activeCertHandles
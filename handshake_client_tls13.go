@@ -23,11 +23,6 @@ import (
 	"github.com/refraction-networking/utls/internal/tls13"
 )
 
-// Gemini: buffer for decrypted handshake messages
-var decryptedHandshakeBuffer bytes.Buffer
-
-var FullRecordBytes []byte
-
 type clientHandshakeStateTLS13 struct {
 	c            *Conn
 	ctx          context.Context
@@ -57,9 +52,6 @@ type clientHandshakeStateTLS13 struct {
 func (hs *clientHandshakeStateTLS13) handshake() error {
 	c := hs.c
 
-	// Gemini: reset buffer for new handshake messages
-	decryptedHandshakeBuffer.Reset()
-
 	// The server must not select TLS 1.3 in a renegotiation. See RFC 8446,
 	// sections 4.1.2 and 4.1.3.
 	if c.handshakes > 0 {
@@ -259,6 +251,7 @@ func (hs *clientHandshakeStateTLS13) sendDummyChangeCipherSpec() error {
 // resends hs.hello, and reads the new ServerHello into hs.serverHello.
 func (hs *clientHandshakeStateTLS13) processHelloRetryRequest() error {
 	c := hs.c
+	c.utls.receivedHelloRetryRequest = true // [uTLS]
 
 	// The first ClientHello gets double-hashed into the transcript upon a
 	// HelloRetryRequest. (The idea is that the server might offload transcript
@@ -666,6 +659,19 @@ func (hs *clientHandshakeStateTLS13) establishHandshakeKeys() error {
 	serverSecret := handshakeSecret.ServerHandshakeTrafficSecret(hs.transcript)
 	c.in.setTrafficSecret(hs.suite, QUICEncryptionLevelHandshake, serverSecret)
 
+	// [uTLS] SECTION BEGIN
+	c.observeKeySchedule(KeyScheduleSharedSecret, sharedKey, nil)
+	c.observeKeySchedule(KeyScheduleEarlySecret, earlySecret.Secret(), nil)
+	if hs.usingPSK {
+		c.observeKeySchedule(KeyScheduleBinderKey, hs.binderKey, nil)
+	}
+	c.observeKeySchedule(KeyScheduleHandshakeSecret, handshakeSecret.Secret(), nil)
+	c.observeTrafficSecret(hs.suite, KeyScheduleClientHandshakeTrafficSecret,
+		KeyScheduleClientHandshakeWriteKey, KeyScheduleClientHandshakeWriteIV, clientSecret, hs.transcript)
+	c.observeTrafficSecret(hs.suite, KeyScheduleServerHandshakeTrafficSecret,
+		KeyScheduleServerHandshakeWriteKey, KeyScheduleServerHandshakeWriteIV, serverSecret, hs.transcript)
+	// [uTLS] SECTION END
+
 	if c.quic != nil {
 		if c.hand.Len() != 0 {
 			c.sendAlert(alertUnexpectedMessage)
@@ -686,6 +692,7 @@ func (hs *clientHandshakeStateTLS13) establishHandshakeKeys() error {
 	}
 
 	hs.masterSecret = handshakeSecret.MasterSecret()
+	c.observeKeySchedule(KeyScheduleMasterSecret, hs.masterSecret.Secret(), nil) // [uTLS]
 
 	return nil
 }
@@ -704,11 +711,6 @@ func (hs *clientHandshakeStateTLS13) readServerParameters() error {
 		return unexpectedMessageError(encryptedExtensions, msg)
 	}
 
-	// Gemini: write marshaled message to buffer
-	if marshaled, err := encryptedExtensions.marshal(); err == nil {
-		decryptedHandshakeBuffer.Write(marshaled)
-	}
-
 	if err := checkALPN(hs.hello.alpnProtocols, encryptedExtensions.alpnProtocol, c.quic != nil); err != nil {
 		// RFC 8446 specifies that no_application_protocol is sent by servers, but
 		// does not specify how clients handle the selection of an incompatible protocol.
@@ -829,10 +831,6 @@ func (hs *clientHandshakeStateTLS13) readServerCertificate() error {
 		c.sendAlert(alertUnexpectedMessage)
 		return unexpectedMessageError(certMsg, msg)
 	}
-	// Gemini: write marshaled message to buffer
-	if marshaled, err := certMsg.marshal(); err == nil {
-		decryptedHandshakeBuffer.Write(marshaled)
-	}
 	if len(certMsg.certificate.Certificate) == 0 {
 		c.sendAlert(alertDecodeError)
 		return errors.New("tls: received empty certificates message")
@@ -865,10 +863,6 @@ func (hs *clientHandshakeStateTLS13) readServerCertificate() error {
 		c.sendAlert(alertUnexpectedMessage)
 		return unexpectedMessageError(certVerify, msg)
 	}
-	// Gemini: write marshaled message to buffer
-	if marshaled, err := certVerify.marshal(); err == nil {
-		decryptedHandshakeBuffer.Write(marshaled)
-	}
 
 	// See RFC 8446, Section 4.4.3.
 	if !isSupportedSignatureAlgorithm(certVerify.signatureAlgorithm, supportedSignatureAlgorithms()) {
@@ -913,24 +907,7 @@ func (hs *clientHandshakeStateTLS13) readServerFinished() error {
 		c.sendAlert(alertUnexpectedMessage)
 		return unexpectedMessageError(finished, msg)
 	}
-	// Gemini: write marshaled message to buffer and dump
-	if marshaled, err := finished.marshal(); err == nil {
-		decryptedHandshakeBuffer.Write(marshaled)
-	}
-	// Gemini: build and dump the full record
-	fullRecord := new(bytes.Buffer)
-	header := make([]byte, 5)
-	header[0] = 22 // recordTypeHandshake
-	header[1] = byte(VersionTLS12 >> 8)
-	header[2] = byte(VersionTLS12 & 0xff)
-	length := decryptedHandshakeBuffer.Len()
-	header[3] = byte(length >> 8)
-	header[4] = byte(length)
-	fullRecord.Write(header)
-	fullRecord.Write(decryptedHandshakeBuffer.Bytes())
-
-	FullRecordBytes = fullRecord.Bytes()
-
+	c.observeKeySchedule(KeyScheduleServerFinishedKey, hs.suite.finishedKey(c.in.trafficSecret), hs.transcript) // [uTLS]
 	expectedMAC := hs.suite.finishedHash(c.in.trafficSecret, hs.transcript)
 	if !hmac.Equal(expectedMAC, finished.verifyData) {
 		c.sendAlert(alertDecryptError)
@@ -947,6 +924,16 @@ func (hs *clientHandshakeStateTLS13) readServerFinished() error {
 	serverSecret := hs.masterSecret.ServerApplicationTrafficSecret(hs.transcript)
 	c.in.setTrafficSecret(hs.suite, QUICEncryptionLevelApplication, serverSecret)
 
+	// [uTLS] SECTION BEGIN
+	c.observeTrafficSecret(hs.suite, KeyScheduleClientApplicationTrafficSecret,
+		KeyScheduleClientApplicationWriteKey, KeyScheduleClientApplicationWriteIV, hs.trafficSecret, hs.transcript)
+	c.observeTrafficSecret(hs.suite, KeyScheduleServerApplicationTrafficSecret,
+		KeyScheduleServerApplicationWriteKey, KeyScheduleServerApplicationWriteIV, serverSecret, hs.transcript)
+	if c.utls.keyScheduleObserver != nil {
+		c.observeKeySchedule(KeyScheduleExporterMasterSecret, hs.masterSecret.ExporterMasterSecret(hs.transcript).Secret(), hs.transcript)
+	}
+	// [uTLS] SECTION END
+
 	err = c.config.writeKeyLog(keyLogLabelClientTraffic, hs.hello.random, hs.trafficSecret)
 	if err != nil {
 		c.sendAlert(alertInternalError)
@@ -1040,6 +1027,7 @@ func (hs *clientHandshakeStateTLS13) sendClientCertificate() error {
 func (hs *clientHandshakeStateTLS13) sendClientFinished() error {
 	c := hs.c
 
+	c.observeKeySchedule(KeyScheduleClientFinishedKey, hs.suite.finishedKey(c.out.trafficSecret), hs.transcript) // [uTLS]
 	finished := &finishedMsg{
 		verifyData: hs.suite.finishedHash(c.out.trafficSecret, hs.transcript),
 	}
@@ -1052,6 +1040,7 @@ func (hs *clientHandshakeStateTLS13) sendClientFinished() error {
 
 	if !c.config.SessionTicketsDisabled && c.config.ClientSessionCache != nil {
 		c.resumptionSecret = hs.masterSecret.ResumptionMasterSecret(hs.transcript)
+		c.observeKeySchedule(KeyScheduleResumptionMasterSecret, c.resumptionSecret, hs.transcript) // [uTLS]
 	}
 
 	if c.quic != nil {
@@ -35,12 +35,17 @@ func (c *cipherSuiteTLS13) trafficKey(trafficSecret []byte) (key, iv []byte) {
 // to RFC 8446, Section 4.4.4. See sections 4.4 and 4.2.11.2 for the baseKey
 // selection.
 func (c *cipherSuiteTLS13) finishedHash(baseKey []byte, transcript hash.Hash) []byte {
-	finishedKey := tls13.ExpandLabel(c.hash.New, baseKey, "finished", nil, c.hash.Size())
-	verifyData := hmac.New(c.hash.New, finishedKey)
+	verifyData := hmac.New(c.hash.New, c.finishedKey(baseKey)) // [uTLS]
 	verifyData.Write(transcript.Sum(nil))
 	return verifyData.Sum(nil)
 }
 
+// [uTLS] finishedKey derives the finished_key used by finishedHash, split
+// out so that it can be reported to a KeyScheduleObserver.
+func (c *cipherSuiteTLS13) finishedKey(baseKey []byte) []byte {
+	return tls13.ExpandLabel(c.hash.New, baseKey, "finished", nil, c.hash.Size())
+}
+
 // exportKeyingMaterial implements RFC5705 exporters for TLS 1.3 according to
 // RFC 8446, Section 7.5.
 func (c *cipherSuiteTLS13) exportKeyingMaterial(s *tls13.MasterSecret, transcript hash.Hash) func(string, []byte, int) ([]byte, error) {
@@ -1,7 +1,43 @@
 package main
 
-import "github.com/refraction-networking/utls/server"
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/refraction-networking/utls/server"
+)
 
 func main() {
-	server.Run()
+	opts := server.DefaultOptions()
+	configPath := flag.String("config", "", "path of a JSON config file; flags given on the command line override its values")
+	flag.StringVar(&opts.Addr, "addr", opts.Addr, "address the API server listens on")
+	flag.StringVar(&opts.TestServerAddr, "test-server", opts.TestServerAddr, "address of the local TLS 1.3 test server to start alongside the API, e.g. 127.0.0.1:8443 (disabled if empty)")
+	flag.Float64Var(&opts.RateLimit, "rate-limit", opts.RateLimit, "requests per second accepted from each client IP (0 disables the limit)")
+	flag.IntVar(&opts.RateBurst, "rate-burst", opts.RateBurst, "requests accepted at once from each client IP above -rate-limit")
+	flag.Var((*server.StringList)(&opts.TrustedProxies), "trusted-proxies", "comma-separated reverse proxies (IP or CIDR) whose X-Forwarded-For header gives the client IP; if empty, the peer address is used")
+	flag.DurationVar((*time.Duration)(&opts.RequestTimeout), "request-timeout", time.Duration(opts.RequestTimeout), "deadline of each request including dialing and the handshake (0 disables it)")
+	flag.IntVar(&opts.MaxConcurrentConnections, "max-connections", opts.MaxConcurrentConnections, "maximum number of concurrent outbound connections (0 disables the limit)")
+	flag.IntVar(&opts.MaxSessions, "max-sessions", opts.MaxSessions, "maximum number of /tls/sessions sessions kept at once (0 disables the limit)")
+	flag.IntVar(&opts.MaxSessionsPerClient, "max-sessions-per-client", opts.MaxSessionsPerClient, "maximum number of /tls/sessions sessions kept at once for each client IP (0 disables the limit)")
+	flag.IntVar(&opts.MaxSavedSessions, "max-saved-sessions", opts.MaxSavedSessions, "maximum number of sessions saved with resumption.save_session at once (0 disables the limit)")
+	flag.IntVar(&opts.MaxSavedSessionsPerClient, "max-saved-sessions-per-client", opts.MaxSavedSessionsPerClient, "maximum number of sessions saved with resumption.save_session at once for each client IP (0 disables the limit)")
+	flag.Var((*server.StringList)(&opts.Allow), "allow", "comma-separated destinations (IP, CIDR or host name, \"*.example.com\" for subdomains) that may be dialed; if set, all others are denied")
+	flag.Var((*server.StringList)(&opts.Deny), "deny", "comma-separated destinations (IP, CIDR or host name) that must not be dialed")
+	flag.BoolVar(&opts.DenyPrivate, "deny-private", opts.DenyPrivate, "deny loopback, private and other non-public destinations (the -test-server address is always allowed)")
+	flag.StringVar(&opts.Fingerprints, "fingerprints", opts.Fingerprints, "directory of ClientHello definition files (*.json) to load as presets, replacing built-in presets of the same name")
+	flag.Parse()
+
+	if *configPath != "" {
+		if err := server.LoadOptions(*configPath, &opts); err != nil {
+			log.Fatal(err)
+		}
+		// コマンドラインで指定したフラグを設定ファイルの値より優先する
+		if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	server.Run(opts)
 }
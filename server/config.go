@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/refraction-networking/utls/server/handler"
+)
+
+// Options は、APIサーバーの起動オプションです。
+// 設定ファイル (JSON) では、各項目を json タグの名前で指定します。
+type Options struct {
+	// Addr は、APIサーバーが待ち受けるアドレスです。
+	Addr string `json:"addr"`
+
+	// TestServerAddr を指定すると、そのアドレスでローカルTLSテストサーバーを起動します。
+	// 空の場合は起動しません。
+	TestServerAddr string `json:"test_server"`
+
+	// RateLimit は、クライアント (IPアドレス) ごとに1秒あたりに受け付けるリクエストの数です。
+	// 0 の場合は制限しません。
+	RateLimit float64 `json:"rate_limit"`
+
+	// RateBurst は、RateLimit を超えて一度に受け付けるリクエストの数です。
+	RateBurst int `json:"rate_burst"`
+
+	// TrustedProxies は、X-Forwarded-For ヘッダーを信頼するリバースプロキシのアドレス (IPアドレス、CIDR) です。
+	// 空の場合はヘッダーを使わず、接続元のアドレスをクライアントのアドレスとします。
+	TrustedProxies []string `json:"trusted_proxies"`
+
+	// RequestTimeout は、1つのリクエストの処理 (接続先への接続とハンドシェイクを含む) の期限です。
+	// 0 の場合は期限を設けません。
+	RequestTimeout Duration `json:"request_timeout"`
+
+	// MaxConcurrentConnections は、同時に開いておける接続先への接続の数です。
+	// /tls/sessions のセッションは、破棄されるまで接続を開いたままにします。0 の場合は制限しません。
+	MaxConcurrentConnections int `json:"max_concurrent_connections"`
+
+	// MaxSessions と MaxSessionsPerClient は、/tls/sessions で同時に保持できるセッションの数です。
+	// MaxSessionsPerClient はクライアント (IPアドレス) ごとの上限です。0 の場合は制限しません。
+	MaxSessions          int `json:"max_sessions"`
+	MaxSessionsPerClient int `json:"max_sessions_per_client"`
+
+	// MaxSavedSessions と MaxSavedSessionsPerClient は、resumption.save_session で同時に保存できる
+	// セッションの数です。MaxSavedSessionsPerClient はクライアント (IPアドレス) ごとの上限です。
+	// 0 の場合は制限しません。
+	MaxSavedSessions          int `json:"max_saved_sessions"`
+	MaxSavedSessionsPerClient int `json:"max_saved_sessions_per_client"`
+
+	// Allow は、接続を許可する宛先 (IPアドレス、CIDR、ホスト名) です。
+	// 空でない場合は、一致しない宛先には接続しません。
+	Allow []string `json:"allow"`
+
+	// Deny は、接続を拒否する宛先 (IPアドレス、CIDR、ホスト名) です。
+	Deny []string `json:"deny"`
+
+	// DenyPrivate を指定すると、ループバックやプライベートアドレスなどへの接続を拒否します。
+	// TestServerAddr で起動したテストサーバーには常に接続できます。
+	DenyPrivate bool `json:"deny_private"`
+
+	// Fingerprints は、起動時に読み込むClientHello定義ファイル (*.json) のディレクトリです。
+	// 読み込んだ定義はプリセットとして使えます。組み込みのプリセットと同じ名前の定義は、それを置き換えます。
+	Fingerprints string `json:"fingerprints"`
+}
+
+// DefaultOptions は、公開サーバーとして動かすための既定のオプションを返します。
+func DefaultOptions() Options {
+	return Options{
+		Addr:                      ":80",
+		RateLimit:                 5,
+		RateBurst:                 10,
+		RequestTimeout:            Duration(30 * time.Second),
+		MaxConcurrentConnections:  32,
+		MaxSessions:               handler.DefaultMaxSessions,
+		MaxSessionsPerClient:      handler.DefaultMaxSessionsPerClient,
+		MaxSavedSessions:          handler.DefaultMaxSavedSessions,
+		MaxSavedSessionsPerClient: handler.DefaultMaxSavedSessionsPerClient,
+		DenyPrivate:               true,
+	}
+}
+
+// LoadOptions は、pathの設定ファイル (JSON) の値でoptsを上書きします。
+// ファイルに書かれていない項目は変更しません。
+func LoadOptions(path string, opts *Options) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(opts); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Duration は、設定ファイルで "30s" のような time.ParseDuration の形式で指定する時間です。
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// StringList は、カンマ区切りの値を受け取るフラグです。指定するたびに値を置き換えます。
+type StringList []string
+
+func (l *StringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *StringList) Set(s string) error {
+	*l = nil
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		want         func(*Options)
+		expectingErr bool
+	}{
+		{
+			name:   "正常系：設定ファイルに書いた項目だけを上書きする",
+			config: `{"rate_limit": 1.5, "request_timeout": "10s", "deny": ["10.0.0.0/8"], "deny_private": false}`,
+			want: func(o *Options) {
+				o.RateLimit = 1.5
+				o.RequestTimeout = Duration(10 * time.Second)
+				o.Deny = []string{"10.0.0.0/8"}
+				o.DenyPrivate = false
+			},
+		},
+		{
+			name:         "異常系：存在しない項目",
+			config:       `{"rate_limits": 1}`,
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：不正な時間",
+			config:       `{"request_timeout": "10"}`,
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got := DefaultOptions()
+			err := LoadOptions(path, &got)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("LoadOptions() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if tt.expectingErr {
+				return
+			}
+			want := DefaultOptions()
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadOptions() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -3,14 +3,16 @@ package handler
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
-	"time"
 
 	"github.com/labstack/echo/v4"
 	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/dicttls"
+	"github.com/refraction-networking/utls/server/mytls"
 	"github.com/refraction-networking/utls/server/openapi"
 )
 
@@ -82,53 +84,86 @@ func (s Server) PostTlsApplication(ctx echo.Context) error {
 	if err := ctx.Bind(&payload); err != nil {
 		return ctx.JSON(400, "Invalid payload")
 	}
+	if payload.ApplicationData != nil && payload.HttpRequest != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: application_data and http_request cannot be specified together"), payload)
+	}
+	if payload.Resumption != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: resumption is only supported by /tls/handshake"), payload)
+	}
+	if payload.HttpRequest != nil {
+		if err := validateHTTPRequest(payload.ServerName, payload.HttpRequest); err != nil {
+			return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		}
+	}
 
-	// 接続先ホストはServerNameを使い、ポートは443をデフォルトとする
-	conn, err := net.DialTimeout("tcp", payload.ServerName+":443", 5*time.Second)
+	// 接続先はaddress/portで指定でき、省略時はServerNameの443番ポートとする
+	if _, _, err := mytls.DialTarget(payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	conn, err := s.Outbound.dial(ctx.Request().Context(), payload)
 	if err != nil {
-		return ctx.JSON(500, fmt.Sprintf("net.Dial error: %v", err))
+		return dialFailed(ctx, err)
 	}
 	defer conn.Close()
+	setDeadline(conn, ctx.Request().Context())
 
 	// Wrap the connection to tee the reads
 	teeConn := NewTeeConn(conn)
 
 	spec, err := createClientHelloSpec(payload)
 	if err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	minVersion, maxVersion, err := tlsVersionRange(payload, spec)
+	if err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 	}
 
-	var serverResponse []byte
 	config := &utls.Config{
-		ServerName:     payload.ServerName,
-		ServerResponse: &serverResponse,
-		KeyLogWriter:   os.Stderr,
-		MinVersion:     utls.VersionTLS13,
-		MaxVersion:     utls.VersionTLS13,
+		ServerName:   payload.ServerName,
+		KeyLogWriter: os.Stderr,
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
 	}
+	verifier, err := s.setCertificateVerification(config, payload)
+	if err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	if err := setClientCertificate(config, payload); err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	recorder := utls.NewHandshakeRecorder()
 	uconn := utls.UClient(teeConn, config, utls.HelloCustom)
+	uconn.SetHandshakeFailureTracking(true)
+	uconn.SetHandshakeRecorder(recorder)
 	if err := uconn.ApplyPreset(spec); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 	}
 
 	clientRandom, err := hex.DecodeString(payload.ClientRandom)
 	if err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 	}
 	if err := uconn.SetClientRandom(clientRandom); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	if err := applyClientHelloFields(uconn, payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 	}
 
-	if err := uconn.Handshake(); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	if err := uconn.HandshakeContext(ctx.Request().Context()); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", handshakeError(uconn.HandshakeFailure(), err)), payload)
 	}
+	serverResponse := recorder.RawRecords(utls.RecordReceived)
+	handshakeRecords := len(recorder.Records())
 
 	var httpResponse []byte
 	var allRawData []byte
+	var parsedResponse *openapi.HttpResponse
 	if payload.ApplicationData != nil {
 		_, err = uconn.Write([]byte(*payload.ApplicationData))
 		if err != nil {
-			return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+			return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 		}
 
 		httpResponse, err = io.ReadAll(uconn)
@@ -138,18 +173,82 @@ func (s Server) PostTlsApplication(ctx echo.Context) error {
 		}
 		// Get all the raw data captured by the tee
 		allRawData = teeConn.GetReadData()
+	} else if payload.HttpRequest != nil {
+		parsedResponse, err = doHTTPRequest(uconn, payload.ServerName, payload.HttpRequest)
+		if err != nil {
+			return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		}
+		httpResponse = receivedApplicationData(recorder.Records()[handshakeRecords:])
+		allRawData = teeConn.GetReadData()
 	}
 
 	// Extract only the application data part from the raw stream
 	encryptedApplicationData := extractApplicationData(allRawData)
 
 	appResponse := openapi.ApplicationResponse{
-		RawClientHello:                          hex.EncodeToString(convertRecordbytes(utls.ClientHelloRaw)),
+		RawClientHello:                          hex.EncodeToString(convertRecordbytes(recorder.Message(utls.RecordSent, utls.HandshakeTypeClientHello))),
 		RawServerResponse:                       hex.EncodeToString(serverResponse),
-		RawServerResponseDecoded:                hex.EncodeToString(utls.FullRecordBytes),
+		RawServerResponseDecoded:                hex.EncodeToString(decryptedServerFlight(recorder)),
 		RawServerApplicationDataResponse:        hex.EncodeToString(encryptedApplicationData),
 		RawServerApplicationDataResponseDecoded: string(httpResponse),
+		HttpResponse:                            parsedResponse,
+		Records:                                 newTlsRecords(recorder.Records()[handshakeRecords:]),
+		Fingerprints:                            newFingerprints(recorder, uconn.ConnectionState()),
+		CertificateVerification:                 verifier.result(),
 	}
 
 	return ctx.JSON(200, appResponse)
 }
+
+/**
+ * newTlsRecords は、記録したレコードをレコードごとの保護の詳細を含めてレスポンス用の構造体に変換する
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.2
+ */
+func newTlsRecords(records []utls.RecordedRecord) []openapi.TlsRecord {
+	res := make([]openapi.TlsRecord, 0, len(records))
+	for _, r := range records {
+		if len(r.Raw) < 5 {
+			continue
+		}
+		record := openapi.TlsRecord{
+			Direction:            openapi.TlsRecordDirection(r.Direction.String()),
+			Header:               hex.EncodeToString(r.Raw[:5]),
+			ContentType:          int(r.Raw[0]),
+			ContentTypeName:      dicttls.DictContentTypeValueIndexed[r.Raw[0]],
+			InnerContentType:     int(r.ContentType),
+			InnerContentTypeName: dicttls.DictContentTypeValueIndexed[r.ContentType],
+			Encrypted:            r.Encrypted,
+			Ciphertext:           hex.EncodeToString(r.Raw[5:]),
+			Plaintext:            hex.EncodeToString(r.Plaintext),
+		}
+		if r.Encrypted {
+			sequenceNumber := int64(r.SequenceNumber)
+			record.SequenceNumber = &sequenceNumber
+			record.Nonce = optionalHexString(r.Nonce)
+			record.PaddingLength = &r.PaddingLength
+		}
+		if r.ContentType == utls.RecordTypeHandshake {
+			record.HandshakeMessages = handshakeMessageTypes(r.Plaintext)
+		}
+		res = append(res, record)
+	}
+	return res
+}
+
+// handshakeMessageTypes は、レコードに含まれるハンドシェイクメッセージの種類を返す。
+// メッセージが複数のレコードに分割されている場合は、先頭のレコードにのみ含まれる。
+func handshakeMessageTypes(data []byte) *[]string {
+	var types []string
+	for len(data) >= 4 {
+		types = append(types, dicttls.DictHandshakeTypeValueIndexed[data[0]])
+		n := 4 + (int(data[1])<<16 | int(data[2])<<8 | int(data[3]))
+		if n > len(data) {
+			break
+		}
+		data = data[n:]
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	return &types
+}
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestPostTlsApplication(t *testing.T) {
+	e, ts := newTestServer(t)
+	request := "GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"
+
+	tests := []struct {
+		name            string
+		modify          func(*openapi.TlsClientParameters)
+		outerType       int
+		sessionTicket   bool
+		explicitNonce   bool
+		wantNonceHexLen int
+	}{
+		{
+			name: "正常系：TLS 1.3ではNewSessionTicketもapplication_dataのレコードで届く",
+			modify: func(p *openapi.TlsClientParameters) {
+				// psk_key_exchange_modes を送るとサーバーが NewSessionTicket を送信する
+				var extensions []openapi.ClientHelloExtension
+				for _, name := range []string{"server_name", "supported_groups", "key_share", "signature_algorithms", "supported_versions", "psk_key_exchange_modes"} {
+					extensions = append(extensions, openapi.ClientHelloExtension{Name: name})
+				}
+				p.Extensions = &extensions
+			},
+			outerType:       23,
+			sessionTicket:   true,
+			wantNonceHexLen: 24,
+		},
+		{
+			name: "正常系：TLS 1.2ではContentTypeが暗号化されない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0xc02b"}
+			},
+			explicitNonce:   true,
+			wantNonceHexLen: 24,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			params.ApplicationData = &request
+			var res openapi.ApplicationResponse
+			code := doJSON(t, e, http.MethodPost, "/tls/application", params, &res)
+			if code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			if !strings.HasPrefix(res.RawServerApplicationDataResponseDecoded, "HTTP/1.1 200 OK") {
+				t.Errorf("unexpected response %q", res.RawServerApplicationDataResponseDecoded)
+			}
+
+			var sentRequest, receivedResponse, receivedTicket bool
+			for _, r := range res.Records {
+				if !r.Encrypted || r.SequenceNumber == nil || r.PaddingLength == nil || r.Nonce == nil {
+					t.Fatalf("record is not protected: %+v", r)
+				}
+				if len(*r.Nonce) != tt.wantNonceHexLen {
+					t.Errorf("nonce = %s, want %d hex digits", *r.Nonce, tt.wantNonceHexLen)
+				}
+				if tt.explicitNonce && !strings.HasSuffix(*r.Nonce, r.Ciphertext[:16]) {
+					t.Errorf("nonce %s does not end with the explicit nonce of %s", *r.Nonce, r.Ciphertext)
+				}
+				if want := tt.outerType; want != 0 && r.ContentType != want {
+					t.Errorf("content_type = %d, want %d", r.ContentType, want)
+				}
+				if tt.outerType == 0 && r.ContentType != r.InnerContentType {
+					t.Errorf("content_type %d differs from inner_content_type %d", r.ContentType, r.InnerContentType)
+				}
+				if r.Header != hex.EncodeToString([]byte{byte(r.ContentType), 3, 3, byte(len(r.Ciphertext) / 2 >> 8), byte(len(r.Ciphertext) / 2)}) {
+					t.Errorf("header %s does not match the record", r.Header)
+				}
+
+				plaintext, _ := hex.DecodeString(r.Plaintext)
+				switch {
+				case r.Direction == openapi.TlsRecordDirectionSent && r.InnerContentTypeName == "application_data":
+					sentRequest = string(plaintext) == request
+				case r.Direction == openapi.TlsRecordDirectionReceived && r.InnerContentTypeName == "application_data":
+					receivedResponse = receivedResponse || strings.HasPrefix(string(plaintext), "HTTP/1.1 200 OK")
+				case r.InnerContentTypeName == "handshake":
+					receivedTicket = r.HandshakeMessages != nil && (*r.HandshakeMessages)[0] == "new_session_ticket"
+				}
+			}
+			if !sentRequest || !receivedResponse {
+				t.Errorf("application data records are missing: sent %v, received %v", sentRequest, receivedResponse)
+			}
+			if receivedTicket != tt.sessionTicket {
+				t.Errorf("received NewSessionTicket = %v, want %v", receivedTicket, tt.sessionTicket)
+			}
+		})
+	}
+}
+
+func TestPostTlsApplicationHttpRequest(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		modify       func(*openapi.TlsClientParameters)
+		wantProtocol string
+		wantRequest  string
+		expectingErr bool
+		wantStatus   int
+	}{
+		{
+			name: "正常系：ALPNでh2を合意した場合はHTTP/2で送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{}
+			},
+			wantProtocol: "h2",
+			// HTTP/2 のコネクションプリフェイス
+			wantRequest: "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n",
+		},
+		{
+			name: "正常系：http/1.1を指定した場合はヘッダを指定した順に送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{
+					Method: ptr("POST"),
+					Path:   ptr("/submit"),
+					Headers: &[]openapi.HttpHeader{
+						{Name: "User-Agent", Value: "tls-learning-api"},
+						{Name: "Accept", Value: "*/*"},
+					},
+					Body:     ptr("hello"),
+					Protocol: ptr(openapi.HttpRequestProtocolHttp11),
+				}
+			},
+			wantProtocol: "http/1.1",
+			wantRequest: "POST /submit HTTP/1.1\r\nHost: localhost\r\nUser-Agent: tls-learning-api\r\nAccept: */*\r\n" +
+				"Content-Length: 5\r\nConnection: close\r\n\r\nhello",
+		},
+		{
+			name: "正常系：クエリを含むパスをHTTP/2で送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{Path: ptr("/search?q=a%20b&lang=ja")}
+			},
+			wantProtocol: "h2",
+		},
+		{
+			name: "正常系：TLS 1.2でもALPNを送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0xc02b"}
+				p.HttpRequest = &openapi.HttpRequest{}
+			},
+			wantProtocol: "h2",
+		},
+		{
+			name: "正常系：プリセットのALPNをhttp_requestのプロトコルで上書きする",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-133")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+				p.HttpRequest = &openapi.HttpRequest{Protocol: ptr(openapi.HttpRequestProtocolHttp11)}
+			},
+			wantProtocol: "http/1.1",
+			wantRequest:  "GET / HTTP/1.1\r\n",
+		},
+		{
+			name: "正常系：プロトコルを指定しない場合はプリセットのALPNで合意する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-133")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+				p.HttpRequest = &openapi.HttpRequest{}
+			},
+			wantProtocol: "h2",
+		},
+		{
+			name: "正常系：extensionsにALPNがない場合はHTTP/1.1で送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				var extensions []openapi.ClientHelloExtension
+				for _, name := range []string{"server_name", "supported_groups", "key_share", "signature_algorithms", "supported_versions"} {
+					extensions = append(extensions, openapi.ClientHelloExtension{Name: name})
+				}
+				p.Extensions = &extensions
+				p.HttpRequest = &openapi.HttpRequest{}
+			},
+			wantProtocol: "http/1.1",
+		},
+		{
+			name: "異常系：extensionsのALPNで提示していないプロトコルを指定した",
+			modify: func(p *openapi.TlsClientParameters) {
+				var extensions []openapi.ClientHelloExtension
+				for _, name := range []string{"server_name", "supported_groups", "key_share", "signature_algorithms", "supported_versions"} {
+					extensions = append(extensions, openapi.ClientHelloExtension{Name: name})
+				}
+				extensions = append(extensions, openapi.ClientHelloExtension{
+					Name:      "application_layer_protocol_negotiation",
+					Protocols: &[]string{"http/1.1"},
+				})
+				p.Extensions = &extensions
+				p.HttpRequest = &openapi.HttpRequest{Protocol: ptr(openapi.HttpRequestProtocolH2)}
+			},
+			expectingErr: true,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "異常系：extensionsのALPNで指定したプロトコル以外も提示した",
+			modify: func(p *openapi.TlsClientParameters) {
+				var extensions []openapi.ClientHelloExtension
+				for _, name := range []string{"server_name", "supported_groups", "key_share", "signature_algorithms", "supported_versions"} {
+					extensions = append(extensions, openapi.ClientHelloExtension{Name: name})
+				}
+				extensions = append(extensions, openapi.ClientHelloExtension{
+					Name:      "application_layer_protocol_negotiation",
+					Protocols: &[]string{"h2", "http/1.1"},
+				})
+				p.Extensions = &extensions
+				p.HttpRequest = &openapi.HttpRequest{Protocol: ptr(openapi.HttpRequestProtocolHttp11)}
+			},
+			expectingErr: true,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "異常系：extensionsにALPNがないのにh2を指定した",
+			modify: func(p *openapi.TlsClientParameters) {
+				var extensions []openapi.ClientHelloExtension
+				for _, name := range []string{"server_name", "supported_groups", "key_share", "signature_algorithms", "supported_versions"} {
+					extensions = append(extensions, openapi.ClientHelloExtension{Name: name})
+				}
+				p.Extensions = &extensions
+				p.HttpRequest = &openapi.HttpRequest{Protocol: ptr(openapi.HttpRequestProtocolH2)}
+			},
+			expectingErr: true,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "異常系：プリセットの拡張をextensionsで置き換えてALPNで提示していないプロトコルを指定した",
+			modify: func(p *openapi.TlsClientParameters) {
+				preset := findPreset(t, e, "Chrome-133")
+				var extensions []openapi.ClientHelloExtension
+				for _, ext := range preset.Extensions {
+					if ext.Name == "application_layer_protocol_negotiation" {
+						ext.Protocols = &[]string{"h2"}
+					}
+					extensions = append(extensions, ext)
+				}
+				p.Preset = ptr("Chrome-133")
+				p.Extensions = &extensions
+				p.HttpRequest = &openapi.HttpRequest{Protocol: ptr(openapi.HttpRequestProtocolHttp11)}
+			},
+			expectingErr: true,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "異常系：ヘッダの値に改行を含む",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{
+					Headers: &[]openapi.HttpHeader{{Name: "Accept", Value: "*/*\r\nX-Injected: 1"}},
+				}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：ヘッダの名前に改行を含む",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{
+					Headers: &[]openapi.HttpHeader{{Name: "X-Injected: 1\r\nAccept", Value: "*/*"}},
+				}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：パスに改行を含む",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{Path: ptr("/ HTTP/1.1\r\nX-Injected: 1\r\n")}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：パスが//で始まる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{Path: ptr("//example.com/")}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：パスに@を含む",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.HttpRequest = &openapi.HttpRequest{Path: ptr("@example.com/")}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：application_dataと同時には指定できない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ApplicationData = ptr("GET / HTTP/1.1\r\n\r\n")
+				p.HttpRequest = &openapi.HttpRequest{}
+			},
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			var res openapi.ApplicationResponse
+			code := doJSON(t, e, http.MethodPost, "/tls/application", params, &res)
+			if (code != http.StatusOK) != tt.expectingErr {
+				t.Fatalf("status = %d, expectingErr %v", code, tt.expectingErr)
+			}
+			if tt.wantStatus != 0 && code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", code, tt.wantStatus)
+			}
+			if tt.expectingErr {
+				return
+			}
+
+			resp := res.HttpResponse
+			if resp == nil {
+				t.Fatal("http_response is missing")
+			}
+			if resp.Protocol != tt.wantProtocol || resp.Status != http.StatusOK {
+				t.Errorf("protocol = %s, status = %d, want %s 200", resp.Protocol, resp.Status, tt.wantProtocol)
+			}
+			if resp.Body != "Hello from the TLS learning test server\n" {
+				t.Errorf("body = %q", resp.Body)
+			}
+			var contentType string
+			for _, h := range resp.Headers {
+				if h.Name == "Content-Type" {
+					contentType = h.Value
+				}
+			}
+			if contentType != "text/plain" {
+				t.Errorf("Content-Type = %q, want text/plain", contentType)
+			}
+
+			if tt.wantRequest == "" {
+				return
+			}
+			var sent []byte
+			for _, r := range res.Records {
+				if r.Direction == openapi.TlsRecordDirectionSent && r.InnerContentTypeName == "application_data" {
+					plaintext, _ := hex.DecodeString(r.Plaintext)
+					sent = append(sent, plaintext...)
+				}
+			}
+			if !strings.HasPrefix(string(sent), tt.wantRequest) {
+				t.Errorf("sent application data = %q, want prefix %q", sent, tt.wantRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/dicttls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// setClientCertificate は、client_certificate が指定されている場合に、
+// サーバーから CertificateRequest を受け取ったとき送信する証明書をconfigに設定する。
+// 標準の選択処理ではサーバーが提示したCAと一致しない証明書は送信されないため、
+// 指定された証明書を常に送信する。
+func setClientCertificate(config *utls.Config, payload openapi.TlsClientParameters) error {
+	if payload.ClientCertificate == nil {
+		return nil
+	}
+	cert, err := clientCertificate(payload.ClientCertificate)
+	if err != nil {
+		return fmt.Errorf("invalid client_certificate: %w", err)
+	}
+	config.GetClientCertificate = func(*utls.CertificateRequestInfo) (*utls.Certificate, error) {
+		return &cert, nil
+	}
+	return nil
+}
+
+func clientCertificate(c *openapi.ClientCertificate) (utls.Certificate, error) {
+	hasPEM := c.Certificate != nil || c.PrivateKey != nil
+	if c.Generate != nil && *c.Generate {
+		if hasPEM {
+			return utls.Certificate{}, errors.New("generate cannot be used with certificate or private_key")
+		}
+		return generateClientCertificate()
+	}
+	if c.Certificate == nil || c.PrivateKey == nil {
+		return utls.Certificate{}, errors.New("both certificate and private_key are required unless generate is true")
+	}
+	return utls.X509KeyPair([]byte(*c.Certificate), []byte(*c.PrivateKey))
+}
+
+// generateClientCertificate は、クライアント認証用の一時的な自己署名証明書を生成する。
+// 生成方法は generate_cert.go の ECDSA P-256 の場合と同じ。
+func generateClientCertificate() (utls.Certificate, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(24 * time.Hour)
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"TLS Learning API"},
+			CommonName:   "ephemeral client certificate",
+		},
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+	return utls.Certificate{
+		Certificate: [][]byte{derBytes},
+		PrivateKey:  priv,
+		Leaf:        leaf,
+	}, nil
+}
+
+// newClientCertificate は、クライアントが送信した Certificate を解析する。
+// 送信していない場合はnilを返す。
+func newClientCertificate(recorder *utls.HandshakeRecorder, version uint16) *openapi.CertificateMessage {
+	raw := recorder.Message(utls.RecordSent, utls.HandshakeTypeCertificate)
+	if raw == nil {
+		return nil
+	}
+	var m *utls.DecodedCertificate
+	var err error
+	if version == utls.VersionTLS12 {
+		m, err = utls.DecodeCertificateTLS12(raw)
+	} else {
+		m, err = utls.DecodeCertificate(raw)
+	}
+	if err != nil {
+		return nil
+	}
+	return newCertificateMessage(m)
+}
+
+// newClientCertificateVerify は、クライアントが送信した CertificateVerify を解析する。
+// 送信する証明書がなかった場合、CertificateVerify は送信されないのでnilを返す。
+func newClientCertificateVerify(recorder *utls.HandshakeRecorder) *openapi.CertificateVerifyMessage {
+	raw := recorder.Message(utls.RecordSent, utls.HandshakeTypeCertificateVerify)
+	if raw == nil {
+		return nil
+	}
+	m, err := utls.DecodeCertificateVerify(raw)
+	if err != nil {
+		return nil
+	}
+	return newCertificateVerifyMessage(m)
+}
+
+func newCertificateVerifyMessage(m *utls.DecodedCertificateVerify) *openapi.CertificateVerifyMessage {
+	return &openapi.CertificateVerifyMessage{
+		Raw:                 hex.EncodeToString(m.Raw),
+		SignatureScheme:     uint16ToString(uint16(m.SignatureScheme)),
+		SignatureSchemeName: dicttls.DictSignatureSchemeValueIndexed[uint16(m.SignatureScheme)],
+		Signature:           hex.EncodeToString(m.Signature),
+	}
+}
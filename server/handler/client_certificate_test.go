@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
+)
+
+func TestPostTlsHandshakeClientCertificate(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	cert, err := generateClientCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	generate := true
+
+	tests := []struct {
+		name         string
+		modify       func(*openapi.TlsClientParameters)
+		wantCerts    int
+		wantVerify   bool
+		expectingErr bool
+	}{
+		{
+			name: "正常系：生成した証明書でTLS 1.3のクライアント認証ができる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ClientCertificate = &openapi.ClientCertificate{Generate: &generate}
+			},
+			wantCerts:  1,
+			wantVerify: true,
+		},
+		{
+			name: "正常系：PEMで指定した証明書でTLS 1.2のクライアント認証ができる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0xc02b"}
+				p.ClientCertificate = &openapi.ClientCertificate{Certificate: &certPEM, PrivateKey: &keyPEM}
+			},
+			wantCerts:  1,
+			wantVerify: true,
+		},
+		{
+			// TLS 1.3 ではクライアントの Finished を送った時点でハンドシェイクが完了するため、
+			// サーバーが証明書を拒否してもエラーにはならない
+			name:       "正常系：証明書を指定しない場合は空の Certificate を送信する",
+			modify:     func(p *openapi.TlsClientParameters) {},
+			wantCerts:  0,
+			wantVerify: false,
+		},
+		{
+			name: "異常系：generate と PEM を同時に指定できない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ClientCertificate = &openapi.ClientCertificate{Generate: &generate, Certificate: &certPEM, PrivateKey: &keyPEM}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：秘密鍵が証明書と対応していない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ClientCertificate = &openapi.ClientCertificate{Certificate: &certPEM, PrivateKey: &certPEM}
+			},
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			params.ServerName = testserver.ClientAuthServerName
+			tt.modify(&params)
+
+			var res openapi.HandshakeResponse
+			code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+			if tt.expectingErr {
+				if code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+				}
+				return
+			}
+			if code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+
+			if res.ServerFlight == nil || res.ServerFlight.CertificateRequest == nil {
+				t.Fatalf("certificate_request is missing: %+v", res.ServerFlight)
+			}
+			if res.ClientCertificate == nil {
+				t.Fatal("client_certificate is missing")
+			}
+			if n := len(res.ClientCertificate.CertificateList); n != tt.wantCerts {
+				t.Errorf("len(client_certificate.certificate_list) = %d, want %d", n, tt.wantCerts)
+			}
+			if (res.ClientCertificateVerify != nil) != tt.wantVerify {
+				t.Errorf("client_certificate_verify = %+v, want present: %v", res.ClientCertificateVerify, tt.wantVerify)
+			}
+			if v := res.ClientCertificateVerify; v != nil && (v.SignatureSchemeName != "ecdsa_secp256r1_sha256" || v.Signature == "") {
+				t.Errorf("unexpected client_certificate_verify: %+v", v)
+			}
+		})
+	}
+
+	t.Run("正常系：CertificateRequest を送らないサーバーには証明書を送信しない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ClientCertificate = &openapi.ClientCertificate{Generate: &generate}
+
+		var res openapi.HandshakeResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		if res.ClientCertificate != nil || res.ClientCertificateVerify != nil {
+			t.Errorf("client certificate was sent without a CertificateRequest: %+v", res.ClientCertificate)
+		}
+	})
+}
@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/dicttls"
+	"github.com/refraction-networking/utls/server/mytls"
+	"github.com/refraction-networking/utls/server/openapi"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// PostTlsCompare は、同じパラメータでuTLSと独自実装 (mytls) のハンドシェイクを行い、
+// ClientHelloとサーバーの応答をフィールドごとに比較する
+func (s Server) PostTlsCompare(ctx echo.Context) error {
+	var payload openapi.HandshakeRequest
+	if err := ctx.Bind(&payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	if payload.Resumption != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: resumption is only supported by /tls/handshake"), payload)
+	}
+	// mytls は既定の拡張しか送信できないため、同じ ClientHello にならない指定は受け付けない
+	if payload.Preset != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: preset is not supported by /tls/compare"), payload)
+	}
+	if payload.Extensions != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: extensions are not supported by /tls/compare"), payload)
+	}
+	spec, err := createClientHelloSpec(payload)
+	if err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	if _, _, err := mytls.DialTarget(payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	// どちらの実装も接続できない宛先は、比較せずに拒否する
+	if err := s.Outbound.check(ctx.Request().Context(), payload); err != nil {
+		return dialFailed(ctx, err)
+	}
+
+	utlsResult, utlsHello, utlsFlight := s.compareUTLS(ctx.Request().Context(), payload, spec)
+
+	mytlsResult, mytlsErr := mytls.PerformHandshake(ctx.Request().Context(), s.Outbound.dialer(payload), payload)
+	mytlsSide := openapi.CompareImplementationResult{
+		Success:           mytlsErr == nil,
+		RawClientHello:    hex.EncodeToString(mytlsResult.ClientHelloRecord),
+		RawServerResponse: hex.EncodeToString(mytlsResult.Received),
+	}
+	if mytlsErr != nil {
+		msg := mytlsErr.Error()
+		mytlsSide.Error = &msg
+	}
+	// mytlsが受信したメッセージもuTLSと同じ方法で解析し、同じ形式で比較する
+	var recorded []utls.RecordedMessage
+	for _, m := range mytlsResult.ServerFlight {
+		recorded = append(recorded, utls.RecordedMessage{Direction: utls.RecordReceived, Type: uint8(m.Type), Raw: m.Raw})
+	}
+	mytlsFlight, _ := utls.DecodeServerFlight(recorded)
+
+	response := openapi.CompareResponse{
+		Utls:         utlsResult,
+		Mytls:        mytlsSide,
+		ClientHello:  diffFields(clientHelloFields(utlsHello), clientHelloFields(mytlsResult.ClientHelloRecord), clientHelloVolatile),
+		ServerFlight: diffFields(serverFlightFields(utlsFlight), serverFlightFields(mytlsFlight), serverFlightVolatile),
+	}
+	response.Identical = response.Utls.Success && response.Mytls.Success
+	for _, d := range append(response.ClientHello, response.ServerFlight...) {
+		if !d.Equal && !d.Volatile {
+			response.Identical = false
+		}
+	}
+	return ctx.JSON(200, response)
+}
+
+// compareUTLS は、uTLSでハンドシェイクを行い、結果と送信したClientHelloのレコード、
+// 解析したサーバーのハンドシェイクメッセージを返す。失敗した場合もそれまでの内容を返す。
+func (s Server) compareUTLS(reqCtx context.Context, payload openapi.TlsClientParameters, spec *utls.ClientHelloSpec) (openapi.CompareImplementationResult, []byte, *utls.DecodedServerFlight) {
+	recorder := utls.NewHandshakeRecorder()
+	err := func() error {
+		clientRandom, err := hex.DecodeString(payload.ClientRandom)
+		if err != nil {
+			return fmt.Errorf("invalid ClientRandom: %w", err)
+		}
+		version, err := tlsVersion(payload)
+		if err != nil {
+			return err
+		}
+		conn, err := s.Outbound.dial(reqCtx, payload)
+		if err != nil {
+			return fmt.Errorf("net.Dial error: %w", err)
+		}
+		defer conn.Close()
+		setDeadline(conn, reqCtx)
+
+		config := &utls.Config{
+			ServerName: payload.ServerName,
+			MinVersion: version,
+			MaxVersion: version,
+		}
+		if _, err := s.setCertificateVerification(config, payload); err != nil {
+			return err
+		}
+		uconn := utls.UClient(conn, config, utls.HelloCustom)
+		uconn.SetHandshakeRecorder(recorder)
+		if err := uconn.ApplyPreset(spec); err != nil {
+			return err
+		}
+		if err := uconn.SetClientRandom(clientRandom); err != nil {
+			return err
+		}
+		if err := applyClientHelloFields(uconn, payload); err != nil {
+			return err
+		}
+		return uconn.HandshakeContext(reqCtx)
+	}()
+
+	var clientHello []byte
+	for _, r := range recorder.Records() {
+		if r.Direction == utls.RecordSent {
+			clientHello = r.Raw
+			break
+		}
+	}
+	result := openapi.CompareImplementationResult{
+		Success:           err == nil,
+		RawClientHello:    hex.EncodeToString(clientHello),
+		RawServerResponse: hex.EncodeToString(recorder.RawRecords(utls.RecordReceived)),
+	}
+	if err != nil {
+		msg := err.Error()
+		result.Error = &msg
+	}
+	flight, _ := recorder.DecodeServerFlight()
+	return result, clientHello, flight
+}
+
+// field は、比較の単位となる1つのフィールドの値
+type field struct {
+	name  string
+	value string
+}
+
+// volatileRule は、接続ごとに値が変わるフィールドを表す。
+// anyLength が false の場合、長さが同じときに限り実装の違いではないとみなす。
+type volatileRule struct {
+	pattern   *regexp.Regexp
+	anyLength bool
+}
+
+var clientHelloVolatile = []volatileRule{
+	{pattern: regexp.MustCompile(`^random$`)},
+	{pattern: regexp.MustCompile(`^legacy_session_id$`)},
+	{pattern: regexp.MustCompile(`^extensions\[0x0033\]\.client_shares\[\d+\]\.key_exchange$`)},
+}
+
+var serverFlightVolatile = []volatileRule{
+	{pattern: regexp.MustCompile(`^(server_hello|hello_retry_request)\.(raw|legacy_session_id)$`)},
+	{pattern: regexp.MustCompile(`^server_hello\.random$`)},
+	{pattern: regexp.MustCompile(`^server_hello\.key_share\.key_exchange$`)},
+	{pattern: regexp.MustCompile(`^server_hello\.extensions\[0x0033\]\.data$`)},
+	// ECDSAの署名はDERエンコードのため長さも変わる
+	{pattern: regexp.MustCompile(`^certificate_verify\.(raw|signature)$`), anyLength: true},
+	{pattern: regexp.MustCompile(`^finished\.(raw|verify_data)$`)},
+}
+
+// diffFields は、2つのフィールドの列を名前で突き合わせる。
+// 順序はuTLS側を基準とし、mytlsにしかないフィールドはmytls側で直前にあったフィールドの後ろに入れる。
+func diffFields(utlsFields, mytlsFields []field, rules []volatileRule) []openapi.FieldDiff {
+	diffs := make([]openapi.FieldDiff, 0, len(utlsFields))
+	index := map[string]int{}
+	for _, f := range utlsFields {
+		value := f.value
+		index[f.name] = len(diffs)
+		diffs = append(diffs, openapi.FieldDiff{Field: f.name, Utls: &value})
+	}
+	last := -1
+	for _, f := range mytlsFields {
+		value := f.value
+		if i, ok := index[f.name]; ok {
+			diffs[i].Mytls = &value
+			last = i
+			continue
+		}
+		last++
+		diffs = append(diffs[:last], append([]openapi.FieldDiff{{Field: f.name, Mytls: &value}}, diffs[last:]...)...)
+		for name, i := range index {
+			if i >= last {
+				index[name] = i + 1
+			}
+		}
+		index[f.name] = last
+	}
+
+	for i := range diffs {
+		d := &diffs[i]
+		d.Equal = d.Utls != nil && d.Mytls != nil && *d.Utls == *d.Mytls
+		if d.Equal || d.Utls == nil || d.Mytls == nil {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.pattern.MatchString(d.Field) && (rule.anyLength || len(*d.Utls) == len(*d.Mytls)) {
+				d.Volatile = true
+				break
+			}
+		}
+	}
+	return diffs
+}
+
+/**
+ * clientHelloFields は、ClientHelloのレコードをフィールドに分解する。
+ * 解析できなかった場合は、それまでに得られたフィールドとエラーの内容を返す。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.1.2
+ */
+func clientHelloFields(record []byte) []field {
+	var fields []field
+	add := func(name, value string) {
+		fields = append(fields, field{name: name, value: value})
+	}
+	if len(record) == 0 {
+		return nil
+	}
+
+	s := cryptobyte.String(record)
+	var contentType, msgType uint8
+	var recordVersion, recordLength, legacyVersion uint16
+	var msgLength uint32
+	if !s.ReadUint8(&contentType) || !s.ReadUint16(&recordVersion) || !s.ReadUint16(&recordLength) {
+		add("error", "truncated record header")
+		return fields
+	}
+	add("record.content_type", fmt.Sprintf("0x%02x", contentType))
+	add("record.legacy_record_version", uint16ToString(recordVersion))
+	add("record.length", strconv.Itoa(int(recordLength)))
+	if !s.ReadUint8(&msgType) || !s.ReadUint24(&msgLength) {
+		add("error", "truncated handshake header")
+		return fields
+	}
+	add("handshake.msg_type", fmt.Sprintf("0x%02x", msgType))
+	add("handshake.length", strconv.Itoa(int(msgLength)))
+
+	var random, sessionID, compressionMethods []byte
+	var cipherSuites, extensions cryptobyte.String
+	if !s.ReadUint16(&legacyVersion) || !s.ReadBytes(&random, 32) ||
+		!s.ReadUint8LengthPrefixed((*cryptobyte.String)(&sessionID)) ||
+		!s.ReadUint16LengthPrefixed(&cipherSuites) ||
+		!s.ReadUint8LengthPrefixed((*cryptobyte.String)(&compressionMethods)) {
+		add("error", "malformed ClientHello")
+		return fields
+	}
+	add("legacy_version", uint16ToString(legacyVersion))
+	add("random", hex.EncodeToString(random))
+	add("legacy_session_id", hex.EncodeToString(sessionID))
+	for i := 0; !cipherSuites.Empty(); i++ {
+		var cs uint16
+		if !cipherSuites.ReadUint16(&cs) {
+			add("error", "malformed cipher_suites")
+			return fields
+		}
+		add(fmt.Sprintf("cipher_suites[%d]", i), uint16ToString(cs))
+	}
+	add("legacy_compression_methods", hex.EncodeToString(compressionMethods))
+	if s.Empty() {
+		return fields
+	}
+	if !s.ReadUint16LengthPrefixed(&extensions) {
+		add("error", "malformed extensions")
+		return fields
+	}
+
+	var order []string
+	var extFields []field
+	for !extensions.Empty() {
+		var extType uint16
+		var data cryptobyte.String
+		if !extensions.ReadUint16(&extType) || !extensions.ReadUint16LengthPrefixed(&data) {
+			add("error", "malformed extension")
+			return fields
+		}
+		order = append(order, uint16ToString(extType))
+		prefix := fmt.Sprintf("extensions[%s]", uint16ToString(extType))
+		if extType == dicttls.ExtType_key_share {
+			// key_exchangeは接続ごとに異なるため、エントリごとに分けて比較する
+			if shares, ok := keyShareFields(prefix, data); ok {
+				extFields = append(extFields, shares...)
+				continue
+			}
+		}
+		extFields = append(extFields, field{name: prefix + ".data", value: hex.EncodeToString(data)})
+	}
+	add("extensions", strings.Join(order, ","))
+	return append(fields, extFields...)
+}
+
+func keyShareFields(prefix string, data cryptobyte.String) ([]field, bool) {
+	var shares cryptobyte.String
+	if !data.ReadUint16LengthPrefixed(&shares) || !data.Empty() {
+		return nil, false
+	}
+	var fields []field
+	for i := 0; !shares.Empty(); i++ {
+		var group uint16
+		var keyExchange []byte
+		if !shares.ReadUint16(&group) || !shares.ReadUint16LengthPrefixed((*cryptobyte.String)(&keyExchange)) {
+			return nil, false
+		}
+		name := fmt.Sprintf("%s.client_shares[%d]", prefix, i)
+		fields = append(fields,
+			field{name: name + ".group", value: uint16ToString(group)},
+			field{name: name + ".key_exchange", value: hex.EncodeToString(keyExchange)},
+		)
+	}
+	return fields, true
+}
+
+// serverFlightFields は、解析したサーバーのハンドシェイクメッセージをレスポンスと同じ形式に変換し、
+// メッセージの順にフィールドに分解する。拡張は種類ごとに分ける。
+func serverFlightFields(flight *utls.DecodedServerFlight) []field {
+	if flight == nil {
+		return nil
+	}
+	f := newServerFlight(flight)
+	messages := []struct {
+		name    string
+		message any
+	}{
+		{"hello_retry_request", f.HelloRetryRequest},
+		{"server_hello", f.ServerHello},
+		{"encrypted_extensions", f.EncryptedExtensions},
+		{"certificate_request", f.CertificateRequest},
+		{"certificate", f.Certificate},
+		{"certificate_verify", f.CertificateVerify},
+		{"finished", f.Finished},
+	}
+	var fields []field
+	for _, m := range messages {
+		b, err := json.Marshal(m.message)
+		if err != nil || string(b) == "null" {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := flattenJSON(dec, m.name, &fields); err != nil {
+			fields = append(fields, field{name: m.name + ".error", value: err.Error()})
+		}
+	}
+	return fields
+}
+
+// flattenJSON は、JSONの値を "a.b[0].c" 形式の名前を持つフィールドに分解する。
+// オブジェクトのキーの順序は保たれる。"type"を持つ拡張の配列は、添字の代わりに種類で区別する。
+func flattenJSON(dec *json.Decoder, name string, fields *[]field) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := flattenJSON(dec, name+"."+key.(string), fields); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				start := len(*fields)
+				if err := flattenJSON(dec, fmt.Sprintf("%s[%d]", name, i), fields); err != nil {
+					return err
+				}
+				if strings.HasSuffix(name, ".extensions") {
+					renameExtension(name, i, (*fields)[start:])
+				}
+			}
+		}
+		_, err = dec.Token() // 閉じ括弧
+		return err
+	case string:
+		*fields = append(*fields, field{name: name, value: t})
+	case json.Number:
+		*fields = append(*fields, field{name: name, value: t.String()})
+	case bool:
+		*fields = append(*fields, field{name: name, value: strconv.FormatBool(t)})
+	}
+	return nil
+}
+
+// renameExtension は、"extensions[1].data" を "extensions[0x002b].data" のように
+// 拡張の種類で区別する名前に変える。順序の違いで以降の拡張がすべて差分にならないようにするため。
+func renameExtension(name string, i int, fields []field) {
+	var extType string
+	for _, f := range fields {
+		if f.name == fmt.Sprintf("%s[%d].type", name, i) {
+			extType = f.value
+		}
+	}
+	if extType == "" {
+		return
+	}
+	old := fmt.Sprintf("%s[%d]", name, i)
+	for j := range fields {
+		fields[j].name = fmt.Sprintf("%s[%s]", name, extType) + strings.TrimPrefix(fields[j].name, old)
+	}
+}
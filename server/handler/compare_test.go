@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestPostTlsCompare(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	var res openapi.CompareResponse
+	code := doJSON(t, e, http.MethodPost, "/tls/compare", testServerParameters(ts), &res)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if !res.Utls.Success || !res.Mytls.Success {
+		t.Fatalf("handshake failed: utls = %v, mytls = %v", res.Utls.Error, res.Mytls.Error)
+	}
+
+	diffs := map[string]openapi.FieldDiff{}
+	for _, d := range append(res.ClientHello, res.ServerFlight...) {
+		diffs[d.Field] = d
+	}
+	for _, name := range []string{"cipher_suites[0]", "extensions", "extensions[0x0000].data", "extensions[0x0033].client_shares[0].group"} {
+		if d, ok := diffs[name]; !ok || !d.Equal {
+			t.Errorf("client_hello field %s = %+v, want equal", name, d)
+		}
+	}
+	// client_randomを指定しているためRandomは一致し、key_exchangeは接続ごとに異なる
+	if d := diffs["random"]; !d.Equal {
+		t.Errorf("random = %+v, want equal", d)
+	}
+	if d := diffs["extensions[0x0033].client_shares[0].key_exchange"]; d.Equal || !d.Volatile {
+		t.Errorf("key_exchange = %+v, want volatile", d)
+	}
+	if d := diffs["server_hello.cipher_suite"]; !d.Equal {
+		t.Errorf("server_hello.cipher_suite = %+v, want equal", d)
+	}
+	if d := diffs["certificate.certificate_list[0].cert_data"]; !d.Equal {
+		t.Errorf("certificate = %+v, want equal", d)
+	}
+}
+
+func TestPostTlsCompareUnsupportedParameters(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		modify func(*openapi.TlsClientParameters)
+	}{
+		{
+			name: "異常系：preset は指定できない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-133")
+			},
+		},
+		{
+			name: "異常系：extensions は指定できない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Extensions = &[]openapi.ClientHelloExtension{{Name: "server_name"}, {Name: "supported_versions"}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			var res openapi.ErrorResponse
+			if code := doJSON(t, e, http.MethodPost, "/tls/compare", params, &res); code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+			}
+			if res.Code != openapi.ErrorCodeInvalidRequest {
+				t.Errorf("code = %s, want %s", res.Code, openapi.ErrorCodeInvalidRequest)
+			}
+		})
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		utls  []field
+		mytls []field
+		want  []openapi.FieldDiff
+	}{
+		{
+			name:  "正常系：一致するフィールドと乱数のフィールド",
+			utls:  []field{{"legacy_version", "0x0303"}, {"random", "aaaa"}},
+			mytls: []field{{"legacy_version", "0x0303"}, {"random", "bbbb"}},
+			want: []openapi.FieldDiff{
+				{Field: "legacy_version", Utls: ptr("0x0303"), Mytls: ptr("0x0303"), Equal: true},
+				{Field: "random", Utls: ptr("aaaa"), Mytls: ptr("bbbb"), Volatile: true},
+			},
+		},
+		{
+			name:  "正常系：長さの違う乱数のフィールドは実装の違い",
+			utls:  []field{{"legacy_session_id", "aaaa"}},
+			mytls: []field{{"legacy_session_id", ""}},
+			want: []openapi.FieldDiff{
+				{Field: "legacy_session_id", Utls: ptr("aaaa"), Mytls: ptr("")},
+			},
+		},
+		{
+			name:  "正常系：一方にしかないフィールドは直前のフィールドの後ろに入る",
+			utls:  []field{{"a", "1"}, {"c", "3"}},
+			mytls: []field{{"a", "1"}, {"b", "2"}, {"c", "3"}},
+			want: []openapi.FieldDiff{
+				{Field: "a", Utls: ptr("1"), Mytls: ptr("1"), Equal: true},
+				{Field: "b", Mytls: ptr("2")},
+				{Field: "c", Utls: ptr("3"), Mytls: ptr("3"), Equal: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffFields(tt.utls, tt.mytls, clientHelloVolatile)
+			if len(got) != len(tt.want) {
+				t.Fatalf("diffFields() returned %d fields, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Field != w.Field || g.Equal != w.Equal || g.Volatile != w.Volatile ||
+					!equalPtr(g.Utls, w.Utls) || !equalPtr(g.Mytls, w.Mytls) {
+					t.Errorf("diffFields()[%d] = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func equalPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+const (
+	// extensionNameGREASE は、GREASE値の拡張を表す名前です。
+	extensionNameGREASE = "GREASE"
+	// extensionNameRaw は、種類と中身を直接指定する拡張を表す名前です。
+	extensionNameRaw = "raw"
+)
+
+// newTLSExtensions は、リクエストで指定された順にuTLSの拡張を組み立てます。
+func newTLSExtensions(exts []openapi.ClientHelloExtension, payload openapi.TlsClientParameters) ([]utls.TLSExtension, error) {
+	extensions := make([]utls.TLSExtension, 0, len(exts))
+	for i, ext := range exts {
+		e, err := newTLSExtension(ext, payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extensions[%d] (%s): %w", i, ext.Name, err)
+		}
+		extensions = append(extensions, e)
+	}
+	return extensions, nil
+}
+
+// newTLSExtension は、1つの拡張の指定をuTLSの拡張に変換します。
+// 省略された項目はTlsClientParametersの値で補います。
+func newTLSExtension(ext openapi.ClientHelloExtension, payload openapi.TlsClientParameters) (utls.TLSExtension, error) {
+	switch ext.Name {
+	case extensionNameGREASE:
+		body, err := optionalHex(ext.Data)
+		if err != nil {
+			return nil, err
+		}
+		// Valueは ApplyPreset で GREASE 値が割り当てられる
+		return &utls.UtlsGREASEExtension{Body: body}, nil
+	case extensionNameRaw:
+		if ext.Type == nil {
+			return nil, errors.New("type is required")
+		}
+		id, err := stringToUint16(*ext.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type: %s", *ext.Type)
+		}
+		data, err := optionalHex(ext.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &utls.GenericExtension{Id: id, Data: data}, nil
+	case "server_name":
+		serverName := payload.ServerName
+		if ext.ServerName != nil {
+			serverName = *ext.ServerName
+		}
+		return &utls.SNIExtension{ServerName: serverName}, nil
+	case "status_request":
+		return &utls.StatusRequestExtension{}, nil
+	case "status_request_v2":
+		return &utls.StatusRequestV2Extension{}, nil
+	case "supported_groups":
+		groups, err := parseCodepoints(valueOr(ext.Groups, payload.SupportedGroups), "supported group")
+		if err != nil {
+			return nil, err
+		}
+		curves := make([]utls.CurveID, len(groups))
+		for i, g := range groups {
+			curves[i] = utls.CurveID(g)
+		}
+		return &utls.SupportedCurvesExtension{Curves: curves}, nil
+	case "key_share":
+		groups, err := parseCodepoints(valueOr(ext.Groups, payload.KeyShares), "key share")
+		if err != nil {
+			return nil, err
+		}
+		keyShares := make([]utls.KeyShare, len(groups))
+		for i, g := range groups {
+			keyShares[i] = utls.KeyShare{Group: utls.CurveID(g)}
+			// GREASEのkey_shareは1バイトの中身を持つ
+			if g == utls.GREASE_PLACEHOLDER {
+				keyShares[i].Data = []byte{0}
+			}
+		}
+		return &utls.KeyShareExtension{KeyShares: keyShares}, nil
+	case "ec_point_formats":
+		formats, err := parseUint8s(valueOr(ext.PointFormats, []string{"0x00"}), "point format")
+		if err != nil {
+			return nil, err
+		}
+		return &utls.SupportedPointsExtension{SupportedPoints: formats}, nil
+	case "signature_algorithms":
+		schemes, err := parseSignatureSchemes(valueOr(ext.SignatureAlgorithms, payload.SignatureAlgorithms))
+		if err != nil {
+			return nil, err
+		}
+		return &utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: schemes}, nil
+	case "signature_algorithms_cert":
+		schemes, err := parseSignatureSchemes(valueOr(ext.SignatureAlgorithms, payload.SignatureAlgorithms))
+		if err != nil {
+			return nil, err
+		}
+		return &utls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: schemes}, nil
+	case "delegated_credentials":
+		schemes, err := parseSignatureSchemes(valueOr(ext.SignatureAlgorithms, payload.SignatureAlgorithms))
+		if err != nil {
+			return nil, err
+		}
+		return &utls.FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: schemes}, nil
+	case "application_layer_protocol_negotiation":
+		return &utls.ALPNExtension{AlpnProtocols: valueOr(ext.Protocols, nil)}, nil
+	case "application_settings":
+		return &utls.ApplicationSettingsExtension{SupportedProtocols: valueOr(ext.Protocols, nil)}, nil
+	case "application_settings_new":
+		return &utls.ApplicationSettingsExtensionNew{SupportedProtocols: valueOr(ext.Protocols, nil)}, nil
+	case "next_protocol_negotiation":
+		return &utls.NPNExtension{NextProtos: valueOr(ext.Protocols, nil)}, nil
+	case "signed_certificate_timestamp":
+		return &utls.SCTExtension{}, nil
+	case "padding":
+		if ext.PaddingLength == nil {
+			return &utls.UtlsPaddingExtension{GetPaddingLen: utls.BoringPaddingStyle}, nil
+		}
+		if *ext.PaddingLength < 0 || *ext.PaddingLength > 0xffff {
+			return nil, fmt.Errorf("invalid padding_length: %d", *ext.PaddingLength)
+		}
+		return &utls.UtlsPaddingExtension{PaddingLen: *ext.PaddingLength, WillPad: true}, nil
+	case "extended_master_secret":
+		return &utls.ExtendedMasterSecretExtension{}, nil
+	case "token_binding":
+		versions := valueOr(ext.Versions, []string{"0x000d"})
+		if len(versions) != 1 {
+			return nil, errors.New("token_binding requires exactly one version")
+		}
+		version, err := stringToUint16(versions[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version: %s", versions[0])
+		}
+		params, err := parseUint8s(valueOr(ext.Algorithms, []string{"0x02"}), "key parameter")
+		if err != nil {
+			return nil, err
+		}
+		return &utls.FakeTokenBindingExtension{
+			MajorVersion:  uint8(version >> 8),
+			MinorVersion:  uint8(version),
+			KeyParameters: params,
+		}, nil
+	case "compress_certificate":
+		algorithms, err := parseCodepoints(valueOr(ext.Algorithms, []string{"0x0002"}), "certificate compression algorithm")
+		if err != nil {
+			return nil, err
+		}
+		algos := make([]utls.CertCompressionAlgo, len(algorithms))
+		for i, a := range algorithms {
+			algos[i] = utls.CertCompressionAlgo(a)
+		}
+		return &utls.UtlsCompressCertExtension{Algorithms: algos}, nil
+	case "record_size_limit":
+		if ext.RecordSizeLimit == nil {
+			return nil, errors.New("record_size_limit is required")
+		}
+		// @see https://datatracker.ietf.org/doc/html/rfc8449#section-4
+		if *ext.RecordSizeLimit < 64 || *ext.RecordSizeLimit > 0x4001 {
+			return nil, fmt.Errorf("invalid record_size_limit: %d", *ext.RecordSizeLimit)
+		}
+		return &utls.FakeRecordSizeLimitExtension{Limit: uint16(*ext.RecordSizeLimit)}, nil
+	case "session_ticket":
+		ticket, err := optionalHex(ext.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &utls.SessionTicketExtension{Ticket: ticket}, nil
+	case "supported_versions":
+		versions, err := parseCodepoints(valueOr(ext.Versions, []string{payload.ProtocolVersion}), "version")
+		if err != nil {
+			return nil, err
+		}
+		return &utls.SupportedVersionsExtension{Versions: versions}, nil
+	case "cookie":
+		cookie, err := optionalHex(ext.Data)
+		if err != nil {
+			return nil, err
+		}
+		if len(cookie) == 0 {
+			return nil, errors.New("data is required")
+		}
+		return &utls.CookieExtension{Cookie: cookie}, nil
+	case "psk_key_exchange_modes":
+		modes, err := parseUint8s(valueOr(ext.Modes, []string{"0x01"}), "psk key exchange mode")
+		if err != nil {
+			return nil, err
+		}
+		return &utls.PSKKeyExchangeModesExtension{Modes: modes}, nil
+	case "quic_transport_parameters":
+		data, err := optionalHex(ext.Data)
+		if err != nil {
+			return nil, err
+		}
+		params, err := parseQUICTransportParameters(data)
+		if err != nil {
+			return nil, err
+		}
+		return &utls.QUICTransportParametersExtension{TransportParameters: params}, nil
+	case "renegotiation_info":
+		renegotiation := utls.RenegotiateOnceAsClient
+		if ext.Renegotiation != nil {
+			switch *ext.Renegotiation {
+			case openapi.ClientHelloExtensionRenegotiationNever:
+				renegotiation = utls.RenegotiateNever
+			case openapi.ClientHelloExtensionRenegotiationOnce:
+				renegotiation = utls.RenegotiateOnceAsClient
+			case openapi.ClientHelloExtensionRenegotiationFreely:
+				renegotiation = utls.RenegotiateFreelyAsClient
+			default:
+				return nil, fmt.Errorf("invalid renegotiation: %s", *ext.Renegotiation)
+			}
+		}
+		return &utls.RenegotiationInfoExtension{Renegotiation: renegotiation}, nil
+	case "channel_id":
+		return &utls.FakeChannelIDExtension{}, nil
+	case "channel_id_old":
+		return &utls.FakeChannelIDExtension{OldExtensionID: true}, nil
+	case "encrypted_client_hello":
+		return utls.BoringGREASEECH(), nil
+	case "pre_shared_key":
+		// 事前共有鍵は保存したセッションからしか作れないため、session_handle が必要
+		if payload.Resumption == nil || payload.Resumption.SessionHandle == nil {
+			return nil, errors.New("extension requires resumption.session_handle")
+		}
+		return &utls.UtlsPreSharedKeyExtension{}, nil
+	case "early_data":
+		// 0-RTT の早期データの送信は TCP では対応していない
+		return nil, errEarlyDataNotImplemented
+	default:
+		return nil, errors.New("unsupported extension")
+	}
+}
+
+// applyClientHelloFields は、ApplyPresetで設定できないClientHelloのフィールドを設定します。
+// ApplyPresetはlegacy_session_idを乱数で上書きし、legacy_compression_methodsを使わないため、
+// ApplyPresetの後に呼び出す必要があります。
+func applyClientHelloFields(uconn *utls.UConn, payload openapi.TlsClientParameters) error {
+	if payload.SessionId != nil {
+		sessionID, err := hex.DecodeString(*payload.SessionId)
+		if err != nil {
+			return fmt.Errorf("invalid session_id: %w", err)
+		}
+		if len(sessionID) > 32 {
+			return fmt.Errorf("invalid session_id: %d bytes (max 32)", len(sessionID))
+		}
+		uconn.HandshakeState.Hello.SessionId = sessionID
+	}
+	if payload.CompressionMethods != nil {
+		methods, err := parseUint8s(*payload.CompressionMethods, "compression method")
+		if err != nil {
+			return err
+		}
+		if len(methods) == 0 {
+			return errors.New("compression_methods must not be empty")
+		}
+		uconn.HandshakeState.Hello.CompressionMethods = methods
+	}
+	return nil
+}
+
+/**
+ * 0xから始まる16進数文字列、または "GREASE" をuint16に変換する
+ * "GREASE" はuTLSがハンドシェイクごとにGREASE値に置き換える
+ */
+func parseCodepoint(s string) (uint16, error) {
+	if strings.EqualFold(s, extensionNameGREASE) {
+		return utls.GREASE_PLACEHOLDER, nil
+	}
+	return stringToUint16(s)
+}
+
+func parseCodepoints(values []string, kind string) ([]uint16, error) {
+	result := make([]uint16, len(values))
+	for i, v := range values {
+		val, err := parseCodepoint(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", kind, v)
+		}
+		result[i] = val
+	}
+	return result, nil
+}
+
+func parseUint8s(values []string, kind string) ([]uint8, error) {
+	result := make([]uint8, len(values))
+	for i, v := range values {
+		val, err := stringToUint16(v)
+		if err != nil || val > 0xff {
+			return nil, fmt.Errorf("invalid %s: %s", kind, v)
+		}
+		result[i] = uint8(val)
+	}
+	return result, nil
+}
+
+func parseSignatureSchemes(values []string) ([]utls.SignatureScheme, error) {
+	result := make([]utls.SignatureScheme, len(values))
+	for i, v := range values {
+		val, err := stringToUint16(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature algorithm: %s", v)
+		}
+		result[i] = utls.SignatureScheme(val)
+	}
+	return result, nil
+}
+
+/**
+ * QUICのトランスポートパラメータ (可変長整数のid, length と値の並び) を分解する
+ * @see https://datatracker.ietf.org/doc/html/rfc9000#section-18
+ */
+func parseQUICTransportParameters(data []byte) (utls.TransportParameters, error) {
+	var params utls.TransportParameters
+	for len(data) > 0 {
+		id, n, err := readQUICVarint(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		length, n, err := readQUICVarint(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		if uint64(len(data)) < length {
+			return nil, errors.New("truncated quic transport parameter")
+		}
+		if id == 0 {
+			// FakeQUICTransportParameterはidに0を使えない
+			return nil, errors.New("quic transport parameter id 0 is not supported")
+		}
+		params = append(params, &utls.FakeQUICTransportParameter{Id: id, Val: data[:length]})
+		data = data[length:]
+	}
+	return params, nil
+}
+
+// @see https://datatracker.ietf.org/doc/html/rfc9000#section-16
+func readQUICVarint(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errors.New("truncated quic variable-length integer")
+	}
+	n := 1 << (b[0] >> 6)
+	if len(b) < n {
+		return 0, 0, errors.New("truncated quic variable-length integer")
+	}
+	v := uint64(b[0] & 0x3f)
+	for _, c := range b[1:n] {
+		v = v<<8 | uint64(c)
+	}
+	return v, n, nil
+}
+
+func optionalHex(s *string) ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(*s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %w", err)
+	}
+	return b, nil
+}
+
+func valueOr[T any](p *[]T, def []T) []T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
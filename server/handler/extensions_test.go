@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestNewTLSExtension(t *testing.T) {
+	payload := openapi.TlsClientParameters{
+		ProtocolVersion:     "0x0304",
+		ServerName:          "example.com",
+		CipherSuites:        []string{"0x1301"},
+		SupportedGroups:     []string{"0x001d", "0x0017"},
+		KeyShares:           []string{"0x001d"},
+		SignatureAlgorithms: []string{"0x0403"},
+	}
+	str := func(s string) *string { return &s }
+	strs := func(s ...string) *[]string { return &s }
+	num := func(n int) *int { return &n }
+
+	tests := []struct {
+		name         string
+		ext          openapi.ClientHelloExtension
+		want         utls.TLSExtension
+		expectingErr bool
+	}{
+		{
+			name: "正常系：server_nameは省略時にserver_nameを使う",
+			ext:  openapi.ClientHelloExtension{Name: "server_name"},
+			want: &utls.SNIExtension{ServerName: "example.com"},
+		},
+		{
+			name: "正常系：supported_groupsでGREASEを指定できる",
+			ext:  openapi.ClientHelloExtension{Name: "supported_groups", Groups: strs("GREASE", "0x001d")},
+			want: &utls.SupportedCurvesExtension{Curves: []utls.CurveID{utls.GREASE_PLACEHOLDER, utls.X25519}},
+		},
+		{
+			name: "正常系：key_shareのGREASEは1バイトの中身を持つ",
+			ext:  openapi.ClientHelloExtension{Name: "key_share", Groups: strs("GREASE", "0x001d")},
+			want: &utls.KeyShareExtension{KeyShares: []utls.KeyShare{
+				{Group: utls.GREASE_PLACEHOLDER, Data: []byte{0}},
+				{Group: utls.X25519},
+			}},
+		},
+		{
+			name: "正常系：ALPN",
+			ext:  openapi.ClientHelloExtension{Name: "application_layer_protocol_negotiation", Protocols: strs("h2", "http/1.1")},
+			want: &utls.ALPNExtension{AlpnProtocols: []string{"h2", "http/1.1"}},
+		},
+		{
+			name: "正常系：psk_key_exchange_modesの既定値はpsk_dhe_ke",
+			ext:  openapi.ClientHelloExtension{Name: "psk_key_exchange_modes"},
+			want: &utls.PSKKeyExchangeModesExtension{Modes: []uint8{1}},
+		},
+		{
+			name: "正常系：compress_certificate",
+			ext:  openapi.ClientHelloExtension{Name: "compress_certificate", Algorithms: strs("0x0002", "0x0001")},
+			want: &utls.UtlsCompressCertExtension{Algorithms: []utls.CertCompressionAlgo{utls.CertCompressionBrotli, utls.CertCompressionZlib}},
+		},
+		{
+			name: "正常系：paddingの長さを指定できる",
+			ext:  openapi.ClientHelloExtension{Name: "padding", PaddingLength: num(100)},
+			want: &utls.UtlsPaddingExtension{PaddingLen: 100, WillPad: true},
+		},
+		{
+			name: "正常系：supported_versionsは省略時にprotocol_versionを使う",
+			ext:  openapi.ClientHelloExtension{Name: "supported_versions"},
+			want: &utls.SupportedVersionsExtension{Versions: []uint16{utls.VersionTLS13}},
+		},
+		{
+			name: "正常系：renegotiation_info",
+			ext:  openapi.ClientHelloExtension{Name: "renegotiation_info", Renegotiation: ptr(openapi.ClientHelloExtensionRenegotiationNever)},
+			want: &utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateNever},
+		},
+		{
+			name: "正常系：GREASE",
+			ext:  openapi.ClientHelloExtension{Name: "GREASE", Data: str("00")},
+			want: &utls.UtlsGREASEExtension{Body: []byte{0}},
+		},
+		{
+			name: "正常系：raw",
+			ext:  openapi.ClientHelloExtension{Name: "raw", Type: str("0xff01"), Data: str("00")},
+			want: &utls.GenericExtension{Id: 0xff01, Data: []byte{0}},
+		},
+		{
+			name: "正常系：quic_transport_parameters",
+			ext:  openapi.ClientHelloExtension{Name: "quic_transport_parameters", Data: str("0104800075300f00")},
+			want: &utls.QUICTransportParametersExtension{TransportParameters: utls.TransportParameters{
+				&utls.FakeQUICTransportParameter{Id: 0x01, Val: []byte{0x80, 0x00, 0x75, 0x30}},
+				&utls.FakeQUICTransportParameter{Id: 0x0f, Val: []byte{}},
+			}},
+		},
+		{
+			name:         "異常系：rawでtypeがない",
+			ext:          openapi.ClientHelloExtension{Name: "raw", Data: str("00")},
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：record_size_limitが小さすぎる",
+			ext:          openapi.ClientHelloExtension{Name: "record_size_limit", RecordSizeLimit: num(63)},
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：pre_shared_keyは指定できない",
+			ext:          openapi.ClientHelloExtension{Name: "pre_shared_key"},
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：quic_transport_parametersが途中で切れている",
+			ext:          openapi.ClientHelloExtension{Name: "quic_transport_parameters", Data: str("010480")},
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：未知の拡張",
+			ext:          openapi.ClientHelloExtension{Name: "unknown"},
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTLSExtension(tt.ext, payload)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("newTLSExtension() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if !tt.expectingErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newTLSExtension() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostTlsHandshakeExtensions(t *testing.T) {
+	e, ts := newTestServer(t)
+	str := func(s string) *string { return &s }
+	strs := func(s ...string) *[]string { return &s }
+
+	t.Run("正常系：指定した順に拡張を送信する", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.CipherSuites = []string{"GREASE", "0x1301", "0x1302", "0x1303"}
+		params.SessionId = str("")
+		paddingLen := 32
+		params.Extensions = &[]openapi.ClientHelloExtension{
+			{Name: "GREASE"},
+			{Name: "server_name"},
+			{Name: "extended_master_secret"},
+			{Name: "renegotiation_info"},
+			{Name: "supported_groups", Groups: strs("GREASE", "0x001d", "0x0017")},
+			{Name: "ec_point_formats"},
+			{Name: "session_ticket"},
+			{Name: "application_layer_protocol_negotiation", Protocols: strs("h2", "http/1.1")},
+			{Name: "status_request"},
+			{Name: "signature_algorithms"},
+			{Name: "signed_certificate_timestamp"},
+			{Name: "key_share", Groups: strs("GREASE", "0x001d")},
+			{Name: "psk_key_exchange_modes"},
+			{Name: "supported_versions", Versions: strs("GREASE", "0x0304")},
+			{Name: "compress_certificate"},
+			{Name: "application_settings", Protocols: strs("h2")},
+			{Name: "GREASE", Data: str("00")},
+			{Name: "padding", PaddingLength: &paddingLen},
+		}
+		var res openapi.HandshakeResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		// record(5) + handshake header(4) + legacy_version(2) + random(32) の後に空のsession_id
+		if got := res.RawClientHello[2*43 : 2*44]; got != "00" {
+			t.Errorf("legacy_session_id length = %s, want 00", got)
+		}
+		// 最後の拡張はpadding (0x0015)
+		raw, err := hex.DecodeString(res.RawClientHello)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields := clientHelloFields(raw)
+		for _, f := range fields {
+			if f.name == "extensions" && !strings.HasSuffix(f.value, ",0x0015") {
+				t.Errorf("extensions = %s, want padding last", f.value)
+			}
+		}
+	})
+
+	t.Run("異常系：TLS 1.3で圧縮方式を指定するとサーバーに拒否される", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.CompressionMethods = strs("0x01", "0x00")
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, nil)
+		if code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("異常系：early_data 拡張は実装されていない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.Extensions = &[]openapi.ClientHelloExtension{{Name: "early_data"}}
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusNotImplemented {
+			t.Fatalf("status = %d, want %d", code, http.StatusNotImplemented)
+		}
+		if res.Code != openapi.ErrorCodeNotImplemented || !strings.Contains(res.Message, "extensions[0] (early_data)") {
+			t.Errorf("code = %s, message = %q", res.Code, res.Message)
+		}
+	})
+}
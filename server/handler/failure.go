@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/hex"
+	"errors"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/dicttls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// newHandshakeFailure は、errにハンドシェイク失敗時の状況が含まれていればレスポンス用の構造体に変換する
+func newHandshakeFailure(err error) *openapi.HandshakeFailure {
+	var failure *utls.HandshakeFailure
+	if !errors.As(err, &failure) {
+		return nil
+	}
+	res := &openapi.HandshakeFailure{
+		State:         failure.State,
+		ReceivedAlert: newTlsAlert(failure.ReceivedAlert),
+		SentAlert:     newTlsAlert(failure.SentAlert),
+	}
+	if m := failure.LastMessage; m != nil {
+		res.LastMessage = newRecordedMessage(*m)
+	}
+	return res
+}
+
+// handshakeError は、ハンドシェイクが失敗した時点の状況を記録していればそれを、
+// 記録していなければerrをそのまま返す。返したエラーも errors.Is / errors.As でerrとして扱える。
+func handshakeError(failure *utls.HandshakeFailure, err error) error {
+	if failure != nil {
+		return failure
+	}
+	return err
+}
+
+/**
+ * newTlsAlert は、Alertをレスポンス用の構造体に変換する
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-6
+ */
+func newTlsAlert(a *utls.HandshakeAlert) *openapi.TlsAlert {
+	if a == nil {
+		return nil
+	}
+	levelName := "fatal"
+	if a.Level == utls.AlertLevelWarning {
+		levelName = "warning"
+	}
+	return &openapi.TlsAlert{
+		Level:           int(a.Level),
+		LevelName:       levelName,
+		Description:     int(a.Description),
+		DescriptionName: dicttls.DictAlertValueIndexed[a.Description],
+	}
+}
+
+// newRecordedMessage は、記録されたハンドシェイクメッセージを解析せずにレスポンス用の構造体に変換する
+func newRecordedMessage(m utls.RecordedMessage) *openapi.HandshakeStepMessage {
+	return &openapi.HandshakeStepMessage{
+		Direction: openapi.HandshakeStepMessageDirection(m.Direction.String()),
+		Type:      int(m.Type),
+		TypeName:  dicttls.DictHandshakeTypeValueIndexed[m.Type],
+		Raw:       hex.EncodeToString(m.Raw),
+		Encrypted: m.Encrypted,
+	}
+}
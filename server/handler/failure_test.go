@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestHandshakeFailure(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name          string
+		modify        func(*openapi.TlsClientParameters)
+		state         string
+		lastMessage   string
+		receivedAlert string
+		sentAlert     string
+	}{
+		{
+			name: "異常系：サーバーが対応していない暗号スイートではhandshake_failureを受信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0x009c"}
+			},
+			state:         "WAIT_SH",
+			lastMessage:   "sent client_hello",
+			receivedAlert: "fatal handshake_failure",
+		},
+		{
+			name: "異常系：証明書のホスト名が一致しない場合はbad_certificateを送信する",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ServerName = "example.com"
+			},
+			state:       "WAIT_CV",
+			lastMessage: "received certificate",
+			sentAlert:   "fatal bad_certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			var res openapi.ErrorResponse
+			code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+			if code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+			}
+			failure := res.Failure
+			if failure == nil {
+				t.Fatalf("failure is missing: %+v", res)
+			}
+			if failure.State != tt.state {
+				t.Errorf("state = %s, want %s", failure.State, tt.state)
+			}
+			if m := failure.LastMessage; m == nil || string(m.Direction)+" "+m.TypeName != tt.lastMessage {
+				t.Errorf("last_message = %+v, want %s", m, tt.lastMessage)
+			}
+			if got := alertString(failure.ReceivedAlert); got != tt.receivedAlert {
+				t.Errorf("received_alert = %q, want %q", got, tt.receivedAlert)
+			}
+			if got := alertString(failure.SentAlert); got != tt.sentAlert {
+				t.Errorf("sent_alert = %q, want %q", got, tt.sentAlert)
+			}
+		})
+	}
+
+	t.Run("異常系：セッションで進めたハンドシェイクの失敗にもfailureを含める", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ServerName = "example.com"
+		id := createSession(t, e, params)
+		var res openapi.HandshakeStepResponse
+		for i := 0; i < 10 && !res.Done; i++ {
+			if code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, &res); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+		}
+		if res.Error == nil || res.Failure == nil {
+			t.Fatalf("error or failure is missing: %+v", res)
+		}
+		if got := alertString(res.Failure.SentAlert); got != "fatal bad_certificate" {
+			t.Errorf("sent_alert = %q, want %q", got, "fatal bad_certificate")
+		}
+	})
+
+	t.Run("異常系：ハンドシェイク前のエラーにはfailureを含めない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ProtocolVersion = "0x0302"
+		var res openapi.ErrorResponse
+		doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+		if res.Failure != nil {
+			t.Errorf("failure = %+v, want nil", res.Failure)
+		}
+	})
+}
+
+func alertString(a *openapi.TlsAlert) string {
+	if a == nil {
+		return ""
+	}
+	return a.LevelName + " " + a.DescriptionName
+}
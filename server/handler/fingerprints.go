@@ -0,0 +1,48 @@
+package handler
+
+import (
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// newFingerprints は、送信した ClientHello の JA3 と JA4、受信した ServerHello の JA3S と JA4S、
+// サーバー証明書の JA4X をまとめる。ClientHello を送信していない場合や解析できない場合は nil を返す
+func newFingerprints(recorder *utls.HandshakeRecorder, state utls.ConnectionState) *openapi.Fingerprints {
+	raw := recorder.Message(utls.RecordSent, utls.HandshakeTypeClientHello)
+	if raw == nil {
+		return nil
+	}
+	hello := &utls.PubClientHelloMsg{Raw: raw}
+	ja3, err := hello.JA3()
+	if err != nil {
+		return nil
+	}
+	ja4, err := hello.JA4()
+	if err != nil {
+		return nil
+	}
+	res := &openapi.Fingerprints{
+		Ja3:     ja3.String,
+		Ja3Hash: ja3.Hash,
+		Ja4:     ja4.Fingerprint,
+		Ja4R:    ja4.Raw,
+	}
+	// ServerHello を解析できなかった場合は空になる
+	if state.JA3S.String != "" {
+		res.Ja3s = &state.JA3S.String
+		res.Ja3sHash = &state.JA3S.Hash
+		res.Ja4s = &state.JA4S.Fingerprint
+		res.Ja4sR = &state.JA4S.Raw
+	}
+	if len(state.JA4X) > 0 {
+		ja4x := make([]string, len(state.JA4X))
+		ja4xR := make([]string, len(state.JA4X))
+		for i, x := range state.JA4X {
+			ja4x[i] = x.Fingerprint
+			ja4xR[i] = x.Raw
+		}
+		res.Ja4x = &ja4x
+		res.Ja4xR = &ja4xR
+	}
+	return res
+}
@@ -2,31 +2,67 @@ package handler
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"net"
+	"net/http"
 	"os"
-	"time"
 
 	"github.com/labstack/echo/v4"
 	utls "github.com/refraction-networking/utls"
 	"github.com/refraction-networking/utls/server/mytls"
 	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
 )
 
-type Server struct{}
+type Server struct {
+	// TestServer は、起動している場合のローカルテストサーバーです。
+	TestServer *testserver.Server
+
+	// Sessions は、/tls/sessions で作成したハンドシェイクのセッションを保持します。
+	Sessions *SessionStore
+
+	// Tickets は、/tls/handshake で保存したセッションチケットを保持します。
+	Tickets *TicketStore
+
+	// Outbound は、接続先への接続を制限します。nil の場合は制限しません。
+	Outbound *Outbound
+}
 
 // handleBadRequest は、リクエスト処理中にエラーが発生した場合に、
 // mytlsでの通信試行結果を含めたエラーレスポンスを返します。
-func handleBadRequest(ctx echo.Context, originalError error, params openapi.TlsClientParameters) error {
-	sent, received, mytlsErr := mytls.PerformHandshake(params)
-
-	response := map[string]interface{}{
-		"message":             originalError.Error(),
-		"raw_client_hello":    hex.EncodeToString(sent),
-		"raw_server_response": hex.EncodeToString(received),
+// ハンドシェイクが失敗した場合は、受信・送信したAlertと失敗した時点の状態も返します。
+// 接続先が Outbound で許可されていない場合は、mytlsでの通信は行いません。
+// 0-RTT の早期データのように実装されていない機能が指定された場合は、mytlsでの通信を行わずに 501 を返します。
+func (s Server) handleBadRequest(ctx echo.Context, originalError error, params openapi.TlsClientParameters) error {
+	if errors.Is(originalError, errEarlyDataNotImplemented) {
+		return ctx.JSON(http.StatusNotImplemented, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeNotImplemented,
+			Message: originalError.Error(),
+		})
+	}
+	response := openapi.ErrorResponse{
+		Code:    openapi.ErrorCodeInvalidRequest,
+		Message: originalError.Error(),
+		Failure: newHandshakeFailure(originalError),
 	}
-	if mytlsErr != nil {
-		response["mytls_error"] = mytlsErr.Error()
+	var verificationErr *verificationError
+	if errors.As(originalError, &verificationErr) {
+		response.Code = openapi.ErrorCodeCertificateVerificationFailed
+		response.CertificateVerification = verificationErr.result
+	} else if isTimeout(originalError) {
+		response.Code = openapi.ErrorCodeTimeout
+	} else if response.Failure != nil {
+		response.Code = openapi.ErrorCodeHandshakeFailed
+	}
+	// mytlsも Outbound を通して接続し、許可されていない接続先の場合は結果を含めない
+	result, mytlsErr := mytls.PerformHandshake(ctx.Request().Context(), s.Outbound.dialer(params), params)
+	if !errors.Is(mytlsErr, errDestinationNotAllowed) {
+		response.RawClientHello = hex.EncodeToString(result.ClientHelloRecord)
+		response.RawServerResponse = hex.EncodeToString(result.Received)
+		if mytlsErr != nil {
+			mytlsError := mytlsErr.Error()
+			response.MytlsError = &mytlsError
+		}
 	}
 
 	return ctx.JSON(400, response)
@@ -35,115 +71,256 @@ func handleBadRequest(ctx echo.Context, originalError error, params openapi.TlsC
 func (s Server) PostTlsHandshake(ctx echo.Context) error {
 	var payload openapi.HandshakeRequest
 	if err := ctx.Bind(&payload); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+
+	if payload.Resumption != nil && payload.Resumption.EarlyData != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid resumption.early_data: %w", errEarlyDataNotImplemented), payload)
 	}
 
 	spec, err := createClientHelloSpec(payload)
 	if err != nil {
-		return handleBadRequest(ctx, err, payload)
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	minVersion, maxVersion, err := tlsVersionRange(payload, spec)
+	if err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 	}
 
 	clientRandom, err := hex.DecodeString(payload.ClientRandom)
 	if err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid ClientRandom: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid ClientRandom: %w", err), payload)
 	}
 
-	// 接続先ホストはServerNameを使い、ポートは443をデフォルトとする
-	conn, err := net.DialTimeout("tcp", payload.ServerName+":443", 5*time.Second)
+	// 接続先はaddress/portで指定でき、省略時はServerNameの443番ポートとする
+	if _, _, err := mytls.DialTarget(payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	conn, err := s.Outbound.dial(ctx.Request().Context(), payload)
 	if err != nil {
-		return ctx.JSON(500, fmt.Sprintf("net.Dial error: %v", err))
+		return dialFailed(ctx, err)
 	}
 	defer conn.Close()
+	setDeadline(conn, ctx.Request().Context())
 
-	var serverResponse []byte
 	config := &utls.Config{
-		ServerName:     payload.ServerName,
-		ServerResponse: &serverResponse,
-		KeyLogWriter:   os.Stderr,
-		MinVersion:     utls.VersionTLS13,
-		MaxVersion:     utls.VersionTLS13,
+		ServerName:   payload.ServerName,
+		KeyLogWriter: os.Stderr,
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+	}
+	verifier, err := s.setCertificateVerification(config, payload)
+	if err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	if err := setClientCertificate(config, payload); err != nil {
+		return s.handleBadRequest(ctx, err, payload)
 	}
+	resumption, err := s.setResumption(config, payload)
+	if err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	recorder := utls.NewHandshakeRecorder()
+	trace := utls.NewKeyScheduleTrace()
 	uconn := utls.UClient(conn, config, utls.HelloCustom)
+	uconn.SetHandshakeFailureTracking(true)
+	uconn.SetHandshakeRecorder(recorder)
+	uconn.SetKeyScheduleObserver(trace)
 	if err := uconn.ApplyPreset(spec); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 		// return ctx.JSON(500, fmt.Sprintf("ApplyPreset error: %v", err))
 	}
 
 	if err := uconn.SetClientRandom(clientRandom); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
 		// return ctx.JSON(500, fmt.Sprintf("SetClientRandom error: %v", err))
 	}
 
-	if err := uconn.Handshake(); err != nil {
-		return handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	if err := applyClientHelloFields(uconn, payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+
+	if err := uconn.HandshakeContext(ctx.Request().Context()); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", handshakeError(uconn.HandshakeFailure(), err)), payload)
 		// return ctx.JSON(500, fmt.Sprintf("uconn.Handshake() error: %v", err))
 	}
 
 	response := openapi.HandshakeResponse{
-		RawClientHello:           hex.EncodeToString(convertRecordbytes(utls.ClientHelloRaw)),
-		RawServerResponse:        hex.EncodeToString(serverResponse),
-		RawServerResponseDecoded: hex.EncodeToString(utls.FullRecordBytes),
+		RawClientHello:           hex.EncodeToString(convertRecordbytes(recorder.Message(utls.RecordSent, utls.HandshakeTypeClientHello))),
+		RawServerResponse:        hex.EncodeToString(recorder.RawRecords(utls.RecordReceived)),
+		RawServerResponseDecoded: hex.EncodeToString(decryptedServerFlight(recorder)),
+		KeySchedule:              newKeySchedule(trace),
+		Fingerprints:             newFingerprints(recorder, uconn.ConnectionState()),
+		CertificateVerification:  verifier.result(),
+	}
+	if flight, err := recorder.DecodeServerFlight(); err == nil {
+		response.ServerFlight = newServerFlight(flight)
+		response.ServerFlight.ChangeCipherSpec = receivedChangeCipherSpec(recorder)
+		if flight.Version == utls.VersionTLS12 {
+			response.ClientKeyExchange = newClientKeyExchange(recorder, flight.ServerHello.Hello.CipherSuite)
+		}
+		if flight.CertificateRequest != nil {
+			response.ClientCertificate = newClientCertificate(recorder, flight.Version)
+			response.ClientCertificateVerify = newClientCertificateVerify(recorder)
+		}
+	}
+
+	if resumption != nil {
+		if uconn.ConnectionState().Version == utls.VersionTLS13 && (resumption.save || resumption.handle != "") {
+			waitSessionTickets(uconn, conn)
+		}
+		if response.Resumption, err = s.newResumptionResult(resumption, ctx.RealIP(), uconn, recorder); err != nil {
+			if errors.Is(err, errTooManySavedSessions) || errors.Is(err, errTooManyClientSavedSessions) {
+				return savedSessionsFull(ctx, err)
+			}
+			return ctx.JSON(500, fmt.Sprintf("failed to decode session tickets: %v", err))
+		}
 	}
 
 	return ctx.JSON(200, response)
 }
 
 func createClientHelloSpec(payload openapi.TlsClientParameters) (*utls.ClientHelloSpec, error) {
-	keyShares := make([]utls.KeyShare, len(payload.KeyShares))
-	for i, v := range payload.KeyShares {
-		val, err := stringToUint16(v)
+	// プリセットが指定されている場合は、プリセットを元に指定された値だけ上書きする
+	if payload.Preset != nil {
+		spec, err := lookupPreset(*payload.Preset)
 		if err != nil {
-			return nil, fmt.Errorf("invalid key share: %s", v)
+			return nil, err
+		}
+		if err := applyPresetOverrides(spec, payload); err != nil {
+			return nil, err
 		}
-		keyShares[i] = utls.KeyShare{
-			Group: utls.CurveID(val),
+		if payload.HttpRequest != nil {
+			if err := validateHTTPRequestALPN(spec, payload.HttpRequest); err != nil {
+				return nil, err
+			}
 		}
+		return spec, nil
 	}
 
-	cipherSuites := make([]uint16, len(payload.CipherSuites))
-	for i, v := range payload.CipherSuites {
-		val, err := stringToUint16(v)
+	version, err := tlsVersion(payload)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := parseCodepoints(payload.CipherSuites, "cipher suite")
+	if err != nil {
+		return nil, err
+	}
+
+	// 拡張が指定されている場合は、指定された順にそのまま使う
+	if payload.Extensions != nil {
+		extensions, err := newTLSExtensions(*payload.Extensions, payload)
 		if err != nil {
-			return nil, fmt.Errorf("invalid cipher suite: %s", v)
+			return nil, err
+		}
+		spec := &utls.ClientHelloSpec{CipherSuites: cipherSuites, Extensions: extensions}
+		if payload.HttpRequest != nil {
+			if err := validateHTTPRequestALPN(spec, payload.HttpRequest); err != nil {
+				return nil, err
+			}
 		}
-		cipherSuites[i] = val
+		return spec, nil
 	}
 
-	supportedCurves := make([]utls.CurveID, len(payload.SupportedGroups))
-	for i, v := range payload.SupportedGroups {
-		val, err := stringToUint16(v)
+	names := []string{"server_name", "supported_groups", "key_share", "signature_algorithms"}
+	if version == utls.VersionTLS12 {
+		// TLS 1.2 では key_share と supported_versions を送らず、
+		// ECDHE の点の形式と master_secret の導出方法を指定する
+		names = []string{"server_name", "supported_groups", "ec_point_formats", "signature_algorithms",
+			"extended_master_secret", "renegotiation_info"}
+	}
+	var extensions []utls.TLSExtension
+	for _, name := range names {
+		ext, err := newTLSExtension(openapi.ClientHelloExtension{Name: name}, payload)
 		if err != nil {
-			return nil, fmt.Errorf("invalid supported group: %s", v)
+			return nil, err
 		}
-		supportedCurves[i] = utls.CurveID(val)
+		extensions = append(extensions, ext)
 	}
-
-	supportedSignatureAlgorithms := make([]utls.SignatureScheme, len(payload.SignatureAlgorithms))
-	for i, v := range payload.SignatureAlgorithms {
-		val, err := stringToUint16(v)
+	if payload.HttpRequest != nil {
+		// HTTPリクエストを送る場合は、ALPNでHTTPのバージョンを合意する
+		extensions = append(extensions, &utls.ALPNExtension{AlpnProtocols: alpnProtocols(payload.HttpRequest)})
+	}
+	if payload.Resumption != nil {
+		// セッションを再開する場合は、チケットを受け取るための拡張を追加する
+		name := "session_ticket"
+		if version == utls.VersionTLS13 {
+			name = "psk_key_exchange_modes"
+		}
+		ext, err := newTLSExtension(openapi.ClientHelloExtension{Name: name}, payload)
 		if err != nil {
-			return nil, fmt.Errorf("invalid signature algorithm: %s", v)
+			return nil, err
+		}
+		extensions = append(extensions, ext)
+	}
+	if version == utls.VersionTLS13 {
+		extensions = append(extensions, &utls.SupportedVersionsExtension{Versions: []uint16{utls.VersionTLS13}})
+		if payload.Resumption != nil && payload.Resumption.SessionHandle != nil {
+			// pre_shared_key は最後の拡張でなければならない
+			extensions = append(extensions, &utls.UtlsPreSharedKeyExtension{})
 		}
-		supportedSignatureAlgorithms[i] = utls.SignatureScheme(val)
 	}
 
 	spec := &utls.ClientHelloSpec{
 		CipherSuites: cipherSuites,
-		Extensions: []utls.TLSExtension{
-			&utls.SNIExtension{ServerName: payload.ServerName},
-			&utls.SupportedCurvesExtension{
-				Curves: supportedCurves,
-			},
-			&utls.KeyShareExtension{KeyShares: keyShares},
-			&utls.SignatureAlgorithmsExtension{
-				SupportedSignatureAlgorithms: supportedSignatureAlgorithms,
-			},
-			&utls.SupportedVersionsExtension{Versions: []uint16{utls.VersionTLS13}},
-		},
+		Extensions:   extensions,
 	}
 	return spec, nil
 }
 
+// tlsVersion は、protocol_version で指定されたTLSバージョンを返します。
+// 省略した場合はTLS 1.3になります。
+func tlsVersion(payload openapi.TlsClientParameters) (uint16, error) {
+	if payload.ProtocolVersion == "" {
+		return utls.VersionTLS13, nil
+	}
+	version, err := stringToUint16(payload.ProtocolVersion)
+	if err != nil || (version != utls.VersionTLS13 && version != utls.VersionTLS12) {
+		return 0, fmt.Errorf("unsupported protocol_version: %s", payload.ProtocolVersion)
+	}
+	return version, nil
+}
+
+/**
+ * tlsVersionRange は、ClientHello で提示する TLS バージョンの範囲を返す。
+ * preset を指定した場合は protocol_version ではなく、uTLS の UConn.SetTLSVers と同じく
+ * spec の TLSVersMin/TLSVersMax、それがなければ supported_versions 拡張から求める。
+ * supported_versions 拡張もなければ TLS 1.0 から 1.2 になる。
+ */
+func tlsVersionRange(payload openapi.TlsClientParameters, spec *utls.ClientHelloSpec) (uint16, uint16, error) {
+	version, err := tlsVersion(payload)
+	if err != nil {
+		return 0, 0, err
+	}
+	if payload.Preset == nil {
+		return version, version, nil
+	}
+	if spec.TLSVersMin != 0 || spec.TLSVersMax != 0 {
+		return spec.TLSVersMin, spec.TLSVersMax, nil
+	}
+	minVersion, maxVersion := uint16(utls.VersionTLS10), uint16(utls.VersionTLS12)
+	for _, ext := range spec.Extensions {
+		sv, ok := ext.(*utls.SupportedVersionsExtension)
+		if !ok {
+			continue
+		}
+		minVersion, maxVersion = 0, 0
+		for _, v := range sv.Versions {
+			if v < utls.VersionTLS10 || v > utls.VersionTLS13 {
+				continue // GREASE
+			}
+			if minVersion == 0 || v < minVersion {
+				minVersion = v
+			}
+			maxVersion = max(maxVersion, v)
+		}
+		if minVersion == 0 {
+			return 0, 0, errors.New("supported_versions does not contain TLS 1.0 to 1.3")
+		}
+	}
+	return minVersion, maxVersion, nil
+}
+
 /**
  * 0xから始まる16進数文字列をuint16に変換する
  * 例: "0x1301" -> 4865
@@ -157,7 +334,31 @@ func stringToUint16(s string) (uint16, error) {
 	return result, nil
 }
 
-// ClientHelloRawをRecord Layerの形式に変換する
+// decryptedServerFlight は、暗号化されて届いたサーバーのハンドシェイクメッセージ
+// (EncryptedExtensionsからFinishedまで) を連結し、Record Layerの形式で返す
+func decryptedServerFlight(recorder *utls.HandshakeRecorder) []byte {
+	var flight []byte
+	for _, m := range recorder.Messages() {
+		if m.Direction != utls.RecordReceived || !m.Encrypted {
+			continue
+		}
+		flight = append(flight, m.Raw...)
+		if m.Type == utls.HandshakeTypeFinished {
+			break
+		}
+	}
+
+	record := make([]byte, 5+len(flight))
+	record[0] = 0x16                     // Content Type: Handshake
+	record[1] = 0x03                     // Version: TLS 1.2
+	record[2] = 0x03                     // Version: TLS 1.2
+	record[3] = byte(len(flight) >> 8)   // Length (high byte)
+	record[4] = byte(len(flight) & 0xff) // Length (low byte)
+	copy(record[5:], flight)
+	return record
+}
+
+// ClientHelloをRecord Layerの形式に変換する
 func convertRecordbytes(b []byte) []byte {
 	record := make([]byte, 5+len(b))
 	record[0] = 0x16                // Content Type: Handshake
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
+)
+
+// newTestServer は、ローカルテストサーバーとそれを信頼するAPIサーバーを起動します。
+func newTestServer(t *testing.T) (*echo.Echo, *testserver.Server) {
+	t.Helper()
+	ts, err := testserver.Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("testserver.Start() error = %v", err)
+	}
+	t.Cleanup(func() { ts.Close() })
+
+	e := echo.New()
+	validator, err := NewRequestValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Use(validator)
+	openapi.RegisterHandlers(e, Server{
+		TestServer: ts,
+		Sessions:   NewSessionStore(DefaultSessionTTL),
+		Tickets:    NewTicketStore(DefaultTicketTTL),
+	})
+	return e, ts
+}
+
+// testServerParameters は、テストサーバーに接続するための最小限のパラメータを返します。
+func testServerParameters(ts *testserver.Server) openapi.TlsClientParameters {
+	address := "127.0.0.1"
+	port := ts.Addr().Port
+	return openapi.TlsClientParameters{
+		ProtocolVersion:     "0x0304",
+		ServerName:          testserver.ServerName,
+		Address:             &address,
+		Port:                &port,
+		ClientRandom:        strings.Repeat("ab", 32),
+		CipherSuites:        []string{"0x1301", "0x1302", "0x1303"},
+		SupportedGroups:     []string{"0x001d", "0x0017"},
+		KeyShares:           []string{"0x001d"},
+		SignatureAlgorithms: []string{"0x0403", "0x0804", "0x0401"},
+	}
+}
+
+// doJSON は、APIサーバーにリクエストを送り、レスポンスをdestにデコードします。
+func doJSON(t *testing.T, e *echo.Echo, method, path string, body any, dest any) int {
+	t.Helper()
+	var reqBody strings.Builder
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(reqBody.String()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if dest != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), dest); err != nil {
+			t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestPostTlsHandshake(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	t.Run("正常系：テストサーバーとハンドシェイクできる", func(t *testing.T) {
+		var res openapi.HandshakeResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", testServerParameters(ts), &res)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		if !strings.HasPrefix(res.RawClientHello, "1603010") {
+			t.Errorf("raw_client_hello is not a handshake record: %s", res.RawClientHello)
+		}
+		if res.ServerFlight == nil || res.ServerFlight.ServerHello == nil || res.ServerFlight.Finished == nil {
+			t.Fatalf("server_flight is incomplete: %+v", res.ServerFlight)
+		}
+		if got := res.ServerFlight.ServerHello.CipherSuite; got != "0x1301" {
+			t.Errorf("cipher suite = %s, want 0x1301", got)
+		}
+		if res.KeySchedule == nil || len(*res.KeySchedule) == 0 {
+			t.Errorf("key_schedule is empty")
+		}
+	})
+
+	t.Run("正常系：TLS 1.2でハンドシェイクできる", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ProtocolVersion = "0x0303"
+		params.CipherSuites = []string{"0xc02b", "0xc02c"}
+		var res openapi.HandshakeResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		flight := res.ServerFlight
+		if flight == nil || flight.Version == nil || *flight.Version != "0x0303" {
+			t.Fatalf("server_flight does not report TLS 1.2: %+v", flight)
+		}
+		if flight.EncryptedExtensions != nil || flight.CertificateVerify != nil {
+			t.Errorf("unexpected TLS 1.3 messages")
+		}
+		if flight.Certificate == nil || len(flight.Certificate.CertificateList) != 1 {
+			t.Errorf("certificate is missing")
+		}
+		skx := flight.ServerKeyExchange
+		if skx == nil || skx.NamedCurve == nil || *skx.NamedCurve != "0x001d" || skx.SignatureScheme == nil || skx.Signature == "" {
+			t.Fatalf("server_key_exchange is incomplete: %+v", skx)
+		}
+		if flight.ServerHelloDone == nil || flight.Finished == nil {
+			t.Errorf("server_hello_done or finished is missing")
+		}
+		if flight.ChangeCipherSpec == nil || !*flight.ChangeCipherSpec {
+			t.Errorf("change_cipher_spec was not reported")
+		}
+		ckx := res.ClientKeyExchange
+		if ckx == nil || ckx.KeyExchange != openapi.ClientKeyExchangeMessageKeyExchangeECDHE || ckx.PublicKey == nil {
+			t.Fatalf("client_key_exchange is incomplete: %+v", ckx)
+		}
+		var names []string
+		for _, step := range *res.KeySchedule {
+			names = append(names, step.Name)
+		}
+		want := "pre_master_secret,master_secret,client_write_key,server_write_key,client_write_iv,server_write_iv"
+		if got := strings.Join(names, ","); got != want {
+			t.Errorf("key_schedule = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("正常系：ClientHello、ServerHello、証明書のフィンガープリントを返す", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.CipherSuites = []string{"0x0a0a", "0x1301", "0x1302"}
+		var res openapi.HandshakeResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		fp := res.Fingerprints
+		if fp == nil {
+			t.Fatal("fingerprints is missing")
+		}
+		if !strings.HasPrefix(fp.Ja3, "771,4865-4866,") {
+			t.Errorf("ja3 = %s, want GREASE removed from the cipher suites", fp.Ja3)
+		}
+		if hash := md5.Sum([]byte(fp.Ja3)); fp.Ja3Hash != hex.EncodeToString(hash[:]) {
+			t.Errorf("ja3_hash = %s, want the MD5 hash of ja3", fp.Ja3Hash)
+		}
+		if !strings.HasPrefix(fp.Ja4, "t13d02") || !strings.HasPrefix(fp.Ja4R, fp.Ja4[:10]+"_1301,1302_") {
+			t.Errorf("ja4 = %s, ja4_r = %s", fp.Ja4, fp.Ja4R)
+		}
+		if fp.Ja3s == nil || !strings.HasPrefix(*fp.Ja3s, "771,4865,") || fp.Ja4s == nil || !strings.HasPrefix(*fp.Ja4s, "t13") {
+			t.Errorf("ja3s = %v, ja4s = %v", fp.Ja3s, fp.Ja4s)
+		}
+		if fp.Ja4x == nil || len(*fp.Ja4x) != 1 || fp.Ja4xR == nil || len(*fp.Ja4xR) != 1 {
+			t.Errorf("ja4x = %v, want one fingerprint for the test server certificate", fp.Ja4x)
+		}
+	})
+
+	t.Run("異常系：対応していないTLSバージョン", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ProtocolVersion = "0x0302"
+		var res openapi.ErrorResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+		if code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if !strings.Contains(res.Message, "unsupported protocol_version") {
+			t.Errorf("message = %q", res.Message)
+		}
+	})
+
+	t.Run("異常系：不正なポート番号", func(t *testing.T) {
+		params := testServerParameters(ts)
+		port := 70000
+		params.Port = &port
+		var res openapi.ErrorResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+		if code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		// ポート番号の範囲は OpenAPI のスキーマで検証される
+		if res.Code != openapi.ErrorCodeSchemaViolation || res.Errors == nil || (*res.Errors)[0].Field != "/port" {
+			t.Errorf("code = %s, errors = %+v", res.Code, res.Errors)
+		}
+	})
+}
+
+func TestGetTlsTestServer(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	var res openapi.TestServerResponse
+	if code := doJSON(t, e, http.MethodGet, "/tls/test-server", nil, &res); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if !res.Running || res.Port == nil || *res.Port != ts.Addr().Port {
+		t.Errorf("unexpected response %+v", res)
+	}
+	if res.ServerName == nil || *res.ServerName != testserver.ServerName {
+		t.Errorf("server_name = %v, want %s", res.ServerName, testserver.ServerName)
+	}
+}
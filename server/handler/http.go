@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http2"
+)
+
+// alpnProtocols は、http_request を送信するときに ALPN で提示するプロトコルを返す
+func alpnProtocols(req *openapi.HttpRequest) []string {
+	if req.Protocol != nil {
+		return []string{string(*req.Protocol)}
+	}
+	return []string{string(openapi.HttpRequestProtocolH2), string(openapi.HttpRequestProtocolHttp11)}
+}
+
+/**
+ * validateHTTPRequestALPN は、spec の ALPN で http_request のプロトコルを合意できることを検証する。
+ * ALPN を送信しない場合は HTTP/1.1 を使用する。protocol を指定した場合は、
+ * サーバーが別のプロトコルを選ばないように、h2 と http/1.1 のうちそのプロトコルだけを提示していること。
+ */
+func validateHTTPRequestALPN(spec *utls.ClientHelloSpec, req *openapi.HttpRequest) error {
+	var alpn *utls.ALPNExtension
+	for _, ext := range spec.Extensions {
+		if e, ok := ext.(*utls.ALPNExtension); ok {
+			alpn = e
+		}
+	}
+	if alpn == nil {
+		if req.Protocol != nil && *req.Protocol != openapi.HttpRequestProtocolHttp11 {
+			return fmt.Errorf("http_request protocol %s requires the application_layer_protocol_negotiation extension", *req.Protocol)
+		}
+		return nil
+	}
+
+	var offered []string
+	for _, p := range alpn.AlpnProtocols {
+		if p == string(openapi.HttpRequestProtocolH2) || p == string(openapi.HttpRequestProtocolHttp11) {
+			offered = append(offered, p)
+		}
+	}
+	if len(offered) == 0 || (req.Protocol != nil && (len(offered) != 1 || offered[0] != string(*req.Protocol))) {
+		return fmt.Errorf("application_layer_protocol_negotiation offers %q, which cannot negotiate http_request protocol %s",
+			alpn.AlpnProtocols, strings.Join(alpnProtocols(req), " or "))
+	}
+	return nil
+}
+
+/**
+ * validateHTTPRequest は、http_request をリクエストラインとヘッダに書き込めることを検証する。
+ * CR や LF などの制御文字で別のヘッダやリクエストを挿入できないようにし、
+ * パスは接続先のサーバー上のパス (origin-form) に限る。
+ * @see https://datatracker.ietf.org/doc/html/rfc9112#section-3.2.1
+ */
+func validateHTTPRequest(serverName string, req *openapi.HttpRequest) error {
+	if req.Method != nil && !httpguts.ValidHeaderFieldName(*req.Method) {
+		return fmt.Errorf("http_request method is not a valid token: %q", *req.Method)
+	}
+	if req.Path != nil {
+		if _, err := requestPath(*req.Path); err != nil {
+			return err
+		}
+	}
+	var headers []openapi.HttpHeader
+	if req.Headers != nil {
+		headers = *req.Headers
+	}
+	for _, h := range headers {
+		if !httpguts.ValidHeaderFieldName(h.Name) {
+			return fmt.Errorf("http_request header name is not valid: %q", h.Name)
+		}
+		if !httpguts.ValidHeaderFieldValue(h.Value) {
+			return fmt.Errorf("http_request header %s has an invalid value: %q", h.Name, h.Value)
+		}
+	}
+	if !hasHeader(headers, "Host") && !httpguts.ValidHostHeader(serverName) {
+		return fmt.Errorf("server_name can not be used as the Host header: %q", serverName)
+	}
+	return nil
+}
+
+// requestPath は、origin-form のパスを解析する。
+// スキームやホストを含むもの、// で始まるもの、@ を含むものは受け付けない。
+func requestPath(path string) (*url.URL, error) {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.Contains(path, "@") {
+		return nil, fmt.Errorf("http_request path must be an absolute path such as /index.html: %q", path)
+	}
+	for _, c := range []byte(path) {
+		if c <= ' ' || c == 0x7f {
+			return nil, fmt.Errorf("http_request path contains a space or control character: %q", path)
+		}
+	}
+	u, err := url.ParseRequestURI(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http_request path: %w", err)
+	}
+	if u.Scheme != "" || u.Host != "" {
+		return nil, errors.New("http_request path must not contain a scheme or host")
+	}
+	return u, nil
+}
+
+// doHTTPRequest は、ハンドシェイク済みのuconnでHTTPリクエストを送信し、レスポンスを返す。
+// req は validateHTTPRequest で検証済みであること。
+// ALPN で h2 が合意された場合は HTTP/2、それ以外の場合は HTTP/1.1 を使用する。
+func doHTTPRequest(uconn *utls.UConn, serverName string, req *openapi.HttpRequest) (*openapi.HttpResponse, error) {
+	protocol := openapi.HttpRequestProtocolHttp11
+	if uconn.ConnectionState().NegotiatedProtocol == string(openapi.HttpRequestProtocolH2) {
+		protocol = openapi.HttpRequestProtocolH2
+	}
+	if req.Protocol != nil && *req.Protocol != protocol {
+		return nil, fmt.Errorf("protocol %s was requested but %s was negotiated via ALPN", *req.Protocol, protocol)
+	}
+
+	method := "GET"
+	if req.Method != nil {
+		method = *req.Method
+	}
+	path := "/"
+	if req.Path != nil {
+		path = *req.Path
+	}
+	var headers []openapi.HttpHeader
+	if req.Headers != nil {
+		headers = *req.Headers
+	}
+	var body string
+	if req.Body != nil {
+		body = *req.Body
+	}
+
+	var resp *http.Response
+	var err error
+	if protocol == openapi.HttpRequestProtocolH2 {
+		resp, err = roundTripHTTP2(uconn, serverName, method, path, headers, body)
+	} else {
+		resp, err = roundTripHTTP1(uconn, serverName, method, path, headers, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return &openapi.HttpResponse{
+		Protocol: string(protocol),
+		Status:   resp.StatusCode,
+		Headers:  httpHeaders(resp.Header),
+		Body:     string(respBody),
+	}, nil
+}
+
+/**
+ * roundTripHTTP1 は、HTTP/1.1 のリクエストを指定されたヘッダの順に書き込み、レスポンスを読み込む
+ * @see https://datatracker.ietf.org/doc/html/rfc9112#section-3
+ */
+func roundTripHTTP1(uconn *utls.UConn, serverName, method, path string, headers []openapi.HttpHeader, body string) (*http.Response, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s HTTP/1.1\r\n", method, path)
+	if !hasHeader(headers, "Host") {
+		fmt.Fprintf(&b, "Host: %s\r\n", serverName)
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", h.Name, h.Value)
+	}
+	if body != "" && !hasHeader(headers, "Content-Length") {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n", len(body))
+	}
+	if !hasHeader(headers, "Connection") {
+		b.WriteString("Connection: close\r\n")
+	}
+	b.WriteString("\r\n")
+	b.WriteString(body)
+
+	if _, err := uconn.Write([]byte(b.String())); err != nil {
+		return nil, fmt.Errorf("failed to write request: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(uconn), &http.Request{Method: method})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, nil
+}
+
+/**
+ * roundTripHTTP2 は、uconn上でHTTP/2のコネクションを開始してリクエストを1つ送信する
+ * @see https://datatracker.ietf.org/doc/html/rfc9113
+ */
+func roundTripHTTP2(uconn *utls.UConn, serverName, method, path string, headers []openapi.HttpHeader, body string) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+	target, err := requestPath(path)
+	if err != nil {
+		return nil, err
+	}
+	u := &url.URL{Scheme: "https", Host: serverName, Path: target.Path, RawPath: target.RawPath, RawQuery: target.RawQuery}
+	httpReq, err := http.NewRequest(method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http_request: %w", err)
+	}
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, "Host") {
+			httpReq.Host = h.Value
+			continue
+		}
+		httpReq.Header.Add(h.Name, h.Value)
+	}
+
+	cc, err := (&http2.Transport{}).NewClientConn(uconn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start HTTP/2: %w", err)
+	}
+	resp, err := cc.RoundTrip(httpReq)
+	if err != nil {
+		cc.Close()
+		return nil, fmt.Errorf("HTTP/2 request failed: %w", err)
+	}
+	// ボディを読み終えてからコネクションを閉じる
+	resp.Body = &closeConnBody{ReadCloser: resp.Body, cc: cc}
+	return resp, nil
+}
+
+type closeConnBody struct {
+	io.ReadCloser
+	cc *http2.ClientConn
+}
+
+func (b *closeConnBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cc.Close()
+	return err
+}
+
+func hasHeader(headers []openapi.HttpHeader, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpHeaders は、レスポンスヘッダを名前順に並べる
+func httpHeaders(header http.Header) []openapi.HttpHeader {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := []openapi.HttpHeader{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			res = append(res, openapi.HttpHeader{Name: name, Value: value})
+		}
+	}
+	return res
+}
+
+// receivedApplicationData は、受信したアプリケーションデータのレコードの平文を連結する
+func receivedApplicationData(records []utls.RecordedRecord) []byte {
+	var data []byte
+	for _, r := range records {
+		if r.Direction == utls.RecordReceived && r.ContentType == utls.RecordTypeApplicationData {
+			data = append(data, r.Plaintext...)
+		}
+	}
+	return data
+}
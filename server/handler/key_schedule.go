@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/hex"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// newKeySchedule は、鍵スケジュールのトレースをレスポンス用の構造体に変換する
+func newKeySchedule(trace *utls.KeyScheduleTrace) *[]openapi.KeyScheduleStep {
+	steps := trace.Steps()
+	if len(steps) == 0 {
+		return nil
+	}
+	res := newKeyScheduleSteps(steps)
+	return &res
+}
+
+// newKeyScheduleSteps は、鍵スケジュールの各ステップをレスポンス用の構造体に変換する
+func newKeyScheduleSteps(steps []utls.KeyScheduleStep) []openapi.KeyScheduleStep {
+	res := make([]openapi.KeyScheduleStep, len(steps))
+	for i, step := range steps {
+		res[i] = openapi.KeyScheduleStep{
+			Name:  step.Name,
+			Value: hex.EncodeToString(step.Value),
+		}
+		if step.TranscriptHash != nil {
+			transcriptHash := hex.EncodeToString(step.TranscriptHash)
+			res[i].TranscriptHash = &transcriptHash
+		}
+	}
+	return res
+}
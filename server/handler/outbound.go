@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/mytls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// dialTimeout は、接続先へのTCP接続を待つ最大の時間です。
+const dialTimeout = 5 * time.Second
+
+// OutboundConfig は、APIが接続先へ行う接続の制限です。
+type OutboundConfig struct {
+	// MaxConcurrentConnections は、同時に開いておける接続先への接続の数です。0 の場合は制限しません。
+	// 上限に達している場合は、リクエストの期限まで空きを待ちます。
+	MaxConcurrentConnections int
+
+	// Allow は、接続を許可する宛先です。IPアドレス、CIDR、ホスト名 ("*.example.com" で
+	// サブドメインを表します) を指定できます。空でない場合は、一致しない宛先には接続しません。
+	// 一致した宛先には、Deny と DenyPrivate に関係なく接続します。
+	Allow []string
+
+	// Deny は、接続を拒否する宛先です。書式は Allow と同じです。
+	Deny []string
+
+	// DenyPrivate を指定すると、ループバック、プライベート、リンクローカルなどの
+	// インターネットから到達できないアドレスへの接続を拒否します。
+	DenyPrivate bool
+
+	// Exempt は、Allow と Deny に関係なく常に接続を許可するアドレスです (ローカルテストサーバーなど)。
+	Exempt []netip.AddrPort
+}
+
+// Outbound は、接続先の許可・拒否と同時接続数の制限を行います。
+// nil の Outbound は、どの宛先にも制限なしで接続します。
+type Outbound struct {
+	allow       []destinationRule
+	deny        []destinationRule
+	denyPrivate bool
+	exempt      map[netip.AddrPort]bool
+	slots       chan struct{}
+	resolver    *net.Resolver
+}
+
+// NewOutbound は、configの制限を行う Outbound を返します。
+func NewOutbound(config OutboundConfig) (*Outbound, error) {
+	o := &Outbound{
+		denyPrivate: config.DenyPrivate,
+		exempt:      map[netip.AddrPort]bool{},
+		resolver:    net.DefaultResolver,
+	}
+	var err error
+	if o.allow, err = parseDestinationRules(config.Allow); err != nil {
+		return nil, fmt.Errorf("invalid allow list: %w", err)
+	}
+	if o.deny, err = parseDestinationRules(config.Deny); err != nil {
+		return nil, fmt.Errorf("invalid deny list: %w", err)
+	}
+	for _, addr := range config.Exempt {
+		o.exempt[netip.AddrPortFrom(addr.Addr().Unmap(), addr.Port())] = true
+	}
+	if config.MaxConcurrentConnections > 0 {
+		o.slots = make(chan struct{}, config.MaxConcurrentConnections)
+	}
+	return o, nil
+}
+
+// destinationRule は、Allow または Deny の1つの宛先です。
+type destinationRule struct {
+	prefix netip.Prefix
+	// host は、ホスト名で指定した場合の小文字のホスト名。"*." で始まる場合はサブドメインに一致する
+	host string
+}
+
+func parseDestinationRules(values []string) ([]destinationRule, error) {
+	rules := make([]destinationRule, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if prefix, err := netip.ParsePrefix(v); err == nil {
+			rules = append(rules, destinationRule{prefix: prefix.Masked()})
+			continue
+		}
+		if addr, err := netip.ParseAddr(v); err == nil {
+			addr = addr.Unmap()
+			rules = append(rules, destinationRule{prefix: netip.PrefixFrom(addr, addr.BitLen())})
+			continue
+		}
+		if v == "" || strings.ContainsAny(v, "/: ") {
+			return nil, fmt.Errorf("%q is not an IP address, CIDR or host name", v)
+		}
+		rules = append(rules, destinationRule{host: strings.ToLower(strings.TrimSuffix(v, "."))})
+	}
+	return rules, nil
+}
+
+func (r destinationRule) matchHost(host string) bool {
+	if r.host == "" {
+		return false
+	}
+	if suffix, ok := strings.CutPrefix(r.host, "*"); ok {
+		return strings.HasSuffix(host, suffix)
+	}
+	return host == r.host
+}
+
+func matchDestination(rules []destinationRule, host string, addr netip.Addr) bool {
+	for _, r := range rules {
+		if r.matchHost(host) || (r.prefix.IsValid() && r.prefix.Contains(addr)) {
+			return true
+		}
+	}
+	return false
+}
+
+// errDestinationNotAllowed は、接続先が Allow / Deny で許可されていないことを表します。
+var errDestinationNotAllowed = errors.New("destination is not allowed")
+
+// errTooManyConnections は、同時接続数の上限に達したまま期限になったことを表します。
+var errTooManyConnections = errors.New("too many concurrent connections")
+
+// allowed は、hostをaddrに解決した宛先に接続してよいかを返す
+func (o *Outbound) allowed(host string, addr netip.AddrPort) bool {
+	ip := addr.Addr().Unmap()
+	if o.exempt[netip.AddrPortFrom(ip, addr.Port())] {
+		return true
+	}
+	if matchDestination(o.allow, host, ip) {
+		return true
+	}
+	if len(o.allow) > 0 || matchDestination(o.deny, host, ip) {
+		return false
+	}
+	return !(o.denyPrivate && isPrivateAddr(ip))
+}
+
+// privatePrefixes は、IsPrivate などで判定できない、インターネットから到達できないアドレスの範囲
+var privatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"), // Shared Address Space (RFC 6598)
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF Protocol Assignments (RFC 6890)
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking (RFC 2544)
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved (RFC 1112)
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+func isPrivateAddr(ip netip.Addr) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, p := range privatePrefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve は、接続先のアドレスを解決し、すべてのアドレスに接続してよいかを確認する。
+// 一部のアドレスだけが許可されている場合も、DNSの応答によって宛先が変わらないように拒否する。
+func (o *Outbound) resolve(ctx context.Context, payload openapi.TlsClientParameters) ([]netip.AddrPort, error) {
+	host, port, err := mytls.DialTarget(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		resolver := net.DefaultResolver
+		if o != nil {
+			resolver = o.resolver
+		}
+		if addrs, err = resolver.LookupNetIP(ctx, "ip", host); err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	result := make([]netip.AddrPort, len(addrs))
+	for i, addr := range addrs {
+		result[i] = netip.AddrPortFrom(addr.Unmap(), uint16(port))
+		if o != nil && !o.allowed(name, result[i]) {
+			return nil, fmt.Errorf("%w: %s (%s)", errDestinationNotAllowed, host, addr)
+		}
+	}
+	return result, nil
+}
+
+// check は、payloadの接続先に接続してよいかを確認する
+func (o *Outbound) check(ctx context.Context, payload openapi.TlsClientParameters) error {
+	if o == nil {
+		return nil
+	}
+	_, err := o.resolve(ctx, payload)
+	return err
+}
+
+// dial は、許可されている場合にpayloadの接続先へTCPで接続する。
+// 同時接続数の上限に達している場合はctxの期限まで待ち、返した接続を閉じると枠が空く。
+func (o *Outbound) dial(ctx context.Context, payload openapi.TlsClientParameters) (net.Conn, error) {
+	addrs, err := o.resolve(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	release := func() {}
+	if o != nil && o.slots != nil {
+		select {
+		case o.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", errTooManyConnections, ctx.Err())
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-o.slots }) }
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	for _, addr := range addrs {
+		var conn net.Conn
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr.Addr().String(), strconv.Itoa(int(addr.Port()))))
+		if err == nil {
+			return &releasingConn{Conn: conn, release: release}, nil
+		}
+	}
+	release()
+	return nil, err
+}
+
+// dialer は、payloadの接続先へ dial で接続する mytls.DialFunc を返す
+func (o *Outbound) dialer(payload openapi.TlsClientParameters) mytls.DialFunc {
+	return func(ctx context.Context) (net.Conn, error) {
+		return o.dial(ctx, payload)
+	}
+}
+
+// releasingConn は、閉じたときに同時接続数の枠を空ける接続です。
+type releasingConn struct {
+	net.Conn
+	release func()
+}
+
+func (c *releasingConn) Close() error {
+	defer c.release()
+	return c.Conn.Close()
+}
+
+// dialFailed は、接続先に接続できなかった場合のレスポンスを返す
+func dialFailed(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errDestinationNotAllowed):
+		return ctx.JSON(http.StatusForbidden, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeDestinationNotAllowed,
+			Message: err.Error(),
+		})
+	case errors.Is(err, errTooManyConnections):
+		return ctx.JSON(http.StatusServiceUnavailable, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeTooManyConnections,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(500, fmt.Sprintf("net.Dial error: %v", err))
+}
+
+// setDeadline は、リクエストの期限を接続の読み書きの期限にする
+func setDeadline(conn net.Conn, ctx context.Context) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+}
+
+// isTimeout は、リクエストの期限や接続の読み書きの期限によってerrが発生したかを返す
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
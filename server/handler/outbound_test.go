@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/mytls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestOutboundAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		config OutboundConfig
+		host   string
+		addr   string
+		want   bool
+	}{
+		{
+			name: "正常系：制限しない場合はプライベートアドレスにも接続できる",
+			host: "localhost", addr: "127.0.0.1:443",
+			want: true,
+		},
+		{
+			name:   "異常系：DenyPrivate ではループバックアドレスに接続できない",
+			config: OutboundConfig{DenyPrivate: true},
+			host:   "localhost", addr: "[::1]:443",
+			want: false,
+		},
+		{
+			name:   "異常系：DenyPrivate では Shared Address Space に接続できない",
+			config: OutboundConfig{DenyPrivate: true},
+			host:   "100.64.0.1", addr: "100.64.0.1:443",
+			want: false,
+		},
+		{
+			name:   "正常系：DenyPrivate でもグローバルアドレスには接続できる",
+			config: OutboundConfig{DenyPrivate: true},
+			host:   "example.com", addr: "93.184.215.14:443",
+			want: true,
+		},
+		{
+			name:   "正常系：Exempt のアドレスには DenyPrivate でも接続できる",
+			config: OutboundConfig{DenyPrivate: true, Exempt: []netip.AddrPort{netip.MustParseAddrPort("127.0.0.1:8443")}},
+			host:   "127.0.0.1", addr: "127.0.0.1:8443",
+			want: true,
+		},
+		{
+			name:   "異常系：Exempt はポート番号も一致する必要がある",
+			config: OutboundConfig{DenyPrivate: true, Exempt: []netip.AddrPort{netip.MustParseAddrPort("127.0.0.1:8443")}},
+			host:   "127.0.0.1", addr: "127.0.0.1:22",
+			want: false,
+		},
+		{
+			name:   "異常系：Deny の CIDR に含まれる",
+			config: OutboundConfig{Deny: []string{"93.184.0.0/16"}},
+			host:   "example.com", addr: "93.184.215.14:443",
+			want: false,
+		},
+		{
+			name:   "異常系：Deny のホスト名のサブドメイン",
+			config: OutboundConfig{Deny: []string{"*.example.com"}},
+			host:   "www.example.com", addr: "93.184.215.14:443",
+			want: false,
+		},
+		{
+			name:   "正常系：Allow のホスト名は Deny より優先される",
+			config: OutboundConfig{Allow: []string{"example.com"}, Deny: []string{"93.184.0.0/16"}},
+			host:   "example.com", addr: "93.184.215.14:443",
+			want: true,
+		},
+		{
+			name:   "異常系：Allow を指定した場合は一致しない宛先に接続できない",
+			config: OutboundConfig{Allow: []string{"example.com"}},
+			host:   "example.org", addr: "93.184.215.14:443",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := NewOutbound(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := o.allowed(tt.host, netip.MustParseAddrPort(tt.addr)); got != tt.want {
+				t.Errorf("allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("異常系：不正な宛先", func(t *testing.T) {
+		if _, err := NewOutbound(OutboundConfig{Deny: []string{"10.0.0.0/33"}}); err == nil {
+			t.Error("NewOutbound() succeeded")
+		}
+	})
+}
+
+func TestOutboundDial(t *testing.T) {
+	_, ts := newTestServer(t)
+	params := testServerParameters(ts)
+	o, err := NewOutbound(OutboundConfig{MaxConcurrentConnections: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := o.dial(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := o.dial(ctx, params); !errors.Is(err, errTooManyConnections) {
+		t.Fatalf("dial() over the limit = %v, want %v", err, errTooManyConnections)
+	}
+
+	// 接続を閉じると枠が空く。2回閉じても枠は1つだけ空く
+	conn.Close()
+	conn.Close()
+	conn, err = o.dial(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if len(o.slots) != 1 {
+		t.Errorf("used slots = %d, want 1", len(o.slots))
+	}
+}
+
+func TestOutboundDialerMytls(t *testing.T) {
+	_, ts := newTestServer(t)
+	params := testServerParameters(ts)
+	o, err := NewOutbound(OutboundConfig{MaxConcurrentConnections: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mytls も同時接続数の枠を使う
+	conn, err := o.dial(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := mytls.PerformHandshake(ctx, o.dialer(params), params); !errors.Is(err, errTooManyConnections) {
+		t.Fatalf("PerformHandshake() over the limit = %v, want %v", err, errTooManyConnections)
+	}
+	conn.Close()
+	if _, err := mytls.PerformHandshake(context.Background(), o.dialer(params), params); err != nil {
+		t.Fatal(err)
+	}
+	if len(o.slots) != 0 {
+		t.Errorf("used slots = %d, want 0", len(o.slots))
+	}
+}
+
+func TestPostTlsHandshakeOutbound(t *testing.T) {
+	_, ts := newTestServer(t)
+	newServer := func(config OutboundConfig) *echo.Echo {
+		o, err := NewOutbound(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := echo.New()
+		openapi.RegisterHandlers(e, Server{TestServer: ts, Sessions: NewSessionStore(DefaultSessionTTL), Outbound: o})
+		return e
+	}
+
+	t.Run("異常系：許可されていない宛先には接続しない", func(t *testing.T) {
+		e := newServer(OutboundConfig{DenyPrivate: true})
+		for _, path := range []string{"/tls/handshake", "/tls/application", "/tls/sessions", "/tls/compare"} {
+			var res openapi.ErrorResponse
+			if code := doJSON(t, e, http.MethodPost, path, testServerParameters(ts), &res); code != http.StatusForbidden {
+				t.Fatalf("%s: status = %d, want %d", path, code, http.StatusForbidden)
+			}
+			if res.Code != openapi.ErrorCodeDestinationNotAllowed {
+				t.Errorf("%s: code = %s", path, res.Code)
+			}
+		}
+	})
+
+	t.Run("異常系：許可されていない宛先には mytls でも接続しない", func(t *testing.T) {
+		e := newServer(OutboundConfig{DenyPrivate: true})
+		params := testServerParameters(ts)
+		params.ProtocolVersion = "0x0302"
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if res.RawClientHello != "" || res.MytlsError != nil {
+			t.Errorf("mytls dialed a denied destination: %+v", res)
+		}
+	})
+
+	t.Run("正常系：テストサーバーは DenyPrivate でも接続できる", func(t *testing.T) {
+		e := newServer(OutboundConfig{DenyPrivate: true, Exempt: []netip.AddrPort{ts.Addr().AddrPort()}})
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", testServerParameters(ts), nil); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// builtinPresets は、GET /tls/presets で公開するuTLS組み込みのClientHelloIDです。
+// HelloChrome_Auto などの別名は、実体のIDと重複するため含めません。
+var builtinPresets = []utls.ClientHelloID{
+	utls.HelloChrome_58,
+	utls.HelloChrome_62,
+	utls.HelloChrome_70,
+	utls.HelloChrome_72,
+	utls.HelloChrome_83,
+	utls.HelloChrome_87,
+	utls.HelloChrome_96,
+	utls.HelloChrome_100,
+	utls.HelloChrome_102,
+	utls.HelloChrome_106_Shuffle,
+	utls.HelloChrome_100_PSK,
+	utls.HelloChrome_112_PSK_Shuf,
+	utls.HelloChrome_114_Padding_PSK_Shuf,
+	utls.HelloChrome_115_PQ,
+	utls.HelloChrome_115_PQ_PSK,
+	utls.HelloChrome_120,
+	utls.HelloChrome_120_PQ,
+	utls.HelloChrome_131,
+	utls.HelloChrome_133,
+	utls.HelloFirefox_55,
+	utls.HelloFirefox_56,
+	utls.HelloFirefox_63,
+	utls.HelloFirefox_65,
+	utls.HelloFirefox_99,
+	utls.HelloFirefox_102,
+	utls.HelloFirefox_105,
+	utls.HelloFirefox_120,
+	utls.HelloIOS_11_1,
+	utls.HelloIOS_12_1,
+	utls.HelloIOS_13,
+	utls.HelloIOS_14,
+	utls.HelloAndroid_11_OkHttp,
+	utls.HelloEdge_85,
+	utls.HelloEdge_106,
+	utls.HelloSafari_16_0,
+	utls.Hello360_7_5,
+	utls.Hello360_11_0,
+	utls.HelloQQ_11_1,
+}
+
+// presets は、公開するプリセットのClientHelloIDを返します。
+// 組み込みのプリセットの後に、実行時に読み込んだClientHello定義のIDを登録順に続けます。
+func presets() []utls.ClientHelloID {
+	ids := slices.Clone(builtinPresets)
+	for _, id := range utls.ClientHelloDefinitionIDs() {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s Server) GetTlsPresets(ctx echo.Context) error {
+	ids := presets()
+	response := openapi.PresetsResponse{Presets: make([]openapi.Preset, 0, len(ids))}
+	for _, id := range ids {
+		spec, err := utls.UTLSIdToSpec(id)
+		if err != nil {
+			return ctx.JSON(http.StatusInternalServerError, openapi.ErrorResponse{
+				Code:    openapi.ErrorCodeInternalError,
+				Message: fmt.Sprintf("failed to load preset %s: %v", id.Str(), err),
+			})
+		}
+		response.Presets = append(response.Presets, newPreset(id, spec))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// lookupPreset は、名前 (例: "Chrome-133") に対応するプリセットのClientHelloSpecを返します。
+func lookupPreset(name string) (*utls.ClientHelloSpec, error) {
+	for _, id := range presets() {
+		if id.Str() != name {
+			continue
+		}
+		spec, err := utls.UTLSIdToSpec(id)
+		if err != nil {
+			return nil, err
+		}
+		return &spec, nil
+	}
+	return nil, fmt.Errorf("unknown preset: %s", name)
+}
+
+// newPreset は、ClientHelloSpecをTlsClientParametersと同じ表現に変換します。
+func newPreset(id utls.ClientHelloID, spec utls.ClientHelloSpec) openapi.Preset {
+	preset := openapi.Preset{
+		Name:               id.Str(),
+		Client:             id.Client,
+		Version:            id.Version,
+		CipherSuites:       codepointStrings(spec.CipherSuites),
+		CompressionMethods: make([]string, len(spec.CompressionMethods)),
+		Extensions:         make([]openapi.ClientHelloExtension, len(spec.Extensions)),
+	}
+	if spec.TLSVersMin != 0 {
+		v := uint16ToString(spec.TLSVersMin)
+		preset.TlsVersionMin = &v
+	}
+	if spec.TLSVersMax != 0 {
+		v := uint16ToString(spec.TLSVersMax)
+		preset.TlsVersionMax = &v
+	}
+	for i, m := range spec.CompressionMethods {
+		preset.CompressionMethods[i] = fmt.Sprintf("0x%02x", m)
+	}
+	for i, ext := range spec.Extensions {
+		preset.Extensions[i] = newClientHelloExtension(ext)
+	}
+	return preset
+}
+
+// newClientHelloExtension は、newTLSExtension の逆の変換を行います。
+// 対応していない拡張は raw として種類と中身をそのまま返します。
+func newClientHelloExtension(ext utls.TLSExtension) openapi.ClientHelloExtension {
+	switch e := ext.(type) {
+	case *utls.UtlsGREASEExtension:
+		return openapi.ClientHelloExtension{Name: extensionNameGREASE, Data: optionalHexString(e.Body)}
+	case *utls.SNIExtension:
+		r := openapi.ClientHelloExtension{Name: "server_name"}
+		if e.ServerName != "" {
+			r.ServerName = &e.ServerName
+		}
+		return r
+	case *utls.StatusRequestExtension:
+		return openapi.ClientHelloExtension{Name: "status_request"}
+	case *utls.StatusRequestV2Extension:
+		return openapi.ClientHelloExtension{Name: "status_request_v2"}
+	case *utls.SupportedCurvesExtension:
+		groups := make([]uint16, len(e.Curves))
+		for i, c := range e.Curves {
+			groups[i] = uint16(c)
+		}
+		return openapi.ClientHelloExtension{Name: "supported_groups", Groups: ptr(codepointStrings(groups))}
+	case *utls.KeyShareExtension:
+		groups := make([]uint16, len(e.KeyShares))
+		for i, ks := range e.KeyShares {
+			groups[i] = uint16(ks.Group)
+		}
+		return openapi.ClientHelloExtension{Name: "key_share", Groups: ptr(codepointStrings(groups))}
+	case *utls.SupportedPointsExtension:
+		return openapi.ClientHelloExtension{Name: "ec_point_formats", PointFormats: ptr(uint8Strings(e.SupportedPoints))}
+	case *utls.SignatureAlgorithmsExtension:
+		return openapi.ClientHelloExtension{Name: "signature_algorithms", SignatureAlgorithms: ptr(signatureSchemeStrings(e.SupportedSignatureAlgorithms))}
+	case *utls.SignatureAlgorithmsCertExtension:
+		return openapi.ClientHelloExtension{Name: "signature_algorithms_cert", SignatureAlgorithms: ptr(signatureSchemeStrings(e.SupportedSignatureAlgorithms))}
+	case *utls.FakeDelegatedCredentialsExtension:
+		return openapi.ClientHelloExtension{Name: "delegated_credentials", SignatureAlgorithms: ptr(signatureSchemeStrings(e.SupportedSignatureAlgorithms))}
+	case *utls.ALPNExtension:
+		return openapi.ClientHelloExtension{Name: "application_layer_protocol_negotiation", Protocols: ptr(e.AlpnProtocols)}
+	case *utls.ApplicationSettingsExtension:
+		return openapi.ClientHelloExtension{Name: "application_settings", Protocols: ptr(e.SupportedProtocols)}
+	case *utls.ApplicationSettingsExtensionNew:
+		return openapi.ClientHelloExtension{Name: "application_settings_new", Protocols: ptr(e.SupportedProtocols)}
+	case *utls.NPNExtension:
+		return openapi.ClientHelloExtension{Name: "next_protocol_negotiation", Protocols: ptr(e.NextProtos)}
+	case *utls.SCTExtension:
+		return openapi.ClientHelloExtension{Name: "signed_certificate_timestamp"}
+	case *utls.UtlsPaddingExtension:
+		r := openapi.ClientHelloExtension{Name: "padding"}
+		// GetPaddingLenがある場合は、ClientHelloの長さから決まる
+		if e.GetPaddingLen == nil && e.WillPad {
+			r.PaddingLength = &e.PaddingLen
+		}
+		return r
+	case *utls.ExtendedMasterSecretExtension:
+		return openapi.ClientHelloExtension{Name: "extended_master_secret"}
+	case *utls.FakeTokenBindingExtension:
+		version := uint16(e.MajorVersion)<<8 | uint16(e.MinorVersion)
+		return openapi.ClientHelloExtension{
+			Name:       "token_binding",
+			Versions:   &[]string{uint16ToString(version)},
+			Algorithms: ptr(uint8Strings(e.KeyParameters)),
+		}
+	case *utls.UtlsCompressCertExtension:
+		algorithms := make([]uint16, len(e.Algorithms))
+		for i, a := range e.Algorithms {
+			algorithms[i] = uint16(a)
+		}
+		return openapi.ClientHelloExtension{Name: "compress_certificate", Algorithms: ptr(codepointStrings(algorithms))}
+	case *utls.FakeRecordSizeLimitExtension:
+		limit := int(e.Limit)
+		return openapi.ClientHelloExtension{Name: "record_size_limit", RecordSizeLimit: &limit}
+	case *utls.SessionTicketExtension:
+		return openapi.ClientHelloExtension{Name: "session_ticket", Data: optionalHexString(e.Ticket)}
+	case *utls.SupportedVersionsExtension:
+		return openapi.ClientHelloExtension{Name: "supported_versions", Versions: ptr(codepointStrings(e.Versions))}
+	case *utls.CookieExtension:
+		return openapi.ClientHelloExtension{Name: "cookie", Data: optionalHexString(e.Cookie)}
+	case *utls.PSKKeyExchangeModesExtension:
+		return openapi.ClientHelloExtension{Name: "psk_key_exchange_modes", Modes: ptr(uint8Strings(e.Modes))}
+	case *utls.QUICTransportParametersExtension:
+		return openapi.ClientHelloExtension{Name: "quic_transport_parameters", Data: optionalHexString(e.TransportParameters.Marshal())}
+	case *utls.RenegotiationInfoExtension:
+		renegotiation := openapi.ClientHelloExtensionRenegotiationOnce
+		switch e.Renegotiation {
+		case utls.RenegotiateNever:
+			renegotiation = openapi.ClientHelloExtensionRenegotiationNever
+		case utls.RenegotiateFreelyAsClient:
+			renegotiation = openapi.ClientHelloExtensionRenegotiationFreely
+		}
+		return openapi.ClientHelloExtension{Name: "renegotiation_info", Renegotiation: &renegotiation}
+	case *utls.FakeChannelIDExtension:
+		if e.OldExtensionID {
+			return openapi.ClientHelloExtension{Name: "channel_id_old"}
+		}
+		return openapi.ClientHelloExtension{Name: "channel_id"}
+	case *utls.GREASEEncryptedClientHelloExtension:
+		return openapi.ClientHelloExtension{Name: "encrypted_client_hello"}
+	case utls.PreSharedKeyExtension:
+		return openapi.ClientHelloExtension{Name: "pre_shared_key"}
+	case *utls.GenericExtension:
+		t := uint16ToString(e.Id)
+		return openapi.ClientHelloExtension{Name: extensionNameRaw, Type: &t, Data: optionalHexString(e.Data)}
+	default:
+		return rawClientHelloExtension(ext)
+	}
+}
+
+// rawClientHelloExtension は、拡張をバイト列にして raw として返します。
+func rawClientHelloExtension(ext utls.TLSExtension) openapi.ClientHelloExtension {
+	b := make([]byte, ext.Len())
+	if _, err := ext.Read(b); (err != nil && !errors.Is(err, io.EOF)) || len(b) < 4 {
+		return openapi.ClientHelloExtension{Name: extensionNameRaw}
+	}
+	t := uint16ToString(uint16(b[0])<<8 | uint16(b[1]))
+	return openapi.ClientHelloExtension{Name: extensionNameRaw, Type: &t, Data: optionalHexString(b[4:])}
+}
+
+// applyPresetOverrides は、プリセットの値をTlsClientParametersで指定された値で上書きします。
+// 空のリストは上書きしません。
+func applyPresetOverrides(spec *utls.ClientHelloSpec, payload openapi.TlsClientParameters) error {
+	if len(payload.CipherSuites) > 0 {
+		cipherSuites, err := parseCodepoints(payload.CipherSuites, "cipher suite")
+		if err != nil {
+			return err
+		}
+		spec.CipherSuites = cipherSuites
+	}
+	if payload.Extensions != nil {
+		extensions, err := newTLSExtensions(*payload.Extensions, payload)
+		if err != nil {
+			return err
+		}
+		spec.Extensions = extensions
+		return nil
+	}
+
+	overrides := map[string]bool{
+		"supported_groups":     len(payload.SupportedGroups) > 0,
+		"key_share":            len(payload.KeyShares) > 0,
+		"signature_algorithms": len(payload.SignatureAlgorithms) > 0,
+	}
+	for i, ext := range spec.Extensions {
+		if alpn, ok := ext.(*utls.ALPNExtension); ok && payload.HttpRequest != nil && payload.HttpRequest.Protocol != nil {
+			// http_request のプロトコルを指定した場合は、そのプロトコルだけを ALPN で提示する
+			alpn.AlpnProtocols = alpnProtocols(payload.HttpRequest)
+			continue
+		}
+		name := newClientHelloExtension(ext).Name
+		if !overrides[name] {
+			continue
+		}
+		e, err := newTLSExtension(openapi.ClientHelloExtension{Name: name}, payload)
+		if err != nil {
+			return err
+		}
+		spec.Extensions[i] = e
+	}
+	return nil
+}
+
+func codepointStrings(values []uint16) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		if v == utls.GREASE_PLACEHOLDER {
+			result[i] = extensionNameGREASE
+			continue
+		}
+		result[i] = uint16ToString(v)
+	}
+	return result
+}
+
+func uint8Strings(values []uint8) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = fmt.Sprintf("0x%02x", v)
+	}
+	return result
+}
+
+func signatureSchemeStrings(values []utls.SignatureScheme) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = uint16ToString(uint16(v))
+	}
+	return result
+}
+
+func optionalHexString(b []byte) *string {
+	if len(b) == 0 {
+		return nil
+	}
+	s := fmt.Sprintf("%x", b)
+	return &s
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestGetTlsPresets(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	var res openapi.PresetsResponse
+	if code := doJSON(t, e, http.MethodGet, "/tls/presets", nil, &res); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	if len(res.Presets) != len(presets()) {
+		t.Fatalf("len(presets) = %d, want %d", len(res.Presets), len(presets()))
+	}
+	for _, p := range res.Presets {
+		if len(p.CipherSuites) == 0 || len(p.Extensions) == 0 {
+			t.Errorf("%s: empty spec %+v", p.Name, p)
+		}
+		for _, ext := range p.Extensions {
+			if ext.Name == extensionNameRaw {
+				t.Errorf("%s: extension not decoded: %+v", p.Name, ext)
+			}
+		}
+	}
+}
+
+func TestPostTlsHandshakePreset(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		modify       func(*openapi.TlsClientParameters)
+		wantTLS12    bool
+		expectingErr bool
+	}{
+		{
+			name: "正常系：Chromeのプリセットでハンドシェイクできる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-133")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+			},
+		},
+		{
+			name: "正常系：Firefoxのプリセットでハンドシェイクできる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Firefox-120")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+			},
+		},
+		{
+			name: "正常系：プリセットのkey_shareを上書きできる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Safari-16.0")
+				p.CipherSuites, p.SupportedGroups, p.SignatureAlgorithms = nil, nil, nil
+				p.KeyShares = []string{"0x0017"}
+			},
+		},
+		{
+			name: "正常系：GET /tls/presets の拡張をextensionsに指定できる",
+			modify: func(p *openapi.TlsClientParameters) {
+				preset := findPreset(t, e, "Chrome-133")
+				p.CipherSuites = preset.CipherSuites
+				p.KeyShares = []string{"0x001d"}
+				p.Extensions = &preset.Extensions
+			},
+		},
+		{
+			name: "正常系：TLS 1.2までのプリセットはprotocol_versionが0x0304でもTLS 1.2でハンドシェイクする",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-58")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+			},
+			wantTLS12: true,
+		},
+		{
+			name: "正常系：TLS 1.2までのプリセットでセッションを保存できる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-58")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+				p.Resumption = &openapi.ResumptionParameters{SaveSession: ptr(true)}
+			},
+			wantTLS12: true,
+		},
+		{
+			name: "正常系：プリセットのsupported_versionsはprotocol_versionより優先される",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-133")
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+			},
+		},
+		{
+			name: "異常系：存在しないプリセット",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-1")
+			},
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			var res openapi.HandshakeResponse
+			code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res)
+			if (code != http.StatusOK) != tt.expectingErr {
+				t.Fatalf("status = %d, expectingErr %v", code, tt.expectingErr)
+			}
+			if tt.expectingErr {
+				return
+			}
+			if !strings.HasPrefix(res.RawClientHello, "16030") {
+				t.Errorf("raw_client_hello is not a handshake record: %s", res.RawClientHello)
+			}
+			// ClientKeyExchange は TLS 1.2 でのみ送信する
+			if tls12 := res.ClientKeyExchange != nil; tls12 != tt.wantTLS12 {
+				t.Errorf("negotiated TLS 1.2 = %v, want %v", tls12, tt.wantTLS12)
+			}
+			if tt.wantTLS12 && params.Resumption != nil && (res.Resumption == nil || res.Resumption.SessionHandle == nil) {
+				t.Errorf("session was not saved: %+v", res.Resumption)
+			}
+		})
+	}
+}
+
+func TestTLSVersionRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		preset       *string
+		version      string
+		wantMin      uint16
+		wantMax      uint16
+		expectingErr bool
+	}{
+		{
+			name:    "正常系：プリセットを指定しない場合はprotocol_versionのみ",
+			version: "0x0304",
+			wantMin: utls.VersionTLS13,
+			wantMax: utls.VersionTLS13,
+		},
+		{
+			name:    "正常系：TLS 1.2までのプリセットはprotocol_versionによらずTLS 1.2まで",
+			preset:  ptr("Chrome-58"),
+			version: "0x0304",
+			wantMin: utls.VersionTLS10,
+			wantMax: utls.VersionTLS12,
+		},
+		{
+			name:    "正常系：プリセットのsupported_versionsからGREASEを除いて求める",
+			preset:  ptr("Chrome-133"),
+			version: "0x0303",
+			wantMin: utls.VersionTLS12,
+			wantMax: utls.VersionTLS13,
+		},
+		{
+			name:         "異常系：対応していないprotocol_version",
+			preset:       ptr("Chrome-133"),
+			version:      "0x0301",
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := openapi.TlsClientParameters{ProtocolVersion: tt.version, ServerName: "example.com", Preset: tt.preset}
+			spec, err := createClientHelloSpec(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			minVersion, maxVersion, err := tlsVersionRange(payload, spec)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("err = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if minVersion != tt.wantMin || maxVersion != tt.wantMax {
+				t.Errorf("range = %#04x-%#04x, want %#04x-%#04x", minVersion, maxVersion, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestClientHelloDefinitionPreset(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	// 組み込みのChrome 120の定義を別の名前で読み込む
+	data, err := os.ReadFile("../../parrots/chrome_120.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), `"client": "Chrome"`, `"client": "HandlerTest"`, 1))
+	if _, err := utls.LoadClientHelloDefinitions(fstest.MapFS{"handler_test.json": {Data: data}}); err != nil {
+		t.Fatal(err)
+	}
+
+	preset := findPreset(t, e, "HandlerTest-120")
+	if want := findPreset(t, e, "Chrome-120"); len(preset.Extensions) != len(want.Extensions) || len(preset.CipherSuites) != len(want.CipherSuites) {
+		t.Errorf("preset = %+v, want the spec of %+v", preset, want)
+	}
+
+	params := testServerParameters(ts)
+	params.Preset = ptr("HandlerTest-120")
+	params.CipherSuites, params.SupportedGroups, params.KeyShares, params.SignatureAlgorithms = nil, nil, nil, nil
+	var res openapi.HandshakeResponse
+	if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+// findPreset は、GET /tls/presets から名前が一致するプリセットを取得します。
+func findPreset(t *testing.T, e *echo.Echo, name string) openapi.Preset {
+	t.Helper()
+	var res openapi.PresetsResponse
+	doJSON(t, e, http.MethodGet, "/tls/presets", nil, &res)
+	for _, p := range res.Presets {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("preset %s not found", name)
+	return openapi.Preset{}
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// DefaultTicketTTL は、保存したセッションチケットを最後に使ってから破棄するまでの既定の時間です。
+const DefaultTicketTTL = 30 * time.Minute
+
+// DefaultMaxSavedSessions と DefaultMaxSavedSessionsPerClient は、resumption.save_session で
+// 同時に保存できるセッションの数の既定の上限です。
+const (
+	DefaultMaxSavedSessions          = 1024
+	DefaultMaxSavedSessionsPerClient = 32
+)
+
+// errTooManySavedSessions は、サーバー全体で保存できるセッション数の上限に達していることを表します。
+var errTooManySavedSessions = errors.New("too many saved sessions")
+
+// errTooManyClientSavedSessions は、クライアントごとに保存できるセッション数の上限に達していることを表します。
+var errTooManyClientSavedSessions = errors.New("too many saved sessions from this client")
+
+// sessionTicketWait は、TLS 1.3 でハンドシェイク後に届く NewSessionTicket を待つ時間です。
+const sessionTicketWait = time.Second
+
+// errEarlyDataNotImplemented は、0-RTT の早期データの送信を指定された場合のエラーです。
+// ライブラリは TCP 上で早期データを送信できないため、部分的な結果ではなく 501 を返します。
+var errEarlyDataNotImplemented = errors.New("0-RTT early data is not implemented: the library can not send early data over TCP")
+
+// TicketStore は、resumption.save_session で保存したセッションチケットを、返したハンドルごとに保持します。
+// 期限切れのチケットは、次にストアを操作したときに破棄されます。
+type TicketStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	// MaxSessions は、同時に保存できるセッションの数です。0 の場合は制限しません。
+	MaxSessions int
+
+	// MaxSessionsPerClient は、クライアント (IPアドレス) ごとに同時に保存できるセッションの数です。
+	// 0 の場合は制限しません。
+	MaxSessionsPerClient int
+
+	mu      sync.Mutex
+	tickets map[string]*storedTickets
+}
+
+// NewTicketStore は、最後に使ってからttlが経過したチケットを破棄するストアを返します。
+// 保存できるセッション数の上限は既定値になります。
+func NewTicketStore(ttl time.Duration) *TicketStore {
+	return &TicketStore{
+		ttl:                  ttl,
+		now:                  time.Now,
+		MaxSessions:          DefaultMaxSavedSessions,
+		MaxSessionsPerClient: DefaultMaxSavedSessionsPerClient,
+		tickets:              map[string]*storedTickets{},
+	}
+}
+
+// storedTickets は、1つのハンドルで保存しているセッションです。
+// セッションは utls.ClientSessionCache に server_name ごとに保存されます。
+type storedTickets struct {
+	cache utls.ClientSessionCache
+	// client は、セッションを保存したクライアントのIPアドレス
+	client    string
+	expiresAt time.Time
+}
+
+// add は、clientが受け取ったセッションを保存します。セッション数が上限に達している場合は
+// errTooManySavedSessions または errTooManyClientSavedSessions を返します。
+func (st *TicketStore) add(client string, cache utls.ClientSessionCache) (string, error) {
+	handle, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweepLocked()
+	if st.MaxSessions > 0 && len(st.tickets) >= st.MaxSessions {
+		return "", errTooManySavedSessions
+	}
+	if st.MaxSessionsPerClient > 0 {
+		n := 0
+		for _, stored := range st.tickets {
+			if stored.client == client {
+				n++
+			}
+		}
+		if n >= st.MaxSessionsPerClient {
+			return "", errTooManyClientSavedSessions
+		}
+	}
+	st.tickets[handle] = &storedTickets{cache: cache, client: client, expiresAt: st.now().Add(st.ttl)}
+	return handle, nil
+}
+
+// get は、handleで保存したセッションを返し、有効期限を延長します。
+func (st *TicketStore) get(handle string) (utls.ClientSessionCache, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweepLocked()
+	stored, ok := st.tickets[handle]
+	if !ok {
+		return nil, false
+	}
+	stored.expiresAt = st.now().Add(st.ttl)
+	return stored.cache, true
+}
+
+func (st *TicketStore) sweepLocked() {
+	now := st.now()
+	for handle, stored := range st.tickets {
+		if now.After(stored.expiresAt) {
+			delete(st.tickets, handle)
+		}
+	}
+}
+
+// resumption は、1回のハンドシェイクで使うセッションキャッシュです。
+type resumption struct {
+	// handle は、リクエストで指定されたハンドル。新しく保存する場合は空
+	handle string
+	save   bool
+	cache  utls.ClientSessionCache
+}
+
+// setResumption は、resumption が指定されている場合に、保存したセッション
+// またはチケットを受け取るための空のキャッシュをconfigに設定する。
+func (s Server) setResumption(config *utls.Config, payload openapi.TlsClientParameters) (*resumption, error) {
+	if payload.Resumption == nil {
+		return nil, nil
+	}
+	if s.Tickets == nil {
+		return nil, errors.New("ticket store is not configured")
+	}
+	r := &resumption{save: payload.Resumption.SaveSession != nil && *payload.Resumption.SaveSession}
+	if handle := payload.Resumption.SessionHandle; handle != nil {
+		cache, ok := s.Tickets.get(*handle)
+		if !ok {
+			return nil, fmt.Errorf("invalid resumption: session_handle %q not found or expired", *handle)
+		}
+		r.handle = *handle
+		r.cache = cache
+	} else {
+		r.cache = utls.NewLRUClientSessionCache(0)
+	}
+	config.ClientSessionCache = r.cache
+	// 保存したセッションが server_name と一致しない場合は、PSKを送らずに通常のハンドシェイクを行う
+	config.OmitEmptyPsk = true
+	return r, nil
+}
+
+// waitSessionTickets は、TLS 1.3 でハンドシェイク後に送られる NewSessionTicket を
+// sessionTicketWait の間読み込む。受け取ったチケットはconfigのキャッシュに保存される。
+func waitSessionTickets(uconn *utls.UConn, conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(sessionTicketWait))
+	buf := make([]byte, 1024)
+	for {
+		// アプリケーションデータを受け取った場合は読み捨てる
+		if _, err := uconn.Read(buf); err != nil {
+			return
+		}
+	}
+}
+
+// newResumptionResult は、提示したPSKと受け取ったチケットを返し、
+// チケットを受け取った場合はclientのセッションとして保存する。
+func (s Server) newResumptionResult(r *resumption, client string, uconn *utls.UConn, recorder *utls.HandshakeRecorder) (*openapi.ResumptionResult, error) {
+	state := uconn.ConnectionState()
+	res := &openapi.ResumptionResult{
+		Resumed:           state.DidResume,
+		NewSessionTickets: []openapi.NewSessionTicketMessage{},
+	}
+	for _, m := range recorder.Messages() {
+		if m.Direction != utls.RecordReceived || m.Type != utls.HandshakeTypeNewSessionTicket {
+			continue
+		}
+		ticket, err := utls.DecodeNewSessionTicket(m.Raw, state.Version)
+		if err != nil {
+			return nil, err
+		}
+		res.NewSessionTickets = append(res.NewSessionTickets, newNewSessionTicketMessage(ticket, state.Version))
+	}
+
+	calc, err := uconn.PskBinderCalculation()
+	if err != nil {
+		return nil, err
+	}
+	if calc != nil {
+		res.Psk = &openapi.PreSharedKeyOffer{
+			Identity:            hex.EncodeToString(calc.Identity.Label),
+			ObfuscatedTicketAge: int64(calc.Identity.ObfuscatedTicketAge),
+			// TLS 1.3 ではPSKを受け入れた場合にのみセッションが再開される
+			Accepted: state.DidResume,
+			BinderCalculation: openapi.PskBinderCalculation{
+				EarlySecret:    hex.EncodeToString(calc.EarlySecret),
+				BinderKey:      hex.EncodeToString(calc.BinderKey),
+				FinishedKey:    hex.EncodeToString(calc.FinishedKey),
+				TranscriptHash: hex.EncodeToString(calc.TranscriptHash),
+				Binder:         hex.EncodeToString(calc.Binder),
+			},
+		}
+	}
+
+	if r.handle == "" && r.save && len(res.NewSessionTickets) > 0 {
+		if r.handle, err = s.Tickets.add(client, r.cache); err != nil {
+			return nil, err
+		}
+	}
+	if r.handle != "" {
+		res.SessionHandle = &r.handle
+	}
+	return res, nil
+}
+
+// savedSessionsFull は、セッションを保存できなかった場合のレスポンスを返す
+func savedSessionsFull(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errTooManyClientSavedSessions):
+		return ctx.JSON(http.StatusTooManyRequests, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeTooManySavedSessions,
+			Message: err.Error(),
+		})
+	case errors.Is(err, errTooManySavedSessions):
+		return ctx.JSON(http.StatusServiceUnavailable, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeTooManySavedSessions,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(500, fmt.Sprintf("failed to save the session: %v", err))
+}
+
+func newNewSessionTicketMessage(m *utls.DecodedNewSessionTicket, version uint16) openapi.NewSessionTicketMessage {
+	res := openapi.NewSessionTicketMessage{
+		Raw:            hex.EncodeToString(m.Raw),
+		TicketLifetime: int64(m.Lifetime),
+		Ticket:         hex.EncodeToString(m.Ticket),
+	}
+	if version != utls.VersionTLS13 {
+		return res
+	}
+	ageAdd := int64(m.AgeAdd)
+	nonce := hex.EncodeToString(m.Nonce)
+	extensions := newHandshakeExtensions(m.Extensions)
+	res.TicketAgeAdd = &ageAdd
+	res.TicketNonce = &nonce
+	res.Extensions = &extensions
+	if m.MaxEarlyData != 0 {
+		maxEarlyData := int64(m.MaxEarlyData)
+		res.MaxEarlyDataSize = &maxEarlyData
+	}
+	return res
+}
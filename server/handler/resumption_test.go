@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestPostTlsHandshakeResumption(t *testing.T) {
+	e, ts := newTestServer(t)
+	save := true
+
+	tests := []struct {
+		name    string
+		modify  func(*openapi.TlsClientParameters)
+		wantPsk bool
+	}{
+		{
+			name:    "正常系：TLS 1.3 では PSK でセッションを再開できる",
+			modify:  func(p *openapi.TlsClientParameters) {},
+			wantPsk: true,
+		},
+		{
+			name: "正常系：TLS 1.2 ではセッションチケットでセッションを再開できる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0xc02b"}
+			},
+			wantPsk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			params.Resumption = &openapi.ResumptionParameters{SaveSession: &save}
+
+			var first openapi.HandshakeResponse
+			if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &first); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			r := first.Resumption
+			if r == nil || r.SessionHandle == nil || len(r.NewSessionTickets) == 0 {
+				t.Fatalf("session was not saved: %+v", r)
+			}
+			if r.Resumed || r.Psk != nil {
+				t.Errorf("first handshake resumed: %+v", r)
+			}
+			// TLS 1.2 の ticket_lifetime_hint は 0 (指定なし) でもよい
+			if r.NewSessionTickets[0].Ticket == "" {
+				t.Errorf("incomplete NewSessionTicket: %+v", r.NewSessionTickets[0])
+			}
+
+			params.Resumption = &openapi.ResumptionParameters{SessionHandle: r.SessionHandle}
+			var second openapi.HandshakeResponse
+			if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &second); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			r2 := second.Resumption
+			if r2 == nil || !r2.Resumed {
+				t.Fatalf("second handshake did not resume: %+v", r2)
+			}
+			if r2.SessionHandle == nil || *r2.SessionHandle != *r.SessionHandle {
+				t.Errorf("session_handle = %v, want %s", r2.SessionHandle, *r.SessionHandle)
+			}
+			if second.ServerFlight == nil || second.ServerFlight.Certificate != nil {
+				t.Errorf("the server sent a certificate on resumption: %+v", second.ServerFlight)
+			}
+
+			if (r2.Psk != nil) != tt.wantPsk {
+				t.Fatalf("psk = %+v, want present: %v", r2.Psk, tt.wantPsk)
+			}
+			if psk := r2.Psk; psk != nil {
+				if !psk.Accepted {
+					t.Errorf("psk was not accepted")
+				}
+				if psk.Identity != r.NewSessionTickets[0].Ticket {
+					t.Errorf("identity = %s, want the saved ticket %s", psk.Identity, r.NewSessionTickets[0].Ticket)
+				}
+				if !strings.HasSuffix(second.RawClientHello, psk.BinderCalculation.Binder) {
+					t.Errorf("binder %s is not the one sent in the ClientHello", psk.BinderCalculation.Binder)
+				}
+				if step := keyScheduleStep(second.KeySchedule, "binder_key"); step == nil || step.Value != psk.BinderCalculation.BinderKey {
+					t.Errorf("binder_key = %+v, want %s", step, psk.BinderCalculation.BinderKey)
+				}
+			}
+		})
+	}
+
+	t.Run("異常系：存在しない session_handle", func(t *testing.T) {
+		params := testServerParameters(ts)
+		handle := "unknown"
+		params.Resumption = &openapi.ResumptionParameters{SessionHandle: &handle}
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if !strings.Contains(res.Message, "not found") {
+			t.Errorf("message = %q", res.Message)
+		}
+	})
+
+	t.Run("異常系：session_handle なしでは pre_shared_key を指定できない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.Extensions = &[]openapi.ClientHelloExtension{{Name: "pre_shared_key"}}
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, nil); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("異常系：0-RTT の早期データは実装されていない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.Resumption = &openapi.ResumptionParameters{SaveSession: &save, EarlyData: ptr("GET / HTTP/1.1\r\n\r\n")}
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusNotImplemented {
+			t.Fatalf("status = %d, want %d", code, http.StatusNotImplemented)
+		}
+		if res.Code != openapi.ErrorCodeNotImplemented {
+			t.Errorf("code = %s, want %s", res.Code, openapi.ErrorCodeNotImplemented)
+		}
+	})
+
+	t.Run("異常系：/tls/application では指定できない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.Resumption = &openapi.ResumptionParameters{SaveSession: &save}
+		if code := doJSON(t, e, http.MethodPost, "/tls/application", params, nil); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestTicketStore(t *testing.T) {
+	now := time.Now()
+	store := NewTicketStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	handle, err := store.add("192.0.2.1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(45 * time.Second)
+	if _, ok := store.get(handle); !ok {
+		t.Fatal("ticket expired too early")
+	}
+	// 使うと有効期限が延長される
+	now = now.Add(45 * time.Second)
+	if _, ok := store.get(handle); !ok {
+		t.Fatal("ticket expired although it was used")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.get(handle); ok {
+		t.Error("expired ticket was returned")
+	}
+}
+
+func TestTicketStoreLimits(t *testing.T) {
+	t.Run("異常系：クライアントごとに保存できるセッション数の上限", func(t *testing.T) {
+		store := NewTicketStore(time.Minute)
+		store.MaxSessionsPerClient = 3
+		for i := 0; i < 3; i++ {
+			if _, err := store.add("192.0.2.1", nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := store.add("192.0.2.1", nil); !errors.Is(err, errTooManyClientSavedSessions) {
+			t.Errorf("add() error = %v, want %v", err, errTooManyClientSavedSessions)
+		}
+		// 他のクライアントは保存できる
+		if _, err := store.add("192.0.2.2", nil); err != nil {
+			t.Errorf("add() from another client error = %v", err)
+		}
+	})
+
+	t.Run("異常系：サーバー全体で保存できるセッション数の上限", func(t *testing.T) {
+		now := time.Now()
+		store := NewTicketStore(time.Minute)
+		store.now = func() time.Time { return now }
+		store.MaxSessions = 3
+		store.MaxSessionsPerClient = 0
+		for i := 0; i < 3; i++ {
+			if _, err := store.add(fmt.Sprintf("192.0.2.%d", i), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := store.add("192.0.2.10", nil); !errors.Is(err, errTooManySavedSessions) {
+			t.Errorf("add() error = %v, want %v", err, errTooManySavedSessions)
+		}
+		// 期限切れのセッションが破棄されると再び保存できる
+		now = now.Add(2 * time.Minute)
+		if _, err := store.add("192.0.2.10", nil); err != nil {
+			t.Errorf("add() after expiry error = %v", err)
+		}
+	})
+
+	t.Run("異常系：上限に達している場合は too_many_saved_sessions", func(t *testing.T) {
+		_, ts := newTestServer(t)
+		store := NewTicketStore(time.Minute)
+		store.MaxSessionsPerClient = 1
+		e := echo.New()
+		openapi.RegisterHandlers(e, Server{TestServer: ts, Tickets: store})
+
+		save := true
+		params := testServerParameters(ts)
+		params.Resumption = &openapi.ResumptionParameters{SaveSession: &save}
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, nil); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want %d", code, http.StatusTooManyRequests)
+		}
+		if res.Code != openapi.ErrorCodeTooManySavedSessions {
+			t.Errorf("code = %s", res.Code)
+		}
+	})
+}
+
+func keyScheduleStep(steps *[]openapi.KeyScheduleStep, name string) *openapi.KeyScheduleStep {
+	if steps == nil {
+		return nil
+	}
+	for _, s := range *steps {
+		if s.Name == name {
+			return &s
+		}
+	}
+	return nil
+}
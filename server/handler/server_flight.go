@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/dicttls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// newServerFlight は、ライブラリが解析したサーバーのハンドシェイクメッセージを
+// レスポンス用の構造体に変換する
+func newServerFlight(flight *utls.DecodedServerFlight) *openapi.ServerFlight {
+	res := &openapi.ServerFlight{}
+	if flight.Version != 0 {
+		version := uint16ToString(flight.Version)
+		res.Version = &version
+	}
+	if flight.HelloRetryRequest != nil {
+		res.HelloRetryRequest = newServerHelloMessage(flight.HelloRetryRequest)
+	}
+	if flight.ServerHello != nil {
+		res.ServerHello = newServerHelloMessage(flight.ServerHello)
+	}
+	if m := flight.EncryptedExtensions; m != nil {
+		res.EncryptedExtensions = &openapi.EncryptedExtensionsMessage{
+			Raw:        hex.EncodeToString(m.Raw),
+			Extensions: newHandshakeExtensions(m.Extensions),
+		}
+	}
+	if m := flight.CertificateRequest; m != nil {
+		res.CertificateRequest = newCertificateRequestMessage(m)
+	}
+	if m := flight.ServerKeyExchange; m != nil {
+		res.ServerKeyExchange = newServerKeyExchangeMessage(m)
+	}
+	if m := flight.ServerHelloDone; m != nil {
+		res.ServerHelloDone = &openapi.ServerHelloDoneMessage{Raw: hex.EncodeToString(m.Raw)}
+	}
+	if m := flight.Certificate; m != nil {
+		res.Certificate = newCertificateMessage(m)
+	}
+	if m := flight.CertificateVerify; m != nil {
+		res.CertificateVerify = newCertificateVerifyMessage(m)
+	}
+	if m := flight.Finished; m != nil {
+		res.Finished = &openapi.FinishedMessage{
+			Raw:        hex.EncodeToString(m.Raw),
+			VerifyData: hex.EncodeToString(m.VerifyData),
+		}
+	}
+	return res
+}
+
+func newServerHelloMessage(m *utls.DecodedServerHello) *openapi.ServerHelloMessage {
+	res := &openapi.ServerHelloMessage{
+		Raw:                     hex.EncodeToString(m.Raw),
+		LegacyVersion:           uint16ToString(m.Hello.Vers),
+		Random:                  hex.EncodeToString(m.Hello.Random),
+		LegacySessionId:         hex.EncodeToString(m.Hello.SessionId),
+		CipherSuite:             uint16ToString(m.Hello.CipherSuite),
+		CipherSuiteName:         dicttls.DictCipherSuiteValueIndexed[m.Hello.CipherSuite],
+		LegacyCompressionMethod: int(m.Hello.CompressionMethod),
+		Extensions:              newHandshakeExtensions(m.Extensions),
+	}
+	if m.Hello.SupportedVersion != 0 {
+		v := uint16ToString(m.Hello.SupportedVersion)
+		res.SelectedVersion = &v
+	}
+	// HelloRetryRequest には公開鍵が含まれず、グループのみが指定される
+	group := m.Hello.ServerShare.Group
+	if group == 0 {
+		group = m.Hello.SelectedGroup
+	}
+	if group != 0 {
+		res.KeyShare = &openapi.KeyShareEntry{
+			Group:       uint16ToString(uint16(group)),
+			GroupName:   dicttls.DictSupportedGroupsValueIndexed[uint16(group)],
+			KeyExchange: hex.EncodeToString(m.Hello.ServerShare.Data),
+		}
+	}
+	return res
+}
+
+func newCertificateRequestMessage(m *utls.DecodedCertificateRequest) *openapi.CertificateRequestMessage {
+	res := &openapi.CertificateRequestMessage{
+		Raw:                       hex.EncodeToString(m.Raw),
+		CertificateRequestContext: hex.EncodeToString(m.RequestContext),
+		Extensions:                newHandshakeExtensions(m.Extensions),
+	}
+	// TLS 1.2 の CertificateRequest は拡張ではなく固定のフィールドを持つ
+	if m.CertificateTypes != nil {
+		types := make([]string, len(m.CertificateTypes))
+		for i, t := range m.CertificateTypes {
+			types[i] = fmt.Sprintf("0x%02x", t)
+		}
+		schemes := make([]string, len(m.SignatureSchemes))
+		for i, scheme := range m.SignatureSchemes {
+			schemes[i] = uint16ToString(uint16(scheme))
+		}
+		authorities := make([]string, len(m.CertificateAuthorities))
+		for i, ca := range m.CertificateAuthorities {
+			authorities[i] = hex.EncodeToString(ca)
+		}
+		res.CertificateTypes = &types
+		res.SignatureAlgorithms = &schemes
+		res.CertificateAuthorities = &authorities
+	}
+	return res
+}
+
+func newServerKeyExchangeMessage(m *utls.DecodedServerKeyExchange) *openapi.ServerKeyExchangeMessage {
+	res := &openapi.ServerKeyExchangeMessage{
+		Raw:       hex.EncodeToString(m.Raw),
+		CurveType: int(m.CurveType),
+		Signature: hex.EncodeToString(m.Signature),
+	}
+	if m.NamedCurve != 0 {
+		curve := uint16ToString(uint16(m.NamedCurve))
+		curveName := dicttls.DictSupportedGroupsValueIndexed[uint16(m.NamedCurve)]
+		publicKey := hex.EncodeToString(m.PublicKey)
+		res.NamedCurve = &curve
+		res.NamedCurveName = &curveName
+		res.PublicKey = &publicKey
+	}
+	if m.SignatureScheme != 0 {
+		scheme := uint16ToString(uint16(m.SignatureScheme))
+		schemeName := dicttls.DictSignatureSchemeValueIndexed[uint16(m.SignatureScheme)]
+		res.SignatureScheme = &scheme
+		res.SignatureSchemeName = &schemeName
+	}
+	return res
+}
+
+// newClientKeyExchange は、TLS 1.2 でクライアントが送信した ClientKeyExchange を解析する
+func newClientKeyExchange(recorder *utls.HandshakeRecorder, cipherSuite uint16) *openapi.ClientKeyExchangeMessage {
+	raw := recorder.Message(utls.RecordSent, utls.HandshakeTypeClientKeyExchange)
+	if raw == nil {
+		return nil
+	}
+	m, err := utls.DecodeClientKeyExchange(raw, cipherSuite)
+	if err != nil {
+		return nil
+	}
+	res := &openapi.ClientKeyExchangeMessage{Raw: hex.EncodeToString(m.Raw)}
+	if m.PublicKey != nil {
+		publicKey := hex.EncodeToString(m.PublicKey)
+		res.KeyExchange = openapi.ClientKeyExchangeMessageKeyExchangeECDHE
+		res.PublicKey = &publicKey
+	} else {
+		encrypted := hex.EncodeToString(m.EncryptedPreMasterSecret)
+		res.KeyExchange = openapi.ClientKeyExchangeMessageKeyExchangeRSA
+		res.EncryptedPreMasterSecret = &encrypted
+	}
+	return res
+}
+
+// receivedChangeCipherSpec は、サーバーが ChangeCipherSpec を送信したかどうかを返す
+func receivedChangeCipherSpec(recorder *utls.HandshakeRecorder) *bool {
+	received := false
+	for _, r := range recorder.Records() {
+		if r.Direction == utls.RecordReceived && r.ContentType == utls.RecordTypeChangeCipherSpec {
+			received = true
+			break
+		}
+	}
+	return &received
+}
+
+func newCertificateMessage(m *utls.DecodedCertificate) *openapi.CertificateMessage {
+	res := &openapi.CertificateMessage{
+		Raw:                       hex.EncodeToString(m.Raw),
+		CertificateRequestContext: hex.EncodeToString(m.RequestContext),
+		CertificateList:           make([]openapi.CertificateEntry, len(m.Entries)),
+	}
+	if m.CompressionAlgorithm != 0 {
+		alg := uint16ToString(uint16(m.CompressionAlgorithm))
+		res.CompressionAlgorithm = &alg
+	}
+	for i, entry := range m.Entries {
+		e := openapi.CertificateEntry{
+			CertData:   hex.EncodeToString(entry.Data),
+			Extensions: newHandshakeExtensions(entry.Extensions),
+		}
+		if cert := entry.Certificate; cert != nil {
+			subject := cert.Subject.String()
+			issuer := cert.Issuer.String()
+			serial := fmt.Sprintf("0x%x", cert.SerialNumber)
+			notBefore := cert.NotBefore.Format(time.RFC3339)
+			notAfter := cert.NotAfter.Format(time.RFC3339)
+			dnsNames := cert.DNSNames
+			e.Subject = &subject
+			e.Issuer = &issuer
+			e.SerialNumber = &serial
+			e.NotBefore = &notBefore
+			e.NotAfter = &notAfter
+			e.DnsNames = &dnsNames
+		} else if entry.ParseError != nil {
+			parseError := entry.ParseError.Error()
+			e.ParseError = &parseError
+		}
+		res.CertificateList[i] = e
+	}
+	return res
+}
+
+func newHandshakeExtensions(exts []utls.HandshakeExtension) []openapi.HandshakeExtension {
+	res := make([]openapi.HandshakeExtension, len(exts))
+	for i, ext := range exts {
+		res[i] = openapi.HandshakeExtension{
+			Type: uint16ToString(ext.Type),
+			Name: dicttls.DictExtTypeValueIndexed[ext.Type],
+			Data: hex.EncodeToString(ext.Data),
+		}
+	}
+	return res
+}
+
+// uint16ToString は、stringToUint16 の逆変換を行う
+// 例: 4865 -> "0x1301"
+func uint16ToString(v uint16) string {
+	return fmt.Sprintf("0x%04x", v)
+}
@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/mytls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// DefaultSessionTTL は、最後に操作してからセッションを破棄するまでの既定の時間です。
+const DefaultSessionTTL = 5 * time.Minute
+
+// DefaultMaxSessions と DefaultMaxSessionsPerClient は、同時に保持できるセッション数の既定の上限です。
+const (
+	DefaultMaxSessions          = 256
+	DefaultMaxSessionsPerClient = 8
+)
+
+// stepTimeout は、1メッセージ進めるときにサーバーの応答を待つ時間です。
+const stepTimeout = 10 * time.Second
+
+// sessionSweepInterval は、期限切れのセッションを破棄する間隔の上限です。
+const sessionSweepInterval = 30 * time.Second
+
+// errTooManySessions は、サーバー全体で保持できるセッション数の上限に達していることを表します。
+var errTooManySessions = errors.New("too many sessions")
+
+// errTooManyClientSessions は、クライアントごとに保持できるセッション数の上限に達していることを表します。
+var errTooManyClientSessions = errors.New("too many sessions from this client")
+
+// SessionStore は、/tls/sessions で作成したハンドシェイクのセッションを保持します。
+// 期限切れのセッションは、バックグラウンドで定期的に、またはストアを操作したときに破棄され、
+// その接続は閉じられます (Outbound の同時接続数の枠も空きます)。
+type SessionStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	// MaxSessions は、同時に保持できるセッションの数です。0 の場合は制限しません。
+	MaxSessions int
+
+	// MaxSessionsPerClient は、クライアント (IPアドレス) ごとに同時に保持できるセッションの数です。
+	// 0 の場合は制限しません。
+	MaxSessionsPerClient int
+
+	mu       sync.Mutex
+	sessions map[string]*session
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSessionStore は、最後の操作からttlが経過したセッションを破棄するストアを返します。
+// セッション数の上限は既定値になります。期限切れのセッションを破棄するゴルーチンは Close で止まります。
+func NewSessionStore(ttl time.Duration) *SessionStore {
+	st := &SessionStore{
+		ttl:                  ttl,
+		now:                  time.Now,
+		MaxSessions:          DefaultMaxSessions,
+		MaxSessionsPerClient: DefaultMaxSessionsPerClient,
+		sessions:             map[string]*session{},
+		stop:                 make(chan struct{}),
+	}
+	go st.expire(min(ttl, sessionSweepInterval))
+	return st
+}
+
+// Close は、期限切れのセッションを破棄するゴルーチンを止め、すべてのセッションを破棄します。
+func (st *SessionStore) Close() {
+	st.stopOnce.Do(func() { close(st.stop) })
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for id, sess := range st.sessions {
+		delete(st.sessions, id)
+		sess.stepper.Close()
+	}
+}
+
+// expire は、Close されるまでintervalごとに期限切れのセッションを破棄する
+func (st *SessionStore) expire(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			st.mu.Lock()
+			st.sweepLocked()
+			st.mu.Unlock()
+		case <-st.stop:
+			return
+		}
+	}
+}
+
+// session は、1メッセージずつ進めているハンドシェイクです。
+type session struct {
+	stepper  *utls.HandshakeStepper
+	verifier *certificateVerifier
+	// client は、セッションを作成したクライアントのIPアドレス
+	client string
+
+	// mu は、同じセッションへのステップ要求を直列化する
+	mu    sync.Mutex
+	steps int
+
+	// expiresAt は SessionStore.mu で保護される
+	expiresAt time.Time
+}
+
+// add は、clientが作成したセッションを保存します。セッション数が上限に達している場合は
+// errTooManySessions または errTooManyClientSessions を返します。
+func (st *SessionStore) add(client string, stepper *utls.HandshakeStepper, verifier *certificateVerifier) (string, time.Time, error) {
+	id, err := randomID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	sess := &session{stepper: stepper, verifier: verifier, client: client}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweepLocked()
+	if st.MaxSessions > 0 && len(st.sessions) >= st.MaxSessions {
+		return "", time.Time{}, errTooManySessions
+	}
+	if st.MaxSessionsPerClient > 0 {
+		n := 0
+		for _, s := range st.sessions {
+			if s.client == client {
+				n++
+			}
+		}
+		if n >= st.MaxSessionsPerClient {
+			return "", time.Time{}, errTooManyClientSessions
+		}
+	}
+	sess.expiresAt = st.now().Add(st.ttl)
+	st.sessions[id] = sess
+	return id, sess.expiresAt, nil
+}
+
+// get は、idのセッションを返し、有効期限を延長します。
+func (st *SessionStore) get(id string) (*session, time.Time, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweepLocked()
+	sess, ok := st.sessions[id]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	sess.expiresAt = st.now().Add(st.ttl)
+	return sess, sess.expiresAt, true
+}
+
+func (st *SessionStore) remove(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	sess, ok := st.sessions[id]
+	if ok {
+		delete(st.sessions, id)
+		sess.stepper.Close()
+	}
+	return ok
+}
+
+// randomID は、セッションやチケットのハンドルに使う推測できないIDを返します。
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (st *SessionStore) sweepLocked() {
+	now := st.now()
+	for id, sess := range st.sessions {
+		if now.After(sess.expiresAt) {
+			delete(st.sessions, id)
+			sess.stepper.Close()
+		}
+	}
+}
+
+// PostTlsSessions は、ハンドシェイクを開始せずに待機するセッションを作成する
+func (s Server) PostTlsSessions(ctx echo.Context) error {
+	var payload openapi.HandshakeRequest
+	if err := ctx.Bind(&payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	if payload.Resumption != nil {
+		return s.handleBadRequest(ctx, errors.New("invalid payload: resumption is only supported by /tls/handshake"), payload)
+	}
+	if s.Sessions == nil {
+		return ctx.JSON(500, "session store is not configured")
+	}
+
+	spec, err := createClientHelloSpec(payload)
+	if err != nil {
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	minVersion, maxVersion, err := tlsVersionRange(payload, spec)
+	if err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	clientRandom, err := hex.DecodeString(payload.ClientRandom)
+	if err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid ClientRandom: %w", err), payload)
+	}
+
+	if _, _, err := mytls.DialTarget(payload); err != nil {
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	conn, err := s.Outbound.dial(ctx.Request().Context(), payload)
+	if err != nil {
+		return dialFailed(ctx, err)
+	}
+
+	config := &utls.Config{
+		ServerName:   payload.ServerName,
+		KeyLogWriter: os.Stderr,
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+	}
+	verifier, err := s.setCertificateVerification(config, payload)
+	if err != nil {
+		conn.Close()
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	if err := setClientCertificate(config, payload); err != nil {
+		conn.Close()
+		return s.handleBadRequest(ctx, err, payload)
+	}
+	uconn := utls.UClient(conn, config, utls.HelloCustom)
+	uconn.SetHandshakeFailureTracking(true)
+	stepper := utls.NewHandshakeStepper(uconn)
+	if err := uconn.ApplyPreset(spec); err != nil {
+		stepper.Close()
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	if err := uconn.SetClientRandom(clientRandom); err != nil {
+		stepper.Close()
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+	if err := applyClientHelloFields(uconn, payload); err != nil {
+		stepper.Close()
+		return s.handleBadRequest(ctx, fmt.Errorf("invalid payload: %w", err), payload)
+	}
+
+	id, expiresAt, err := s.Sessions.add(ctx.RealIP(), stepper, verifier)
+	if err != nil {
+		stepper.Close()
+		return sessionsFull(ctx, err)
+	}
+	return ctx.JSON(201, openapi.SessionResponse{Id: id, ExpiresAt: expiresAt})
+}
+
+// PostTlsSessionsIdStep は、一時停止しているハンドシェイクを次のメッセージまで進める
+func (s Server) PostTlsSessionsIdStep(ctx echo.Context, id string) error {
+	sess, expiresAt, ok := s.lookupSession(id)
+	if !ok {
+		return sessionNotFound(ctx, id)
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	stepCtx, cancel := context.WithTimeout(ctx.Request().Context(), stepTimeout)
+	defer cancel()
+	step, err := sess.stepper.Next(stepCtx)
+
+	response := openapi.HandshakeStepResponse{
+		Index:     sess.steps,
+		ExpiresAt: expiresAt,
+	}
+	switch {
+	case err == nil:
+		sess.steps++
+		response.Message = newHandshakeStepMessage(sess.stepper.Recorder(), step.Message)
+		response.KeySchedule = newKeyScheduleSteps(step.KeySchedule)
+	case errors.Is(err, io.EOF):
+		response.Done = true
+		response.KeySchedule = newKeyScheduleSteps(sess.stepper.Trace().Steps())
+		response.CertificateVerification = sess.verifier.result()
+	default:
+		response.Done = true
+		response.KeySchedule = newKeyScheduleSteps(sess.stepper.Trace().Steps())
+		response.CertificateVerification = sess.verifier.result()
+		message := err.Error()
+		response.Error = &message
+		response.Failure = newHandshakeFailure(handshakeError(sess.stepper.HandshakeFailure(), err))
+	}
+	return ctx.JSON(200, response)
+}
+
+// DeleteTlsSessionsId は、ハンドシェイクを中断してセッションを破棄する
+func (s Server) DeleteTlsSessionsId(ctx echo.Context, id string) error {
+	if s.Sessions == nil || !s.Sessions.remove(id) {
+		return sessionNotFound(ctx, id)
+	}
+	return ctx.NoContent(204)
+}
+
+func (s Server) lookupSession(id string) (*session, time.Time, bool) {
+	if s.Sessions == nil {
+		return nil, time.Time{}, false
+	}
+	return s.Sessions.get(id)
+}
+
+// sessionsFull は、セッションを保存できなかった場合のレスポンスを返す
+func sessionsFull(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errTooManyClientSessions):
+		return ctx.JSON(http.StatusTooManyRequests, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeTooManySessions,
+			Message: err.Error(),
+		})
+	case errors.Is(err, errTooManySessions):
+		return ctx.JSON(http.StatusServiceUnavailable, openapi.ErrorResponse{
+			Code:    openapi.ErrorCodeTooManySessions,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(500, fmt.Sprintf("failed to create session: %v", err))
+}
+
+func sessionNotFound(ctx echo.Context, id string) error {
+	return ctx.JSON(404, openapi.ErrorResponse{
+		Code:    openapi.ErrorCodeSessionNotFound,
+		Message: fmt.Sprintf("session %q not found or expired", id),
+	})
+}
+
+// newHandshakeStepMessage は、1つのハンドシェイクメッセージとその解析結果を返す。
+// 受信したメッセージは、それまでに受信したメッセージと合わせて解析し、
+// このメッセージに対応する項目だけを server_flight に含める。
+func newHandshakeStepMessage(recorder *utls.HandshakeRecorder, m utls.RecordedMessage) *openapi.HandshakeStepMessage {
+	res := newRecordedMessage(m)
+
+	messages := recorder.Messages()
+	if m.Direction == utls.RecordReceived {
+		// 解析に失敗したメッセージの項目は省略される
+		flight, _ := utls.DecodeServerFlight(messages)
+		res.ServerFlight = newServerFlight(onlyMessage(flight, m.Type))
+		return res
+	}
+
+	switch m.Type {
+	case utls.HandshakeTypeClientHello:
+		var fields []openapi.MessageField
+		for _, f := range clientHelloFields(convertRecordbytes(m.Raw)) {
+			// レコードヘッダはこのAPIで付け直したものなので含めない
+			if strings.HasPrefix(f.name, "record.") {
+				continue
+			}
+			fields = append(fields, openapi.MessageField{Name: f.name, Value: f.value})
+		}
+		res.ClientHello = &fields
+	case utls.HandshakeTypeFinished:
+		if finished, err := utls.DecodeFinished(m.Raw); err == nil {
+			res.Finished = &openapi.FinishedMessage{
+				Raw:        hex.EncodeToString(finished.Raw),
+				VerifyData: hex.EncodeToString(finished.VerifyData),
+			}
+		}
+	case utls.HandshakeTypeClientKeyExchange:
+		if flight, err := utls.DecodeServerFlight(messages); err == nil && flight.ServerHello != nil {
+			res.ClientKeyExchange = newClientKeyExchange(recorder, flight.ServerHello.Hello.CipherSuite)
+		}
+	case utls.HandshakeTypeCertificate:
+		if flight, err := utls.DecodeServerFlight(messages); err == nil {
+			res.ClientCertificate = newClientCertificate(recorder, flight.Version)
+		}
+	case utls.HandshakeTypeCertificateVerify:
+		res.ClientCertificateVerify = newClientCertificateVerify(recorder)
+	}
+	return res
+}
+
+// onlyMessage は、flightのうちtypの項目とバージョンだけを残したコピーを返す
+func onlyMessage(flight *utls.DecodedServerFlight, typ uint8) *utls.DecodedServerFlight {
+	res := &utls.DecodedServerFlight{Version: flight.Version}
+	switch typ {
+	case utls.HandshakeTypeServerHello:
+		// HelloRetryRequest も ServerHello と同じ種別なので、ServerHello を受信済みならそちらを返す
+		if flight.ServerHello != nil {
+			res.ServerHello = flight.ServerHello
+		} else {
+			res.HelloRetryRequest = flight.HelloRetryRequest
+		}
+	case utls.HandshakeTypeEncryptedExtensions:
+		res.EncryptedExtensions = flight.EncryptedExtensions
+	case utls.HandshakeTypeCertificateRequest:
+		res.CertificateRequest = flight.CertificateRequest
+	case utls.HandshakeTypeCertificate, utls.HandshakeTypeCompressedCert:
+		res.Certificate = flight.Certificate
+	case utls.HandshakeTypeServerKeyExchange:
+		res.ServerKeyExchange = flight.ServerKeyExchange
+	case utls.HandshakeTypeServerHelloDone:
+		res.ServerHelloDone = flight.ServerHelloDone
+	case utls.HandshakeTypeCertificateVerify:
+		res.CertificateVerify = flight.CertificateVerify
+	case utls.HandshakeTypeFinished:
+		res.Finished = flight.Finished
+	}
+	return res
+}
@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
+)
+
+func TestPostTlsSessionsStep(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		modify func(*openapi.TlsClientParameters)
+		want   []string
+	}{
+		{
+			name:   "正常系：TLS 1.3のハンドシェイクを1メッセージずつ進められる",
+			modify: func(p *openapi.TlsClientParameters) {},
+			want: []string{
+				"sent client_hello",
+				"received server_hello",
+				"received encrypted_extensions",
+				"received certificate",
+				"received certificate_verify",
+				"received finished",
+				"sent finished",
+			},
+		},
+		{
+			name: "正常系：TLS 1.2のハンドシェイクを1メッセージずつ進められる",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ProtocolVersion = "0x0303"
+				p.CipherSuites = []string{"0xc02b"}
+			},
+			want: []string{
+				"sent client_hello",
+				"received server_hello",
+				"received certificate",
+				"received server_key_exchange",
+				"received server_hello_done",
+				"sent client_key_exchange",
+				"sent finished",
+				"received finished",
+			},
+		},
+		{
+			name: "正常系：TLS 1.2までのプリセットはprotocol_versionが0x0304でもTLS 1.2で進められる",
+			modify: func(p *openapi.TlsClientParameters) {
+				// NewSessionTicket を受け取らないように session_ticket を除く
+				var extensions []openapi.ClientHelloExtension
+				for _, ext := range findPreset(t, e, "Chrome-58").Extensions {
+					if ext.Name != "session_ticket" {
+						extensions = append(extensions, ext)
+					}
+				}
+				p.Preset = ptr("Chrome-58")
+				p.CipherSuites, p.SupportedGroups, p.KeyShares, p.SignatureAlgorithms = nil, nil, nil, nil
+				p.Extensions = &extensions
+			},
+			want: []string{
+				"sent client_hello",
+				"received server_hello",
+				"received certificate",
+				"received server_key_exchange",
+				"received server_hello_done",
+				"sent client_key_exchange",
+				"sent finished",
+				"received finished",
+			},
+		},
+		{
+			name: "正常系：クライアント証明書を送信するメッセージも1つずつ進められる",
+			modify: func(p *openapi.TlsClientParameters) {
+				generate := true
+				p.ServerName = testserver.ClientAuthServerName
+				p.ClientCertificate = &openapi.ClientCertificate{Generate: &generate}
+			},
+			want: []string{
+				"sent client_hello",
+				"received server_hello",
+				"received encrypted_extensions",
+				"received certificate_request",
+				"received certificate",
+				"received certificate_verify",
+				"received finished",
+				"sent certificate",
+				"sent certificate_verify",
+				"sent finished",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			id := createSession(t, e, params)
+
+			var got []string
+			var last openapi.HandshakeStepResponse
+			for i := 0; ; i++ {
+				var res openapi.HandshakeStepResponse
+				code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, &res)
+				if code != http.StatusOK {
+					t.Fatalf("status = %d, want %d", code, http.StatusOK)
+				}
+				if res.Index != i {
+					t.Errorf("index = %d, want %d", res.Index, i)
+				}
+				if res.Done {
+					if res.Error != nil {
+						t.Fatalf("handshake failed: %s", *res.Error)
+					}
+					if res.Message != nil {
+						t.Errorf("message is set after the handshake completed")
+					}
+					break
+				}
+				if len(res.KeySchedule) < len(last.KeySchedule) {
+					t.Errorf("key_schedule shrank at step %d", i)
+				}
+				checkStepMessage(t, res.Message)
+				got = append(got, string(res.Message.Direction)+" "+res.Message.TypeName)
+				last = res
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("steps = %q, want %q", got, tt.want)
+			}
+			if len(last.KeySchedule) == 0 {
+				t.Errorf("key_schedule is empty at the last step")
+			}
+
+			if code := doJSON(t, e, http.MethodDelete, "/tls/sessions/"+id, nil, nil); code != http.StatusNoContent {
+				t.Errorf("DELETE status = %d, want %d", code, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	t.Run("異常系：存在しないセッション", func(t *testing.T) {
+		var res openapi.ErrorResponse
+		code := doJSON(t, e, http.MethodPost, "/tls/sessions/unknown/step", nil, &res)
+		if code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", code, http.StatusNotFound)
+		}
+		if !strings.Contains(res.Message, "not found") {
+			t.Errorf("message = %q", res.Message)
+		}
+	})
+
+	t.Run("異常系：破棄したセッション", func(t *testing.T) {
+		id := createSession(t, e, testServerParameters(ts))
+		if code := doJSON(t, e, http.MethodDelete, "/tls/sessions/"+id, nil, nil); code != http.StatusNoContent {
+			t.Fatalf("DELETE status = %d, want %d", code, http.StatusNoContent)
+		}
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, nil); code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("異常系：有効期限が切れたセッション", func(t *testing.T) {
+		now := time.Now()
+		store := NewSessionStore(time.Minute)
+		store.now = func() time.Time { return now }
+		e := echo.New()
+		openapi.RegisterHandlers(e, Server{TestServer: ts, Sessions: store})
+
+		id := createSession(t, e, testServerParameters(ts))
+		now = now.Add(30 * time.Second)
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, nil); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		// 操作すると有効期限が延長される
+		now = now.Add(45 * time.Second)
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, nil); code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", code, http.StatusOK)
+		}
+		now = now.Add(2 * time.Minute)
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions/"+id+"/step", nil, nil); code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("異常系：不正なパラメータではセッションを作成しない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ProtocolVersion = "0x0302"
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions", params, nil); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestSessionStoreLimits(t *testing.T) {
+	_, ts := newTestServer(t)
+	newServer := func(t *testing.T, store *SessionStore, o *Outbound) *echo.Echo {
+		t.Cleanup(store.Close)
+		e := echo.New()
+		openapi.RegisterHandlers(e, Server{TestServer: ts, Sessions: store, Outbound: o})
+		return e
+	}
+
+	t.Run("異常系：クライアントごとのセッション数の上限", func(t *testing.T) {
+		store := NewSessionStore(time.Minute)
+		store.MaxSessionsPerClient = 1
+		e := newServer(t, store, nil)
+		createSession(t, e, testServerParameters(ts))
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions", testServerParameters(ts), &res); code != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want %d", code, http.StatusTooManyRequests)
+		}
+		if res.Code != openapi.ErrorCodeTooManySessions {
+			t.Errorf("code = %s", res.Code)
+		}
+	})
+
+	t.Run("異常系：サーバー全体のセッション数の上限", func(t *testing.T) {
+		store := NewSessionStore(time.Minute)
+		store.MaxSessions = 1
+		e := newServer(t, store, nil)
+		createSession(t, e, testServerParameters(ts))
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions", testServerParameters(ts), &res); code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", code, http.StatusServiceUnavailable)
+		}
+		if res.Code != openapi.ErrorCodeTooManySessions {
+			t.Errorf("code = %s", res.Code)
+		}
+	})
+
+	t.Run("正常系：期限切れのセッションは操作しなくても破棄され、接続の枠が空く", func(t *testing.T) {
+		o, err := NewOutbound(OutboundConfig{MaxConcurrentConnections: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := NewSessionStore(50 * time.Millisecond)
+		e := newServer(t, store, o)
+		createSession(t, e, testServerParameters(ts))
+		if len(o.slots) != 1 {
+			t.Fatalf("used slots = %d, want 1", len(o.slots))
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for len(o.slots) != 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("the connection of the expired session is still open")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		if len(store.sessions) != 0 {
+			t.Errorf("sessions = %d, want 0", len(store.sessions))
+		}
+	})
+}
+
+// createSession は、POST /tls/sessions でセッションを作成し、そのIDを返します。
+func createSession(t *testing.T, e *echo.Echo, params openapi.TlsClientParameters) string {
+	t.Helper()
+	var res openapi.SessionResponse
+	code := doJSON(t, e, http.MethodPost, "/tls/sessions", params, &res)
+	if code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", code, http.StatusCreated)
+	}
+	if res.Id == "" || res.ExpiresAt.IsZero() {
+		t.Fatalf("incomplete session: %+v", res)
+	}
+	return res.Id
+}
+
+// checkStepMessage は、メッセージの種類に応じた解析結果が含まれていることを確認します。
+func checkStepMessage(t *testing.T, m *openapi.HandshakeStepMessage) {
+	t.Helper()
+	if m == nil || m.Raw == "" {
+		t.Fatalf("message is missing: %+v", m)
+	}
+	var decoded bool
+	switch m.TypeName {
+	case "client_hello":
+		decoded = m.ClientHello != nil && len(*m.ClientHello) > 0
+	case "client_key_exchange":
+		decoded = m.ClientKeyExchange != nil
+	case "server_hello":
+		decoded = m.ServerFlight != nil && m.ServerFlight.ServerHello != nil
+	case "encrypted_extensions":
+		decoded = m.ServerFlight != nil && m.ServerFlight.EncryptedExtensions != nil && m.Encrypted
+	case "certificate_request":
+		decoded = m.ServerFlight != nil && m.ServerFlight.CertificateRequest != nil
+	case "certificate":
+		if m.Direction == openapi.HandshakeStepMessageDirectionSent {
+			decoded = m.ClientCertificate != nil && len(m.ClientCertificate.CertificateList) == 1
+		} else {
+			decoded = m.ServerFlight != nil && m.ServerFlight.Certificate != nil
+		}
+	case "certificate_verify":
+		if m.Direction == openapi.HandshakeStepMessageDirectionSent {
+			decoded = m.ClientCertificateVerify != nil
+		} else {
+			decoded = m.ServerFlight != nil && m.ServerFlight.CertificateVerify != nil
+		}
+	case "server_key_exchange":
+		decoded = m.ServerFlight != nil && m.ServerFlight.ServerKeyExchange != nil
+	case "server_hello_done":
+		decoded = m.ServerFlight != nil && m.ServerFlight.ServerHelloDone != nil
+	case "finished":
+		if m.Direction == openapi.HandshakeStepMessageDirectionSent {
+			decoded = m.Finished != nil
+		} else {
+			decoded = m.ServerFlight != nil && m.ServerFlight.Finished != nil && m.ServerFlight.ServerHello == nil
+		}
+	}
+	if !decoded {
+		t.Errorf("%s %s is not decoded: %+v", m.Direction, m.TypeName, m)
+	}
+}
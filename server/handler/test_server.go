@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
+)
+
+func (s Server) GetTlsTestServer(ctx echo.Context) error {
+	if s.TestServer == nil {
+		return ctx.JSON(http.StatusOK, openapi.TestServerResponse{Running: false})
+	}
+
+	addr := s.TestServer.Addr()
+	address := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		// 全インターフェースで待ち受けている場合はループバックで接続する
+		address = "127.0.0.1"
+	}
+	port := addr.Port
+	serverName := testserver.ServerName
+	clientAuthServerName := testserver.ClientAuthServerName
+	certificate := hex.EncodeToString(s.TestServer.Certificate().Raw)
+
+	return ctx.JSON(http.StatusOK, openapi.TestServerResponse{
+		Running:              true,
+		Address:              &address,
+		Port:                 &port,
+		ServerName:           &serverName,
+		Certificate:          &certificate,
+		ClientAuthServerName: &clientAuthServerName,
+	})
+}
+
+// rootCAs は、テストサーバーが起動している場合に、その自己署名証明書を
+// システムのルート証明書に加えた証明書プールを返します。
+// 起動していない場合はnilを返し、システムのルート証明書が使われます。
+func (s Server) rootCAs() *x509.CertPool {
+	if s.TestServer == nil {
+		return nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AddCert(s.TestServer.Certificate())
+	return pool
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/labstack/echo/v4"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// maxRequestBodySize は、検証のために読み込むリクエストボディの上限のバイト数です。
+// 上限を超えるリクエストには 413 を返します。
+const maxRequestBodySize = 1 << 20
+
+// tlsClientParametersOperations は、リクエストボディが TlsClientParameters のオペレーションです。
+var tlsClientParametersOperations = map[string]bool{
+	"PostTlsHandshake":   true,
+	"PostTlsApplication": true,
+	"PostTlsSessions":    true,
+	"PostTlsCompare":     true,
+}
+
+// NewRequestValidator は、リクエストを openapi.yaml のスキーマと値の妥当性で検証するミドルウェアを返します。
+// 検証に失敗したリクエストは、ハンドラーを実行せずに項目ごとのエラーを含む 400 を返します。
+// ボディが maxRequestBodySize を超えるリクエストには 413 を返します。
+// openapi.yaml に定義されていないパスはそのまま次のハンドラーに渡します。
+func NewRequestValidator() (echo.MiddlewareFunc, error) {
+	swagger, err := openapi.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the OpenAPI specification: %w", err)
+	}
+	// servers のホスト名に関係なくパスだけで照合する
+	swagger.Servers = nil
+	router, err := legacy.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OpenAPI router: %w", err)
+	}
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			route, pathParams, err := router.FindRoute(req)
+			if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+				return next(ctx)
+			}
+			if err != nil {
+				return err
+			}
+
+			var body []byte
+			if req.Body != nil {
+				body, err = io.ReadAll(http.MaxBytesReader(ctx.Response(), req.Body, maxRequestBodySize))
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					return ctx.JSON(http.StatusRequestEntityTooLarge, openapi.ErrorResponse{
+						Code:    openapi.ErrorCodeRequestTooLarge,
+						Message: fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit),
+					})
+				}
+				if err != nil {
+					return err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+				return validationFailed(ctx, openapi.ErrorCodeSchemaViolation, "invalid payload: the request does not match the API schema", schemaErrors(err))
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			if tlsClientParametersOperations[route.Operation.OperationID] {
+				var payload openapi.TlsClientParameters
+				if err := json.Unmarshal(body, &payload); err != nil {
+					return validationFailed(ctx, openapi.ErrorCodeSchemaViolation, fmt.Sprintf("invalid payload: %v", err), nil)
+				}
+				if errs := validateTlsClientParameters(payload); len(errs) > 0 {
+					// レスポンスの code は最初のエラーの種類にする
+					return validationFailed(ctx, errs[0].Code, "invalid payload: "+errs[0].Message, errs)
+				}
+			}
+			return next(ctx)
+		}
+	}, nil
+}
+
+func validationFailed(ctx echo.Context, code openapi.ErrorCode, message string, errs []openapi.ValidationError) error {
+	response := openapi.ErrorResponse{
+		Code:    code,
+		Message: message,
+	}
+	if len(errs) > 0 {
+		response.Errors = &errs
+	}
+	return ctx.JSON(http.StatusBadRequest, response)
+}
+
+// schemaErrors は、kin-openapi の検証エラーを項目ごとのエラーに分解する。
+// allOf などで入れ子になったエラーは、最も内側のものだけを返す。
+func schemaErrors(err error) []openapi.ValidationError {
+	var result []openapi.ValidationError
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, err := range e {
+				walk(err)
+			}
+		case *openapi3filter.RequestError:
+			if e.Err == nil {
+				result = append(result, schemaError("", e.Error()))
+				return
+			}
+			before := len(result)
+			walk(e.Err)
+			if len(result) == before {
+				result = append(result, schemaError("", e.Error()))
+			}
+		case *openapi3.SchemaError:
+			before := len(result)
+			if e.Origin != nil {
+				walk(e.Origin)
+			}
+			if len(result) == before {
+				result = append(result, schemaError(jsonPointer(e.JSONPointer()), e.Reason))
+			}
+		default:
+			if inner := errors.Unwrap(err); inner != nil {
+				walk(inner)
+			}
+		}
+	}
+	walk(err)
+	if len(result) == 0 {
+		result = append(result, schemaError("", err.Error()))
+	}
+	return result
+}
+
+func schemaError(field, message string) openapi.ValidationError {
+	return openapi.ValidationError{Code: openapi.ErrorCodeSchemaViolation, Field: field, Message: message}
+}
+
+func jsonPointer(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	escaped := make([]string, len(tokens))
+	for i, t := range tokens {
+		escaped[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(t)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+var (
+	// codepointPattern は、16ビットの値を表す 0x から始まる4桁の16進数文字列
+	codepointPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{4}$`)
+	// uint8Pattern は、8ビットの値を表す 0x から始まる2桁の16進数文字列
+	uint8Pattern = regexp.MustCompile(`^0x[0-9a-fA-F]{2}$`)
+)
+
+/**
+ * validateTlsClientParameters は、スキーマでは表せない値の妥当性を検証する
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.1.2
+ */
+func validateTlsClientParameters(p openapi.TlsClientParameters) []openapi.ValidationError {
+	var errs []openapi.ValidationError
+	add := func(code openapi.ErrorCode, field, format string, args ...any) {
+		errs = append(errs, openapi.ValidationError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !codepointPattern.MatchString(p.ProtocolVersion) {
+		add(openapi.ErrorCodeInvalidCodepoint, "/protocol_version", "protocol_version must be a 16-bit hex value such as 0x0304: %q", p.ProtocolVersion)
+	}
+	if random, err := hex.DecodeString(p.ClientRandom); err != nil {
+		add(openapi.ErrorCodeInvalidHex, "/client_random", "client_random is not a hex string: %v", err)
+	} else if len(random) != 32 {
+		add(openapi.ErrorCodeInvalidClientRandom, "/client_random", "client_random must be 32 bytes, got %d", len(random))
+	}
+	if p.SessionId != nil {
+		if sessionID, err := hex.DecodeString(*p.SessionId); err != nil {
+			add(openapi.ErrorCodeInvalidHex, "/session_id", "session_id is not a hex string: %v", err)
+		} else if len(sessionID) > 32 {
+			add(openapi.ErrorCodeInvalidSessionId, "/session_id", "session_id must be at most 32 bytes, got %d", len(sessionID))
+		}
+	}
+	if p.Preset != nil && !presetExists(*p.Preset) {
+		add(openapi.ErrorCodeUnknownPreset, "/preset", "unknown preset: %s", *p.Preset)
+	}
+
+	checkCodepoints := func(name string, values []string, allowGREASE bool) {
+		for i, v := range values {
+			if allowGREASE && strings.EqualFold(v, extensionNameGREASE) {
+				continue
+			}
+			if !codepointPattern.MatchString(v) {
+				add(openapi.ErrorCodeInvalidCodepoint, fmt.Sprintf("/%s/%d", name, i), "%s must be 16-bit hex values such as 0x1301: %q", name, v)
+			}
+		}
+	}
+	checkCodepoints("cipher_suites", p.CipherSuites, true)
+	checkCodepoints("supported_groups", p.SupportedGroups, true)
+	checkCodepoints("key_shares", p.KeyShares, true)
+	checkCodepoints("signature_algorithms", p.SignatureAlgorithms, false)
+	if p.CompressionMethods != nil {
+		for i, v := range *p.CompressionMethods {
+			if !uint8Pattern.MatchString(v) {
+				add(openapi.ErrorCodeInvalidCodepoint, fmt.Sprintf("/compression_methods/%d", i), "compression_methods must be 8-bit hex values such as 0x00: %q", v)
+			}
+		}
+	}
+
+	// プリセットを使う場合、supported_groups を省略するとプリセットの値が使われる
+	if len(p.SupportedGroups) > 0 || p.Preset == nil {
+		groups := map[string]bool{}
+		for _, g := range p.SupportedGroups {
+			groups[strings.ToLower(g)] = true
+		}
+		for i, k := range p.KeyShares {
+			if !groups[strings.ToLower(k)] {
+				add(openapi.ErrorCodeKeyShareNotInSupportedGroups, fmt.Sprintf("/key_shares/%d", i), "key share %s is not in supported_groups", k)
+			}
+		}
+	}
+
+	if p.Extensions != nil {
+		for i, ext := range *p.Extensions {
+			if ext.Type != nil && !codepointPattern.MatchString(*ext.Type) {
+				add(openapi.ErrorCodeInvalidCodepoint, fmt.Sprintf("/extensions/%d/type", i), "extension type must be a 16-bit hex value such as 0xfe0d: %q", *ext.Type)
+			}
+			if ext.Data != nil {
+				if _, err := hex.DecodeString(*ext.Data); err != nil {
+					add(openapi.ErrorCodeInvalidHex, fmt.Sprintf("/extensions/%d/data", i), "extension data is not a hex string: %v", err)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func presetExists(name string) bool {
+	for _, id := range presets() {
+		if id.Str() == name {
+			return true
+		}
+	}
+	return false
+}
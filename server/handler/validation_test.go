@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestRequestValidator(t *testing.T) {
+	e, ts := newTestServer(t)
+
+	tests := []struct {
+		name      string
+		modify    func(*openapi.TlsClientParameters)
+		wantCode  openapi.ErrorCode
+		wantField string
+	}{
+		{
+			name: "異常系：スキーマの列挙値にない verify_mode",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyMode("none")
+				p.VerifyMode = &mode
+			},
+			wantCode:  openapi.ErrorCodeSchemaViolation,
+			wantField: "/verify_mode",
+		},
+		{
+			name: "異常系：client_random が16進数ではない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ClientRandom = strings.Repeat("zz", 32)
+			},
+			wantCode:  openapi.ErrorCodeInvalidHex,
+			wantField: "/client_random",
+		},
+		{
+			name: "異常系：client_random が32バイトではない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ClientRandom = strings.Repeat("ab", 16)
+			},
+			wantCode:  openapi.ErrorCodeInvalidClientRandom,
+			wantField: "/client_random",
+		},
+		{
+			name: "異常系：session_id が32バイトを超える",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.SessionId = ptr(strings.Repeat("ab", 33))
+			},
+			wantCode:  openapi.ErrorCodeInvalidSessionId,
+			wantField: "/session_id",
+		},
+		{
+			name: "異常系：cipher_suites の値が4桁の16進数ではない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.CipherSuites = []string{"0x1301", "TLS_AES_256_GCM_SHA384"}
+			},
+			wantCode:  openapi.ErrorCodeInvalidCodepoint,
+			wantField: "/cipher_suites/1",
+		},
+		{
+			name: "異常系：key_shares が supported_groups に含まれない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.KeyShares = []string{"0x0018"}
+			},
+			wantCode:  openapi.ErrorCodeKeyShareNotInSupportedGroups,
+			wantField: "/key_shares/0",
+		},
+		{
+			name: "異常系：存在しないプリセット",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.Preset = ptr("Chrome-1")
+			},
+			wantCode:  openapi.ErrorCodeUnknownPreset,
+			wantField: "/preset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+			for _, path := range []string{"/tls/handshake", "/tls/application", "/tls/sessions", "/tls/compare"} {
+				var res openapi.ErrorResponse
+				if code := doJSON(t, e, http.MethodPost, path, params, &res); code != http.StatusBadRequest {
+					t.Fatalf("%s: status = %d, want %d", path, code, http.StatusBadRequest)
+				}
+				if res.Code != tt.wantCode {
+					t.Errorf("%s: code = %s, want %s", path, res.Code, tt.wantCode)
+				}
+				if res.Errors == nil || len(*res.Errors) != 1 || (*res.Errors)[0].Field != tt.wantField {
+					t.Errorf("%s: errors = %+v, want one for %s", path, res.Errors, tt.wantField)
+				}
+			}
+		})
+	}
+
+	t.Run("異常系：必須の項目がない場合は項目ごとにエラーを返す", func(t *testing.T) {
+		var res openapi.ErrorResponse
+		body := map[string]string{"protocol_version": "0x0304"}
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", body, &res); code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if res.Code != openapi.ErrorCodeSchemaViolation || res.Errors == nil || len(*res.Errors) != 6 {
+			t.Errorf("code = %s, errors = %+v", res.Code, res.Errors)
+		}
+	})
+
+	t.Run("異常系：ボディが上限のサイズを超える場合は 413", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.ServerName = strings.Repeat("a", maxRequestBodySize)
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, want %d", code, http.StatusRequestEntityTooLarge)
+		}
+		if res.Code != openapi.ErrorCodeRequestTooLarge {
+			t.Errorf("code = %s", res.Code)
+		}
+	})
+
+	t.Run("異常系：存在しないセッションは session_not_found", func(t *testing.T) {
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/sessions/unknown/step", nil, &res); code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", code, http.StatusNotFound)
+		}
+		if res.Code != openapi.ErrorCodeSessionNotFound {
+			t.Errorf("code = %s", res.Code)
+		}
+	})
+
+	t.Run("正常系：GREASE を指定した key_shares は GREASE の supported_groups と対応する", func(t *testing.T) {
+		params := testServerParameters(ts)
+		params.SupportedGroups = []string{"GREASE", "0x001d"}
+		params.KeyShares = []string{"grease", "0x001d"}
+		if errs := validateTlsClientParameters(params); len(errs) != 0 {
+			t.Errorf("errors = %+v", errs)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/mytls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// certificateVerifier は、verify_mode に従ってサーバー証明書を検証し、その結果を保持する。
+// 検証に失敗した場合も結果を返せるように、Config.InsecureSkipVerify で標準の検証を無効にし、
+// VerifyPeerCertificate で同じ検証を行う。
+type certificateVerifier struct {
+	mode       openapi.TlsClientParametersVerifyMode
+	serverName string
+	roots      *x509.CertPool
+	pins       [][]byte
+
+	// ハンドシェイクは /tls/sessions では別のゴルーチンで行われる
+	mu           sync.Mutex
+	verification *openapi.CertificateVerification
+}
+
+// setCertificateVerification は、verify_mode で指定された方法でサーバー証明書を検証するようにconfigを設定する。
+func (s Server) setCertificateVerification(config *utls.Config, payload openapi.TlsClientParameters) (*certificateVerifier, error) {
+	v := &certificateVerifier{
+		mode:       openapi.TlsClientParametersVerifyModeSystem,
+		serverName: payload.ServerName,
+		roots:      s.rootCAs(),
+	}
+	if payload.VerifyMode != nil {
+		v.mode = *payload.VerifyMode
+	}
+	if v.serverName == "" {
+		// server_name を送らない場合は、接続先のホスト名またはIPアドレスで検証する
+		host, _, err := mytls.DialTarget(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+		v.serverName = host
+	}
+	switch v.mode {
+	case openapi.TlsClientParametersVerifyModeSystem, openapi.TlsClientParametersVerifyModeInsecure:
+	case openapi.TlsClientParametersVerifyModeCustomRoots:
+		if payload.RootCertificates == nil {
+			return nil, errors.New("invalid payload: root_certificates is required for verify_mode custom_roots")
+		}
+		v.roots = x509.NewCertPool()
+		if !v.roots.AppendCertsFromPEM([]byte(*payload.RootCertificates)) {
+			return nil, errors.New("invalid payload: no certificates found in root_certificates")
+		}
+	case openapi.TlsClientParametersVerifyModePinnedSpki:
+		if payload.PinnedSpkiSha256 == nil || len(*payload.PinnedSpkiSha256) == 0 {
+			return nil, errors.New("invalid payload: pinned_spki_sha256 is required for verify_mode pinned_spki")
+		}
+		for _, pin := range *payload.PinnedSpkiSha256 {
+			hash, err := base64.StdEncoding.DecodeString(pin)
+			if err != nil || len(hash) != sha256.Size {
+				return nil, fmt.Errorf("invalid payload: pinned_spki_sha256 must be a base64 SHA-256 hash: %s", pin)
+			}
+			v.pins = append(v.pins, hash)
+		}
+	default:
+		return nil, fmt.Errorf("invalid payload: unsupported verify_mode: %s", v.mode)
+	}
+
+	config.InsecureSkipVerify = true
+	config.VerifyPeerCertificate = v.verifyPeerCertificate
+	return v, nil
+}
+
+// result は、証明書を検証した結果を返す。証明書を受け取っていない場合はnilを返す。
+func (v *certificateVerifier) result() *openapi.CertificateVerification {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.verification
+}
+
+/**
+ * verifyPeerCertificate は、証明書チェーンを検証し、検証方法に応じてハンドシェイクを続けるかどうかを決める
+ * @see https://datatracker.ietf.org/doc/html/rfc5280#section-6
+ */
+func (v *certificateVerifier) verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	result := &openapi.CertificateVerification{
+		Mode:           string(v.mode),
+		Certificates:   make([]openapi.X509Certificate, len(rawCerts)),
+		VerifiedChains: []openapi.VerifiedChain{},
+	}
+	var certs []*x509.Certificate
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		result.Certificates[i] = newX509Certificate(raw, cert, err)
+		if err == nil {
+			certs = append(certs, cert)
+		}
+	}
+
+	chains, chainErr := v.verifyChain(certs)
+	for _, chain := range chains {
+		verified := openapi.VerifiedChain{Certificates: make([]openapi.X509Certificate, len(chain))}
+		for i, cert := range chain {
+			verified.Certificates[i] = newX509Certificate(cert.Raw, cert, nil)
+		}
+		result.VerifiedChains = append(result.VerifiedChains, verified)
+	}
+
+	// err は、ハンドシェイクを中断する理由
+	var err error
+	switch v.mode {
+	case openapi.TlsClientParametersVerifyModePinnedSpki:
+		matched := v.pinMatched(certs)
+		result.PinMatched = &matched
+		if !matched {
+			err = errors.New("tls: no certificate matches pinned_spki_sha256")
+		}
+	case openapi.TlsClientParametersVerifyModeInsecure:
+		// 検証結果を返すだけで、ハンドシェイクは続ける
+	default:
+		err = chainErr
+	}
+	result.Verified = err == nil && (v.mode != openapi.TlsClientParametersVerifyModeInsecure || chainErr == nil)
+	if err != nil {
+		message := err.Error()
+		result.Error = &message
+	} else if chainErr != nil {
+		message := chainErr.Error()
+		result.Error = &message
+	}
+
+	v.mu.Lock()
+	v.verification = result
+	v.mu.Unlock()
+	if err != nil {
+		return &verificationError{err: err, result: result}
+	}
+	return nil
+}
+
+// verifyChain は、crypto/tls のクライアントと同じ条件で証明書チェーンと server_name を検証する
+func (v *certificateVerifier) verifyChain(certs []*x509.Certificate) ([][]*x509.Certificate, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("tls: no parsable certificates received")
+	}
+	if v.serverName == "" {
+		// DNSName が空の場合、x509 はホスト名を検証しない
+		return nil, errors.New("tls: no host name to verify the certificate against")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         v.roots,
+		CurrentTime:   time.Now(),
+		DNSName:       v.serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	chains, err := certs[0].Verify(opts)
+	if err != nil {
+		return nil, fmt.Errorf("tls: failed to verify certificate: %w", err)
+	}
+	return chains, nil
+}
+
+func (v *certificateVerifier) pinMatched(certs []*x509.Certificate) bool {
+	for _, cert := range certs {
+		hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, pin := range v.pins {
+			if string(hash[:]) == string(pin) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verificationError は、証明書の検証に失敗してハンドシェイクを中断したことを表し、
+// エラーレスポンスで返す検証結果を保持する。
+type verificationError struct {
+	err    error
+	result *openapi.CertificateVerification
+}
+
+func (e *verificationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *verificationError) Unwrap() error {
+	return e.err
+}
+
+func newX509Certificate(raw []byte, cert *x509.Certificate, parseErr error) openapi.X509Certificate {
+	fingerprint := sha256.Sum256(raw)
+	res := openapi.X509Certificate{FingerprintSha256: hex.EncodeToString(fingerprint[:])}
+	if parseErr != nil {
+		message := parseErr.Error()
+		res.ParseError = &message
+		return res
+	}
+	subject := cert.Subject.String()
+	issuer := cert.Issuer.String()
+	serial := fmt.Sprintf("0x%x", cert.SerialNumber)
+	notBefore := cert.NotBefore.Format(time.RFC3339)
+	notAfter := cert.NotAfter.Format(time.RFC3339)
+	dnsNames := cert.DNSNames
+	ipAddresses := ipStrings(cert.IPAddresses)
+	isCA := cert.IsCA
+	signatureAlgorithm := cert.SignatureAlgorithm.String()
+	publicKeyAlgorithm := cert.PublicKeyAlgorithm.String()
+	spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	spkiHash := base64.StdEncoding.EncodeToString(spki[:])
+	res.Subject = &subject
+	res.Issuer = &issuer
+	res.SerialNumber = &serial
+	res.NotBefore = &notBefore
+	res.NotAfter = &notAfter
+	res.DnsNames = &dnsNames
+	res.IpAddresses = &ipAddresses
+	res.IsCa = &isCA
+	res.SignatureAlgorithm = &signatureAlgorithm
+	res.PublicKeyAlgorithm = &publicKeyAlgorithm
+	res.SpkiSha256 = &spkiHash
+	return res
+}
+
+func ipStrings(ips []net.IP) []string {
+	res := make([]string, len(ips))
+	for i, ip := range ips {
+		res[i] = ip.String()
+	}
+	return res
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestPostTlsHandshakeVerifyMode(t *testing.T) {
+	e, ts := newTestServer(t)
+	cert := ts.Certificate()
+	rootPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(spki[:])
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	tests := []struct {
+		name         string
+		modify       func(*openapi.TlsClientParameters)
+		expectingErr bool
+		wantVerified bool
+	}{
+		{
+			name:         "正常系：verify_mode を省略するとシステムのルート証明書で検証する",
+			modify:       func(p *openapi.TlsClientParameters) {},
+			wantVerified: true,
+		},
+		{
+			name: "正常系：custom_roots で指定したルート証明書で検証する",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyModeCustomRoots
+				p.VerifyMode = &mode
+				p.RootCertificates = &rootPEM
+			},
+			wantVerified: true,
+		},
+		{
+			name: "正常系：pinned_spki で公開鍵のハッシュが一致する",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyModePinnedSpki
+				p.VerifyMode = &mode
+				p.PinnedSpkiSha256 = &[]string{otherPin, pin}
+			},
+			wantVerified: true,
+		},
+		{
+			name: "正常系：insecure では検証に失敗してもハンドシェイクを続ける",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyModeInsecure
+				p.VerifyMode = &mode
+				p.ServerName = "unknown.localhost"
+			},
+			wantVerified: false,
+		},
+		{
+			name: "正常系：server_name を省略すると接続先のIPアドレスで検証する",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyModeCustomRoots
+				p.VerifyMode = &mode
+				p.RootCertificates = &rootPEM
+				p.ServerName = ""
+			},
+			wantVerified: true,
+		},
+		{
+			name: "異常系：pinned_spki で公開鍵のハッシュが一致しない",
+			modify: func(p *openapi.TlsClientParameters) {
+				mode := openapi.TlsClientParametersVerifyModePinnedSpki
+				p.VerifyMode = &mode
+				p.PinnedSpkiSha256 = &[]string{otherPin}
+			},
+			expectingErr: true,
+		},
+		{
+			name: "異常系：system では server_name が証明書と一致しない",
+			modify: func(p *openapi.TlsClientParameters) {
+				p.ServerName = "unknown.localhost"
+			},
+			expectingErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testServerParameters(ts)
+			tt.modify(&params)
+
+			if tt.expectingErr {
+				var res openapi.ErrorResponse
+				if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusBadRequest {
+					t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+				}
+				v := res.CertificateVerification
+				if v == nil || v.Verified || v.Error == nil {
+					t.Fatalf("certificate_verification = %+v", v)
+				}
+				return
+			}
+
+			var res openapi.HandshakeResponse
+			if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", code, http.StatusOK)
+			}
+			v := res.CertificateVerification
+			if v == nil {
+				t.Fatal("certificate_verification is missing")
+			}
+			if v.Verified != tt.wantVerified {
+				t.Errorf("verified = %v, want %v (error: %v)", v.Verified, tt.wantVerified, v.Error)
+			}
+			if len(v.Certificates) != 1 || v.Certificates[0].SpkiSha256 == nil || *v.Certificates[0].SpkiSha256 != pin {
+				t.Errorf("certificates = %+v", v.Certificates)
+			}
+			if (len(v.VerifiedChains) > 0) != tt.wantVerified {
+				t.Errorf("verified_chains = %+v", v.VerifiedChains)
+			}
+		})
+	}
+
+	t.Run("異常系：server_name を省略した場合も接続先と一致しない証明書は拒否する", func(t *testing.T) {
+		for _, mode := range []openapi.TlsClientParametersVerifyMode{openapi.TlsClientParametersVerifyModeSystem, openapi.TlsClientParametersVerifyModeCustomRoots} {
+			params := testServerParameters(ts)
+			params.ServerName = ""
+			address := "127.0.0.2"
+			params.Address = &address
+			params.VerifyMode = &mode
+			params.RootCertificates = &rootPEM
+			config := &utls.Config{}
+			v, err := Server{TestServer: ts}.setCertificateVerification(config, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := config.VerifyPeerCertificate([][]byte{cert.Raw}, nil); err == nil {
+				t.Fatalf("%s: certificate for 127.0.0.1 was accepted for %s", mode, address)
+			}
+			if r := v.result(); r == nil || r.Verified {
+				t.Errorf("%s: certificate_verification = %+v", mode, r)
+			}
+		}
+	})
+
+	t.Run("異常系：pinned_spki_sha256 が SHA-256 ハッシュではない", func(t *testing.T) {
+		params := testServerParameters(ts)
+		mode := openapi.TlsClientParametersVerifyModePinnedSpki
+		params.VerifyMode = &mode
+		params.PinnedSpkiSha256 = &[]string{"not-a-hash"}
+		var res openapi.ErrorResponse
+		if code := doJSON(t, e, http.MethodPost, "/tls/handshake", params, &res); code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", code, http.StatusBadRequest)
+		}
+		if !strings.Contains(res.Message, "pinned_spki_sha256") {
+			t.Errorf("message = %q", res.Message)
+		}
+	})
+}
@@ -0,0 +1,553 @@
+package mytls
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"fmt"
+	"net"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/mytls/internal/crypto"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
+	"github.com/refraction-networking/utls/server/mytls/internal/record"
+)
+
+// 鍵スケジュールで導出した値の名前です。
+// uTLSのKeyScheduleObserverが報告する名前と同じにしているため、両者の結果をそのまま比較できます。
+const (
+	SecretShared                    = "shared_secret"
+	SecretEarly                     = "early_secret"
+	SecretHandshake                 = "handshake_secret"
+	SecretMaster                    = "master_secret"
+	SecretClientHandshakeTraffic    = "client_handshake_traffic_secret"
+	SecretClientHandshakeWriteKey   = "client_handshake_write_key"
+	SecretClientHandshakeWriteIV    = "client_handshake_write_iv"
+	SecretServerHandshakeTraffic    = "server_handshake_traffic_secret"
+	SecretServerHandshakeWriteKey   = "server_handshake_write_key"
+	SecretServerHandshakeWriteIV    = "server_handshake_write_iv"
+	SecretServerFinishedKey         = "server_finished_key"
+	SecretClientFinishedKey         = "client_finished_key"
+	SecretClientApplicationTraffic  = "client_application_traffic_secret_0"
+	SecretClientApplicationWriteKey = "client_application_write_key"
+	SecretClientApplicationWriteIV  = "client_application_write_iv"
+	SecretServerApplicationTraffic  = "server_application_traffic_secret_0"
+	SecretServerApplicationWriteKey = "server_application_write_key"
+	SecretServerApplicationWriteIV  = "server_application_write_iv"
+	SecretExporterMaster            = "exporter_master_secret"
+	SecretResumptionMaster          = "resumption_master_secret"
+)
+
+// Secret は、鍵スケジュールで導出した値の1つです。
+type Secret struct {
+	Name  string
+	Value []byte
+}
+
+// Message は、サーバーから受信したハンドシェイクメッセージです。
+// Rawはハンドシェイクヘッダを含む平文のバイト列で、暗号化されていたメッセージは復号後の値です。
+type Message struct {
+	Type common.HandshakeType
+	Raw  []byte
+}
+
+// Result は、独自実装でのハンドシェイクの結果です。
+// ハンドシェイクが途中で失敗した場合も、それまでに送受信した内容が入ります。
+type Result struct {
+	// ClientHelloRecord は、最初に送信したClientHelloのレコードです。
+	ClientHelloRecord []byte
+	// Sent は、送信したすべてのレコードを連結したバイト列です。
+	Sent []byte
+	// Received は、受信したすべてのレコードを連結したバイト列です。
+	Received []byte
+	// ServerFlight は、サーバーから受信したハンドシェイクメッセージです。
+	ServerFlight []Message
+
+	HelloRetryRequest bool
+	CipherSuite       common.CipherSuite
+	Group             common.SupportedGroupsType
+	Secrets           []Secret
+}
+
+// Secret は、nameの値を返します。導出されていない場合はnilを返します。
+func (r *Result) Secret(name string) []byte {
+	for _, s := range r.Secrets {
+		if s.Name == name {
+			return s.Value
+		}
+	}
+	return nil
+}
+
+// client は、TLS 1.3クライアントのハンドシェイクの状態です。
+// PSK、0-RTTやクライアント証明書には対応せず、(EC)DHEのフルハンドシェイクのみを行います。
+// @see https://datatracker.ietf.org/doc/html/rfc8446#section-2
+type client struct {
+	conn   net.Conn
+	result *Result
+
+	hello     *handshake.ClientHello
+	keyShares map[common.SupportedGroupsType]*ecdh.PrivateKey
+
+	suite    *crypto.CipherSuiteParams
+	schedule *crypto.KeySchedule
+
+	// transcript は、Transcript-Hashの対象となるハンドシェイクメッセージを連結したものです。
+	transcript []byte
+
+	readCipher  *record.Cipher
+	writeCipher *record.Cipher
+
+	records *record.Reader
+	// handshakeBuf は、受信済みでまだ取り出していないハンドシェイクメッセージです。
+	handshakeBuf record.HandshakeBuffer
+}
+
+func (c *client) handshake() error {
+	if err := c.sendHandshake(common.ClientHello, c.hello.Marshal()); err != nil {
+		return err
+	}
+	c.result.ClientHelloRecord = bytes.Clone(c.result.Sent)
+
+	serverHello, err := c.readServerHello()
+	if err != nil {
+		return err
+	}
+	if serverHello.IsHelloRetryRequest() {
+		if serverHello, err = c.retryClientHello(serverHello); err != nil {
+			return err
+		}
+	}
+
+	if err := c.establishHandshakeKeys(serverHello); err != nil {
+		return err
+	}
+	certificateRequest, err := c.readServerParameters()
+	if err != nil {
+		return err
+	}
+	if err := c.readServerCertificate(); err != nil {
+		return err
+	}
+	if err := c.readServerFinished(); err != nil {
+		return err
+	}
+	return c.sendClientFinished(certificateRequest)
+}
+
+// readServerHello は、ServerHello (またはHelloRetryRequest) を受信し、
+// こちらが提示した内容と矛盾しないことを確認します。
+func (c *client) readServerHello() (*handshake.ServerHello, error) {
+	msg, err := c.readHandshake(common.ServerHello)
+	if err != nil {
+		return nil, err
+	}
+	serverHello := &handshake.ServerHello{}
+	if err := serverHello.UnMarshal(msg.Body); err != nil {
+		return nil, err
+	}
+
+	version, err := serverHello.SelectedVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version != common.TLS_VERSION_1_3 {
+		return nil, fmt.Errorf("server selected unsupported version: %s", version)
+	}
+	if !bytes.Equal(serverHello.SessionID, c.hello.LegacySessionID) {
+		return nil, fmt.Errorf("server echoed a different legacy_session_id")
+	}
+	if !containsCipherSuite(c.hello.CipherSuites, serverHello.CipherSuite) {
+		return nil, fmt.Errorf("server selected a cipher suite that was not offered: %s", serverHello.CipherSuite)
+	}
+	if c.suite != nil && c.suite.ID != serverHello.CipherSuite {
+		return nil, fmt.Errorf("server changed the cipher suite after HelloRetryRequest: %s", serverHello.CipherSuite)
+	}
+	suite, err := crypto.LookupCipherSuite(serverHello.CipherSuite)
+	if err != nil {
+		return nil, err
+	}
+	c.suite = suite
+	return serverHello, nil
+}
+
+/**
+ * retryClientHello は、HelloRetryRequestで指定されたグループの鍵を生成し、
+ * 2つ目のClientHelloを送信してServerHelloを受信します。
+ * Transcript-Hashでは、最初のClientHelloは message_hash メッセージに置き換えられます。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.1.4
+ */
+func (c *client) retryClientHello(hrr *handshake.ServerHello) (*handshake.ServerHello, error) {
+	c.result.HelloRetryRequest = true
+
+	group, _, err := hrr.KeyShare()
+	if err != nil {
+		return nil, err
+	}
+	if !containsGroup(c.hello.Extensions, group) {
+		return nil, fmt.Errorf("HelloRetryRequest selected a group that was not offered: %s", group)
+	}
+	if _, ok := c.keyShares[group]; ok {
+		return nil, fmt.Errorf("HelloRetryRequest selected a group that already has a key share: %s", group)
+	}
+	priv, err := crypto.GenerateKeyShare(group)
+	if err != nil {
+		return nil, err
+	}
+	c.keyShares = map[common.SupportedGroupsType]*ecdh.PrivateKey{group: priv}
+
+	// Transcript-Hash(ClientHello1, HelloRetryRequest, ...) =
+	//   Hash(message_hash || 00 00 Hash.length || Hash(ClientHello1) || HelloRetryRequest || ...)
+	hrrRaw := c.result.ServerFlight[len(c.result.ServerFlight)-1].Raw
+	h := c.suite.Hash()
+	h.Write(c.transcript[:len(c.transcript)-len(hrrRaw)])
+	c.transcript = handshake.NewHandshake(common.MessageHash, h.Sum(nil)).Marshal()
+	c.transcript = append(c.transcript, hrrRaw...)
+
+	// key_shareを選択されたグループのものに置き換え、cookieがあればそのまま返す
+	var exts []extensions.Extension
+	for _, ext := range c.hello.Extensions {
+		if ext.Type == common.KeyShareExtensionType {
+			ext = *extensions.NewKeyShareExtension([]extensions.KeyShareEntry{
+				{Group: uint16(group), KeyExchange: priv.PublicKey().Bytes()},
+			})
+		}
+		exts = append(exts, ext)
+	}
+	if cookie := hrr.Cookie(); cookie != nil {
+		exts = append(exts, extensions.Extension{Type: common.CookieExtensionType, Payload: cookie})
+	}
+	c.hello.Extensions = exts
+
+	if err := c.sendHandshake(common.ClientHello, c.hello.Marshal()); err != nil {
+		return nil, err
+	}
+	serverHello, err := c.readServerHello()
+	if err != nil {
+		return nil, err
+	}
+	if serverHello.IsHelloRetryRequest() {
+		return nil, fmt.Errorf("received a second HelloRetryRequest")
+	}
+	return serverHello, nil
+}
+
+/**
+ * establishHandshakeKeys は、(EC)DHEの共有秘密からハンドシェイク用のトラフィック鍵を導出し、
+ * 以降のレコードを暗号化・復号するようにします。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-7.1
+ */
+func (c *client) establishHandshakeKeys(serverHello *handshake.ServerHello) error {
+	group, keyExchange, err := serverHello.KeyShare()
+	if err != nil {
+		return err
+	}
+	priv, ok := c.keyShares[group]
+	if !ok {
+		return fmt.Errorf("server selected a group without a key share: %s", group)
+	}
+	sharedSecret, err := crypto.SharedSecret(priv, keyExchange)
+	if err != nil {
+		return err
+	}
+	c.result.CipherSuite = c.suite.ID
+	c.result.Group = group
+
+	c.schedule = crypto.NewKeySchedule(c.suite.Hash)
+	c.schedule.DeriveHandshakeSecrets(sharedSecret, c.transcriptHash())
+	c.addSecret(SecretShared, sharedSecret)
+	c.addSecret(SecretEarly, c.schedule.EarlySecret)
+	c.addSecret(SecretHandshake, c.schedule.HandshakeSecret)
+
+	c.writeCipher, err = c.newCipher(c.schedule.ClientHandshakeTrafficSecret,
+		SecretClientHandshakeTraffic, SecretClientHandshakeWriteKey, SecretClientHandshakeWriteIV)
+	if err != nil {
+		return err
+	}
+	c.readCipher, err = c.newCipher(c.schedule.ServerHandshakeTrafficSecret,
+		SecretServerHandshakeTraffic, SecretServerHandshakeWriteKey, SecretServerHandshakeWriteIV)
+	if err != nil {
+		return err
+	}
+
+	// 鍵の切り替えはレコードの境界で行われなければならない
+	// @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.1
+	if c.handshakeBuf.Len() > 0 {
+		return fmt.Errorf("unexpected handshake data after ServerHello")
+	}
+	return nil
+}
+
+// readServerParameters は、EncryptedExtensionsと、送られてきた場合はCertificateRequestを受信します。
+// CertificateRequestを受信した場合はそのメッセージを返します。
+func (c *client) readServerParameters() (*handshake.CertificateRequest, error) {
+	msg, err := c.readHandshake(common.EncryptedExtensions)
+	if err != nil {
+		return nil, err
+	}
+	ee := &handshake.EncryptedExtensions{}
+	if err := ee.UnMarshal(msg.Body); err != nil {
+		return nil, err
+	}
+
+	msg, err = c.peekHandshake()
+	if err != nil {
+		return nil, err
+	}
+	if msg.HandshakeType != common.CertificateRequest {
+		return nil, nil
+	}
+	if _, err := c.readHandshake(common.CertificateRequest); err != nil {
+		return nil, err
+	}
+	certificateRequest := &handshake.CertificateRequest{}
+	if err := certificateRequest.UnMarshal(msg.Body); err != nil {
+		return nil, err
+	}
+	return certificateRequest, nil
+}
+
+/**
+ * readServerCertificate は、CertificateとCertificateVerifyを受信し、
+ * サーバーの証明書の公開鍵で署名を検証します。
+ * 証明書チェーンの検証 (信頼されたルートまでの検証やホスト名の確認) は行いません。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.2
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.3
+ */
+func (c *client) readServerCertificate() error {
+	msg, err := c.readHandshake(common.Certificate)
+	if err != nil {
+		return err
+	}
+	certificate := &handshake.Certificate{}
+	if err := certificate.UnMarshal(msg.Body); err != nil {
+		return err
+	}
+	certs, err := certificate.ParseCertificates()
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("server sent an empty certificate_list")
+	}
+
+	// 署名対象は CertificateVerify の直前までの Transcript-Hash
+	transcriptHash := c.transcriptHash()
+	msg, err = c.readHandshake(common.CertificateVerify)
+	if err != nil {
+		return err
+	}
+	certificateVerify := &handshake.CertificateVerify{}
+	if err := certificateVerify.UnMarshal(msg.Body); err != nil {
+		return err
+	}
+	content := crypto.CertificateVerifyContent("TLS 1.3, server CertificateVerify", transcriptHash)
+	return crypto.VerifySignature(certs[0], certificateVerify.Algorithm, content, certificateVerify.Signature)
+}
+
+/**
+ * readServerFinished は、サーバーのFinishedを検証し、アプリケーションデータ用の鍵を導出します。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.4
+ */
+func (c *client) readServerFinished() error {
+	expected := c.schedule.VerifyData(c.schedule.ServerHandshakeTrafficSecret, c.transcriptHash())
+	c.addSecret(SecretServerFinishedKey, c.schedule.FinishedKey(c.schedule.ServerHandshakeTrafficSecret))
+
+	msg, err := c.readHandshake(common.Finished)
+	if err != nil {
+		return err
+	}
+	finished := &handshake.Finished{}
+	if err := finished.UnMarshal(msg.Body); err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, finished.VerifyData) {
+		return fmt.Errorf("invalid server Finished verify_data")
+	}
+
+	c.schedule.DeriveApplicationSecrets(c.transcriptHash())
+	c.addSecret(SecretMaster, c.schedule.MasterSecret)
+	if _, err := c.newCipher(c.schedule.ClientApplicationTrafficSecret,
+		SecretClientApplicationTraffic, SecretClientApplicationWriteKey, SecretClientApplicationWriteIV); err != nil {
+		return err
+	}
+	if _, err := c.newCipher(c.schedule.ServerApplicationTrafficSecret,
+		SecretServerApplicationTraffic, SecretServerApplicationWriteKey, SecretServerApplicationWriteIV); err != nil {
+		return err
+	}
+	c.addSecret(SecretExporterMaster, c.schedule.ExporterMasterSecret)
+	return nil
+}
+
+/**
+ * sendClientFinished は、CertificateRequestを受信していた場合は空のCertificateを送信し、
+ * その後クライアントのFinishedを送信します。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.2
+ */
+func (c *client) sendClientFinished(certificateRequest *handshake.CertificateRequest) error {
+	if certificateRequest != nil {
+		certificate := &handshake.Certificate{RequestContext: certificateRequest.RequestContext}
+		if err := c.sendHandshake(common.Certificate, certificate.Marshal()); err != nil {
+			return err
+		}
+	}
+
+	finished := &handshake.Finished{
+		VerifyData: c.schedule.VerifyData(c.schedule.ClientHandshakeTrafficSecret, c.transcriptHash()),
+	}
+	c.addSecret(SecretClientFinishedKey, c.schedule.FinishedKey(c.schedule.ClientHandshakeTrafficSecret))
+	if err := c.sendHandshake(common.Finished, finished.Marshal()); err != nil {
+		return err
+	}
+
+	c.schedule.DeriveResumptionMasterSecret(c.transcriptHash())
+	c.addSecret(SecretResumptionMaster, c.schedule.ResumptionMasterSecret)
+	return nil
+}
+
+// newCipher は、トラフィックシークレットから鍵とIVを導出してレコード保護の状態を作り、
+// 導出した値を結果に記録します。
+func (c *client) newCipher(secret []byte, secretName, keyName, ivName string) (*record.Cipher, error) {
+	key, iv := c.schedule.TrafficKey(secret, c.suite.KeyLength)
+	c.addSecret(secretName, secret)
+	c.addSecret(keyName, key)
+	c.addSecret(ivName, iv)
+	aead, err := c.suite.NewAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return record.NewCipher(aead, iv), nil
+}
+
+func (c *client) addSecret(name string, value []byte) {
+	c.result.Secrets = append(c.result.Secrets, Secret{Name: name, Value: bytes.Clone(value)})
+}
+
+func (c *client) transcriptHash() []byte {
+	h := c.suite.Hash()
+	h.Write(c.transcript)
+	return h.Sum(nil)
+}
+
+// sendHandshake は、ハンドシェイクメッセージをTranscriptに加えて送信します。
+// ハンドシェイク用の鍵が導出済みであれば暗号化して送信します。
+func (c *client) sendHandshake(handshakeType common.HandshakeType, body []byte) error {
+	msg := handshake.NewHandshake(handshakeType, body)
+	if msg == nil {
+		return fmt.Errorf("%s message too large: %d bytes", handshakeType, len(body))
+	}
+	raw := msg.Marshal()
+	c.transcript = append(c.transcript, raw...)
+
+	var r *record.Record
+	var err error
+	if c.writeCipher != nil {
+		r, err = c.writeCipher.Encrypt(common.Handshake, raw)
+	} else {
+		r, err = record.NewTLSRecord(common.Handshake, raw)
+	}
+	if err != nil {
+		return err
+	}
+	out := r.Marshal()
+	c.result.Sent = append(c.result.Sent, out...)
+	if _, err := c.conn.Write(out); err != nil {
+		return fmt.Errorf("conn.Write error: %w", err)
+	}
+	return nil
+}
+
+// readHandshake は、次のハンドシェイクメッセージを受信してTranscriptに加えます。
+// メッセージの種類がexpectedでない場合はエラーになります。
+func (c *client) readHandshake(expected common.HandshakeType) (*handshake.Handshake, error) {
+	msg, err := c.peekHandshake()
+	if err != nil {
+		return nil, err
+	}
+	if msg.HandshakeType != expected {
+		return nil, fmt.Errorf("unexpected handshake message: expected %s, got %s", expected, msg.HandshakeType)
+	}
+	raw := msg.Marshal()
+	c.handshakeBuf.Discard(len(raw))
+	c.transcript = append(c.transcript, raw...)
+	c.result.ServerFlight = append(c.result.ServerFlight, Message{Type: msg.HandshakeType, Raw: raw})
+	return msg, nil
+}
+
+// peekHandshake は、次のハンドシェイクメッセージを取り出さずに返します。
+// メッセージ全体がそろうまでレコードを読み込みます。
+func (c *client) peekHandshake() (*handshake.Handshake, error) {
+	for {
+		raw, err := c.handshakeBuf.Next()
+		if err != nil {
+			return nil, err
+		}
+		if raw != nil {
+			msg, _, err := handshake.ParseHandshake(raw)
+			return msg, err
+		}
+		if err := c.readRecord(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readRecord は、レコードを1つ読み込み、ハンドシェイクメッセージであればhandshakeBufに追加します。
+// 互換性のために送られるChangeCipherSpecは無視し、Alertはエラーとして返します。
+func (c *client) readRecord() error {
+	r, err := c.records.ReadRecord()
+	if err != nil {
+		return fmt.Errorf("failed to read record: %w", err)
+	}
+	c.result.Received = append(c.result.Received, r.Marshal()...)
+
+	contentType, data := r.Type, r.Payload
+	if contentType == common.ChangeCipherSpec {
+		// @see https://datatracker.ietf.org/doc/html/rfc8446#appendix-D.4
+		return nil
+	}
+	// 鍵の導出後も、サーバーが平文のAlertを送ってくることがある
+	if c.readCipher != nil && contentType != common.Alert {
+		if contentType, data, err = c.readCipher.Decrypt(r); err != nil {
+			return err
+		}
+	}
+
+	switch contentType {
+	case common.Handshake:
+		c.handshakeBuf.Write(data)
+		return nil
+	case common.Alert:
+		if len(data) != 2 {
+			return fmt.Errorf("invalid alert length: %d", len(data))
+		}
+		return fmt.Errorf("received alert: %s %s", common.AlertLevel(data[0]), common.AlertDescription(data[1]))
+	default:
+		return fmt.Errorf("unexpected record type during handshake: 0x%02x", byte(contentType))
+	}
+}
+
+func containsCipherSuite(suites []common.CipherSuite, cs common.CipherSuite) bool {
+	for _, s := range suites {
+		if s == cs {
+			return true
+		}
+	}
+	return false
+}
+
+// containsGroup は、ClientHelloのsupported_groups拡張にgroupが含まれているかどうかを返します。
+func containsGroup(exts []extensions.Extension, group common.SupportedGroupsType) bool {
+	for _, ext := range exts {
+		if ext.Type != common.SupportedGroupsExtensionType || len(ext.Payload) < 2 {
+			continue
+		}
+		for p := ext.Payload[2:]; len(p) >= 2; p = p[2:] {
+			if common.SupportedGroupsType(common.DecodeBytesToUint16(p)) == group {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -45,10 +45,48 @@ func (v TLSVersion) String() string {
 type HandshakeType uint8
 
 const (
-	ClientHello HandshakeType = 0x01
-	ServerHello HandshakeType = 0x02
+	ClientHello         HandshakeType = 0x01
+	ServerHello         HandshakeType = 0x02
+	NewSessionTicket    HandshakeType = 0x04
+	EndOfEarlyData      HandshakeType = 0x05
+	EncryptedExtensions HandshakeType = 0x08
+	Certificate         HandshakeType = 0x0b
+	CertificateRequest  HandshakeType = 0x0d
+	CertificateVerify   HandshakeType = 0x0f
+	Finished            HandshakeType = 0x14
+	KeyUpdate           HandshakeType = 0x18
+	MessageHash         HandshakeType = 0xfe
 )
 
+func (ht HandshakeType) String() string {
+	switch ht {
+	case ClientHello:
+		return "ClientHello"
+	case ServerHello:
+		return "ServerHello"
+	case NewSessionTicket:
+		return "NewSessionTicket"
+	case EndOfEarlyData:
+		return "EndOfEarlyData"
+	case EncryptedExtensions:
+		return "EncryptedExtensions"
+	case Certificate:
+		return "Certificate"
+	case CertificateRequest:
+		return "CertificateRequest"
+	case CertificateVerify:
+		return "CertificateVerify"
+	case Finished:
+		return "Finished"
+	case KeyUpdate:
+		return "KeyUpdate"
+	case MessageHash:
+		return "MessageHash"
+	default:
+		return fmt.Sprintf("Unknown HandshakeType: 0x%02x", uint8(ht))
+	}
+}
+
 /**
  * @see https://datatracker.ietf.org/doc/html/rfc8446#appendix-B.4
  */
@@ -90,7 +128,17 @@ const (
 	SignatureAlgorithmsExtensionType ExtensionType = 0x000d
 	KeyShareExtensionType            ExtensionType = 0x0033
 	SupportedVersionsExtensionType   ExtensionType = 0x002b
+	CookieExtensionType              ExtensionType = 0x002c
 	PSKKeyExchangeModesExtensionType ExtensionType = 0x002d
+
+	// 以下はサーバーがEncryptedExtensions、CertificateRequest、Certificateで送ってくる拡張です。
+	StatusRequestExtensionType              ExtensionType = 0x0005
+	ALPNExtensionType                       ExtensionType = 0x0010
+	SignedCertificateTimestampExtensionType ExtensionType = 0x0012
+	EarlyDataExtensionType                  ExtensionType = 0x002a
+	CertificateAuthoritiesExtensionType     ExtensionType = 0x002f
+	OIDFiltersExtensionType                 ExtensionType = 0x0030
+	SignatureAlgorithmsCertExtensionType    ExtensionType = 0x0032
 )
 
 func (et ExtensionType) String() string {
@@ -105,8 +153,24 @@ func (et ExtensionType) String() string {
 		return "KeyShare"
 	case SupportedVersionsExtensionType:
 		return "SupportedVersions"
+	case CookieExtensionType:
+		return "Cookie"
 	case PSKKeyExchangeModesExtensionType:
 		return "PSKKeyExchangeModes"
+	case StatusRequestExtensionType:
+		return "StatusRequest"
+	case ALPNExtensionType:
+		return "ALPN"
+	case SignedCertificateTimestampExtensionType:
+		return "SignedCertificateTimestamp"
+	case EarlyDataExtensionType:
+		return "EarlyData"
+	case CertificateAuthoritiesExtensionType:
+		return "CertificateAuthorities"
+	case OIDFiltersExtensionType:
+		return "OIDFilters"
+	case SignatureAlgorithmsCertExtensionType:
+		return "SignatureAlgorithmsCert"
 	default:
 		return fmt.Sprintf("Unknown ExtensionType: 0x%04x", uint16(et))
 	}
@@ -118,14 +182,143 @@ func (et ExtensionType) String() string {
 type SupportedGroupsType uint16
 
 const (
-	X25519 SupportedGroupsType = 0x001d
+	Secp256r1 SupportedGroupsType = 0x0017
+	Secp384r1 SupportedGroupsType = 0x0018
+	X25519    SupportedGroupsType = 0x001d
 )
 
+func (g SupportedGroupsType) String() string {
+	switch g {
+	case Secp256r1:
+		return "secp256r1"
+	case Secp384r1:
+		return "secp384r1"
+	case X25519:
+		return "x25519"
+	default:
+		return fmt.Sprintf("Unknown SupportedGroup: 0x%04x", uint16(g))
+	}
+}
+
 /**
  * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.2.3
  */
 type SignatureAlgorithmType uint16
 
 const (
-	Ed25519 SignatureAlgorithmType = 0x0807
+	RsaPkcs1Sha256       SignatureAlgorithmType = 0x0401
+	EcdsaSecp256r1Sha256 SignatureAlgorithmType = 0x0403
+	RsaPkcs1Sha384       SignatureAlgorithmType = 0x0501
+	EcdsaSecp384r1Sha384 SignatureAlgorithmType = 0x0503
+	RsaPkcs1Sha512       SignatureAlgorithmType = 0x0601
+	EcdsaSecp521r1Sha512 SignatureAlgorithmType = 0x0603
+	RsaPssRsaeSha256     SignatureAlgorithmType = 0x0804
+	RsaPssRsaeSha384     SignatureAlgorithmType = 0x0805
+	RsaPssRsaeSha512     SignatureAlgorithmType = 0x0806
+	Ed25519              SignatureAlgorithmType = 0x0807
 )
+
+func (sa SignatureAlgorithmType) String() string {
+	switch sa {
+	case RsaPkcs1Sha256:
+		return "rsa_pkcs1_sha256"
+	case EcdsaSecp256r1Sha256:
+		return "ecdsa_secp256r1_sha256"
+	case RsaPkcs1Sha384:
+		return "rsa_pkcs1_sha384"
+	case EcdsaSecp384r1Sha384:
+		return "ecdsa_secp384r1_sha384"
+	case RsaPkcs1Sha512:
+		return "rsa_pkcs1_sha512"
+	case EcdsaSecp521r1Sha512:
+		return "ecdsa_secp521r1_sha512"
+	case RsaPssRsaeSha256:
+		return "rsa_pss_rsae_sha256"
+	case RsaPssRsaeSha384:
+		return "rsa_pss_rsae_sha384"
+	case RsaPssRsaeSha512:
+		return "rsa_pss_rsae_sha512"
+	case Ed25519:
+		return "ed25519"
+	default:
+		return fmt.Sprintf("Unknown SignatureAlgorithm: 0x%04x", uint16(sa))
+	}
+}
+
+/**
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-6
+ */
+type AlertLevel uint8
+
+const (
+	AlertLevelWarning AlertLevel = 0x01
+	AlertLevelFatal   AlertLevel = 0x02
+)
+
+func (al AlertLevel) String() string {
+	switch al {
+	case AlertLevelWarning:
+		return "warning"
+	case AlertLevelFatal:
+		return "fatal"
+	default:
+		return fmt.Sprintf("Unknown AlertLevel: %d", uint8(al))
+	}
+}
+
+type AlertDescription uint8
+
+const (
+	AlertCloseNotify           AlertDescription = 0
+	AlertUnexpectedMessage     AlertDescription = 10
+	AlertBadRecordMAC          AlertDescription = 20
+	AlertHandshakeFailure      AlertDescription = 40
+	AlertBadCertificate        AlertDescription = 42
+	AlertIllegalParameter      AlertDescription = 47
+	AlertDecodeError           AlertDescription = 50
+	AlertDecryptError          AlertDescription = 51
+	AlertProtocolVersion       AlertDescription = 70
+	AlertInternalError         AlertDescription = 80
+	AlertMissingExtension      AlertDescription = 109
+	AlertUnsupportedExtension  AlertDescription = 110
+	AlertUnrecognizedName      AlertDescription = 112
+	AlertCertificateRequired   AlertDescription = 116
+	AlertNoApplicationProtocol AlertDescription = 120
+)
+
+func (ad AlertDescription) String() string {
+	switch ad {
+	case AlertCloseNotify:
+		return "close_notify"
+	case AlertUnexpectedMessage:
+		return "unexpected_message"
+	case AlertBadRecordMAC:
+		return "bad_record_mac"
+	case AlertHandshakeFailure:
+		return "handshake_failure"
+	case AlertBadCertificate:
+		return "bad_certificate"
+	case AlertIllegalParameter:
+		return "illegal_parameter"
+	case AlertDecodeError:
+		return "decode_error"
+	case AlertDecryptError:
+		return "decrypt_error"
+	case AlertProtocolVersion:
+		return "protocol_version"
+	case AlertInternalError:
+		return "internal_error"
+	case AlertMissingExtension:
+		return "missing_extension"
+	case AlertUnsupportedExtension:
+		return "unsupported_extension"
+	case AlertUnrecognizedName:
+		return "unrecognized_name"
+	case AlertCertificateRequired:
+		return "certificate_required"
+	case AlertNoApplicationProtocol:
+		return "no_application_protocol"
+	default:
+		return fmt.Sprintf("Unknown AlertDescription: %d", uint8(ad))
+	}
+}
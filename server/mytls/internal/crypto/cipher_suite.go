@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// CipherSuiteParams は、TLS 1.3のCipher Suiteが使うハッシュ関数とAEADの組み合わせです。
+// @see https://datatracker.ietf.org/doc/html/rfc8446#appendix-B.4
+type CipherSuiteParams struct {
+	ID        common.CipherSuite
+	KeyLength int
+	Hash      func() hash.Hash
+	NewAEAD   func(key []byte) (cipher.AEAD, error)
+}
+
+// LookupCipherSuite は、実装しているCipher Suiteのパラメータを返します。
+// CCMを使うCipher Suiteには対応していません。
+func LookupCipherSuite(cs common.CipherSuite) (*CipherSuiteParams, error) {
+	switch cs {
+	case common.TLS_AES_128_GCM_SHA256:
+		return &CipherSuiteParams{ID: cs, KeyLength: 16, Hash: sha256.New, NewAEAD: newAESGCM}, nil
+	case common.TLS_AES_256_GCM_SHA384:
+		return &CipherSuiteParams{ID: cs, KeyLength: 32, Hash: sha512.New384, NewAEAD: newAESGCM}, nil
+	case common.TLS_CHACHA20_POLY1305_SHA256:
+		return &CipherSuiteParams{ID: cs, KeyLength: chacha20poly1305.KeySize, Hash: sha256.New, NewAEAD: chacha20poly1305.New}, nil
+	default:
+		return nil, fmt.Errorf("unsupported cipher suite: %s", cs)
+	}
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
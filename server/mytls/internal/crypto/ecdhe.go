@@ -0,0 +1,43 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+// curveForGroup は、supported_groupsの値に対応する楕円曲線を返します。
+func curveForGroup(group common.SupportedGroupsType) (ecdh.Curve, error) {
+	switch group {
+	case common.X25519:
+		return ecdh.X25519(), nil
+	case common.Secp256r1:
+		return ecdh.P256(), nil
+	case common.Secp384r1:
+		return ecdh.P384(), nil
+	default:
+		return nil, fmt.Errorf("unsupported key share group: %s", group)
+	}
+}
+
+// GenerateKeyShare は、groupの鍵ペアを生成します。
+// 公開鍵 (PublicKey().Bytes()) はKeyShareEntryのkey_exchangeにそのまま使える形式です。
+// @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.2.8.2
+func GenerateKeyShare(group common.SupportedGroupsType) (*ecdh.PrivateKey, error) {
+	curve, err := curveForGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	return curve.GenerateKey(rand.Reader)
+}
+
+// SharedSecret は、自分の秘密鍵とサーバーのkey_exchangeから(EC)DHEの共有秘密を計算します。
+func SharedSecret(priv *ecdh.PrivateKey, peerKeyExchange []byte) ([]byte, error) {
+	peer, err := priv.Curve().NewPublicKey(peerKeyExchange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server key share: %w", err)
+	}
+	return priv.ECDH(peer)
+}
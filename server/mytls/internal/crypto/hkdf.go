@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"hash"
+)
+
+/**
+ * HKDF-Extract(salt, IKM) = HMAC-Hash(salt, IKM)
+ * @see https://datatracker.ietf.org/doc/html/rfc5869#section-2.2
+ */
+func HKDFExtract(newHash func() hash.Hash, salt, ikm []byte) []byte {
+	if salt == nil {
+		salt = make([]byte, newHash().Size())
+	}
+	mac := hmac.New(newHash, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+/**
+ * HKDF-Expand(PRK, info, L)
+ *   T(0) = empty string
+ *   T(i) = HMAC-Hash(PRK, T(i-1) | info | i)
+ * @see https://datatracker.ietf.org/doc/html/rfc5869#section-2.3
+ */
+func HKDFExpand(newHash func() hash.Hash, prk, info []byte, length int) []byte {
+	var result, prev []byte
+	for i := byte(1); len(result) < length; i++ {
+		mac := hmac.New(newHash, prk)
+		mac.Write(prev)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		prev = mac.Sum(nil)
+		result = append(result, prev...)
+	}
+	return result[:length]
+}
+
+/**
+ * HKDF-Expand-Label(Secret, Label, Context, Length) =
+ *   HKDF-Expand(Secret, HkdfLabel, Length)
+ *
+ * struct {
+ *   uint16 length = Length;
+ *   opaque label<7..255> = "tls13 " + Label;
+ *   opaque context<0..255> = Context;
+ * } HkdfLabel;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-7.1
+ */
+func HKDFExpandLabel(newHash func() hash.Hash, secret []byte, label string, context []byte, length int) []byte {
+	fullLabel := "tls13 " + label
+	hkdfLabel := make([]byte, 2, 2+1+len(fullLabel)+1+len(context))
+	binary.BigEndian.PutUint16(hkdfLabel, uint16(length))
+	hkdfLabel = append(hkdfLabel, byte(len(fullLabel)))
+	hkdfLabel = append(hkdfLabel, fullLabel...)
+	hkdfLabel = append(hkdfLabel, byte(len(context)))
+	hkdfLabel = append(hkdfLabel, context...)
+	return HKDFExpand(newHash, secret, hkdfLabel, length)
+}
+
+/**
+ * Derive-Secret(Secret, Label, Messages) =
+ *   HKDF-Expand-Label(Secret, Label, Transcript-Hash(Messages), Hash.length)
+ * transcriptHashにはTranscript-Hash(Messages)を計算済みの値を渡します。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-7.1
+ */
+func DeriveSecret(newHash func() hash.Hash, secret []byte, label string, transcriptHash []byte) []byte {
+	return HKDFExpandLabel(newHash, secret, label, transcriptHash, newHash().Size())
+}
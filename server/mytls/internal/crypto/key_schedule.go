@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// KeySchedule は、TLS 1.3の鍵スケジュールで導出される値を保持します。
+// PSKを使わない (EC)DHEのみのフルハンドシェイクを対象とします。
+// @see https://datatracker.ietf.org/doc/html/rfc8446#section-7.1
+//
+//	          0
+//	          |
+//	          v
+//	0 ->  HKDF-Extract = Early Secret
+//	          |
+//	    Derive-Secret(., "derived", "")
+//	          |
+//	          v
+//	(EC)DHE -> HKDF-Extract = Handshake Secret
+//	          |
+//	          +--> Derive-Secret(., "c hs traffic", ClientHello...ServerHello)
+//	          +--> Derive-Secret(., "s hs traffic", ClientHello...ServerHello)
+//	          |
+//	    Derive-Secret(., "derived", "")
+//	          |
+//	          v
+//	0 -> HKDF-Extract = Master Secret
+//	          |
+//	          +--> Derive-Secret(., "c ap traffic", ClientHello...server Finished)
+//	          +--> Derive-Secret(., "s ap traffic", ClientHello...server Finished)
+//	          +--> Derive-Secret(., "exp master", ClientHello...server Finished)
+//	          +--> Derive-Secret(., "res master", ClientHello...client Finished)
+type KeySchedule struct {
+	Hash func() hash.Hash
+
+	EarlySecret     []byte
+	HandshakeSecret []byte
+	MasterSecret    []byte
+
+	ClientHandshakeTrafficSecret   []byte
+	ServerHandshakeTrafficSecret   []byte
+	ClientApplicationTrafficSecret []byte
+	ServerApplicationTrafficSecret []byte
+	ExporterMasterSecret           []byte
+	ResumptionMasterSecret         []byte
+}
+
+// NewKeySchedule は、PSKを使わない場合のEarly Secretを計算した鍵スケジュールを返します。
+func NewKeySchedule(newHash func() hash.Hash) *KeySchedule {
+	zeros := make([]byte, newHash().Size())
+	return &KeySchedule{
+		Hash:        newHash,
+		EarlySecret: HKDFExtract(newHash, nil, zeros),
+	}
+}
+
+// DeriveHandshakeSecrets は、(EC)DHEの共有秘密とClientHello...ServerHelloの
+// Transcript-Hashから、Handshake Secretとハンドシェイク用のトラフィックシークレットを導出します。
+func (ks *KeySchedule) DeriveHandshakeSecrets(sharedSecret, transcriptHash []byte) {
+	derived := DeriveSecret(ks.Hash, ks.EarlySecret, "derived", ks.emptyHash())
+	ks.HandshakeSecret = HKDFExtract(ks.Hash, derived, sharedSecret)
+	ks.ClientHandshakeTrafficSecret = DeriveSecret(ks.Hash, ks.HandshakeSecret, "c hs traffic", transcriptHash)
+	ks.ServerHandshakeTrafficSecret = DeriveSecret(ks.Hash, ks.HandshakeSecret, "s hs traffic", transcriptHash)
+}
+
+// DeriveApplicationSecrets は、ClientHello...server FinishedのTranscript-Hashから
+// Master Secret、アプリケーションデータ用のトラフィックシークレットとExporter Master Secretを導出します。
+func (ks *KeySchedule) DeriveApplicationSecrets(transcriptHash []byte) {
+	derived := DeriveSecret(ks.Hash, ks.HandshakeSecret, "derived", ks.emptyHash())
+	ks.MasterSecret = HKDFExtract(ks.Hash, derived, make([]byte, ks.Hash().Size()))
+	ks.ClientApplicationTrafficSecret = DeriveSecret(ks.Hash, ks.MasterSecret, "c ap traffic", transcriptHash)
+	ks.ServerApplicationTrafficSecret = DeriveSecret(ks.Hash, ks.MasterSecret, "s ap traffic", transcriptHash)
+	ks.ExporterMasterSecret = DeriveSecret(ks.Hash, ks.MasterSecret, "exp master", transcriptHash)
+}
+
+// DeriveResumptionMasterSecret は、ClientHello...client FinishedのTranscript-Hashから
+// Resumption Master Secretを導出します。
+func (ks *KeySchedule) DeriveResumptionMasterSecret(transcriptHash []byte) {
+	ks.ResumptionMasterSecret = DeriveSecret(ks.Hash, ks.MasterSecret, "res master", transcriptHash)
+}
+
+/**
+ * [sender]_write_key = HKDF-Expand-Label(Secret, "key", "", key_length)
+ * [sender]_write_iv  = HKDF-Expand-Label(Secret, "iv", "", iv_length)
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-7.3
+ */
+func (ks *KeySchedule) TrafficKey(trafficSecret []byte, keyLength int) (key, iv []byte) {
+	key = HKDFExpandLabel(ks.Hash, trafficSecret, "key", nil, keyLength)
+	iv = HKDFExpandLabel(ks.Hash, trafficSecret, "iv", nil, 12)
+	return key, iv
+}
+
+/**
+ * finished_key = HKDF-Expand-Label(BaseKey, "finished", "", Hash.length)
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.4
+ */
+func (ks *KeySchedule) FinishedKey(baseKey []byte) []byte {
+	return HKDFExpandLabel(ks.Hash, baseKey, "finished", nil, ks.Hash().Size())
+}
+
+/**
+ * verify_data = HMAC(finished_key, Transcript-Hash(Handshake Context, Certificate*, CertificateVerify*))
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.4
+ */
+func (ks *KeySchedule) VerifyData(baseKey, transcriptHash []byte) []byte {
+	mac := hmac.New(ks.Hash, ks.FinishedKey(baseKey))
+	mac.Write(transcriptHash)
+	return mac.Sum(nil)
+}
+
+func (ks *KeySchedule) emptyHash() []byte {
+	return ks.Hash().Sum(nil)
+}
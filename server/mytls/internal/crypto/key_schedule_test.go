@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// RFC 8448 "Simple 1-RTT Handshake" の値で鍵スケジュールを確認する
+// @see https://datatracker.ietf.org/doc/html/rfc8448#section-3
+func TestKeySchedule(t *testing.T) {
+	sharedSecret := mustDecodeHex(t, "8bd4054fb55b9d63fdfbacf9f04b9f0d35e6d63f537563efd46272900f89492d")
+	helloHash := mustDecodeHex(t, "860c06edc07858ee8e78f0e7428c58edd6b43f2ca3e6e95f02ed063cf0e1cad8")
+
+	ks := NewKeySchedule(sha256.New)
+	ks.DeriveHandshakeSecrets(sharedSecret, helloHash)
+	// Master SecretはTranscript-Hashに依存しない
+	ks.DeriveApplicationSecrets(helloHash)
+	serverKey, serverIV := ks.TrafficKey(ks.ServerHandshakeTrafficSecret, 16)
+
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{name: "正常系：Early Secret", got: ks.EarlySecret, want: "33ad0a1c607ec03b09e6cd9893680ce210adf300aa1f2660e1b22e10f170f92a"},
+		{name: "正常系：Handshake Secret", got: ks.HandshakeSecret, want: "1dc826e93606aa6fdc0aadc12f741b01046aa6b99f691ed221a9f0ca043fbeac"},
+		{name: "正常系：client_handshake_traffic_secret", got: ks.ClientHandshakeTrafficSecret, want: "b3eddb126e067f35a780b3abf45e2d8f3b1a950738f52e9600746a0e27a55a21"},
+		{name: "正常系：server_handshake_traffic_secret", got: ks.ServerHandshakeTrafficSecret, want: "b67b7d690cc16c4e75e54213cb2d37b4e9c912bcded9105d42befd59d391ad38"},
+		{name: "正常系：server handshake write key", got: serverKey, want: "3fce516009c21727d0f2e4e86ee403bc"},
+		{name: "正常系：server handshake write iv", got: serverIV, want: "5d313eb2671276ee13000b30"},
+		{name: "正常系：Master Secret", got: ks.MasterSecret, want: "18df06843d13a08bf2a449844c5f8a478001bc4d4c627984d5a41da8d0402919"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if want := mustDecodeHex(t, tt.want); !bytes.Equal(tt.got, want) {
+				t.Errorf("got %x, want %x", tt.got, want)
+			}
+		})
+	}
+}
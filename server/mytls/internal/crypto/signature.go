@@ -0,0 +1,80 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+/**
+ * CertificateVerifyで署名される内容
+ *   - 0x20 (スペース) を64回繰り返したもの
+ *   - コンテキスト文字列 ("TLS 1.3, server CertificateVerify")
+ *   - 区切りの0x00
+ *   - Transcript-Hash(Handshake Context, Certificate)
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.3
+ */
+func CertificateVerifyContent(context string, transcriptHash []byte) []byte {
+	var content []byte
+	content = append(content, bytes.Repeat([]byte{0x20}, 64)...)
+	content = append(content, context...)
+	content = append(content, 0x00)
+	content = append(content, transcriptHash...)
+	return content
+}
+
+// VerifySignature は、証明書の公開鍵でCertificateVerifyの署名を検証します。
+// TLS 1.3のCertificateVerifyで使えないRSASSA-PKCS1-v1_5はエラーになります。
+func VerifySignature(cert *x509.Certificate, algorithm common.SignatureAlgorithmType, content, signature []byte) error {
+	var hashFunc crypto.Hash
+	switch algorithm {
+	case common.EcdsaSecp256r1Sha256, common.RsaPssRsaeSha256:
+		hashFunc = crypto.SHA256
+	case common.EcdsaSecp384r1Sha384, common.RsaPssRsaeSha384:
+		hashFunc = crypto.SHA384
+	case common.EcdsaSecp521r1Sha512, common.RsaPssRsaeSha512:
+		hashFunc = crypto.SHA512
+	case common.Ed25519:
+		pub, ok := cert.PublicKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("certificate key does not match signature algorithm %s", algorithm)
+		}
+		if !ed25519.Verify(pub, content, signature) {
+			return fmt.Errorf("invalid %s signature", algorithm)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported signature algorithm for CertificateVerify: %s", algorithm)
+	}
+
+	h := hashFunc.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+
+	switch pub := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if algorithm != common.EcdsaSecp256r1Sha256 && algorithm != common.EcdsaSecp384r1Sha384 && algorithm != common.EcdsaSecp521r1Sha512 {
+			return fmt.Errorf("certificate key does not match signature algorithm %s", algorithm)
+		}
+		if !ecdsa.VerifyASN1(pub, digest, signature) {
+			return fmt.Errorf("invalid %s signature", algorithm)
+		}
+	case *rsa.PublicKey:
+		if algorithm != common.RsaPssRsaeSha256 && algorithm != common.RsaPssRsaeSha384 && algorithm != common.RsaPssRsaeSha512 {
+			return fmt.Errorf("certificate key does not match signature algorithm %s", algorithm)
+		}
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hashFunc}
+		if err := rsa.VerifyPSS(pub, hashFunc, digest, signature, opts); err != nil {
+			return fmt.Errorf("invalid %s signature: %w", algorithm, err)
+		}
+	default:
+		return fmt.Errorf("unsupported certificate public key type %T", pub)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package handshake
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
+)
+
+/**
+ * struct {
+ *   opaque cert_data<1..2^24-1>;
+ *   Extension extensions<0..2^16-1>;
+ * } CertificateEntry;
+ */
+type CertificateEntry struct {
+	CertData   []byte
+	Extensions []*extensions.Extension
+}
+
+/**
+ * struct {
+ *   opaque certificate_request_context<0..2^8-1>;
+ *   CertificateEntry certificate_list<0..2^24-1>;
+ * } Certificate;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.2
+ */
+type Certificate struct {
+	RequestContext  []byte
+	CertificateList []CertificateEntry
+}
+
+func (c *Certificate) UnMarshal(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("data too short for Certificate: %d bytes", len(data))
+	}
+	contextLength := int(data[0])
+	if len(data) < 1+contextLength+3 {
+		return fmt.Errorf("data too short for Certificate with context length %d: %d bytes", contextLength, len(data))
+	}
+	c.RequestContext = data[1 : 1+contextLength]
+	data = data[1+contextLength:]
+	listLength := decodeUint24(data[0:3])
+	if len(data) != 3+listLength {
+		return fmt.Errorf("data length mismatch for certificate_list: expected %d, got %d", 3+listLength, len(data))
+	}
+	list := data[3:]
+	c.CertificateList = nil
+	for len(list) > 0 {
+		if len(list) < 3 {
+			return fmt.Errorf("data too short for cert_data length: %d bytes", len(list))
+		}
+		certLength := decodeUint24(list[0:3])
+		if certLength == 0 || len(list) < 3+certLength+2 {
+			return fmt.Errorf("invalid cert_data length: %d", certLength)
+		}
+		entry := CertificateEntry{CertData: list[3 : 3+certLength]}
+		list = list[3+certLength:]
+		extLength := int(common.DecodeBytesToUint16(list[0:2]))
+		if len(list) < 2+extLength {
+			return fmt.Errorf("data too short for CertificateEntry extensions: expected %d, got %d", extLength, len(list)-2)
+		}
+		exts, err := extensions.UnMarshalExtensions(list[2 : 2+extLength])
+		if err != nil {
+			return err
+		}
+		entry.Extensions = exts
+		c.CertificateList = append(c.CertificateList, entry)
+		list = list[2+extLength:]
+	}
+	return nil
+}
+
+// Marshal は、Certificateの本体を組み立てます。
+// クライアント証明書を持たない場合は、空のcertificate_listで送信します。
+func (c *Certificate) Marshal() []byte {
+	var list []byte
+	for _, entry := range c.CertificateList {
+		list = append(list, encodeUint24(len(entry.CertData))...)
+		list = append(list, entry.CertData...)
+		exts := &extensions.Extensions{Extensions: entry.Extensions}
+		list = append(list, common.EncodeUint16ToBytes(uint16(exts.Length()))...)
+		list = append(list, exts.Marshal()...)
+	}
+
+	var result []byte
+	result = append(result, byte(len(c.RequestContext)))
+	result = append(result, c.RequestContext...)
+	result = append(result, encodeUint24(len(list))...)
+	result = append(result, list...)
+	return result
+}
+
+// ParseCertificates は、certificate_listをX.509証明書として解析します。
+// 先頭がサーバー自身の証明書 (end-entity) です。
+func (c *Certificate) ParseCertificates() ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, len(c.CertificateList))
+	for i, entry := range c.CertificateList {
+		cert, err := x509.ParseCertificate(entry.CertData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %d: %w", i, err)
+		}
+		certs[i] = cert
+	}
+	return certs, nil
+}
+
+func decodeUint24(b []byte) int {
+	return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+}
+
+func encodeUint24(v int) []byte {
+	return []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+}
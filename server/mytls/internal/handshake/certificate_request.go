@@ -0,0 +1,42 @@
+package handshake
+
+import (
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
+)
+
+/**
+ * struct {
+ *   opaque certificate_request_context<0..2^8-1>;
+ *   Extension extensions<2..2^16-1>;
+ * } CertificateRequest;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.3.2
+ */
+type CertificateRequest struct {
+	RequestContext []byte
+	Extensions     []*extensions.Extension
+}
+
+func (cr *CertificateRequest) UnMarshal(data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("data too short for CertificateRequest: %d bytes", len(data))
+	}
+	contextLength := int(data[0])
+	if len(data) < 1+contextLength+2 {
+		return fmt.Errorf("data too short for CertificateRequest with context length %d: %d bytes", contextLength, len(data))
+	}
+	cr.RequestContext = data[1 : 1+contextLength]
+	data = data[1+contextLength:]
+	length := int(common.DecodeBytesToUint16(data[0:2]))
+	if len(data) != 2+length {
+		return fmt.Errorf("data length mismatch for CertificateRequest extensions: expected %d, got %d", 2+length, len(data))
+	}
+	exts, err := extensions.UnMarshalExtensions(data[2:])
+	if err != nil {
+		return err
+	}
+	cr.Extensions = exts
+	return nil
+}
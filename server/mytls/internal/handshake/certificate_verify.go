@@ -0,0 +1,32 @@
+package handshake
+
+import (
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+/**
+ * struct {
+ *   SignatureScheme algorithm;
+ *   opaque signature<0..2^16-1>;
+ * } CertificateVerify;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.3
+ */
+type CertificateVerify struct {
+	Algorithm common.SignatureAlgorithmType
+	Signature []byte
+}
+
+func (cv *CertificateVerify) UnMarshal(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("data too short for CertificateVerify: %d bytes", len(data))
+	}
+	cv.Algorithm = common.SignatureAlgorithmType(common.DecodeBytesToUint16(data[0:2]))
+	length := int(common.DecodeBytesToUint16(data[2:4]))
+	if len(data) != 4+length {
+		return fmt.Errorf("data length mismatch for CertificateVerify: expected %d, got %d", 4+length, len(data))
+	}
+	cv.Signature = data[4:]
+	return nil
+}
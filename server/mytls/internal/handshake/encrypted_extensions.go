@@ -0,0 +1,34 @@
+package handshake
+
+import (
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
+)
+
+/**
+ * struct {
+ *   Extension extensions<0..2^16-1>;
+ * } EncryptedExtensions;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.3.1
+ */
+type EncryptedExtensions struct {
+	Extensions []*extensions.Extension
+}
+
+func (ee *EncryptedExtensions) UnMarshal(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("data too short for EncryptedExtensions: %d bytes", len(data))
+	}
+	length := int(common.DecodeBytesToUint16(data[0:2]))
+	if len(data) != 2+length {
+		return fmt.Errorf("data length mismatch for EncryptedExtensions: expected %d, got %d", 2+length, len(data))
+	}
+	exts, err := extensions.UnMarshalExtensions(data[2:])
+	if err != nil {
+		return err
+	}
+	ee.Extensions = exts
+	return nil
+}
@@ -64,7 +64,7 @@ func UnMarshalExtension(data []byte) (*Extension, error) {
 		return nil, fmt.Errorf("data too short for extension: %d bytes", len(data))
 	}
 
-	switch common.ExtensionType(common.DecodeBytesToUint16(data[0:2])) {
+	switch extType := common.ExtensionType(common.DecodeBytesToUint16(data[0:2])); extType {
 	case common.ServerNameExtensionType:
 		return &Extension{
 			Type:    common.ServerNameExtensionType,
@@ -95,6 +95,23 @@ func UnMarshalExtension(data []byte) (*Extension, error) {
 			Type:    common.PSKKeyExchangeModesExtensionType,
 			Payload: data[4:],
 		}, nil
+	case common.CookieExtensionType:
+		return &Extension{
+			Type:    common.CookieExtensionType,
+			Payload: data[4:],
+		}, nil
+	case common.StatusRequestExtensionType,
+		common.ALPNExtensionType,
+		common.SignedCertificateTimestampExtensionType,
+		common.EarlyDataExtensionType,
+		common.CertificateAuthoritiesExtensionType,
+		common.OIDFiltersExtensionType,
+		common.SignatureAlgorithmsCertExtensionType:
+		// 中身は解釈せず、そのまま保持する
+		return &Extension{
+			Type:    extType,
+			Payload: data[4:],
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown extension type: 0x%04x", common.DecodeBytesToUint16(data[0:2]))
 	}
@@ -0,0 +1,20 @@
+package handshake
+
+/**
+ * struct {
+ *   opaque verify_data[Hash.length];
+ * } Finished;
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.4.4
+ */
+type Finished struct {
+	VerifyData []byte
+}
+
+func (f *Finished) UnMarshal(data []byte) error {
+	f.VerifyData = data
+	return nil
+}
+
+func (f *Finished) Marshal() []byte {
+	return f.VerifyData
+}
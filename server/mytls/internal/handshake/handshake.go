@@ -1,6 +1,10 @@
 package handshake
 
-import "github.com/refraction-networking/utls/server/mytls/internal/common"
+import (
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
 
 type Handshake struct {
 	HandshakeType common.HandshakeType
@@ -27,3 +31,21 @@ func (h *Handshake) Marshal() []byte {
 	result = append(result, h.Body...)
 	return result
 }
+
+// ParseHandshake は、dataの先頭にあるハンドシェイクメッセージを1つ読み取り、
+// そのメッセージと残りのバイト列を返します。
+// メッセージ全体がそろっていない場合はエラーになります。
+func ParseHandshake(data []byte) (*Handshake, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("data too short for handshake header: %d bytes", len(data))
+	}
+	length := int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	if len(data)-4 < length {
+		return nil, nil, fmt.Errorf("data too short for handshake message: expected %d bytes, got %d", length, len(data)-4)
+	}
+	return &Handshake{
+		HandshakeType: common.HandshakeType(data[0]),
+		Length:        [3]byte{data[1], data[2], data[3]},
+		Body:          data[4 : 4+length],
+	}, data[4+length:], nil
+}
@@ -1,10 +1,24 @@
 package handshake
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/refraction-networking/utls/server/mytls/internal/common"
 	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
 )
 
+/**
+ * HelloRetryRequestのrandomに使われる特別な値 (SHA-256("HelloRetryRequest"))
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.1.3
+ */
+var helloRetryRequestRandom = [32]byte{
+	0xcf, 0x21, 0xad, 0x74, 0xe5, 0x9a, 0x61, 0x11,
+	0xbe, 0x1d, 0x8c, 0x02, 0x1e, 0x65, 0xb8, 0x91,
+	0xc2, 0xa2, 0x11, 0x16, 0x7a, 0xbb, 0x8c, 0x5e,
+	0x07, 0x9e, 0x09, 0xe2, 0xc8, 0xa8, 0x33, 0x9c,
+}
+
 /**
  * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.1.3
  */
@@ -17,47 +31,108 @@ type ServerHello struct {
 	Extensions        []extensions.Extension
 }
 
-// func (sh *ServerHello) UnMarshal(data []byte) error {
-// 	if len(data) < 38 {
-// 		return fmt.Errorf("data too short for ServerHello: %d bytes", len(data))
-// 	}
-// 	sh.ProtocolVersion = common.TLSVersion(common.DecodeBytesToUint16(data[0:2]))
-// 	copy(sh.Random[:], data[2:34])
-// 	sessionIDLen := int(data[34])
-// 	if len(data) < 35+sessionIDLen+3 {
-// 		return fmt.Errorf("data too short for ServerHello with session ID length %d: %d bytes", sessionIDLen, len(data))
-// 	}
-// 	sh.SessionID = make([]byte, sessionIDLen)
-// 	copy(sh.SessionID, data[35:35+sessionIDLen])
-// 	sh.CipherSuite = common.CipherSuite(common.DecodeBytesToUint16(data[35+sessionIDLen : 37+sessionIDLen]))
-// 	sh.CompressionMethod = data[37+sessionIDLen]
-// 	if len(data) == 38+sessionIDLen {
-// 		return nil
-// 	}
-// 	if len(data) < 40+sessionIDLen {
-// 		return fmt.Errorf("data too short for ServerHello extensions with session ID length %d: %d bytes", sessionIDLen, len(data))
-// 	}
-// 	extensionsLength := int(common.DecodeBytesToUint16(data[38+sessionIDLen : 40+sessionIDLen]))
-// 	if len(data) != 40+sessionIDLen+extensionsLength {
-// 		return fmt.Errorf("data length mismatch for ServerHello extensions: expected %d, got %d", 40+sessionIDLen+extensionsLength, len(data))
-// 	}
-// 	extensionsData := data[40+sessionIDLen:]
-// 	for len(extensionsData) > 0 {
-// 		if len(extensionsData) < 4 {
-// 			return fmt.Errorf("insufficient data for extension header: %d bytes", len(extensionsData))
-// 		}
-// 		extType := common.DecodeBytesToUint16(extensionsData[0:2])
-// 		extLen := int(common.DecodeBytesToUint16(extensionsData[2:4]))
-// 		if len(extensionsData) < 4+extLen {
-// 			return fmt.Errorf("insufficient data for extension data: expected %d, got %d", extLen, len(extensionsData)-4)
-// 		}
-// 		extData := extensionsData[4 : 4+extLen]
-// 		ext, err := extensions.UnMarshalExtension(extType, extData)
-// 		if err != nil {
-// 			return err
-// 		}
-// 		sh.Extensions = append(sh.Extensions, *ext)
-// 		extensionsData = extensionsData[4+extLen:]
-// 	}
-// 	return nil
-// }
+// UnMarshal は、ハンドシェイクヘッダを除いたServerHelloの本体を解析します。
+func (sh *ServerHello) UnMarshal(data []byte) error {
+	if len(data) < 38 {
+		return fmt.Errorf("data too short for ServerHello: %d bytes", len(data))
+	}
+	sh.ProtocolVersion = common.TLSVersion(common.DecodeBytesToUint16(data[0:2]))
+	copy(sh.Random[:], data[2:34])
+	sessionIDLen := int(data[34])
+	if len(data) < 35+sessionIDLen+3 {
+		return fmt.Errorf("data too short for ServerHello with session ID length %d: %d bytes", sessionIDLen, len(data))
+	}
+	sh.SessionID = make([]byte, sessionIDLen)
+	copy(sh.SessionID, data[35:35+sessionIDLen])
+	sh.CipherSuite = common.CipherSuite(common.DecodeBytesToUint16(data[35+sessionIDLen : 37+sessionIDLen]))
+	sh.CompressionMethod = data[37+sessionIDLen]
+	sh.Extensions = nil
+	if len(data) == 38+sessionIDLen {
+		return nil
+	}
+	if len(data) < 40+sessionIDLen {
+		return fmt.Errorf("data too short for ServerHello extensions with session ID length %d: %d bytes", sessionIDLen, len(data))
+	}
+	extensionsLength := int(common.DecodeBytesToUint16(data[38+sessionIDLen : 40+sessionIDLen]))
+	if len(data) != 40+sessionIDLen+extensionsLength {
+		return fmt.Errorf("data length mismatch for ServerHello extensions: expected %d, got %d", 40+sessionIDLen+extensionsLength, len(data))
+	}
+	exts, err := extensions.UnMarshalExtensions(data[40+sessionIDLen:])
+	if err != nil {
+		return err
+	}
+	for _, ext := range exts {
+		sh.Extensions = append(sh.Extensions, *ext)
+	}
+	return nil
+}
+
+// IsHelloRetryRequest は、このServerHelloがHelloRetryRequestかどうかを返します。
+func (sh *ServerHello) IsHelloRetryRequest() bool {
+	return sh.Random == helloRetryRequestRandom
+}
+
+// Extension は、指定した種類の拡張を返します。含まれていない場合はnilを返します。
+func (sh *ServerHello) Extension(t common.ExtensionType) *extensions.Extension {
+	for i := range sh.Extensions {
+		if sh.Extensions[i].Type == t {
+			return &sh.Extensions[i]
+		}
+	}
+	return nil
+}
+
+/**
+ * SelectedVersion は、supported_versions拡張でサーバーが選択したバージョンを返します。
+ * 拡張がない場合はlegacy_versionを返します。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.2.1
+ */
+func (sh *ServerHello) SelectedVersion() (common.TLSVersion, error) {
+	ext := sh.Extension(common.SupportedVersionsExtensionType)
+	if ext == nil {
+		return sh.ProtocolVersion, nil
+	}
+	if len(ext.Payload) != 2 {
+		return 0, fmt.Errorf("invalid supported_versions extension length: %d", len(ext.Payload))
+	}
+	return common.TLSVersion(common.DecodeBytesToUint16(ext.Payload)), nil
+}
+
+/**
+ * KeyShare は、key_share拡張でサーバーが送ってきたグループと鍵を返します。
+ * HelloRetryRequestの場合、key_exchangeは空で、選択されたグループのみが返されます。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-4.2.8
+ */
+func (sh *ServerHello) KeyShare() (common.SupportedGroupsType, []byte, error) {
+	ext := sh.Extension(common.KeyShareExtensionType)
+	if ext == nil {
+		return 0, nil, fmt.Errorf("ServerHello has no key_share extension")
+	}
+	p := ext.Payload
+	if sh.IsHelloRetryRequest() {
+		// struct { NamedGroup selected_group; } KeyShareHelloRetryRequest;
+		if len(p) != 2 {
+			return 0, nil, fmt.Errorf("invalid key_share extension length in HelloRetryRequest: %d", len(p))
+		}
+		return common.SupportedGroupsType(common.DecodeBytesToUint16(p)), nil, nil
+	}
+	// struct { KeyShareEntry server_share; } KeyShareServerHello;
+	if len(p) < 4 {
+		return 0, nil, fmt.Errorf("invalid key_share extension length: %d", len(p))
+	}
+	group := common.SupportedGroupsType(common.DecodeBytesToUint16(p[0:2]))
+	keyLength := int(common.DecodeBytesToUint16(p[2:4]))
+	if len(p) != 4+keyLength {
+		return 0, nil, fmt.Errorf("key_share length mismatch: expected %d, got %d", 4+keyLength, len(p))
+	}
+	return group, p[4:], nil
+}
+
+// Cookie は、HelloRetryRequestのcookie拡張の中身を返します。含まれていない場合はnilを返します。
+func (sh *ServerHello) Cookie() []byte {
+	ext := sh.Extension(common.CookieExtensionType)
+	if ext == nil {
+		return nil
+	}
+	return bytes.Clone(ext.Payload)
+}
@@ -0,0 +1,92 @@
+package handshake
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+func TestServerHelloUnMarshal(t *testing.T) {
+	serverHello := func(random [32]byte, exts []byte) []byte {
+		var b []byte
+		b = append(b, 0x03, 0x03)
+		b = append(b, random[:]...)
+		b = append(b, 0x00)       // legacy_session_id_echo
+		b = append(b, 0x13, 0x01) // TLS_AES_128_GCM_SHA256
+		b = append(b, 0x00)       // legacy_compression_method
+		b = append(b, byte(len(exts)>>8), byte(len(exts)))
+		return append(b, exts...)
+	}
+	keyExchange := bytes.Repeat([]byte{0x42}, 32)
+	supportedVersions := []byte{0x00, 0x2b, 0x00, 0x02, 0x03, 0x04}
+	keyShare := append([]byte{0x00, 0x33, 0x00, 0x24, 0x00, 0x1d, 0x00, 0x20}, keyExchange...)
+	hrrKeyShare := []byte{0x00, 0x33, 0x00, 0x02, 0x00, 0x17}
+	cookie := []byte{0x00, 0x2c, 0x00, 0x04, 0x00, 0x02, 0xca, 0xfe}
+
+	tests := []struct {
+		name         string
+		data         []byte
+		wantHRR      bool
+		wantGroup    common.SupportedGroupsType
+		wantKey      []byte
+		wantCookie   []byte
+		expectingErr bool
+	}{
+		{
+			name:      "正常系：ServerHello",
+			data:      serverHello([32]byte{0x01}, append(append([]byte{}, supportedVersions...), keyShare...)),
+			wantGroup: common.X25519,
+			wantKey:   keyExchange,
+		},
+		{
+			name:       "正常系：cookie付きのHelloRetryRequest",
+			data:       serverHello(helloRetryRequestRandom, append(append(append([]byte{}, supportedVersions...), hrrKeyShare...), cookie...)),
+			wantHRR:    true,
+			wantGroup:  common.Secp256r1,
+			wantCookie: []byte{0x00, 0x02, 0xca, 0xfe},
+		},
+		{
+			name:         "異常系：データが短すぎる",
+			data:         []byte{0x03, 0x03},
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：拡張の長さが一致しない",
+			data:         append(serverHello([32]byte{}, supportedVersions), 0x00),
+			expectingErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := &ServerHello{}
+			err := sh.UnMarshal(tt.data)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("UnMarshal() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if tt.expectingErr {
+				return
+			}
+			if sh.CipherSuite != common.TLS_AES_128_GCM_SHA256 {
+				t.Errorf("CipherSuite = %v", sh.CipherSuite)
+			}
+			if version, err := sh.SelectedVersion(); err != nil || version != common.TLS_VERSION_1_3 {
+				t.Errorf("SelectedVersion() = %v, %v", version, err)
+			}
+			if sh.IsHelloRetryRequest() != tt.wantHRR {
+				t.Errorf("IsHelloRetryRequest() = %v, want %v", sh.IsHelloRetryRequest(), tt.wantHRR)
+			}
+			group, key, err := sh.KeyShare()
+			if err != nil {
+				t.Fatalf("KeyShare() error = %v", err)
+			}
+			if group != tt.wantGroup || !bytes.Equal(key, tt.wantKey) {
+				t.Errorf("KeyShare() = %v, %x, want %v, %x", group, key, tt.wantGroup, tt.wantKey)
+			}
+			if !bytes.Equal(sh.Cookie(), tt.wantCookie) {
+				t.Errorf("Cookie() = %x, want %x", sh.Cookie(), tt.wantCookie)
+			}
+		})
+	}
+}
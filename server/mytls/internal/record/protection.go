@@ -0,0 +1,92 @@
+package record
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+// Cipher は、一方向 (送信または受信) のレコード保護の状態です。
+// トラフィックシークレットごとにシーケンス番号は0から始まります。
+// @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.2
+type Cipher struct {
+	aead cipher.AEAD
+	iv   []byte
+	seq  uint64
+}
+
+func NewCipher(aead cipher.AEAD, iv []byte) *Cipher {
+	return &Cipher{aead: aead, iv: iv}
+}
+
+/**
+ * per-record nonce = write_iv XOR (64bitのシーケンス番号を左側0埋めでiv_lengthにしたもの)
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.3
+ */
+func (c *Cipher) nonce() []byte {
+	nonce := make([]byte, len(c.iv))
+	copy(nonce, c.iv)
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], c.seq)
+	for i := range seq {
+		nonce[len(nonce)-8+i] ^= seq[i]
+	}
+	return nonce
+}
+
+/**
+ * struct {
+ *   opaque content[TLSPlaintext.length];
+ *   ContentType type;
+ *   uint8 zeros[length_of_padding];
+ * } TLSInnerPlaintext;
+ *
+ * Encrypt は、TLSInnerPlaintextを暗号化し、application_data型のレコードにします。
+ */
+func (c *Cipher) Encrypt(contentType common.ContentType, plaintext []byte) (*Record, error) {
+	inner := make([]byte, 0, len(plaintext)+1+c.aead.Overhead())
+	inner = append(inner, plaintext...)
+	inner = append(inner, byte(contentType))
+
+	length := len(inner) + c.aead.Overhead()
+	if length > 16384+256 {
+		return nil, fmt.Errorf("payload too large: %d bytes", len(plaintext))
+	}
+	r := &Record{
+		Type:    common.ApplicationData,
+		Version: common.TLS_VERSION_1_2,
+		Length:  uint16(length),
+	}
+	// additional_data = TLSCiphertext.opaque_type || legacy_record_version || length
+	r.Payload = c.aead.Seal(nil, c.nonce(), inner, r.header())
+	c.seq++
+	return r, nil
+}
+
+// Decrypt は、application_data型のレコードを復号し、本来のContentTypeと内容を返します。
+func (c *Cipher) Decrypt(r *Record) (common.ContentType, []byte, error) {
+	if r.Type != common.ApplicationData {
+		return common.Invalid, nil, fmt.Errorf("unexpected protected record type: 0x%02x", byte(r.Type))
+	}
+	inner, err := c.aead.Open(nil, c.nonce(), r.Payload, r.header())
+	if err != nil {
+		return common.Invalid, nil, fmt.Errorf("failed to decrypt record: %w", err)
+	}
+	c.seq++
+
+	// 末尾の0埋め (padding) を取り除き、最後の0でないバイトをContentTypeとする
+	i := len(inner) - 1
+	for i >= 0 && inner[i] == 0 {
+		i--
+	}
+	if i < 0 {
+		return common.Invalid, nil, fmt.Errorf("decrypted record has no content type")
+	}
+	return common.ContentType(inner[i]), inner[:i], nil
+}
+
+func (r *Record) header() []byte {
+	return []byte{byte(r.Type), byte(r.Version >> 8), byte(r.Version), byte(r.Length >> 8), byte(r.Length)}
+}
@@ -0,0 +1,80 @@
+package record
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewCipher(aead, make([]byte, 12))
+}
+
+func TestCipher(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  common.ContentType
+		plaintext    []byte
+		tamper       bool
+		expectingErr bool
+	}{
+		{
+			name:        "正常系：ハンドシェイクメッセージ",
+			contentType: common.Handshake,
+			plaintext:   []byte{0x14, 0x00, 0x00, 0x02, 0xaa, 0xbb},
+		},
+		{
+			name:        "正常系：末尾が0のアプリケーションデータ",
+			contentType: common.ApplicationData,
+			plaintext:   []byte{0x01, 0x00, 0x00},
+		},
+		{
+			name:         "異常系：改ざんされたレコード",
+			contentType:  common.ApplicationData,
+			plaintext:    []byte("hello"),
+			tamper:       true,
+			expectingErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, reader := newTestCipher(t), newTestCipher(t)
+			// シーケンス番号が進んでもnonceがそろうことを確認するため2回送る
+			for i := 0; i < 2; i++ {
+				r, err := writer.Encrypt(tt.contentType, tt.plaintext)
+				if err != nil {
+					t.Fatalf("Encrypt() error = %v", err)
+				}
+				if r.Type != common.ApplicationData {
+					t.Errorf("Encrypt() record type = %v, want application_data", r.Type)
+				}
+				if tt.tamper {
+					r.Payload[0] ^= 0xff
+				}
+				contentType, plaintext, err := reader.Decrypt(r)
+				if (err != nil) != tt.expectingErr {
+					t.Fatalf("Decrypt() error = %v, expectingErr %v", err, tt.expectingErr)
+				}
+				if tt.expectingErr {
+					return
+				}
+				if contentType != tt.contentType || !bytes.Equal(plaintext, tt.plaintext) {
+					t.Errorf("Decrypt() = %v, %x, want %v, %x", contentType, plaintext, tt.contentType, tt.plaintext)
+				}
+			}
+		})
+	}
+}
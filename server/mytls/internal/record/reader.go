@@ -0,0 +1,177 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+const (
+	// MaxPlaintextLength は、TLSPlaintext.fragmentの最大長 (2^14) です。
+	MaxPlaintextLength = 16384
+	// MaxCiphertextLength は、TLSCiphertext.encrypted_recordの最大長 (2^14 + 256) です。
+	// @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.2
+	MaxCiphertextLength = MaxPlaintextLength + 256
+
+	// MaxHandshakeLength は、受け付けるハンドシェイクメッセージの最大長です。
+	// プロトコル上は2^24-1まで可能ですが、証明書チェーンを含めても十分な大きさに制限します。
+	MaxHandshakeLength = 65536
+
+	headerLength = 5
+	readSize     = 4096
+)
+
+// ErrTruncatedRecord は、レコードの途中で接続が閉じられた場合のエラーです。
+var ErrTruncatedRecord = errors.New("record truncated by EOF")
+
+// LengthError は、レコードのlengthフィールドが不正な場合のエラーです。
+type LengthError struct {
+	Type   common.ContentType
+	Length int
+	Max    int
+}
+
+func (e *LengthError) Error() string {
+	if e.Length == 0 {
+		return fmt.Sprintf("invalid zero-length record of type 0x%02x", byte(e.Type))
+	}
+	return fmt.Sprintf("record of type 0x%02x too large: %d bytes (max %d)", byte(e.Type), e.Length, e.Max)
+}
+
+// ContentTypeError は、未知のContentTypeのレコードを受信した場合のエラーです。
+type ContentTypeError struct {
+	Type common.ContentType
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("unknown record content type: 0x%02x", byte(e.Type))
+}
+
+// HandshakeLengthError は、ハンドシェイクメッセージの長さが上限を超えている場合のエラーです。
+type HandshakeLengthError struct {
+	Type   common.HandshakeType
+	Length int
+	Max    int
+}
+
+func (e *HandshakeLengthError) Error() string {
+	return fmt.Sprintf("%s message too large: %d bytes (max %d)", e.Type, e.Length, e.Max)
+}
+
+/**
+ * Reader は、ストリームからTLSレコードを1つずつ読み出します。
+ * 1回のReadで届くバイト列はレコードの境界と一致しないため、
+ * レコード全体がそろうまで内部のバッファに蓄えます。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.1
+ */
+type Reader struct {
+	r   io.Reader
+	buf []byte
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadRecord は、次のレコードを返します。
+// lengthが不正なレコードは *LengthError、未知のContentTypeは *ContentTypeError、
+// レコードの途中で接続が閉じられた場合は ErrTruncatedRecord を返します。
+// レコードの前で接続が閉じられた場合は io.EOF を返します。
+func (rd *Reader) ReadRecord() (*Record, error) {
+	if err := rd.fill(headerLength); err != nil {
+		return nil, err
+	}
+	contentType := common.ContentType(rd.buf[0])
+	length := int(common.DecodeBytesToUint16(rd.buf[3:5]))
+	switch contentType {
+	case common.ChangeCipherSpec, common.Alert, common.Handshake, common.ApplicationData:
+	default:
+		return nil, &ContentTypeError{Type: contentType}
+	}
+	if length > MaxCiphertextLength {
+		return nil, &LengthError{Type: contentType, Length: length, Max: MaxCiphertextLength}
+	}
+	// 長さ0のハンドシェイクやアラートのレコードは送ってはならない
+	// @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.1
+	if length == 0 && contentType != common.ApplicationData {
+		return nil, &LengthError{Type: contentType, Length: length, Max: MaxCiphertextLength}
+	}
+
+	if err := rd.fill(headerLength + length); err != nil {
+		return nil, err
+	}
+	r, err := ParseRecord(rd.buf[:headerLength+length])
+	if err != nil {
+		return nil, err
+	}
+	// 次のReadでバッファが上書きされても影響がないようにコピーする
+	r.Payload = append([]byte(nil), r.Payload...)
+	rd.buf = rd.buf[headerLength+length:]
+	return r, nil
+}
+
+// fill は、バッファにnバイト以上たまるまで読み込みます。
+func (rd *Reader) fill(n int) error {
+	for len(rd.buf) < n {
+		chunk := make([]byte, readSize)
+		m, err := rd.r.Read(chunk)
+		rd.buf = append(rd.buf, chunk[:m]...)
+		if len(rd.buf) >= n {
+			return nil
+		}
+		if err == io.EOF {
+			if len(rd.buf) == 0 {
+				return io.EOF
+			}
+			return ErrTruncatedRecord
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+ * HandshakeBuffer は、レコードの境界をまたいで届くハンドシェイクメッセージを組み立てます。
+ * 1つのレコードに複数のメッセージが含まれる場合や、1つのメッセージが複数のレコードに
+ * 分割される場合があります。
+ * @see https://datatracker.ietf.org/doc/html/rfc8446#section-5.1
+ */
+type HandshakeBuffer struct {
+	buf []byte
+}
+
+// Write は、ハンドシェイクレコードの内容 (復号済み) を追加します。
+func (hb *HandshakeBuffer) Write(fragment []byte) {
+	hb.buf = append(hb.buf, fragment...)
+}
+
+// Next は、そろっている次のハンドシェイクメッセージをヘッダを含めて返します。
+// まだそろっていない場合はnilを返します。取り出しは行いません。
+func (hb *HandshakeBuffer) Next() ([]byte, error) {
+	if len(hb.buf) < 4 {
+		return nil, nil
+	}
+	length := int(hb.buf[1])<<16 | int(hb.buf[2])<<8 | int(hb.buf[3])
+	if length > MaxHandshakeLength {
+		return nil, &HandshakeLengthError{Type: common.HandshakeType(hb.buf[0]), Length: length, Max: MaxHandshakeLength}
+	}
+	if len(hb.buf) < 4+length {
+		return nil, nil
+	}
+	return hb.buf[:4+length], nil
+}
+
+// Discard は、Nextで返したメッセージを取り除きます。
+func (hb *HandshakeBuffer) Discard(n int) {
+	hb.buf = hb.buf[n:]
+}
+
+// Len は、まだ取り出していないバイト数を返します。
+// 鍵を切り替える時点では0でなければなりません。
+func (hb *HandshakeBuffer) Len() int {
+	return len(hb.buf)
+}
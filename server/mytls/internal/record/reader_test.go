@@ -0,0 +1,156 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+)
+
+func TestReaderReadRecord(t *testing.T) {
+	handshakeRecord := []byte{0x16, 0x03, 0x03, 0x00, 0x04, 0x02, 0x00, 0x00, 0x00}
+	ccsRecord := []byte{0x14, 0x03, 0x03, 0x00, 0x01, 0x01}
+
+	tests := []struct {
+		name      string
+		input     io.Reader
+		wantTypes []common.ContentType
+		wantErr   func(error) bool
+	}{
+		{
+			name:      "正常系：1回のReadに複数のレコード",
+			input:     bytes.NewReader(append(append([]byte{}, handshakeRecord...), ccsRecord...)),
+			wantTypes: []common.ContentType{common.Handshake, common.ChangeCipherSpec},
+			wantErr:   func(err error) bool { return err == io.EOF },
+		},
+		{
+			name:      "正常系：1バイトずつ届くレコード",
+			input:     iotest.OneByteReader(bytes.NewReader(append(append([]byte{}, ccsRecord...), handshakeRecord...))),
+			wantTypes: []common.ContentType{common.ChangeCipherSpec, common.Handshake},
+			wantErr:   func(err error) bool { return err == io.EOF },
+		},
+		{
+			name:    "異常系：レコードの途中で切断",
+			input:   bytes.NewReader(handshakeRecord[:7]),
+			wantErr: func(err error) bool { return errors.Is(err, ErrTruncatedRecord) },
+		},
+		{
+			name:  "異常系：ヘッダの途中で切断",
+			input: bytes.NewReader(handshakeRecord[:3]),
+			wantErr: func(err error) bool {
+				return errors.Is(err, ErrTruncatedRecord)
+			},
+		},
+		{
+			name:  "異常系：lengthが上限を超える",
+			input: bytes.NewReader([]byte{0x17, 0x03, 0x03, 0x41, 0x01}),
+			wantErr: func(err error) bool {
+				var lengthErr *LengthError
+				return errors.As(err, &lengthErr) && lengthErr.Length == 0x4101 && lengthErr.Max == MaxCiphertextLength
+			},
+		},
+		{
+			name:  "異常系：長さ0のハンドシェイクレコード",
+			input: bytes.NewReader([]byte{0x16, 0x03, 0x03, 0x00, 0x00}),
+			wantErr: func(err error) bool {
+				var lengthErr *LengthError
+				return errors.As(err, &lengthErr) && lengthErr.Length == 0
+			},
+		},
+		{
+			name:  "異常系：未知のContentType",
+			input: bytes.NewReader([]byte{0x48, 0x54, 0x54, 0x50, 0x2f}),
+			wantErr: func(err error) bool {
+				var typeErr *ContentTypeError
+				return errors.As(err, &typeErr) && typeErr.Type == 0x48
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := NewReader(tt.input)
+			for _, want := range tt.wantTypes {
+				r, err := rd.ReadRecord()
+				if err != nil {
+					t.Fatalf("ReadRecord() error = %v", err)
+				}
+				if r.Type != want {
+					t.Errorf("ReadRecord() type = %v, want %v", r.Type, want)
+				}
+				if int(r.Length) != len(r.Payload) {
+					t.Errorf("ReadRecord() length = %d, payload %d bytes", r.Length, len(r.Payload))
+				}
+			}
+			if _, err := rd.ReadRecord(); !tt.wantErr(err) {
+				t.Errorf("ReadRecord() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestHandshakeBuffer(t *testing.T) {
+	finished := []byte{0x14, 0x00, 0x00, 0x03, 0xaa, 0xbb, 0xcc}
+	encryptedExtensions := []byte{0x08, 0x00, 0x00, 0x02, 0x00, 0x00}
+
+	tests := []struct {
+		name         string
+		fragments    [][]byte
+		want         [][]byte
+		expectingErr bool
+	}{
+		{
+			name:      "正常系：1つのレコードに2つのメッセージ",
+			fragments: [][]byte{append(append([]byte{}, encryptedExtensions...), finished...)},
+			want:      [][]byte{encryptedExtensions, finished},
+		},
+		{
+			name:      "正常系：複数のレコードに分割されたメッセージ",
+			fragments: [][]byte{finished[:2], finished[2:5], finished[5:]},
+			want:      [][]byte{finished},
+		},
+		{
+			name:         "異常系：長さが上限を超えるメッセージ",
+			fragments:    [][]byte{{0x0b, 0x10, 0x00, 0x00}},
+			expectingErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hb HandshakeBuffer
+			var got [][]byte
+			for _, fragment := range tt.fragments {
+				hb.Write(fragment)
+				for {
+					msg, err := hb.Next()
+					if (err != nil) != tt.expectingErr {
+						t.Fatalf("Next() error = %v, expectingErr %v", err, tt.expectingErr)
+					}
+					if err != nil || msg == nil {
+						break
+					}
+					got = append(got, append([]byte(nil), msg...))
+					hb.Discard(len(msg))
+				}
+			}
+			if tt.expectingErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d messages, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !bytes.Equal(got[i], tt.want[i]) {
+					t.Errorf("message %d = %x, want %x", i, got[i], tt.want[i])
+				}
+			}
+			if hb.Len() != 0 {
+				t.Errorf("Len() = %d, want 0", hb.Len())
+			}
+		})
+	}
+}
@@ -1,18 +1,18 @@
 package mytls
 
 import (
+	"context"
 	"crypto/ecdh"
-	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-
-	"github.com/refraction-networking/utls/server/mytls/internal/handshake"
-	handshake2 "github.com/refraction-networking/utls/server/mytls/internal/handshake"
+	"net"
+	"time"
 
 	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/mytls/internal/crypto"
+	"github.com/refraction-networking/utls/server/mytls/internal/handshake"
 	"github.com/refraction-networking/utls/server/mytls/internal/handshake/extensions"
 	"github.com/refraction-networking/utls/server/mytls/internal/record"
-	"github.com/refraction-networking/utls/server/mytls/internal/tcp"
 	"github.com/refraction-networking/utls/server/openapi"
 )
 
@@ -39,25 +39,49 @@ func stringsToUint16(ss []string) ([]uint16, error) {
 	return results, nil
 }
 
-func GenEcdhX25519() (*ecdh.PrivateKey, *ecdh.PublicKey, error) {
-	curve := ecdh.X25519()
-	priv, err := curve.GenerateKey(rand.Reader)
+// DialFunc は、接続先へのTCP接続を開きます。接続先の制限や同時接続数の制限は呼び出し側で行います。
+type DialFunc func(ctx context.Context) (net.Conn, error)
+
+// PerformHandshake は、指定されたTLSパラメータを使用して独自のTLS実装でTLS 1.3のハンドシェイクを実行します。
+// 接続はdialで開き、ctxの期限とhandshakeTimeoutの早い方をハンドシェイクの期限とします。
+// エラーが発生した場合も、それまでに送受信したバイト列を含む結果を返します。
+func PerformHandshake(ctx context.Context, dial DialFunc, params openapi.TlsClientParameters) (*Result, error) {
+	result := &Result{}
+	hello, keyShares, err := newClientHello(params)
 	if err != nil {
-		return nil, nil, err
+		return result, err
 	}
-	pub := priv.PublicKey()
-	return priv, pub, nil
-}
 
-// PerformHandshake は、指定されたTLSパラメータを使用して独自のTLS実装でハンドシェイクを実行し、
-// サーバーからの生の応答バイト列を返します。
-func PerformHandshake(params openapi.TlsClientParameters) ([]byte, []byte, error) {
-	conn, err := tcp.Conn(params.ServerName, 443) // ポートは443で固定
+	if _, _, err := DialTarget(params); err != nil {
+		return result, err
+	}
+	conn, err := dial(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("tcp.Conn error: %w", err)
+		return result, fmt.Errorf("dial error: %w", err)
 	}
 	defer conn.Close()
+	deadline := time.Now().Add(handshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	c := &client{
+		conn:      conn,
+		result:    result,
+		hello:     hello,
+		keyShares: keyShares,
+		records:   record.NewReader(conn),
+	}
+	return result, c.handshake()
+}
 
+// handshakeTimeout は、ハンドシェイク全体にかけられる時間です。
+const handshakeTimeout = 10 * time.Second
+
+// newClientHello は、パラメータからClientHelloを組み立てます。
+// key_sharesに指定されたグループごとに鍵ペアを生成し、その秘密鍵も返します。
+func newClientHello(params openapi.TlsClientParameters) (*handshake.ClientHello, map[common.SupportedGroupsType]*ecdh.PrivateKey, error) {
 	// SupportedGroups
 	supportedGroups, err := stringsToUint16(params.SupportedGroups)
 	if err != nil {
@@ -72,27 +96,25 @@ func PerformHandshake(params openapi.TlsClientParameters) ([]byte, []byte, error
 
 	// KeyShare
 	keyShareEntries := []extensions.KeyShareEntry{}
-	// すべてのグループで使いまわすためのダミー鍵を一度だけ生成
-	curve := ecdh.X25519()
-	priv, err := curve.GenerateKey(rand.Reader)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate dummy key for KeyShare: %w", err)
-	}
-	pub := priv.PublicKey()
-
+	keyShares := map[common.SupportedGroupsType]*ecdh.PrivateKey{}
 	for _, groupStr := range params.KeyShares {
 		group, err := stringToUint16(groupStr)
 		if err != nil {
 			return nil, nil, fmt.Errorf("invalid key share group: %w", err)
 		}
+		priv, err := crypto.GenerateKeyShare(common.SupportedGroupsType(group))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate key share: %w", err)
+		}
+		keyShares[common.SupportedGroupsType(group)] = priv
 		keyShareEntries = append(keyShareEntries, extensions.KeyShareEntry{
 			Group:       group,
-			KeyExchange: pub.Bytes(), // すべてのグループで同じ公開鍵を使いまわす
+			KeyExchange: priv.PublicKey().Bytes(),
 		})
 	}
 
 	// SupportedVersions
-	var supportedVersion uint16
+	supportedVersion := uint16(common.TLS_VERSION_1_3)
 	if params.ProtocolVersion != "" {
 		supportedVersion, err = stringToUint16(params.ProtocolVersion)
 		if err != nil {
@@ -100,16 +122,17 @@ func PerformHandshake(params openapi.TlsClientParameters) ([]byte, []byte, error
 		}
 	}
 
+	// 拡張の順序はuTLSで送信するClientHelloSpecと同じにする
 	exts := []extensions.Extension{
 		*extensions.NewServerNameExtension(params.ServerName),
-		*extensions.NewSupportedVersionsExtension([]uint16{supportedVersion}),
-		*extensions.NewSignatureAlgorithmsExtension(sigAlgs),
 		*extensions.NewSupportedGroupsExtension(supportedGroups),
+		*extensions.NewKeyShareExtension(keyShareEntries),
+		*extensions.NewSignatureAlgorithmsExtension(sigAlgs),
+		*extensions.NewSupportedVersionsExtension([]uint16{supportedVersion}),
 	}
-	exts = append(exts, *extensions.NewKeyShareExtension(keyShareEntries))
 
 	// --- ClientHello構築 ---
-	client, err := handshake2.NewClientHello(exts)
+	hello, err := handshake.NewClientHello(exts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("handshake.NewClientHello error: %w", err)
 	}
@@ -123,7 +146,7 @@ func PerformHandshake(params openapi.TlsClientParameters) ([]byte, []byte, error
 	for i, cs := range cipherSuites {
 		cipherSuitesConverted[i] = common.CipherSuite(cs)
 	}
-	client.CipherSuites = cipherSuitesConverted
+	hello.CipherSuites = cipherSuitesConverted
 
 	// ClientRandom
 	if params.ClientRandom != "" {
@@ -132,29 +155,29 @@ func PerformHandshake(params openapi.TlsClientParameters) ([]byte, []byte, error
 			return nil, nil, fmt.Errorf("failed to decode ClientRandom: %w", err)
 		}
 		if len(randomBytes) == 32 {
-			copy(client.Random[:], randomBytes)
+			copy(hello.Random[:], randomBytes)
 		}
 	}
 
-	// --- ハンドシェイク実行 ---
-
-	clientHandshake := handshake.NewHandshake(common.ClientHello, client.Marshal())
-	clientRecord, err := record.NewTLSRecord(common.Handshake, clientHandshake.Marshal())
-	if err != nil {
-		return nil, nil, fmt.Errorf("record.NewTLSRecord error: %w", err)
-	}
-
-	sentBytes := clientRecord.Marshal()
-	_, err = conn.Write(sentBytes)
-	if err != nil {
-		return sentBytes, nil, fmt.Errorf("conn.Write error: %w", err)
+	// LegacySessionID
+	if params.SessionId != nil {
+		sessionID, err := hex.DecodeString(*params.SessionId)
+		if err != nil || len(sessionID) > 32 {
+			return nil, nil, fmt.Errorf("invalid SessionId: %s", *params.SessionId)
+		}
+		hello.LegacySessionID = sessionID
 	}
 
-	buffer := make([]byte, 8192)
-	n, err := conn.Read(buffer)
-	if err != nil {
-		return sentBytes, nil, fmt.Errorf("conn.Read error: %w", err)
+	// LegacyCompressionMethods
+	if params.CompressionMethods != nil {
+		methods, err := stringsToUint16(*params.CompressionMethods)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse CompressionMethods: %w", err)
+		}
+		hello.LegacyCompressionMethods = make([]byte, len(methods))
+		for i, m := range methods {
+			hello.LegacyCompressionMethods[i] = byte(m)
+		}
 	}
-
-	return sentBytes, buffer[:n], nil
+	return hello, keyShares, nil
 }
@@ -0,0 +1,220 @@
+package mytls
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/refraction-networking/utls/server/mytls/internal/common"
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// startServer は、crypto/tlsのTLS 1.3サーバーを起動し、アドレスと
+// ハンドシェイクの結果 (エラー) を受け取るチャネルを返します。
+func startServer(t *testing.T, keyLog *bytes.Buffer, configure func(*tls.Config)) (*net.TCPAddr, <-chan error) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: priv}},
+		MinVersion:   tls.VersionTLS13,
+		KeyLogWriter: keyLog,
+	}
+	if configure != nil {
+		configure(config)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	done := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		done <- tls.Server(conn, config).Handshake()
+	}()
+	return listener.Addr().(*net.TCPAddr), done
+}
+
+// keyLogSecrets は、NSS Key Log Formatの内容をラベルごとの値にします。
+func keyLogSecrets(t *testing.T, keyLog *bytes.Buffer) map[string][]byte {
+	t.Helper()
+	secrets := map[string][]byte{}
+	scanner := bufio.NewScanner(keyLog)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := hex.DecodeString(fields[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets[fields[0]] = value
+	}
+	return secrets
+}
+
+func TestPerformHandshake(t *testing.T) {
+	tests := []struct {
+		name          string
+		keyShares     []string
+		cipherSuites  []string
+		configure     func(*tls.Config)
+		wantSuite     common.CipherSuite
+		wantHRR       bool
+		wantCertReq   bool
+		expectingErr  bool
+		serverFailure bool
+	}{
+		{
+			name:         "正常系：X25519とAES-128-GCM",
+			keyShares:    []string{"0x001d"},
+			cipherSuites: []string{"0x1301"},
+			wantSuite:    common.TLS_AES_128_GCM_SHA256,
+		},
+		{
+			name:         "正常系：P-256とAES-256-GCM",
+			keyShares:    []string{"0x0017"},
+			cipherSuites: []string{"0x1302"},
+			configure:    func(c *tls.Config) { c.CurvePreferences = []tls.CurveID{tls.CurveP256} },
+			wantSuite:    common.TLS_AES_256_GCM_SHA384,
+		},
+		{
+			name:         "正常系：ChaCha20-Poly1305",
+			keyShares:    []string{"0x001d"},
+			cipherSuites: []string{"0x1303"},
+			wantSuite:    common.TLS_CHACHA20_POLY1305_SHA256,
+		},
+		{
+			name:         "正常系：HelloRetryRequestでX25519を要求される",
+			keyShares:    []string{"0x0017"},
+			cipherSuites: []string{"0x1301"},
+			configure:    func(c *tls.Config) { c.CurvePreferences = []tls.CurveID{tls.X25519} },
+			wantSuite:    common.TLS_AES_128_GCM_SHA256,
+			wantHRR:      true,
+		},
+		{
+			name:         "正常系：CertificateRequestには空のCertificateで応答する",
+			keyShares:    []string{"0x001d"},
+			cipherSuites: []string{"0x1301"},
+			configure:    func(c *tls.Config) { c.ClientAuth = tls.RequestClientCert },
+			wantSuite:    common.TLS_AES_128_GCM_SHA256,
+			wantCertReq:  true,
+		},
+		{
+			name:          "異常系：共通のグループがない",
+			keyShares:     []string{"0x001d"},
+			cipherSuites:  []string{"0x1301"},
+			configure:     func(c *tls.Config) { c.CurvePreferences = []tls.CurveID{tls.CurveP384} },
+			expectingErr:  true,
+			serverFailure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keyLog bytes.Buffer
+			addr, done := startServer(t, &keyLog, tt.configure)
+			address := addr.IP.String()
+			params := openapi.TlsClientParameters{
+				ServerName:          "localhost",
+				Address:             &address,
+				Port:                &addr.Port,
+				ProtocolVersion:     "0x0304",
+				CipherSuites:        tt.cipherSuites,
+				SupportedGroups:     []string{"0x001d", "0x0017"},
+				KeyShares:           tt.keyShares,
+				SignatureAlgorithms: []string{"0x0403", "0x0804"},
+			}
+
+			dial := func(ctx context.Context) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "tcp", addr.String())
+			}
+			result, err := PerformHandshake(context.Background(), dial, params)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("PerformHandshake() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if len(result.ClientHelloRecord) == 0 || !bytes.HasPrefix(result.Sent, result.ClientHelloRecord) {
+				t.Errorf("ClientHelloRecord is not the beginning of Sent")
+			}
+			serverErr := <-done
+			if (serverErr != nil) != tt.serverFailure {
+				t.Fatalf("server handshake error = %v, want failure %v", serverErr, tt.serverFailure)
+			}
+			if tt.expectingErr {
+				return
+			}
+
+			if result.CipherSuite != tt.wantSuite {
+				t.Errorf("CipherSuite = %v, want %v", result.CipherSuite, tt.wantSuite)
+			}
+			if result.HelloRetryRequest != tt.wantHRR {
+				t.Errorf("HelloRetryRequest = %v, want %v", result.HelloRetryRequest, tt.wantHRR)
+			}
+			var types []common.HandshakeType
+			for _, m := range result.ServerFlight {
+				types = append(types, m.Type)
+			}
+			want := []common.HandshakeType{common.ServerHello, common.EncryptedExtensions, common.Certificate, common.CertificateVerify, common.Finished}
+			if tt.wantHRR {
+				want = append([]common.HandshakeType{common.ServerHello}, want...)
+			}
+			if tt.wantCertReq {
+				want = []common.HandshakeType{common.ServerHello, common.EncryptedExtensions, common.CertificateRequest, common.Certificate, common.CertificateVerify, common.Finished}
+			}
+			if len(types) != len(want) {
+				t.Fatalf("ServerFlight = %v, want %v", types, want)
+			}
+			for i := range want {
+				if types[i] != want[i] {
+					t.Fatalf("ServerFlight = %v, want %v", types, want)
+				}
+			}
+
+			// サーバー側のキーログと導出したシークレットが一致する
+			secrets := keyLogSecrets(t, &keyLog)
+			for label, name := range map[string]string{
+				"CLIENT_HANDSHAKE_TRAFFIC_SECRET": SecretClientHandshakeTraffic,
+				"SERVER_HANDSHAKE_TRAFFIC_SECRET": SecretServerHandshakeTraffic,
+				"CLIENT_TRAFFIC_SECRET_0":         SecretClientApplicationTraffic,
+				"SERVER_TRAFFIC_SECRET_0":         SecretServerApplicationTraffic,
+			} {
+				if !bytes.Equal(secrets[label], result.Secret(name)) {
+					t.Errorf("%s = %x, want %x", name, result.Secret(name), secrets[label])
+				}
+			}
+		})
+	}
+}
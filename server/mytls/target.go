@@ -0,0 +1,62 @@
+package mytls
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+// DefaultPort は、portが指定されなかった場合に使用する接続先ポート番号です。
+const DefaultPort = 443
+
+// DialTarget は、リクエストのパラメータから接続先のホストとポートを決定します。
+// addressが指定されない場合はserver_nameを、portが指定されない場合は443を使用します。
+// ホスト名またはIPアドレスとして不正な値、範囲外のポート番号はエラーになります。
+func DialTarget(params openapi.TlsClientParameters) (string, int, error) {
+	host := params.ServerName
+	if params.Address != nil {
+		host = *params.Address
+	}
+	if err := validateHost(host); err != nil {
+		return "", 0, err
+	}
+
+	port := DefaultPort
+	if params.Port != nil {
+		port = *params.Port
+	}
+	if port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port: %d", port)
+	}
+	return host, port, nil
+}
+
+// validateHost は、hostがIPアドレスまたはRFC 1123形式のホスト名であることを確認します。
+// ポート番号付きのアドレス ("host:443") は受け付けません。
+func validateHost(host string) error {
+	if host == "" {
+		return fmt.Errorf("invalid address: empty host")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("invalid address: %q is too long", host)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return fmt.Errorf("invalid address: %q", host)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("invalid address: %q", host)
+		}
+		for _, c := range label {
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return fmt.Errorf("invalid address: %q", host)
+			}
+		}
+	}
+	return nil
+}
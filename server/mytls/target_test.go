@@ -0,0 +1,96 @@
+package mytls
+
+import (
+	"testing"
+
+	"github.com/refraction-networking/utls/server/openapi"
+)
+
+func TestDialTarget(t *testing.T) {
+	ptr := func(v string) *string { return &v }
+	port := func(v int) *int { return &v }
+
+	tests := []struct {
+		name         string
+		address      *string
+		port         *int
+		wantHost     string
+		wantPort     int
+		expectingErr bool
+	}{
+		{
+			name:     "正常系：省略時はserver_nameの443番ポート",
+			wantHost: "www.example.com",
+			wantPort: 443,
+		},
+		{
+			name:     "正常系：IPv4アドレスとポートを指定",
+			address:  ptr("127.0.0.1"),
+			port:     port(8443),
+			wantHost: "127.0.0.1",
+			wantPort: 8443,
+		},
+		{
+			name:     "正常系：IPv6アドレスを指定",
+			address:  ptr("::1"),
+			wantHost: "::1",
+			wantPort: 443,
+		},
+		{
+			name:     "正常系：SNIと異なるホスト名を指定",
+			address:  ptr("localhost"),
+			wantHost: "localhost",
+			wantPort: 443,
+		},
+		{
+			name:         "異常系：ポート付きのアドレス",
+			address:      ptr("127.0.0.1:443"),
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：空のアドレス",
+			address:      ptr(""),
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：不正な文字を含むホスト名",
+			address:      ptr("example.com/path"),
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：ハイフンで始まるラベル",
+			address:      ptr("-example.com"),
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：ポート番号が0",
+			port:         port(0),
+			expectingErr: true,
+		},
+		{
+			name:         "異常系：ポート番号が範囲外",
+			port:         port(65536),
+			expectingErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := openapi.TlsClientParameters{
+				ServerName: "www.example.com",
+				Address:    tt.address,
+				Port:       tt.port,
+			}
+			host, port, err := DialTarget(params)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("DialTarget() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if tt.expectingErr {
+				return
+			}
+			if host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("DialTarget() = %s, %d, want %s, %d", host, port, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
@@ -15,9 +15,56 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for ClientHelloExtensionRenegotiation, ClientKeyExchangeMessageKeyExchange, ErrorCode, HandshakeStepMessageDirection, HttpRequestProtocol, TlsClientParametersVerifyMode, TlsRecordDirection.
+const (
+	ClientHelloExtensionRenegotiationNever  ClientHelloExtensionRenegotiation = "never"
+	ClientHelloExtensionRenegotiationOnce   ClientHelloExtensionRenegotiation = "once"
+	ClientHelloExtensionRenegotiationFreely ClientHelloExtensionRenegotiation = "freely"
+
+	ClientKeyExchangeMessageKeyExchangeECDHE ClientKeyExchangeMessageKeyExchange = "ECDHE"
+	ClientKeyExchangeMessageKeyExchangeRSA   ClientKeyExchangeMessageKeyExchange = "RSA"
+
+	ErrorCodeSchemaViolation               ErrorCode = "schema_violation"
+	ErrorCodeInvalidHex                    ErrorCode = "invalid_hex"
+	ErrorCodeInvalidCodepoint              ErrorCode = "invalid_codepoint"
+	ErrorCodeInvalidClientRandom           ErrorCode = "invalid_client_random"
+	ErrorCodeInvalidSessionId              ErrorCode = "invalid_session_id"
+	ErrorCodeKeyShareNotInSupportedGroups  ErrorCode = "key_share_not_in_supported_groups"
+	ErrorCodeUnknownPreset                 ErrorCode = "unknown_preset"
+	ErrorCodeInvalidRequest                ErrorCode = "invalid_request"
+	ErrorCodeHandshakeFailed               ErrorCode = "handshake_failed"
+	ErrorCodeCertificateVerificationFailed ErrorCode = "certificate_verification_failed"
+	ErrorCodeSessionNotFound               ErrorCode = "session_not_found"
+	ErrorCodeTimeout                       ErrorCode = "timeout"
+	ErrorCodeDestinationNotAllowed         ErrorCode = "destination_not_allowed"
+	ErrorCodeTooManyConnections            ErrorCode = "too_many_connections"
+	ErrorCodeRateLimited                   ErrorCode = "rate_limited"
+	ErrorCodeTooManySessions               ErrorCode = "too_many_sessions"
+	ErrorCodeTooManySavedSessions          ErrorCode = "too_many_saved_sessions"
+	ErrorCodeRequestTooLarge               ErrorCode = "request_too_large"
+	ErrorCodeNotImplemented                ErrorCode = "not_implemented"
+	ErrorCodeInternalError                 ErrorCode = "internal_error"
+
+	HandshakeStepMessageDirectionSent     HandshakeStepMessageDirection = "sent"
+	HandshakeStepMessageDirectionReceived HandshakeStepMessageDirection = "received"
+
+	HttpRequestProtocolHttp11 HttpRequestProtocol = "http/1.1"
+	HttpRequestProtocolH2     HttpRequestProtocol = "h2"
+
+	TlsClientParametersVerifyModeSystem      TlsClientParametersVerifyMode = "system"
+	TlsClientParametersVerifyModeCustomRoots TlsClientParametersVerifyMode = "custom_roots"
+	TlsClientParametersVerifyModePinnedSpki  TlsClientParametersVerifyMode = "pinned_spki"
+	TlsClientParametersVerifyModeInsecure    TlsClientParametersVerifyMode = "insecure"
+
+	TlsRecordDirectionSent     TlsRecordDirection = "sent"
+	TlsRecordDirectionReceived TlsRecordDirection = "received"
 )
 
 // ApplicationRequest defines model for ApplicationRequest.
@@ -25,6 +72,15 @@ type ApplicationRequest = TlsClientParameters
 
 // ApplicationResponse defines model for ApplicationResponse.
 type ApplicationResponse struct {
+	// CertificateVerification サーバー証明書の検証結果。セッションを再開して証明書を受け取らなかった場合は省略されます。
+	CertificateVerification *CertificateVerification `json:"certificate_verification,omitempty"`
+
+	// Fingerprints 送信した ClientHello (JA3, JA4)、受信した ServerHello (JA3S, JA4S)、サーバー証明書 (JA4X) のフィンガープリント。 GREASE の値は除いて計算されます。
+	Fingerprints *Fingerprints `json:"fingerprints,omitempty"`
+
+	// HttpResponse http_request を指定した場合の HTTP レスポンス
+	HttpResponse *HttpResponse `json:"http_response,omitempty"`
+
 	// RawClientHello ClientHelloのバイト列 (hexエンコード)
 	RawClientHello string `json:"raw_client_hello"`
 
@@ -39,469 +95,2196 @@ type ApplicationResponse struct {
 
 	// RawServerResponseDecoded ServerHelloを含めたサーバー側の応答のバイト列を復号化したもの
 	RawServerResponseDecoded string `json:"raw_server_response_decoded"`
-}
 
-// ErrorResponse defines model for ErrorResponse.
-type ErrorResponse struct {
-	// Message エラーメッセージ
-	Message string `json:"message"`
+	// Records ハンドシェイク完了後に送受信したレコード。 TLS 1.3 ではアプリケーションデータに加えて、NewSessionTicket や KeyUpdate などのハンドシェイクメッセージも application_data として暗号化されたレコードで届くため、レコードごとに復号した結果を確認できます。
+	Records []TlsRecord `json:"records"`
 }
 
-// HandshakeRequest defines model for HandshakeRequest.
-type HandshakeRequest = TlsClientParameters
+// CertificateEntry Certificate メッセージの CertificateEntry
+type CertificateEntry struct {
+	// CertData DER エンコードされた証明書 (hexエンコード)
+	CertData string `json:"cert_data"`
 
-// HandshakeResponse TLSハンドシェイク成功時のレスポンス
-type HandshakeResponse struct {
-	// RawClientHello ClientHelloのバイト列 (hexエンコード)
-	RawClientHello string `json:"raw_client_hello"`
+	// DnsNames Subject Alternative Name の DNS 名
+	DnsNames *[]string `json:"dns_names,omitempty"`
 
-	// RawServerResponse ServerHelloを含めたサーバー側の応答のバイト列 (hexエンコード)
-	RawServerResponse string `json:"raw_server_response"`
+	// Extensions CertificateEntry の拡張のリスト
+	Extensions []HandshakeExtension `json:"extensions"`
 
-	// RawServerResponseDecoded ServerHelloを含めたサーバー側の応答のバイト列を復号化したもの
-	RawServerResponseDecoded string `json:"raw_server_response_decoded"`
-}
+	// Issuer 証明書の Issuer
+	Issuer *string `json:"issuer,omitempty"`
 
-// TlsClientParameters defines model for TlsClientParameters.
-type TlsClientParameters struct {
-	// ApplicationData 送信するアプリケーションデータ（HTTPプロトコル） 平文
-	ApplicationData *string `json:"application_data,omitempty"`
+	// NotAfter 有効期間の終了 (RFC 3339)
+	NotAfter *string `json:"not_after,omitempty"`
 
-	// CipherSuites 使用する Cipher Suite のリスト (16進数文字列)
-	CipherSuites []string `json:"cipher_suites"`
+	// NotBefore 有効期間の開始 (RFC 3339)
+	NotBefore *string `json:"not_before,omitempty"`
 
-	// ClientRandom クライアントの Random 値 (hex)
-	ClientRandom string `json:"client_random"`
+	// ParseError 証明書を解析できなかった場合のエラーメッセージ
+	ParseError *string `json:"parse_error,omitempty"`
 
-	// KeyShares KeyShare に使うアルゴリズム (楕円曲線名)
-	KeyShares []string `json:"key_shares"`
+	// SerialNumber シリアル番号 (16進数文字列)
+	SerialNumber *string `json:"serial_number,omitempty"`
 
-	// ProtocolVersion 使用する TLS バージョン
-	ProtocolVersion string `json:"protocol_version"`
+	// Subject 証明書の Subject
+	Subject *string `json:"subject,omitempty"`
+}
 
-	// ServerName Server Name Indication (SNI)拡張に設定するホスト名。指定しない場合は'server'の値が使用されます。
-	ServerName string `json:"server_name"`
+// CertificateMessage Certificate (CompressedCertificate の場合は展開後の内容)
+type CertificateMessage struct {
+	// CertificateList 証明書チェーン (サーバー証明書が先頭)
+	CertificateList []CertificateEntry `json:"certificate_list"`
 
-	// SignatureAlgorithms サポートする署名アルゴリズム
-	SignatureAlgorithms []string `json:"signature_algorithms"`
+	// CertificateRequestContext certificate_request_context (hexエンコード)
+	CertificateRequestContext string `json:"certificate_request_context"`
 
-	// SupportedGroups サポートする楕円曲線 (KeyShare Group)
-	SupportedGroups []string `json:"supported_groups"`
+	// CompressionAlgorithm CompressedCertificate で使われた圧縮アルゴリズム (16進数文字列)
+	CompressionAlgorithm *string `json:"compression_algorithm,omitempty"`
+
+	// Raw 復号したメッセージ全体のバイト列 (hexエンコード)。CompressedCertificate の場合は圧縮されたままのバイト列
+	Raw string `json:"raw"`
 }
 
-// PostTlsApplicationJSONRequestBody defines body for PostTlsApplication for application/json ContentType.
-type PostTlsApplicationJSONRequestBody = ApplicationRequest
+// CertificateRequestMessage CertificateRequest
+type CertificateRequestMessage struct {
+	// CertificateAuthorities TLS 1.2 の certificate_authorities (DER の識別名、hexエンコード)
+	CertificateAuthorities *[]string `json:"certificate_authorities,omitempty"`
 
-// PostTlsHandshakeJSONRequestBody defines body for PostTlsHandshake for application/json ContentType.
-type PostTlsHandshakeJSONRequestBody = HandshakeRequest
+	// CertificateRequestContext certificate_request_context (hexエンコード、TLS 1.2 では空)
+	CertificateRequestContext string `json:"certificate_request_context"`
 
-// RequestEditorFn  is the function signature for the RequestEditor callback function
-type RequestEditorFn func(ctx context.Context, req *http.Request) error
+	// CertificateTypes TLS 1.2 の certificate_types (16進数文字列)
+	CertificateTypes *[]string `json:"certificate_types,omitempty"`
 
-// Doer performs HTTP requests.
-//
-// The standard http.Client implements this interface.
-type HttpRequestDoer interface {
-	Do(req *http.Request) (*http.Response, error)
+	// Extensions 拡張のリスト (受信した順、TLS 1.2 では空)
+	Extensions []HandshakeExtension `json:"extensions"`
+
+	// Raw 復号したメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// SignatureAlgorithms TLS 1.2 の supported_signature_algorithms (16進数文字列)
+	SignatureAlgorithms *[]string `json:"signature_algorithms,omitempty"`
 }
 
-// Client which conforms to the OpenAPI3 specification for this service.
-type Client struct {
-	// The endpoint of the server conforming to this interface, with scheme,
-	// https://api.deepmap.com for example. This can contain a path relative
-	// to the server, such as https://api.deepmap.com/dev-test, and all the
-	// paths in the swagger spec will be appended to the server.
-	Server string
+// CertificateVerification サーバー証明書の検証結果。セッションを再開して証明書を受け取らなかった場合は省略されます。
+type CertificateVerification struct {
+	// Certificates サーバーが送信した証明書を送信順に解析したもの
+	Certificates []X509Certificate `json:"certificates"`
 
-	// Doer for performing requests, typically a *http.Client with any
-	// customized settings, such as certificate chains.
-	Client HttpRequestDoer
+	// Error 証明書チェーンまたは公開鍵のピン留めの検証に失敗した理由
+	Error *string `json:"error,omitempty"`
 
-	// A list of callbacks for modifying requests which are generated before sending over
-	// the network.
-	RequestEditors []RequestEditorFn
+	// Mode 使用した検証方法 (TlsClientParameters の verify_mode)
+	Mode string `json:"mode"`
+
+	// PinMatched verify_mode が pinned_spki の場合に、いずれかの証明書の公開鍵がピン留めした値と一致したかどうか
+	PinMatched *bool `json:"pin_matched,omitempty"`
+
+	// Verified 検証方法の条件を満たした場合に true。insecure では証明書チェーンの検証に成功した場合に true になりますが、 false でもハンドシェイクは続行されます。
+	Verified bool `json:"verified"`
+
+	// VerifiedChains ルート証明書までたどれた証明書チェーン。チェーンの検証に失敗した場合は空になります。 pinned_spki と insecure では、system と同じルート証明書でチェーンを検証します。
+	VerifiedChains []VerifiedChain `json:"verified_chains"`
 }
 
-// ClientOption allows setting custom parameters during construction
-type ClientOption func(*Client) error
+// CertificateVerifyMessage CertificateVerify
+type CertificateVerifyMessage struct {
+	// Raw 復号したメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
 
-// Creates a new Client, with reasonable defaults
-func NewClient(server string, opts ...ClientOption) (*Client, error) {
-	// create a client with sane default values
-	client := Client{
-		Server: server,
-	}
-	// mutate client and add all optional params
-	for _, o := range opts {
-		if err := o(&client); err != nil {
-			return nil, err
-		}
-	}
-	// ensure the server URL always has a trailing slash
-	if !strings.HasSuffix(client.Server, "/") {
-		client.Server += "/"
-	}
-	// create httpClient, if not already present
-	if client.Client == nil {
-		client.Client = &http.Client{}
-	}
-	return &client, nil
+	// Signature 署名 (hexエンコード)
+	Signature string `json:"signature"`
+
+	// SignatureScheme 署名アルゴリズム (16進数文字列)
+	SignatureScheme string `json:"signature_scheme"`
+
+	// SignatureSchemeName 署名アルゴリズムの名前
+	SignatureSchemeName string `json:"signature_scheme_name"`
 }
 
-// WithHTTPClient allows overriding the default Doer, which is
-// automatically created using http.Client. This is useful for tests.
-func WithHTTPClient(doer HttpRequestDoer) ClientOption {
-	return func(c *Client) error {
-		c.Client = doer
-		return nil
-	}
+// ClientCertificate サーバーから CertificateRequest を受け取ったときに送信するクライアント証明書。 certificate と private_key を PEM で指定するか、generate に true を指定して一時的な証明書を生成します。 サーバーが要求しない場合は送信されません。
+type ClientCertificate struct {
+	// Certificate クライアント証明書 (PEM)。中間証明書を含める場合は、クライアント証明書に続けて連結します。
+	Certificate *string `json:"certificate,omitempty"`
+
+	// Generate true の場合、certificate と private_key の代わりに ECDSA P-256 の自己署名証明書をリクエストごとに生成します。 certificate、private_key と同時には指定できません。
+	Generate *bool `json:"generate,omitempty"`
+
+	// PrivateKey 証明書に対応する秘密鍵 (PEM)。PKCS
+	PrivateKey *string `json:"private_key,omitempty"`
 }
 
-// WithRequestEditorFn allows setting up a callback function, which will be
-// called right before sending the request. This can be used to mutate the request.
-func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
-	return func(c *Client) error {
-		c.RequestEditors = append(c.RequestEditors, fn)
-		return nil
-	}
+// ClientHelloExtension ClientHello の拡張。name で種類を指定し、種類ごとに必要な項目を指定します。 省略できる項目を指定しない場合は、TlsClientParameters の対応する値や既定値が使用されます。
+type ClientHelloExtension struct {
+	// Algorithms compress_certificate の圧縮アルゴリズム、token_binding の鍵パラメータ (16進数文字列)
+	Algorithms *[]string `json:"algorithms,omitempty"`
+
+	// Data 拡張の中身 (hex)。raw の場合はそのまま送信され、GREASE の場合は中身、cookie の場合は cookie の値、 session_ticket の場合はチケット、quic_transport_parameters の場合はエンコード済みのトランスポートパラメータになります。
+	Data *string `json:"data,omitempty"`
+
+	// Groups supported_groups (省略時は TlsClientParameters の supported_groups)、key_share (省略時は key_shares) のグループ (16進数文字列または "GREASE")
+	Groups *[]string `json:"groups,omitempty"`
+
+	// Modes psk_key_exchange_modes のモード (16進数文字列、省略時は psk_dhe_ke (0x01))
+	Modes *[]string `json:"modes,omitempty"`
+
+	// Name 拡張の名前 (IANA登録名)。次のものに対応しています。 server_name, status_request, supported_groups, ec_point_formats, signature_algorithms, application_layer_protocol_negotiation, status_request_v2, signed_certificate_timestamp, padding, extended_master_secret, token_binding, compress_certificate, record_size_limit, delegated_credentials, session_ticket, supported_versions, cookie, psk_key_exchange_modes, signature_algorithms_cert, key_share, quic_transport_parameters, next_protocol_negotiation, application_settings, application_settings_new, channel_id, channel_id_old, renegotiation_info, encrypted_client_hello (GREASE ECH)。 resumption の session_handle を指定した /tls/handshake では pre_shared_key も指定できます (最後に指定してください)。 このほか、GREASE 値の拡張を表す "GREASE" と、任意の種類と中身を指定する "raw" を指定できます。
+	Name string `json:"name"`
+
+	// PaddingLength padding の長さ。省略時は BoringSSL と同じ方法で ClientHello の長さから決まります。
+	PaddingLength *int `json:"padding_length,omitempty"`
+
+	// PointFormats ec_point_formats の形式 (16進数文字列、省略時は uncompressed (0x00))
+	PointFormats *[]string `json:"point_formats,omitempty"`
+
+	// Protocols application_layer_protocol_negotiation、application_settings(_new)、next_protocol_negotiation のプロトコル名
+	Protocols *[]string `json:"protocols,omitempty"`
+
+	// RecordSizeLimit record_size_limit に設定する値
+	RecordSizeLimit *int `json:"record_size_limit,omitempty"`
+
+	// Renegotiation renegotiation_info で許可する再ネゴシエーションの回数 (省略時は once)
+	Renegotiation *ClientHelloExtensionRenegotiation `json:"renegotiation,omitempty"`
+
+	// ServerName server_name に設定するホスト名。省略時は TlsClientParameters の server_name
+	ServerName *string `json:"server_name,omitempty"`
+
+	// SignatureAlgorithms signature_algorithms (省略時は TlsClientParameters の signature_algorithms)、signature_algorithms_cert、delegated_credentials の署名アルゴリズム (16進数文字列)
+	SignatureAlgorithms *[]string `json:"signature_algorithms,omitempty"`
+
+	// Type 拡張の種類 (16進数文字列)。name が "raw" の場合に指定します。
+	Type *string `json:"type,omitempty"`
+
+	// Versions supported_versions のバージョン (16進数文字列または "GREASE"、省略時は protocol_version)。token_binding の場合はプロトコルバージョン (例 '0x000d') を1つ指定します。
+	Versions *[]string `json:"versions,omitempty"`
 }
 
-// The interface specification for the client above.
-type ClientInterface interface {
-	// PostTlsApplicationWithBody request with any body
-	PostTlsApplicationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+// ClientHelloExtensionRenegotiation renegotiation_info で許可する再ネゴシエーションの回数 (省略時は once)
+type ClientHelloExtensionRenegotiation string
 
-	PostTlsApplication(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+// ClientKeyExchangeMessage TLS 1.2 でクライアントが送信した ClientKeyExchange
+type ClientKeyExchangeMessage struct {
+	// EncryptedPreMasterSecret RSA の場合のサーバーの公開鍵で暗号化した pre_master_secret (hexエンコード)
+	EncryptedPreMasterSecret *string `json:"encrypted_pre_master_secret,omitempty"`
 
-	// PostTlsHandshakeWithBody request with any body
-	PostTlsHandshakeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+	// KeyExchange 鍵交換の方式
+	KeyExchange ClientKeyExchangeMessageKeyExchange `json:"key_exchange"`
 
-	PostTlsHandshake(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+	// PublicKey ECDHE の場合のクライアントの一時公開鍵 (hexエンコード)
+	PublicKey *string `json:"public_key,omitempty"`
+
+	// Raw メッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
 }
 
-func (c *Client) PostTlsApplicationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostTlsApplicationRequestWithBody(c.Server, contentType, body)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+// ClientKeyExchangeMessageKeyExchange 鍵交換の方式
+type ClientKeyExchangeMessageKeyExchange string
+
+// CompareImplementationResult 一方の実装でのハンドシェイクの結果
+type CompareImplementationResult struct {
+	// Error ハンドシェイクが失敗した場合のエラーメッセージ
+	Error *string `json:"error,omitempty"`
+
+	// Failure ハンドシェイクが失敗したときの状況。 サーバーから Alert を受信したのか、クライアント側で Alert を送信して中断したのか、 どのメッセージまで進んだのかを確認できます。
+	Failure *HandshakeFailure `json:"failure,omitempty"`
+
+	// RawClientHello 送信した最初の ClientHello のレコード (hexエンコード)
+	RawClientHello string `json:"raw_client_hello"`
+
+	// RawServerResponse サーバーから受信したレコード (hexエンコード)
+	RawServerResponse string `json:"raw_server_response"`
+
+	// Success ハンドシェイクが成功したかどうか
+	Success bool `json:"success"`
 }
 
-func (c *Client) PostTlsApplication(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostTlsApplicationRequest(c.Server, body)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+// CompareResponse uTLS と独自実装 (mytls) のハンドシェイクの比較結果
+type CompareResponse struct {
+	// ClientHello ClientHello (Record Layer のヘッダを含む) のフィールドごとの比較結果 (メッセージ内の順)
+	ClientHello []FieldDiff `json:"client_hello"`
+
+	// Identical volatile でないすべてのフィールドが一致し、両方のハンドシェイクが成功した場合に true
+	Identical bool `json:"identical"`
+
+	// Mytls 一方の実装でのハンドシェイクの結果
+	Mytls CompareImplementationResult `json:"mytls"`
+
+	// ServerFlight サーバーから届いたハンドシェイクメッセージ (復号後) のフィールドごとの比較結果
+	ServerFlight []FieldDiff `json:"server_flight"`
+
+	// Utls 一方の実装でのハンドシェイクの結果
+	Utls CompareImplementationResult `json:"utls"`
 }
 
-func (c *Client) PostTlsHandshakeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostTlsHandshakeRequestWithBody(c.Server, contentType, body)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+// EncryptedExtensionsMessage EncryptedExtensions
+type EncryptedExtensionsMessage struct {
+	// Extensions 拡張のリスト (受信した順)
+	Extensions []HandshakeExtension `json:"extensions"`
+
+	// Raw 復号したメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
 }
 
-func (c *Client) PostTlsHandshake(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewPostTlsHandshakeRequest(c.Server, body)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
-		return nil, err
-	}
-	return c.Client.Do(req)
+// ErrorCode エラーの種類。 schema_violation はリクエストが OpenAPI のスキーマに一致しないこと、 invalid_hex, invalid_codepoint, invalid_client_random, invalid_session_id, key_share_not_in_supported_groups, unknown_preset はリクエストの値が不正であることを表します (詳細は errors)。 handshake_failed はハンドシェイクの失敗 (詳細は failure)、certificate_verification_failed は 証明書の検証の失敗 (詳細は certificate_verification)、invalid_request はそれ以外の不正なリクエストを表します。 timeout はリクエストの期限までにハンドシェイクが終わらなかったこと、destination_not_allowed は 接続先がサーバーの設定で許可されていないこと、too_many_connections は同時接続数の上限に達していること、 rate_limited はクライアントごとのリクエスト数の上限に達していること、too_many_sessions は /tls/sessions で保持できるセッション数の上限に達していること、too_many_saved_sessions は resumption.save_session で保存できるセッション数の上限に達していること、request_too_large は リクエストボディが上限のサイズを超えていること、not_implemented は 0-RTT の早期データのように 実装されていない機能が指定されたことを表します。
+type ErrorCode string
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// CertificateVerification サーバー証明書の検証結果。セッションを再開して証明書を受け取らなかった場合は省略されます。
+	CertificateVerification *CertificateVerification `json:"certificate_verification,omitempty"`
+
+	// Code エラーの種類。 schema_violation はリクエストが OpenAPI のスキーマに一致しないこと、 invalid_hex, invalid_codepoint, invalid_client_random, invalid_session_id, key_share_not_in_supported_groups, unknown_preset はリクエストの値が不正であることを表します (詳細は errors)。 handshake_failed はハンドシェイクの失敗 (詳細は failure)、certificate_verification_failed は 証明書の検証の失敗 (詳細は certificate_verification)、invalid_request はそれ以外の不正なリクエストを表します。 timeout はリクエストの期限までにハンドシェイクが終わらなかったこと、destination_not_allowed は 接続先がサーバーの設定で許可されていないこと、too_many_connections は同時接続数の上限に達していること、 rate_limited はクライアントごとのリクエスト数の上限に達していること、too_many_sessions は /tls/sessions で保持できるセッション数の上限に達していること、too_many_saved_sessions は resumption.save_session で保存できるセッション数の上限に達していること、request_too_large は リクエストボディが上限のサイズを超えていること、not_implemented は 0-RTT の早期データのように 実装されていない機能が指定されたことを表します。
+	Code ErrorCode `json:"code"`
+
+	// Errors リクエストの検証に失敗した場合の、項目ごとのエラー
+	Errors *[]ValidationError `json:"errors,omitempty"`
+
+	// Failure ハンドシェイクが失敗したときの状況。 サーバーから Alert を受信したのか、クライアント側で Alert を送信して中断したのか、 どのメッセージまで進んだのかを確認できます。
+	Failure *HandshakeFailure `json:"failure,omitempty"`
+
+	// Message エラーメッセージ
+	Message string `json:"message"`
+
+	// MytlsError 同じパラメータで独自実装 (mytls) のハンドシェイクを行った場合のエラーメッセージ
+	MytlsError *string `json:"mytls_error,omitempty"`
+
+	// RawClientHello ClientHelloのバイト列 (hexエンコード)
+	RawClientHello string `json:"raw_client_hello"`
+
+	// RawServerResponse ServerHelloを含めたサーバー側の応答のバイト列 (hexエンコード)
+	RawServerResponse string `json:"raw_server_response"`
 }
 
-// NewPostTlsApplicationRequest calls the generic PostTlsApplication builder with application/json body
-func NewPostTlsApplicationRequest(server string, body PostTlsApplicationJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	bodyReader = bytes.NewReader(buf)
-	return NewPostTlsApplicationRequestWithBody(server, "application/json", bodyReader)
+// FieldDiff 1つのフィールドの比較結果。一方にしか存在しないフィールドは、もう一方の値が省略されます。
+type FieldDiff struct {
+	// Equal 両方の値が一致するかどうか
+	Equal bool `json:"equal"`
+
+	// Field フィールドの名前
+	Field string `json:"field"`
+
+	// Mytls 独自実装での値
+	Mytls *string `json:"mytls,omitempty"`
+
+	// Utls uTLS での値
+	Utls *string `json:"utls,omitempty"`
+
+	// Volatile 乱数や一時的な鍵など接続ごとに変わる値で、長さが同じため実装の違いによる差分ではないと考えられる場合に true
+	Volatile bool `json:"volatile"`
 }
 
-// NewPostTlsApplicationRequestWithBody generates requests for PostTlsApplication with any type of body
-func NewPostTlsApplicationRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
+// Fingerprints 送信した ClientHello (JA3, JA4)、受信した ServerHello (JA3S, JA4S)、サーバー証明書 (JA4X) のフィンガープリント。 GREASE の値は除いて計算されます。
+type Fingerprints struct {
+	// Ja3 JA3 の文字列 (SSLVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats)
+	Ja3 string `json:"ja3"`
 
-	serverURL, err := url.Parse(server)
-	if err != nil {
-		return nil, err
-	}
+	// Ja3Hash JA3 の文字列の MD5 ハッシュ (hexエンコード)
+	Ja3Hash string `json:"ja3_hash"`
 
-	operationPath := fmt.Sprintf("/tls/application")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
-	}
+	// Ja3s JA3S の文字列 (SSLVersion,Cipher,Extensions)。HelloRetryRequest ではなく ServerHello から計算します。
+	Ja3s *string `json:"ja3s,omitempty"`
 
-	queryURL, err := serverURL.Parse(operationPath)
-	if err != nil {
-		return nil, err
-	}
+	// Ja3sHash JA3S の文字列の MD5 ハッシュ (hexエンコード)
+	Ja3sHash *string `json:"ja3s_hash,omitempty"`
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
-		return nil, err
-	}
+	// Ja4 JA4 フィンガープリント
+	Ja4 string `json:"ja4"`
 
-	req.Header.Add("Content-Type", contentType)
+	// Ja4R JA4 のハッシュ前の値 (JA4_r)。暗号スイートと拡張はソートされ、署名アルゴリズムは送信順です。
+	Ja4R string `json:"ja4_r"`
 
-	return req, nil
+	// Ja4s JA4S フィンガープリント。TLS 1.3 では ALPN が EncryptedExtensions で送信されるため、ALPN の部分は 00 になります。
+	Ja4s *string `json:"ja4s,omitempty"`
+
+	// Ja4sR JA4S のハッシュ前の値 (JA4S_r)。拡張は受信順です。
+	Ja4sR *string `json:"ja4s_r,omitempty"`
+
+	// Ja4x サーバーが送信した証明書ごとの JA4X フィンガープリント (送信順)
+	Ja4x *[]string `json:"ja4x,omitempty"`
+
+	// Ja4xR サーバーが送信した証明書ごとの JA4X のハッシュ前の値 (発行者、サブジェクト、拡張の OID)
+	Ja4xR *[]string `json:"ja4x_r,omitempty"`
 }
 
-// NewPostTlsHandshakeRequest calls the generic PostTlsHandshake builder with application/json body
-func NewPostTlsHandshakeRequest(server string, body PostTlsHandshakeJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	bodyReader = bytes.NewReader(buf)
-	return NewPostTlsHandshakeRequestWithBody(server, "application/json", bodyReader)
+// FinishedMessage Finished
+type FinishedMessage struct {
+	// Raw 復号したメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// VerifyData verify_data (hexエンコード)
+	VerifyData string `json:"verify_data"`
 }
 
-// NewPostTlsHandshakeRequestWithBody generates requests for PostTlsHandshake with any type of body
-func NewPostTlsHandshakeRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
+// HandshakeExtension ハンドシェイクメッセージに含まれる拡張
+type HandshakeExtension struct {
+	// Data 拡張の中身 (hexエンコード)
+	Data string `json:"data"`
 
-	serverURL, err := url.Parse(server)
-	if err != nil {
-		return nil, err
-	}
+	// Name 拡張の名前 (IANA登録名、不明な場合は空文字列)
+	Name string `json:"name"`
 
-	operationPath := fmt.Sprintf("/tls/handshake")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
-	}
+	// Type 拡張の種類 (16進数文字列)
+	Type string `json:"type"`
+}
+
+// HandshakeFailure ハンドシェイクが失敗したときの状況。 サーバーから Alert を受信したのか、クライアント側で Alert を送信して中断したのか、 どのメッセージまで進んだのかを確認できます。
+type HandshakeFailure struct {
+	// LastMessage 送信または受信した1つのハンドシェイクメッセージ。 解析結果は、メッセージの種類に応じて client_hello、server_flight、finished、client_key_exchange、 client_certificate、client_certificate_verify のいずれかに含まれます。
+	LastMessage *HandshakeStepMessage `json:"last_message,omitempty"`
+
+	// ReceivedAlert TLS の Alert (RFC 8446 6章)
+	ReceivedAlert *TlsAlert `json:"received_alert,omitempty"`
+
+	// SentAlert TLS の Alert (RFC 8446 6章)
+	SentAlert *TlsAlert `json:"sent_alert,omitempty"`
+
+	// State 失敗したときのクライアントの状態。TLS 1.3 は RFC 8446 付録A.1 の状態名 (START, WAIT_SH, WAIT_EE, WAIT_CERT_CR, WAIT_CERT, WAIT_CV, WAIT_FINISHED, CONNECTED) です。 TLS 1.2 の WAIT_SKE (ServerKeyExchange 待ち)、WAIT_SHD (ServerHelloDone 待ち) と、 サーバーのメッセージを受け取り終えてクライアントが Finished までを送信している SEND_FINISHED は、独自の状態名です。
+	State string `json:"state"`
+}
+
+// HandshakeRequest defines model for HandshakeRequest.
+type HandshakeRequest = TlsClientParameters
+
+// HandshakeResponse TLSハンドシェイク成功時のレスポンス
+type HandshakeResponse struct {
+	// CertificateVerification サーバー証明書の検証結果。セッションを再開して証明書を受け取らなかった場合は省略されます。
+	CertificateVerification *CertificateVerification `json:"certificate_verification,omitempty"`
+
+	// ClientCertificate Certificate (CompressedCertificate の場合は展開後の内容)
+	ClientCertificate *CertificateMessage `json:"client_certificate,omitempty"`
+
+	// ClientCertificateVerify CertificateVerify
+	ClientCertificateVerify *CertificateVerifyMessage `json:"client_certificate_verify,omitempty"`
+
+	// ClientKeyExchange TLS 1.2 でクライアントが送信した ClientKeyExchange
+	ClientKeyExchange *ClientKeyExchangeMessage `json:"client_key_exchange,omitempty"`
+
+	// Fingerprints 送信した ClientHello (JA3, JA4)、受信した ServerHello (JA3S, JA4S)、サーバー証明書 (JA4X) のフィンガープリント。 GREASE の値は除いて計算されます。
+	Fingerprints *Fingerprints `json:"fingerprints,omitempty"`
+
+	// KeySchedule 鍵スケジュールで導出された値を導出順に並べたもの。 TLS 1.2 の場合は pre_master_secret、PRF で導出した master_secret、key_block を分割した鍵が含まれます。
+	KeySchedule *[]KeyScheduleStep `json:"key_schedule,omitempty"`
+
+	// RawClientHello ClientHelloのバイト列 (hexエンコード)
+	RawClientHello string `json:"raw_client_hello"`
+
+	// RawServerResponse ServerHelloを含めたサーバー側の応答のバイト列 (hexエンコード)
+	RawServerResponse string `json:"raw_server_response"`
+
+	// RawServerResponseDecoded ServerHelloを含めたサーバー側の応答のバイト列を復号化したもの
+	RawServerResponseDecoded string `json:"raw_server_response_decoded"`
+
+	// Resumption セッションの再開の結果。リクエストで resumption を指定した場合に含まれます。
+	Resumption *ResumptionResult `json:"resumption,omitempty"`
+
+	// ServerFlight サーバーから届いたハンドシェイクメッセージをメッセージごとに復号・解析したもの。サーバーが送信しなかったメッセージは省略されます。
+	ServerFlight *ServerFlight `json:"server_flight,omitempty"`
+}
+
+// HandshakeStepMessage 送信または受信した1つのハンドシェイクメッセージ。 解析結果は、メッセージの種類に応じて client_hello、server_flight、finished、client_key_exchange、 client_certificate、client_certificate_verify のいずれかに含まれます。
+type HandshakeStepMessage struct {
+	// ClientCertificate Certificate (CompressedCertificate の場合は展開後の内容)
+	ClientCertificate *CertificateMessage `json:"client_certificate,omitempty"`
+
+	// ClientCertificateVerify CertificateVerify
+	ClientCertificateVerify *CertificateVerifyMessage `json:"client_certificate_verify,omitempty"`
+
+	// ClientHello 送信した ClientHello をフィールドに分解したもの
+	ClientHello *[]MessageField `json:"client_hello,omitempty"`
+
+	// ClientKeyExchange TLS 1.2 でクライアントが送信した ClientKeyExchange
+	ClientKeyExchange *ClientKeyExchangeMessage `json:"client_key_exchange,omitempty"`
+
+	// Direction クライアントが送信したメッセージか、サーバーから受信したメッセージか
+	Direction HandshakeStepMessageDirection `json:"direction"`
+
+	// Encrypted 暗号化されたレコードで送受信された場合に true
+	Encrypted bool `json:"encrypted"`
+
+	// Finished Finished
+	Finished *FinishedMessage `json:"finished,omitempty"`
+
+	// Raw ヘッダを含むメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// ServerFlight サーバーから届いたハンドシェイクメッセージをメッセージごとに復号・解析したもの。サーバーが送信しなかったメッセージは省略されます。
+	ServerFlight *ServerFlight `json:"server_flight,omitempty"`
+
+	// Type HandshakeType (RFC 8446 4章)
+	Type int `json:"type"`
+
+	// TypeName HandshakeType の名前
+	TypeName string `json:"type_name"`
+}
+
+// HandshakeStepMessageDirection クライアントが送信したメッセージか、サーバーから受信したメッセージか
+type HandshakeStepMessageDirection string
+
+// HandshakeStepResponse ハンドシェイクを1メッセージ進めた結果
+type HandshakeStepResponse struct {
+	// CertificateVerification サーバー証明書の検証結果。セッションを再開して証明書を受け取らなかった場合は省略されます。
+	CertificateVerification *CertificateVerification `json:"certificate_verification,omitempty"`
+
+	// Done ハンドシェイクが完了または失敗し、これ以上進められない場合に true
+	Done bool `json:"done"`
+
+	// Error ハンドシェイクが失敗した場合のエラーメッセージ
+	Error *string `json:"error,omitempty"`
+
+	// ExpiresAt 操作がなかった場合にセッションが破棄される時刻
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Failure ハンドシェイクが失敗したときの状況。 サーバーから Alert を受信したのか、クライアント側で Alert を送信して中断したのか、 どのメッセージまで進んだのかを確認できます。
+	Failure *HandshakeFailure `json:"failure,omitempty"`
+
+	// Index このメッセージの番号 (0から始まる)。done が true の場合は送受信したメッセージの数
+	Index int `json:"index"`
+
+	// KeySchedule この時点までに導出された鍵スケジュール
+	KeySchedule []KeyScheduleStep `json:"key_schedule"`
+
+	// Message 送信または受信した1つのハンドシェイクメッセージ。 解析結果は、メッセージの種類に応じて client_hello、server_flight、finished、client_key_exchange、 client_certificate、client_certificate_verify のいずれかに含まれます。
+	Message *HandshakeStepMessage `json:"message,omitempty"`
+}
+
+// HttpHeader HTTP ヘッダ
+type HttpHeader struct {
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+// HttpRequest /tls/application で送信する HTTP リクエスト。 ALPN で h2 が合意された場合は HTTP/2、それ以外の場合は HTTP/1.1 で送信します。 extensions と preset を指定しない場合は、application_layer_protocol_negotiation 拡張を追加して送信します。 extensions を指定した場合は、その ALPN で protocol を合意できない場合に 400 になります。
+type HttpRequest struct {
+	// Body リクエストボディ
+	Body *string `json:"body,omitempty"`
+
+	// Headers リクエストヘッダ。HTTP/1.1 では指定した順に送信します。 Host を指定しない場合は server_name が、HTTP/1.1 で Connection を指定しない場合は close が使用されます。 名前と値に CR や LF などの制御文字は指定できません。
+	Headers *[]HttpHeader `json:"headers,omitempty"`
+
+	// Method メソッド。指定しない場合は GET になります。
+	Method *string `json:"method,omitempty"`
+
+	// Path リクエストのパス。/ で始まる接続先のサーバー上のパスとクエリで、// で始まるものや @、 空白、制御文字を含むものは指定できません。指定しない場合は / になります。
+	Path *string `json:"path,omitempty"`
+
+	// Protocol 使用するプロトコル。extensions を指定しない場合は、このプロトコルのみを ALPN で提示します。 preset の ALPN もこのプロトコルのみに上書きします。 extensions を指定した場合や ALPN のない preset では、h2 と http/1.1 のうちこのプロトコルだけを ALPN で提示していないと 400 になります。 ALPN のない場合に使用できるのは http/1.1 のみです。 ALPN で合意したプロトコルと異なる場合はエラーになります。 指定しない場合は h2 と http/1.1 を提示し、合意したプロトコルを使用します。
+	Protocol *HttpRequestProtocol `json:"protocol,omitempty"`
+}
+
+// HttpRequestProtocol 使用するプロトコル。extensions を指定しない場合は、このプロトコルのみを ALPN で提示します。 preset の ALPN もこのプロトコルのみに上書きします。 extensions を指定した場合や ALPN のない preset では、h2 と http/1.1 のうちこのプロトコルだけを ALPN で提示していないと 400 になります。 ALPN のない場合に使用できるのは http/1.1 のみです。 ALPN で合意したプロトコルと異なる場合はエラーになります。 指定しない場合は h2 と http/1.1 を提示し、合意したプロトコルを使用します。
+type HttpRequestProtocol string
+
+// HttpResponse http_request を指定した場合の HTTP レスポンス
+type HttpResponse struct {
+	// Body レスポンスボディ
+	Body string `json:"body"`
+
+	// Headers レスポンスヘッダ (名前順)
+	Headers []HttpHeader `json:"headers"`
+
+	// Protocol 使用したプロトコル (http/1.1 または h2)
+	Protocol string `json:"protocol"`
+
+	// Status ステータスコード
+	Status int `json:"status"`
+}
+
+// KeyScheduleStep 鍵スケジュールの1ステップ (TLS 1.3 は RFC 8446 7章、TLS 1.2 は RFC 5246 6.3節・8.1節)
+type KeyScheduleStep struct {
+	// Name 導出された値の名前
+	Name string `json:"name"`
+
+	// TranscriptHash 導出時点のTranscript-Hash (hexエンコード)。トランスクリプトを使わない導出では省略されます。
+	TranscriptHash *string `json:"transcript_hash,omitempty"`
+
+	// Value 導出された値 (hexエンコード)
+	Value string `json:"value"`
+}
+
+// KeyShareEntry key_share 拡張のエントリ
+type KeyShareEntry struct {
+	// Group グループ (16進数文字列)
+	Group string `json:"group"`
+
+	// GroupName グループの名前
+	GroupName string `json:"group_name"`
+
+	// KeyExchange 公開鍵 (hexエンコード)
+	KeyExchange string `json:"key_exchange"`
+}
+
+// MessageField メッセージの1つのフィールド
+type MessageField struct {
+	// Name フィールドの名前
+	Name string `json:"name"`
+
+	// Value フィールドの値
+	Value string `json:"value"`
+}
+
+// NewSessionTicketMessage NewSessionTicket (TLS 1.3 は RFC 8446 4.6.1、TLS 1.2 は RFC 5077 3.3)。 TLS 1.2 では ticket_lifetime と ticket のみが含まれます。
+type NewSessionTicketMessage struct {
+	Extensions *[]HandshakeExtension `json:"extensions,omitempty"`
+
+	// MaxEarlyDataSize early_data 拡張で示された、0-RTT で受け付ける早期データの最大サイズ。0-RTT を受け付けない場合は省略されます。
+	MaxEarlyDataSize *int64 `json:"max_early_data_size,omitempty"`
+
+	// Raw ヘッダを含むメッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// Ticket チケット (hexエンコード)。再開時に PSK の識別子として送信します。
+	Ticket string `json:"ticket"`
+
+	// TicketAgeAdd obfuscated_ticket_age の計算でチケットの経過時間に加える値
+	TicketAgeAdd *int64 `json:"ticket_age_add,omitempty"`
+
+	// TicketLifetime チケットの有効期間 (秒)。TLS 1.2 では ticket_lifetime_hint
+	TicketLifetime int64 `json:"ticket_lifetime"`
+
+	// TicketNonce resumption_master_secret から PSK を導出するときに使う nonce (hexエンコード)
+	TicketNonce *string `json:"ticket_nonce,omitempty"`
+}
+
+// PreSharedKeyOffer TLS 1.3 の ClientHello の pre_shared_key 拡張で提示した PSK と、その binder の計算過程 (RFC 8446 4.2.11)。
+type PreSharedKeyOffer struct {
+	// Accepted サーバーが ServerHello の pre_shared_key でこの PSK を選択した場合に true
+	Accepted bool `json:"accepted"`
+
+	// BinderCalculation PSK binder の計算過程 (RFC 8446 4.2.11.2、7.1節)。値はすべてhexエンコードです。
+	BinderCalculation PskBinderCalculation `json:"binder_calculation"`
+
+	// Identity PSK の識別子。サーバーから受け取ったチケットです (hexエンコード)
+	Identity string `json:"identity"`
+
+	// ObfuscatedTicketAge チケットを受け取ってからの経過時間 (ミリ秒) に ticket_age_add を加えた値
+	ObfuscatedTicketAge int64 `json:"obfuscated_ticket_age"`
+}
+
+// Preset uTLS の ClientHelloID に対応する ClientHello のプリセット
+type Preset struct {
+	// CipherSuites Cipher Suite のリスト (16進数文字列または "GREASE")
+	CipherSuites []string `json:"cipher_suites"`
+
+	// Client クライアント (ブラウザ) の名前
+	Client string `json:"client"`
+
+	// CompressionMethods legacy_compression_methods のリスト (16進数文字列)
+	CompressionMethods []string `json:"compression_methods"`
+
+	// Extensions ClientHello に含まれる拡張のリスト。TlsClientParameters の extensions にそのまま指定できます。 ただし pre_shared_key はセッションの再開でしか送れないため、extensions に指定するとエラーになります。
+	Extensions []ClientHelloExtension `json:"extensions"`
+
+	// Name TlsClientParameters の preset に指定する名前
+	Name string `json:"name"`
+
+	// TlsVersionMax プリセットが対応する最大のTLSバージョン (16進数文字列)
+	TlsVersionMax *string `json:"tls_version_max,omitempty"`
+
+	// TlsVersionMin プリセットが対応する最小のTLSバージョン (16進数文字列)
+	TlsVersionMin *string `json:"tls_version_min,omitempty"`
+
+	// Version クライアントのバージョン
+	Version string `json:"version"`
+}
+
+// PresetsResponse ClientHello のプリセットの一覧
+type PresetsResponse struct {
+	Presets []Preset `json:"presets"`
+}
+
+// PskBinderCalculation PSK binder の計算過程 (RFC 8446 4.2.11.2、7.1節)。値はすべてhexエンコードです。
+type PskBinderCalculation struct {
+	// Binder HMAC(finished_key, transcript_hash)。ClientHello で送信した binder と一致します。
+	Binder string `json:"binder"`
+
+	// BinderKey Derive-Secret(early_secret, "res binder", "")
+	BinderKey string `json:"binder_key"`
+
+	// EarlySecret HKDF-Extract(0, PSK)
+	EarlySecret string `json:"early_secret"`
+
+	// FinishedKey HKDF-Expand-Label(binder_key, "finished", "", Hash.length)
+	FinishedKey string `json:"finished_key"`
+
+	// TranscriptHash binder のリストの直前までで切り詰めた ClientHello の Transcript-Hash
+	TranscriptHash string `json:"transcript_hash"`
+}
+
+// ResumptionParameters セッションの再開 (TLS 1.3 は PSK、TLS 1.2 はセッションチケット)。/tls/handshake でのみ指定できます。 extensions を指定しない場合は、TLS 1.3 では psk_key_exchange_modes と、session_handle を指定したときは pre_shared_key を、 TLS 1.2 では session_ticket を追加して送信します。 0-RTT の早期データは、ライブラリが TCP 上での送信に対応していないため送信できません。 early_data または early_data 拡張を指定すると 501 (not_implemented) を返します。 サーバーが 0-RTT を受け付けるかどうかは、受け取ったチケットの max_early_data_size で確認できます。
+type ResumptionParameters struct {
+	// EarlyData 0-RTT で送信する早期データ (平文)。TCP 上での 0-RTT の送信は実装されていないため、 指定すると 501 (not_implemented) を返します。
+	EarlyData *string `json:"early_data,omitempty"`
+
+	// SaveSession true の場合、ハンドシェイク後に受け取ったセッションチケットをAPIサーバーに保存し、session_handle を返します。 TLS 1.3 ではハンドシェイク後に送られる NewSessionTicket を受け取るため、レスポンスが1秒ほど遅くなります。 保存できるセッションの数にはクライアントごととサーバー全体の上限があり、上限に達している場合は too_many_saved_sessions を返します。
+	SaveSession *bool `json:"save_session,omitempty"`
+
+	// SessionHandle 以前のレスポンスで返された session_handle。保存したセッションで再開を試みます。 再開時に受け取った新しいチケットは同じ session_handle に保存されます。 セッションは server_name ごとに保存されるため、保存時と同じ server_name を指定してください。
+	SessionHandle *string `json:"session_handle,omitempty"`
+}
+
+// ResumptionResult セッションの再開の結果。リクエストで resumption を指定した場合に含まれます。
+type ResumptionResult struct {
+	// NewSessionTickets ハンドシェイク中またはハンドシェイク後に受け取った NewSessionTicket
+	NewSessionTickets []NewSessionTicketMessage `json:"new_session_tickets"`
+
+	// Psk TLS 1.3 の ClientHello の pre_shared_key 拡張で提示した PSK と、その binder の計算過程 (RFC 8446 4.2.11)。
+	Psk *PreSharedKeyOffer `json:"psk,omitempty"`
+
+	// Resumed サーバーがセッションの再開を受け入れ、証明書による認証を省略した場合に true
+	Resumed bool `json:"resumed"`
+
+	// SessionHandle 受け取ったセッションチケットを保存したハンドル。次のリクエストの resumption.session_handle に指定します。 session_handle を指定した場合は同じ値が返ります。save_session を指定してもチケットを受け取らなかった場合は省略されます。
+	SessionHandle *string `json:"session_handle,omitempty"`
+}
+
+// ServerFlight サーバーから届いたハンドシェイクメッセージをメッセージごとに復号・解析したもの。サーバーが送信しなかったメッセージは省略されます。
+type ServerFlight struct {
+	// Certificate Certificate (CompressedCertificate の場合は展開後の内容)
+	Certificate *CertificateMessage `json:"certificate,omitempty"`
+
+	// CertificateRequest CertificateRequest
+	CertificateRequest *CertificateRequestMessage `json:"certificate_request,omitempty"`
+
+	// CertificateVerify CertificateVerify
+	CertificateVerify *CertificateVerifyMessage `json:"certificate_verify,omitempty"`
+
+	// ChangeCipherSpec サーバーが ChangeCipherSpec を送信したかどうか。 TLS 1.2 では Finished の前に必ず送信され、TLS 1.3 では互換性のためにのみ送信されます。
+	ChangeCipherSpec *bool `json:"change_cipher_spec,omitempty"`
+
+	// EncryptedExtensions EncryptedExtensions
+	EncryptedExtensions *EncryptedExtensionsMessage `json:"encrypted_extensions,omitempty"`
+
+	// Finished Finished
+	Finished *FinishedMessage `json:"finished,omitempty"`
+
+	// HelloRetryRequest ServerHello (または HelloRetryRequest)
+	HelloRetryRequest *ServerHelloMessage `json:"hello_retry_request,omitempty"`
+
+	// ServerHello ServerHello (または HelloRetryRequest)
+	ServerHello *ServerHelloMessage `json:"server_hello,omitempty"`
+
+	// ServerHelloDone TLS 1.2 の ServerHelloDone
+	ServerHelloDone *ServerHelloDoneMessage `json:"server_hello_done,omitempty"`
+
+	// ServerKeyExchange TLS 1.2 の ServerKeyExchange (ECDHE)。サーバーの一時公開鍵と、それに対するサーバー証明書の鍵での署名
+	ServerKeyExchange *ServerKeyExchangeMessage `json:"server_key_exchange,omitempty"`
+
+	// Version ServerHelloで選択されたTLSバージョン (16進数文字列)
+	Version *string `json:"version,omitempty"`
+}
+
+// ServerHelloDoneMessage TLS 1.2 の ServerHelloDone
+type ServerHelloDoneMessage struct {
+	// Raw メッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+}
+
+// ServerHelloMessage ServerHello (または HelloRetryRequest)
+type ServerHelloMessage struct {
+	// CipherSuite サーバーが選択した Cipher Suite (16進数文字列)
+	CipherSuite string `json:"cipher_suite"`
+
+	// CipherSuiteName サーバーが選択した Cipher Suite の名前
+	CipherSuiteName string `json:"cipher_suite_name"`
+
+	// Extensions 拡張のリスト (受信した順)
+	Extensions []HandshakeExtension `json:"extensions"`
+
+	// KeyShare key_share 拡張のエントリ
+	KeyShare *KeyShareEntry `json:"key_share,omitempty"`
+
+	// LegacyCompressionMethod legacy_compression_method
+	LegacyCompressionMethod int `json:"legacy_compression_method"`
+
+	// LegacySessionId legacy_session_id_echo (hexエンコード)
+	LegacySessionId string `json:"legacy_session_id"`
+
+	// LegacyVersion legacy_version (16進数文字列)
+	LegacyVersion string `json:"legacy_version"`
+
+	// Random サーバーの Random 値 (hexエンコード)
+	Random string `json:"random"`
+
+	// Raw メッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// SelectedVersion supported_versions 拡張で選択されたバージョン (16進数文字列)
+	SelectedVersion *string `json:"selected_version,omitempty"`
+}
+
+// ServerKeyExchangeMessage TLS 1.2 の ServerKeyExchange (ECDHE)。サーバーの一時公開鍵と、それに対するサーバー証明書の鍵での署名
+type ServerKeyExchangeMessage struct {
+	// CurveType ECParameters の curve_type (3 は named_curve)
+	CurveType int `json:"curve_type"`
+
+	// NamedCurve 鍵交換に使うグループ (16進数文字列)
+	NamedCurve *string `json:"named_curve,omitempty"`
+
+	// NamedCurveName 鍵交換に使うグループの名前
+	NamedCurveName *string `json:"named_curve_name,omitempty"`
+
+	// PublicKey サーバーの一時公開鍵 (hexエンコード)
+	PublicKey *string `json:"public_key,omitempty"`
+
+	// Raw メッセージ全体のバイト列 (hexエンコード)
+	Raw string `json:"raw"`
+
+	// Signature client_random、server_random とパラメータに対する署名 (hexエンコード)
+	Signature string `json:"signature"`
+
+	// SignatureScheme 署名アルゴリズム (16進数文字列)
+	SignatureScheme *string `json:"signature_scheme,omitempty"`
+
+	// SignatureSchemeName 署名アルゴリズムの名前
+	SignatureSchemeName *string `json:"signature_scheme_name,omitempty"`
+}
+
+// SessionResponse 1メッセージずつ進めるハンドシェイクのセッション
+type SessionResponse struct {
+	// ExpiresAt 操作がなかった場合にセッションが破棄される時刻
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Id セッションのID
+	Id string `json:"id"`
+}
+
+// TestServerResponse ローカルTLSテストサーバー (TLS 1.3 と TLS 1.2 に対応) の状態
+type TestServerResponse struct {
+	// Address TlsClientParametersのaddressに指定するIPアドレス
+	Address *string `json:"address,omitempty"`
+
+	// Certificate テストサーバーの自己署名証明書 (DER, hexエンコード)
+	Certificate *string `json:"certificate,omitempty"`
+
+	// ClientAuthServerName server_name にこのホスト名を指定すると、テストサーバーは CertificateRequest を送信し、 クライアント証明書を要求します。証明書は検証されません。
+	ClientAuthServerName *string `json:"client_auth_server_name,omitempty"`
+
+	// Port TlsClientParametersのportに指定するポート番号
+	Port *int `json:"port,omitempty"`
+
+	// Running テストサーバーが起動しているかどうか。起動していない場合、他の項目は省略されます。
+	Running bool `json:"running"`
+
+	// ServerName TlsClientParametersのserver_nameに指定するホスト名
+	ServerName *string `json:"server_name,omitempty"`
+}
+
+// TlsAlert TLS の Alert (RFC 8446 6章)
+type TlsAlert struct {
+	// Description AlertDescription
+	Description int `json:"description"`
+
+	// DescriptionName AlertDescription の名前
+	DescriptionName string `json:"description_name"`
+
+	// Level AlertLevel (1 = warning, 2 = fatal)
+	Level int `json:"level"`
+
+	// LevelName AlertLevel の名前
+	LevelName string `json:"level_name"`
+}
+
+// TlsClientParameters defines model for TlsClientParameters.
+type TlsClientParameters struct {
+	// Address 接続先のホスト名またはIPアドレス。指定しない場合は'server_name'の値が使用されます。
+	Address *string `json:"address,omitempty"`
+
+	// ApplicationData 送信するアプリケーションデータ（HTTPプロトコル） 平文。http_request と同時には指定できません。
+	ApplicationData *string `json:"application_data,omitempty"`
+
+	// CipherSuites 使用する Cipher Suite のリスト (16進数文字列)。"GREASE" を指定するとGREASE値になります。
+	CipherSuites []string `json:"cipher_suites"`
+
+	// ClientCertificate サーバーから CertificateRequest を受け取ったときに送信するクライアント証明書。 certificate と private_key を PEM で指定するか、generate に true を指定して一時的な証明書を生成します。 サーバーが要求しない場合は送信されません。
+	ClientCertificate *ClientCertificate `json:"client_certificate,omitempty"`
+
+	// ClientRandom クライアントの Random 値 (hex)
+	ClientRandom string `json:"client_random"`
+
+	// CompressionMethods legacy_compression_methods のリスト (16進数文字列)。指定しない場合は null (0x00) のみになります。
+	CompressionMethods *[]string `json:"compression_methods,omitempty"`
+
+	// Extensions ClientHello に含める拡張のリスト。指定した順に送信されます。 指定しない場合は server_name, supported_groups, key_share, signature_algorithms, supported_versions の順になります。 独自実装 (mytls) はこの指定に対応しておらず、常に既定の拡張を送信するため、/tls/compare では指定できません。
+	Extensions *[]ClientHelloExtension `json:"extensions,omitempty"`
+
+	// HttpRequest /tls/application で送信する HTTP リクエスト。 ALPN で h2 が合意された場合は HTTP/2、それ以外の場合は HTTP/1.1 で送信します。 extensions と preset を指定しない場合は、application_layer_protocol_negotiation 拡張を追加して送信します。 extensions を指定した場合は、その ALPN で protocol を合意できない場合に 400 になります。
+	HttpRequest *HttpRequest `json:"http_request,omitempty"`
+
+	// KeyShares KeyShare に使うアルゴリズム (楕円曲線名)。"GREASE" を指定するとGREASE値になります。
+	KeyShares []string `json:"key_shares"`
+
+	// PinnedSpkiSha256 verify_mode が pinned_spki の場合に受け入れる公開鍵。SubjectPublicKeyInfo (DER) の SHA-256 を base64 エンコードしたものです (HTTP Public Key Pinning の pin-sha256 と同じ形式)。
+	PinnedSpkiSha256 *[]string `json:"pinned_spki_sha256,omitempty"`
+
+	// Port 接続先のポート番号。指定しない場合は443が使用されます。
+	Port *int `json:"port,omitempty"`
+
+	// Preset 使用するブラウザのプリセットの名前 (GET /tls/presets の name)。 指定した場合は、プリセットの Cipher Suite と拡張を使用します。 cipher_suites, supported_groups, key_shares, signature_algorithms は空でない場合にプリセットの値を上書きし、 extensions を指定した場合はプリセットの拡張の代わりに使用します。 TLS バージョンは protocol_version ではなく、プリセットが提示する範囲 (supported_versions がない場合は TLS 1.0 から 1.2) を使用します。 独自実装 (mytls) はプリセットに対応していないため、/tls/compare では指定できません。
+	Preset *string `json:"preset,omitempty"`
+
+	// ProtocolVersion 使用する TLS バージョン。'0x0304' (TLS 1.3) と '0x0303' (TLS 1.2) に対応しています。 TLS 1.2 の場合、extensions を指定しなければ server_name, supported_groups, ec_point_formats, signature_algorithms, extended_master_secret, renegotiation_info を送信します。 独自実装 (mytls) は TLS 1.3 のみに対応しています。 preset を指定した場合は、このバージョンではなくプリセットが提示するバージョンの範囲で合意します。
+	ProtocolVersion string `json:"protocol_version"`
+
+	// Resumption セッションの再開 (TLS 1.3 は PSK、TLS 1.2 はセッションチケット)。/tls/handshake でのみ指定できます。 extensions を指定しない場合は、TLS 1.3 では psk_key_exchange_modes と、session_handle を指定したときは pre_shared_key を、 TLS 1.2 では session_ticket を追加して送信します。 0-RTT の早期データは、ライブラリが TCP 上での送信に対応していないため送信できません。 early_data または early_data 拡張を指定すると 501 (not_implemented) を返します。 サーバーが 0-RTT を受け付けるかどうかは、受け取ったチケットの max_early_data_size で確認できます。
+	Resumption *ResumptionParameters `json:"resumption,omitempty"`
+
+	// RootCertificates verify_mode が custom_roots の場合に信頼するルート証明書 (PEM)。複数の証明書を連結できます。
+	RootCertificates *string `json:"root_certificates,omitempty"`
+
+	// ServerName Server Name Indication (SNI)拡張に設定するホスト名。指定しない場合は'server'の値が使用されます。
+	ServerName string `json:"server_name"`
+
+	// SessionId legacy_session_id (hex、最大32バイト)。指定しない場合は32バイトの乱数が使用されます。空文字列を指定すると空になります。
+	SessionId *string `json:"session_id,omitempty"`
+
+	// SignatureAlgorithms サポートする署名アルゴリズム
+	SignatureAlgorithms []string `json:"signature_algorithms"`
+
+	// SupportedGroups サポートする楕円曲線 (KeyShare Group)。"GREASE" を指定するとGREASE値になります。
+	SupportedGroups []string `json:"supported_groups"`
+
+	// VerifyMode サーバー証明書の検証方法。指定しない場合は system になります。 system はシステムのルート証明書 (テストサーバー起動時はその証明書も含む)、custom_roots は root_certificates で証明書チェーンと server_name を検証します。 server_name を省略した場合は、接続先のホスト名またはIPアドレス (address) で検証します。 pinned_spki はチェーンを検証せず、いずれかの証明書の公開鍵が pinned_spki_sha256 と一致することを確認します。 insecure は検証に失敗してもハンドシェイクを続け、結果のみを返します。
+	VerifyMode *TlsClientParametersVerifyMode `json:"verify_mode,omitempty"`
+}
+
+// TlsClientParametersVerifyMode サーバー証明書の検証方法。指定しない場合は system になります。 system はシステムのルート証明書 (テストサーバー起動時はその証明書も含む)、custom_roots は root_certificates で証明書チェーンと server_name を検証します。 server_name を省略した場合は、接続先のホスト名またはIPアドレス (address) で検証します。 pinned_spki はチェーンを検証せず、いずれかの証明書の公開鍵が pinned_spki_sha256 と一致することを確認します。 insecure は検証に失敗してもハンドシェイクを続け、結果のみを返します。
+type TlsClientParametersVerifyMode string
+
+// TlsRecord 1つのTLSレコードと、その保護 (RFC 8446 5.2節、RFC 5246 6.2節) の詳細
+type TlsRecord struct {
+	// Ciphertext レコードヘッダを除いたペイロード (hexエンコード)。暗号化されていないレコードでは平文です。
+	Ciphertext string `json:"ciphertext"`
+
+	// ContentType レコードヘッダの ContentType。TLS 1.3 で暗号化されたレコードは常に 23 (application_data) です。
+	ContentType int `json:"content_type"`
+
+	// ContentTypeName レコードヘッダの ContentType の名前
+	ContentTypeName string `json:"content_type_name"`
+
+	// Direction クライアントが送信したレコードか、サーバーから受信したレコードか
+	Direction TlsRecordDirection `json:"direction"`
+
+	// Encrypted レコードが暗号化されていたかどうか
+	Encrypted bool `json:"encrypted"`
+
+	// HandshakeMessages inner_content_type が handshake の場合に、含まれているハンドシェイクメッセージの種類
+	HandshakeMessages *[]string `json:"handshake_messages,omitempty"`
+
+	// Header レコードヘッダ (5バイト, hexエンコード)
+	Header string `json:"header"`
+
+	// InnerContentType 復号後の実際の ContentType。TLS 1.3 では TLSInnerPlaintext の type です。
+	InnerContentType int `json:"inner_content_type"`
+
+	// InnerContentTypeName 復号後の実際の ContentType の名前
+	InnerContentTypeName string `json:"inner_content_type_name"`
+
+	// Nonce レコードの保護に使われたAEADのnonce (hexエンコード)。 TLS 1.3 と ChaCha20-Poly1305 は write_iv とシーケンス番号のXOR、TLS 1.2 の AES-GCM は write_iv と explicit nonce を連結したものです。 CBCモードの暗号スイートと暗号化されていないレコードでは省略されます。
+	Nonce *string `json:"nonce,omitempty"`
+
+	// PaddingLength TLS 1.3 では inner content type の後ろのゼロパディングの長さ、TLS 1.2 の CBC モードでは padding_length の1バイトを含むパディングの長さ。 暗号化されていないレコードでは省略されます。
+	PaddingLength *int `json:"padding_length,omitempty"`
+
+	// Plaintext 復号したペイロード (hexエンコード)。TLS 1.3 の inner content type とパディングは含みません。
+	Plaintext string `json:"plaintext"`
+
+	// SequenceNumber レコードの保護に使われたシーケンス番号。暗号化されていないレコードでは省略されます。
+	SequenceNumber *int64 `json:"sequence_number,omitempty"`
+}
+
+// TlsRecordDirection クライアントが送信したレコードか、サーバーから受信したレコードか
+type TlsRecordDirection string
+
+// ValidationError リクエストの1つの項目のエラー
+type ValidationError struct {
+	// Code エラーの種類。 schema_violation はリクエストが OpenAPI のスキーマに一致しないこと、 invalid_hex, invalid_codepoint, invalid_client_random, invalid_session_id, key_share_not_in_supported_groups, unknown_preset はリクエストの値が不正であることを表します (詳細は errors)。 handshake_failed はハンドシェイクの失敗 (詳細は failure)、certificate_verification_failed は 証明書の検証の失敗 (詳細は certificate_verification)、invalid_request はそれ以外の不正なリクエストを表します。 timeout はリクエストの期限までにハンドシェイクが終わらなかったこと、destination_not_allowed は 接続先がサーバーの設定で許可されていないこと、too_many_connections は同時接続数の上限に達していること、 rate_limited はクライアントごとのリクエスト数の上限に達していること、too_many_sessions は /tls/sessions で保持できるセッション数の上限に達していること、too_many_saved_sessions は resumption.save_session で保存できるセッション数の上限に達していること、request_too_large は リクエストボディが上限のサイズを超えていること、not_implemented は 0-RTT の早期データのように 実装されていない機能が指定されたことを表します。
+	Code ErrorCode `json:"code"`
+
+	// Field エラーのある項目の JSON Pointer (例 "/cipher_suites/1")。リクエスト全体の場合は空文字列
+	Field string `json:"field"`
+
+	// Message エラーメッセージ
+	Message string `json:"message"`
+}
+
+// VerifiedChain サーバー証明書からルート証明書までの証明書チェーン
+type VerifiedChain struct {
+	Certificates []X509Certificate `json:"certificates"`
+}
+
+// X509Certificate X.509 証明書の主な項目。解析に失敗した場合は parse_error と fingerprint_sha256 のみが含まれます。
+type X509Certificate struct {
+	DnsNames *[]string `json:"dns_names,omitempty"`
+
+	// FingerprintSha256 証明書 (DER) の SHA-256 (hexエンコード)
+	FingerprintSha256 string `json:"fingerprint_sha256"`
+
+	IpAddresses *[]string `json:"ip_addresses,omitempty"`
+
+	// IsCa Basic Constraints で CA とされているかどうか
+	IsCa *bool `json:"is_ca,omitempty"`
+
+	Issuer *string `json:"issuer,omitempty"`
+
+	NotAfter *string `json:"not_after,omitempty"`
+
+	NotBefore *string `json:"not_before,omitempty"`
+
+	// ParseError 証明書の解析に失敗した理由
+	ParseError *string `json:"parse_error,omitempty"`
+
+	PublicKeyAlgorithm *string `json:"public_key_algorithm,omitempty"`
+
+	SerialNumber *string `json:"serial_number,omitempty"`
+
+	SignatureAlgorithm *string `json:"signature_algorithm,omitempty"`
+
+	// SpkiSha256 SubjectPublicKeyInfo (DER) の SHA-256 (base64エンコード)。pinned_spki_sha256 に指定できます。
+	SpkiSha256 *string `json:"spki_sha256,omitempty"`
+
+	Subject *string `json:"subject,omitempty"`
+}
+
+// PostTlsApplicationJSONRequestBody defines body for PostTlsApplication for application/json ContentType.
+type PostTlsApplicationJSONRequestBody = ApplicationRequest
+
+// PostTlsCompareJSONRequestBody defines body for PostTlsCompare for application/json ContentType.
+type PostTlsCompareJSONRequestBody = HandshakeRequest
+
+// PostTlsHandshakeJSONRequestBody defines body for PostTlsHandshake for application/json ContentType.
+type PostTlsHandshakeJSONRequestBody = HandshakeRequest
+
+// PostTlsSessionsJSONRequestBody defines body for PostTlsSessions for application/json ContentType.
+type PostTlsSessionsJSONRequestBody = HandshakeRequest
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// PostTlsApplicationWithBody request with any body
+	PostTlsApplicationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTlsApplication(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTlsCompareWithBody request with any body
+	PostTlsCompareWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTlsCompare(ctx context.Context, body PostTlsCompareJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTlsHandshakeWithBody request with any body
+	PostTlsHandshakeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTlsHandshake(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetTlsPresets request
+	GetTlsPresets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTlsSessionsWithBody request with any body
+	PostTlsSessionsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTlsSessions(ctx context.Context, body PostTlsSessionsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteTlsSessionsId request
+	DeleteTlsSessionsId(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTlsSessionsIdStep request
+	PostTlsSessionsIdStep(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetTlsTestServer request
+	GetTlsTestServer(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) PostTlsApplicationWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsApplicationRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsApplication(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsApplicationRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsCompareWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsCompareRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsCompare(ctx context.Context, body PostTlsCompareJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsCompareRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsHandshakeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsHandshakeRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsHandshake(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsHandshakeRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetTlsPresets(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTlsPresetsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsSessionsWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsSessionsRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsSessions(ctx context.Context, body PostTlsSessionsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsSessionsRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteTlsSessionsId(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteTlsSessionsIdRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTlsSessionsIdStep(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTlsSessionsIdStepRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetTlsTestServer(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetTlsTestServerRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewPostTlsApplicationRequest calls the generic PostTlsApplication builder with application/json body
+func NewPostTlsApplicationRequest(server string, body PostTlsApplicationJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTlsApplicationRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostTlsApplicationRequestWithBody generates requests for PostTlsApplication with any type of body
+func NewPostTlsApplicationRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/application")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostTlsCompareRequest calls the generic PostTlsCompare builder with application/json body
+func NewPostTlsCompareRequest(server string, body PostTlsCompareJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTlsCompareRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostTlsCompareRequestWithBody generates requests for PostTlsCompare with any type of body
+func NewPostTlsCompareRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/compare")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostTlsHandshakeRequest calls the generic PostTlsHandshake builder with application/json body
+func NewPostTlsHandshakeRequest(server string, body PostTlsHandshakeJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTlsHandshakeRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostTlsHandshakeRequestWithBody generates requests for PostTlsHandshake with any type of body
+func NewPostTlsHandshakeRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/handshake")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetTlsPresetsRequest generates requests for GetTlsPresets
+func NewGetTlsPresetsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/presets")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostTlsSessionsRequest calls the generic PostTlsSessions builder with application/json body
+func NewPostTlsSessionsRequest(server string, body PostTlsSessionsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTlsSessionsRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostTlsSessionsRequestWithBody generates requests for PostTlsSessions with any type of body
+func NewPostTlsSessionsRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/sessions")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteTlsSessionsIdRequest generates requests for DeleteTlsSessionsId
+func NewDeleteTlsSessionsIdRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/sessions/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostTlsSessionsIdStepRequest generates requests for PostTlsSessionsIdStep
+func NewPostTlsSessionsIdStepRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/sessions/%s/step", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetTlsTestServerRequest generates requests for GetTlsTestServer
+func NewGetTlsTestServerRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/tls/test-server")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// PostTlsApplicationWithBodyWithResponse request with any body
+	PostTlsApplicationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error)
+
+	PostTlsApplicationWithResponse(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error)
+
+	// PostTlsCompareWithBodyWithResponse request with any body
+	PostTlsCompareWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsCompareResponse, error)
+
+	PostTlsCompareWithResponse(ctx context.Context, body PostTlsCompareJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsCompareResponse, error)
+
+	// PostTlsHandshakeWithBodyWithResponse request with any body
+	PostTlsHandshakeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error)
+
+	PostTlsHandshakeWithResponse(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error)
+
+	// GetTlsPresetsWithResponse request
+	GetTlsPresetsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTlsPresetsResponse, error)
+
+	// PostTlsSessionsWithBodyWithResponse request with any body
+	PostTlsSessionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsSessionsResponse, error)
+
+	PostTlsSessionsWithResponse(ctx context.Context, body PostTlsSessionsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsSessionsResponse, error)
+
+	// DeleteTlsSessionsIdWithResponse request
+	DeleteTlsSessionsIdWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DeleteTlsSessionsIdResponse, error)
+
+	// PostTlsSessionsIdStepWithResponse request
+	PostTlsSessionsIdStepWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostTlsSessionsIdStepResponse, error)
+
+	// GetTlsTestServerWithResponse request
+	GetTlsTestServerWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTlsTestServerResponse, error)
+}
+
+type PostTlsApplicationResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ApplicationResponse
+	JSON400      *ErrorResponse
+	JSON403      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON503      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTlsApplicationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTlsApplicationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTlsCompareResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CompareResponse
+	JSON400      *ErrorResponse
+	JSON403      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON503      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTlsCompareResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTlsCompareResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTlsHandshakeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HandshakeResponse
+	JSON400      *ErrorResponse
+	JSON403      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON503      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTlsHandshakeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTlsHandshakeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTlsPresetsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *PresetsResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTlsPresetsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTlsPresetsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTlsSessionsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *SessionResponse
+	JSON400      *ErrorResponse
+	JSON403      *ErrorResponse
+	JSON429      *ErrorResponse
+	JSON503      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTlsSessionsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTlsSessionsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteTlsSessionsIdResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteTlsSessionsIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteTlsSessionsIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTlsSessionsIdStepResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HandshakeStepResponse
+	JSON404      *ErrorResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTlsSessionsIdStepResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTlsSessionsIdStepResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetTlsTestServerResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *TestServerResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetTlsTestServerResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetTlsTestServerResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// PostTlsApplicationWithBodyWithResponse request with arbitrary body returning *PostTlsApplicationResponse
+func (c *ClientWithResponses) PostTlsApplicationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error) {
+	rsp, err := c.PostTlsApplicationWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsApplicationResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTlsApplicationWithResponse(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error) {
+	rsp, err := c.PostTlsApplication(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsApplicationResponse(rsp)
+}
+
+// PostTlsCompareWithBodyWithResponse request with arbitrary body returning *PostTlsCompareResponse
+func (c *ClientWithResponses) PostTlsCompareWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsCompareResponse, error) {
+	rsp, err := c.PostTlsCompareWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsCompareResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTlsCompareWithResponse(ctx context.Context, body PostTlsCompareJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsCompareResponse, error) {
+	rsp, err := c.PostTlsCompare(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsCompareResponse(rsp)
+}
+
+// PostTlsHandshakeWithBodyWithResponse request with arbitrary body returning *PostTlsHandshakeResponse
+func (c *ClientWithResponses) PostTlsHandshakeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error) {
+	rsp, err := c.PostTlsHandshakeWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsHandshakeResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTlsHandshakeWithResponse(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error) {
+	rsp, err := c.PostTlsHandshake(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsHandshakeResponse(rsp)
+}
+
+// GetTlsPresetsWithResponse request returning *GetTlsPresetsResponse
+func (c *ClientWithResponses) GetTlsPresetsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTlsPresetsResponse, error) {
+	rsp, err := c.GetTlsPresets(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTlsPresetsResponse(rsp)
+}
+
+// PostTlsSessionsWithBodyWithResponse request with arbitrary body returning *PostTlsSessionsResponse
+func (c *ClientWithResponses) PostTlsSessionsWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsSessionsResponse, error) {
+	rsp, err := c.PostTlsSessionsWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsSessionsResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTlsSessionsWithResponse(ctx context.Context, body PostTlsSessionsJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsSessionsResponse, error) {
+	rsp, err := c.PostTlsSessions(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsSessionsResponse(rsp)
+}
+
+// DeleteTlsSessionsIdWithResponse request returning *DeleteTlsSessionsIdResponse
+func (c *ClientWithResponses) DeleteTlsSessionsIdWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*DeleteTlsSessionsIdResponse, error) {
+	rsp, err := c.DeleteTlsSessionsId(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteTlsSessionsIdResponse(rsp)
+}
+
+// PostTlsSessionsIdStepWithResponse request returning *PostTlsSessionsIdStepResponse
+func (c *ClientWithResponses) PostTlsSessionsIdStepWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostTlsSessionsIdStepResponse, error) {
+	rsp, err := c.PostTlsSessionsIdStep(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTlsSessionsIdStepResponse(rsp)
+}
+
+// GetTlsTestServerWithResponse request returning *GetTlsTestServerResponse
+func (c *ClientWithResponses) GetTlsTestServerWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetTlsTestServerResponse, error) {
+	rsp, err := c.GetTlsTestServer(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetTlsTestServerResponse(rsp)
+}
+
+// ParsePostTlsApplicationResponse parses an HTTP response from a PostTlsApplicationWithResponse call
+func ParsePostTlsApplicationResponse(rsp *http.Response) (*PostTlsApplicationResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTlsApplicationResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ApplicationResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTlsCompareResponse parses an HTTP response from a PostTlsCompareWithResponse call
+func ParsePostTlsCompareResponse(rsp *http.Response) (*PostTlsCompareResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTlsCompareResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CompareResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+// ParsePostTlsHandshakeResponse parses an HTTP response from a PostTlsHandshakeWithResponse call
+func ParsePostTlsHandshakeResponse(rsp *http.Response) (*PostTlsHandshakeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", queryURL.String(), body)
-	if err != nil {
-		return nil, err
+	response := &PostTlsHandshakeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
 
-	req.Header.Add("Content-Type", contentType)
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest HandshakeResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
 
-	return req, nil
-}
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
 		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
 		}
+		response.JSON503 = &dest
+
 	}
-	return nil
-}
 
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
+	return response, nil
 }
 
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+// ParseGetTlsPresetsResponse parses an HTTP response from a GetTlsPresetsWithResponse call
+func ParseGetTlsPresetsResponse(rsp *http.Response) (*GetTlsPresetsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
 
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
-		newBaseURL, err := url.Parse(baseURL)
-		if err != nil {
-			return err
-		}
-		c.Server = newBaseURL.String()
-		return nil
+	response := &GetTlsPresetsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-}
-
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// PostTlsApplicationWithBodyWithResponse request with any body
-	PostTlsApplicationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error)
 
-	PostTlsApplicationWithResponse(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error)
-
-	// PostTlsHandshakeWithBodyWithResponse request with any body
-	PostTlsHandshakeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error)
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest PresetsResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
 
-	PostTlsHandshakeWithResponse(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error)
-}
+	}
 
-type PostTlsApplicationResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *ApplicationResponse
-	JSON400      *ErrorResponse
+	return response, nil
 }
 
-// Status returns HTTPResponse.Status
-func (r PostTlsApplicationResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+// ParsePostTlsSessionsResponse parses an HTTP response from a PostTlsSessionsWithResponse call
+func ParsePostTlsSessionsResponse(rsp *http.Response) (*PostTlsSessionsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PostTlsApplicationResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	response := &PostTlsSessionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return 0
-}
 
-type PostTlsHandshakeResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *HandshakeResponse
-	JSON400      *ErrorResponse
-}
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest SessionResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
 
-// Status returns HTTPResponse.Status
-func (r PostTlsHandshakeResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
-	}
-	return http.StatusText(0)
-}
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r PostTlsHandshakeResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
-	}
-	return 0
-}
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
 
-// PostTlsApplicationWithBodyWithResponse request with arbitrary body returning *PostTlsApplicationResponse
-func (c *ClientWithResponses) PostTlsApplicationWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error) {
-	rsp, err := c.PostTlsApplicationWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
 	}
-	return ParsePostTlsApplicationResponse(rsp)
+
+	return response, nil
 }
 
-func (c *ClientWithResponses) PostTlsApplicationWithResponse(ctx context.Context, body PostTlsApplicationJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsApplicationResponse, error) {
-	rsp, err := c.PostTlsApplication(ctx, body, reqEditors...)
+// ParseDeleteTlsSessionsIdResponse parses an HTTP response from a DeleteTlsSessionsIdWithResponse call
+func ParseDeleteTlsSessionsIdResponse(rsp *http.Response) (*DeleteTlsSessionsIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
-	return ParsePostTlsApplicationResponse(rsp)
-}
 
-// PostTlsHandshakeWithBodyWithResponse request with arbitrary body returning *PostTlsHandshakeResponse
-func (c *ClientWithResponses) PostTlsHandshakeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error) {
-	rsp, err := c.PostTlsHandshakeWithBody(ctx, contentType, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	response := &DeleteTlsSessionsIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
 	}
-	return ParsePostTlsHandshakeResponse(rsp)
-}
 
-func (c *ClientWithResponses) PostTlsHandshakeWithResponse(ctx context.Context, body PostTlsHandshakeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTlsHandshakeResponse, error) {
-	rsp, err := c.PostTlsHandshake(ctx, body, reqEditors...)
-	if err != nil {
-		return nil, err
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest ErrorResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
 	}
-	return ParsePostTlsHandshakeResponse(rsp)
+
+	return response, nil
 }
 
-// ParsePostTlsApplicationResponse parses an HTTP response from a PostTlsApplicationWithResponse call
-func ParsePostTlsApplicationResponse(rsp *http.Response) (*PostTlsApplicationResponse, error) {
+// ParsePostTlsSessionsIdStepResponse parses an HTTP response from a PostTlsSessionsIdStepWithResponse call
+func ParsePostTlsSessionsIdStepResponse(rsp *http.Response) (*PostTlsSessionsIdStepResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PostTlsApplicationResponse{
+	response := &PostTlsSessionsIdStepResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ApplicationResponse
+		var dest HandshakeStepResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
 		response.JSON200 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
 		var dest ErrorResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
-		response.JSON400 = &dest
+		response.JSON404 = &dest
 
 	}
 
 	return response, nil
 }
 
-// ParsePostTlsHandshakeResponse parses an HTTP response from a PostTlsHandshakeWithResponse call
-func ParsePostTlsHandshakeResponse(rsp *http.Response) (*PostTlsHandshakeResponse, error) {
+// ParseGetTlsTestServerResponse parses an HTTP response from a GetTlsTestServerWithResponse call
+func ParseGetTlsTestServerResponse(rsp *http.Response) (*GetTlsTestServerResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PostTlsHandshakeResponse{
+	response := &GetTlsTestServerResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest HandshakeResponse
+		var dest TestServerResponse
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
 		response.JSON200 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
-		var dest ErrorResponse
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON400 = &dest
-
 	}
 
 	return response, nil
@@ -512,9 +2295,27 @@ type ServerInterface interface {
 	// TLS 1.3 アプリケーションデータを送信
 	// (POST /tls/application)
 	PostTlsApplication(ctx echo.Context) error
+	// uTLS と独自実装 (mytls) のハンドシェイクを比較
+	// (POST /tls/compare)
+	PostTlsCompare(ctx echo.Context) error
 	// TLS 1.3 ハンドシェイクを実行
 	// (POST /tls/handshake)
 	PostTlsHandshake(ctx echo.Context) error
+	// ブラウザのフィンガープリントのプリセット一覧を取得
+	// (GET /tls/presets)
+	GetTlsPresets(ctx echo.Context) error
+	// 1メッセージずつ進めるハンドシェイクのセッションを作成
+	// (POST /tls/sessions)
+	PostTlsSessions(ctx echo.Context) error
+	// セッションを破棄
+	// (DELETE /tls/sessions/{id})
+	DeleteTlsSessionsId(ctx echo.Context, id string) error
+	// ハンドシェイクを1メッセージ進める
+	// (POST /tls/sessions/{id}/step)
+	PostTlsSessionsIdStep(ctx echo.Context, id string) error
+	// ローカルテストサーバーの接続先を取得
+	// (GET /tls/test-server)
+	GetTlsTestServer(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -531,6 +2332,15 @@ func (w *ServerInterfaceWrapper) PostTlsApplication(ctx echo.Context) error {
 	return err
 }
 
+// PostTlsCompare converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTlsCompare(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTlsCompare(ctx)
+	return err
+}
+
 // PostTlsHandshake converts echo context to params.
 func (w *ServerInterfaceWrapper) PostTlsHandshake(ctx echo.Context) error {
 	var err error
@@ -540,6 +2350,65 @@ func (w *ServerInterfaceWrapper) PostTlsHandshake(ctx echo.Context) error {
 	return err
 }
 
+// GetTlsPresets converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTlsPresets(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTlsPresets(ctx)
+	return err
+}
+
+// PostTlsSessions converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTlsSessions(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTlsSessions(ctx)
+	return err
+}
+
+// DeleteTlsSessionsId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTlsSessionsId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteTlsSessionsId(ctx, id)
+	return err
+}
+
+// PostTlsSessionsIdStep converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTlsSessionsIdStep(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTlsSessionsIdStep(ctx, id)
+	return err
+}
+
+// GetTlsTestServer converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTlsTestServer(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTlsTestServer(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -569,38 +2438,254 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.POST(baseURL+"/tls/application", wrapper.PostTlsApplication)
+	router.POST(baseURL+"/tls/compare", wrapper.PostTlsCompare)
 	router.POST(baseURL+"/tls/handshake", wrapper.PostTlsHandshake)
+	router.GET(baseURL+"/tls/presets", wrapper.GetTlsPresets)
+	router.POST(baseURL+"/tls/sessions", wrapper.PostTlsSessions)
+	router.DELETE(baseURL+"/tls/sessions/:id", wrapper.DeleteTlsSessionsId)
+	router.POST(baseURL+"/tls/sessions/:id/step", wrapper.PostTlsSessionsIdStep)
+	router.GET(baseURL+"/tls/test-server", wrapper.GetTlsTestServer)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXb08T2Rf+KpP7+yVi0rRTYXfZvnONEbLEJdJ3appre6Gj05nx3luBGBLujJG6Quyy",
-	"KPHfIq4rCEtZ/xB0F+mHOUzRV36Fzb3TQssMIIm4brIvCLczc+95zjnPc865V1HWLji2RSzOUOoqYtk8",
-	"KWC1PO44ppHF3LCtM+RykTAun2LT/KEfpc5eRf+npB+l0P8S2yck6tsTaZOdMA1i8V5McYFwQhkaOR9D",
-	"fNghKIXsCxdJlqORWKsR5tgWI9KKQ22HUG4QhYTiwUxWHZfJE9O05bMcYVlqOHIjSqHAWJd8CaICXhnc",
-	"J+CV/NK01pYnQ+DOg/cS3JfgrYF34yjaQsI4NawBiUQaYYReITSDt0FlcpjjDG2C1mq4T+0IDLuTfnkR",
-	"XAFiBtwVZaoM3povXoGo+NUHm0tThwkukyNZO0dynxqk3LL+zL+16o/fATGtNrogKvvg/JJi9uWESOIi",
-	"l4sGlSDOhpkdHcK9nflI7h6QRVFqPUmpTXfXaYEwhgciMq7S9UxFchY8D9y/5Np9vW98GidGgenCVo7l",
-	"8SVyeNWpycRuZE739IF3S5LRuwHuKrhzkhXucq1U9n+cqd11FVV+B/cNeA8VZ9+g2D9c3/6T5ueSZhSp",
-	"otgXktJOfYbD8n5UbFRnQdwF9ya4j8GbBm8B3OdKWavgzSlOjqmf1Q9rpa50uld9tAReSaVn8cPaDc1/",
-	"87J2ZwzFEBnCBceUUE+dTGsJTX6fSMaT5+g5q8tmPKUNDg7G61/Fs3ZBvjhhWxbJSkQpLWvajMiH8i8q",
-	"61nDyROaYUWDB062OrTxtro5NR84pJ1Q32p98ltNpXRBSaiktSW/fj/6onb7j9qdMX9p2i9NH21Gfxbp",
-	"Q8l2PYliweJYY9Euk2FwUlCmQ+DqDzCleFiBDVJPsZWzC1EVbVlWNCn1xyrQJRAV7Yz6WvNHnygptABD",
-	"OYJzFwjpb/yPx+NRUbpEhjMsj2lUiL4nw33ylQZiceNtFcR1ZX4R3FcqQH+C90hrq/12279+vXb/xebq",
-	"Pb88EQqPridzKiq6nvzmYFFxqM3trG1mrhDKFKY9s5ju6dMChYL7OqBkS0j0Ib1d74iKQl1QFi7sWqW0",
-	"07hAtG4rV9eJ1tZ3uvto7easv7YCYvHd/JJfuVfXh3c/YI9fnoBRtzY+pl5Ng1gAcc1/9Movl0AsHwms",
-	"HpG1ZPQJiPGGM7fBHQexLg8bdVs82KGJSFeMAQvzIiUZbA7Y1OD5Aosi1IpqEGuKShL05tsXEm4owaF0",
-	"dujtQTq/aiw69Y6D5ZUVHcemnOQyA9QuOh+Fr5lmWtsWNU/JA/bhXH3R2Vh8exC0O+p0iJKt5Nmp5J1l",
-	"KML3Fg3ukr9wYZe4DKs/onn7S083q5OKSJVAEqHicby3G8RcwK+W2sdi2skhTizpGYtpjSgzTVH3GbiT",
-	"78YWNqeeg1hsYvVTZSYZb9ciZxPZEysz72bHlc2JLWYHZdvgitnyhB6CqWVYA9rx3m4UQ1uiR8m4Htdl",
-	"amyHWNgxUAq1x5NxXY42mOdVGhPcZImmPqaanB3Maa0BakI+o5A3tDu33eTF/G6uKD+uwagIRi5/fRzE",
-	"4r4dEdzJRgeVAdsxosljq1Pq1bbqZX9WnnTnUAr12oynTdZ0gUUBMQnj39m5Yelm1rY4sfiOhp64yIJo",
-	"BPOoXO01rUbcw0daRcBpkagHwfShon9M1w8HQX3uURBCo7C2b9yDoO81HY/EUMcnBN96Z4mArcAuy7FV",
-	"tQjwflJ3ldk6T8T4xuuJ2tKvqvCwYqGA6XDdWSWwjyaa1BYeYLJmpXv60Hl5ntJIvnHLOHSFgHgIorK5",
-	"Uq798iBM8ejtogKiqgzt56hYblLUOoj74P68h3K2LleHpJvQ/fAzqyZ8edxNMwe9P/7LFLJnCwqrYmv6",
-	"Y+oiX6QmSqE8504qkTDtLDbz8jbSqXfqaOT8yN8BAAD//8Nsy+/BFAAA",
+	"H4sIAAAAAAAC/+x9+1MbR7rov9Kle6sW6gxYDwTYVafqEoxjNonDGk7O3hunVIM0MloLSasZHHO3XKUZ",
+	"bIINXjvEbzvBjh2DIYg4TuK3/ccMkuCn8y/c6q+7Z7pnekaDwUn23GzVElma6cfXX3/vxz9i2fJkpVzS",
+	"SoYeO/CPmJ6d0CZV+DhQqRQLWdUolEtHtb9PabqBv1WLxY/zsQOf/iP2P6taPnYg9j/2uSPso6/vGyvq",
+	"g8WCVjJG1Ko6qRlaVY+d/kyJGdMVLXYgVh7/m5Y1YqcVcRK9Ui7pGp6lUi1XtKpR0GAlWfwxjx/TMie1",
+	"KvlYKJdiB8JXMei+9wn/2mklli+UjmvVSrVA9x02zCH+2dNKbMIwKpkqt9qwlw8bRsXZ2WklVlU/z2QB",
+	"NJkJrVgs4/dzmp6tFipkSzECuMP4R9us2zOXbOu+PTPXmLuGOia0U7a1Ys88tq3H9sxLe+ZcZ8yBqm5U",
+	"C6XjbBJdq57UqhnVBXAmpxqqsHBx4lF4g0xsLTYurdmWaZtLtvUzTHXJnnnZMH+yzXrjze3W+uV3ubhM",
+	"TsuWc1purxeJX3n9sHHxSWPhqm1egxct26y3WefvCWa/KxBp2XI1p/uXYM9cxHuaOWdbT2xrGQ9ubTTq",
+	"C5vPZxuvF2xzbbtmNi5e23xzl04x870DALtmobEPR1GiO4Vsc9k2N2zrW3vmmj2zaluP8DPWE3tmGcb/",
+	"Av75xjbXGufv2OacbT6wa+YR7fNRTdcL5dJYIXtCM5BtnUEfaNP/UcmphoZsc9U2H8K2Jau0Z+7aMzO2",
+	"9QKGfmpbFvKiKbLNFVj3g+bNawxSV2xrwbsTc7nx6LxtXgQgmnbNFH+9DOOsEXATQLR+vtT85rZtLba+",
+	"fb61egH2f8E2X9vmDbtmHSvFlFjB0Cbb0qyxon4UDid22jk3tVpVp2On4dz+PlWoYtz51E+Q5JgfjoMR",
+	"Sc4OL7+LYDLWwVH3oZJRnZZQUvcJ5D1Ws458AygSvgNL8w99cOgo8lxcBwW2Vl42r/+zeetp5OudK+mZ",
+	"kjqpSW7S6BRsGA0UDa1aUo3CSQ0dUScxFtfRwSOjqHHpAo8UvrHFw1di2ilDK+G7oYcCDCCCJ2nO3228",
+	"/Bluy6ptPbNn5qLi4GG1lNMn1BPaEJtStp6Crk9pVf9aHCjijQ6ThySgK5WNjJo3ZCM0b59rnH/WvL20",
+	"ffUr26y3frY2n8+ijqOHBlEqldrfGTTcuJYvV7W2421fnW8sz7cZr6JWdS2jVavl0C1ai1vL95rfXGL3",
+	"fdU2523znm0uNe781Lg0h8GP8eghIJGAybJZda1aUIuZ0tTkuAwwQEBXgaquta6sNi4+QR2J3u3aj80r",
+	"PzSvftFYv9aYuybdjk6wsc1pUZz1D+AhPe79EtCyzWX/SNN19bgWft07BsuTlaqm61qO/xozPQrQjcaj",
+	"K/gIMTuqN2bPNurPOqUUgL6bKRb00I3PmJiH4PN5jDp4ZsvBZqFxdm77znpn1CvkI1GSC8SvsUoE9Uy2",
+	"XDK0U5LlhjwcmVplKWgx4VaLx8vVgjExKTmOgBNY3nz1xrYuElrZuL3celonqGhbPwFaPrdn7gQgpHZK",
+	"nawU8XLip+LxeDJAVvIvhuexngvUOLuy+eqrKJKZXbMioBXZkSMPYN792jN625uB9xB+soofN9vcG6rE",
+	"Rbk+9NHQ66BOGRP46AsyrkXktyQwqYCXUAfwULO+tX69Mfdd49IFu2bKMTA6c3uXd8Gume62sFjaevhc",
+	"fj+4cfGPO4APPB6A+3vD4v3sHHXwkvj2ndmgje4h0393d1TKtQrHS6oxVdVcctXmTPSpSqVcNbRcRvbu",
+	"Lg/oLe56dO74icc+4uX8UrZUb96/vbXykiofNQvgPuPqWdZiY/bC9tV5ovTwYkvj4jXb/LJx8aptnZOJ",
+	"LRut22bryneMHPJ6TCBp0cPXbZsL2zXTQVh+NeR7jMLmGhOoBA02Egr/NR3fz0FUesPaCXScMAC7XsKc",
+	"4ez321fnty+Q23fZnnncunIDdHMKf6wL3n/UvHKN6oKXZluXH8kQerKckxDwzVdvWpeJYrpEBmxefdZ8",
+	"fAV1SMxxgOZgTJvO4OFE7qpP64Y2KZu6UihlJlUjOyGzPHDjIdtcQJVCqYTvUOVEgeeRa1gTNs/Y5k1A",
+	"innMBDhk5OC0IMIJ76xRu2+bK5tPa1tf/ES+gREe2uasbc67Sx4vl4uaCtQHllWQLZgHEz6Gr+9uvvjF",
+	"thabz2sw8DUOldeQUZ3S7JpVKOladqqqUeoYcOjumTbnLjXOSwdDGBbmqm2dp1fDXLBrJsqrRZ2Mblly",
+	"G4W50frl5tbdBdm9Ct5/JjuhFkpSc80aLHuOO4bXsLslDFlRqxW2WbOCds1jsksNHj73brlmedBkBXkA",
+	"bNdMgo/4x8alBdu8LlvwsrASa5Gt5NpbmE8+oRAbxABrS8HhOnJopojUzH8AUWj4dBRBjTzpI6a/EXv1",
+	"z9l69WPj0oW3YNRwEIEDvp2+0B/viTIpWGKiz4wJ1qULjXMXhOmqupqp6HqmqqtaRp9Qk+neiEK/DwZB",
+	"K+S+lyMUUHyekbVhq/O2dQ75tQAksHng7mC3vEDMuMCIb9jWPNhOHwKB+hZIFnc5axYv5sIlr1QLJ7Gw",
+	"c0KbxjOgkaGP8H1vLnzRqN+kI5rzds08rpW0KnmLUU1rkT2G5ZHNp7XmDat184xtrvLSQOvyUnPuEk8A",
+	"kEeO2HpgNh9Z8MSqbZ5xyBTblkNcb9nWV23lFhl8A0GCOkaGPsIa5ebT9e2rXwkyFbHYW/POejC/DIGu",
+	"udb65aZtfmmbD7Zr91o/X/IRPRcxu/D/3ht6f/gIGhw6OjZ8aHhwYGwIvj1W6u7uPlaCz0NHDkp+l10f",
+	"djz+3VMOx9h+zQxFAbO++eIe2AXO44MeGjw4OoBGupLpXlASv1htPHlE7iAPK7iGG5i2gC7j2NQlZ8/N",
+	"btdMcW7MVpo3LOBNGwy3HNM7f/p+7sqNFGoXW2tsvG68uU1Qu7V8vbExu33hZwcPRj4YHA06qZGjw58M",
+	"jA2hD4b+t/Sk/L9LiU0AiQBPkauuhTkkOYNwzSoRM/Rya6W+ffcb4VLWTPql4+N4c3brgWmbq9t3zrZu",
+	"1cUbzE6I6QoAeGte9qhwTbGiKpdseVBjgdE607z2baN+E382F5ig3E4nCdMWmREsk/XYgQIMWnbNNMon",
+	"tFJmvFDKFUrH8bNYxJ35Eqy6d4kjqx0b+5TZvT7biT1A7sJwLAGbT9e3nq8Bk8aIWFU/Fwxatvk1lgzA",
+	"jiUQxpr5/tGhgdEh4WkyGL7s5fKJgmgaQ9x3tftY0tWpGdGgfjp+WizNPcJSygymHX+fKmQzRlUt6Vgx",
+	"z1TE43ZeEoWM5tM523wDcs0cgPkxkImv4dc5D+z9gmkAwauWpyoSjHBtBuQJ1EHwGejKBgrSwbyvddo1",
+	"84Q2jYWGquYZw/le78Tv2tYPTBC+JsEcR/FEx2LkpI7FdmZIwoKtZKcV/QSmdhntVHZCLR3XQOGD3dgz",
+	"3xLIy5ZTM4XN4FFyE5huoo74qXiic2drk0tpDlITmQx1DA8cGWjdeLG9gDkHRu/m93cBdNgiwBFlLEiA",
+	"TsqIEfVS4mkUpBuqMaUzw4ziOzQFadlMpVwoGZl8uTqpGrqCZJYjRfAlF9VprZqpVMtGOVsuZkra8bJR",
+	"gJ+8M2ZOJsmAWo4nOBmjMKnphjpZUVBFzWG6oiCwFeW0XGZS1Q2tmtG1bFUzFCSQHwXJCJiCiMM1oxf+",
+	"LzYqTxYMBeW0onZcxXvNVrWcVjIKalFXPFeXB8lJrYp/0RV63xUkRxg5iGA9iovqCgq8+goqaaeMAADy",
+	"gNY1wyiUjuvybzMl7XMF4ZWVtGKmkOM/Z8rFHIYKN3SmUMqXFaSVstXpCoCF856jDkoThwYPY2xDVU2f",
+	"mgTsJLedQm1CLeWKXkl2Ce0zivq+CWZCpfovqlQ1AosclZYtn4hyA3U0b9dIbAUvG0PwwR1MsM0zsB7b",
+	"BD3PfEGEa7pa4IuMs1uLW3dX8JAu2YCIh5q5+eJF88xF7Eul3H2Fknt3G5jjomNYl8FvWYv+lfpl0mh3",
+	"Qu5eBaTPFLXScWNCQqnI78BsrzzBYKhZAhF6r4yHGh390LUtMGvQMvIIPnQE0JOaj57j3XDMIqbEJtVT",
+	"hcmpydiB3nQ6lVZik4US+XfcWXqhZGjHtSqsnScX/qV7CQrwuVffNl5ejEBbp0pZx1EF1DW+Q+rKDkCy",
+	"sGinZddM2V3rwJcNs7jAm0vYyDV7Zh2zaMzJ10iIAycETSRjJAxvX6I7sTNZyEfg/Bv0PYI1z62VdQe/",
+	"G7X7/GknelP9/Gn39siOW6Ahskm9JAbf/q2VR42LG3TW2Qv2zAUsU2JL4IoQCGXWG7e+aV75wSMvlEtZ",
+	"Ytgt4XV9GitpJyGKAn8fU2L5qqYVpznjgRBBwLiff63cj17Q2DO3iCYG/jwrmgTEzfXW3hu5nyba/JJX",
+	"MYoGcie7ZkqZIh5thwaq6IhL/h0o7xCKLJvF1dQWXMLMGeP9ipjHcJbX4jnZwTA+HyYKs2cQNS6S4CtA",
+	"2ogSq09sZDSDDo3359OsOC1CoCS+JWy+nkd/Ap0q96dOzK8StnlfCpG39O4BVgeb5z7QpoeoUBRo7+Wd",
+	"sT4rkMcbhnzD+rRaV3DBcoUgJPrnPjo6wMOzLhrQeFfNMhcLCSvxjR7ZDMyLiv4lbV/4efP5/ebFW1hi",
+	"ufqs8fIiR+GGBg8eHoopeN1SulaZGi8WsnJbDbzr2a0f3HVibnR2vpMYXpnnZc8M8VJrsgBKKRqWJytq",
+	"VRvGt31SKxksFH+qKMEGvPWrzzCE6ktb986CVBcQRWvWiTfZj35y12nAKAsyN9KOYuHyaqFIfROR4hYO",
+	"0ecjxerzVw/L33NfQ2CpKDfyQb+7ifiOYr8PCqqOPK8+lc1quh79gHjvZhtPrAdD2VRK1BjkEPw9Ggij",
+	"KUw+bXOlNf89NiID4qKOyWmjSI0oAejb3Li89XImAIkjZ3CgDhKGjT7EkjKZ7zoYtWrM0l+jy7hiW/fg",
+	"YNa44HBhGajDSy5mz2K95M5s5PCcQwWtmDtYyOelobggyGTVosSzXy6qRqFIVdJVsJTcsM1noGL6F7/g",
+	"+udr5ubTu4RqREEh0UEuNbjD2bWNnAyha658my8Wjk8YkS4WDuQ/A7eqfdIA6qApFK8XIh/unpzg1G4h",
+	"47miMB6DuBLz3FERiDz+yG7qEJM7HFeDHijySJ71M5LdRbn9C0e0STl9mxCxIcx2B6VxQy43dQw72P4J",
+	"28+cLJSLTC/f8HnbFtDHFa00MDJMzNHPbGsdVv2Nba5xITqEXnxFjEioUDqpFgu5zIR2SnH+gTM+wN7B",
+	"fUWwraqWcuVJ92tmQsOGOsdKmMGB+4VSxm+YnSqdKJU/L2FhV9cM6S7q1C309EJz/R4QOAu8z7Bgag27",
+	"xsxsWw8ft376AWsiIMnoYFVzTHYZLG5oOTKNnLEQiYYfiIoonaKLVEh15MZF/sA96ahBQ+FpGCyrzMVP",
+	"3DzWwuaL7xr3r4KUS4Cx6oWWCA+8eWyELk8FgBanS9y4xMKJ1oJYQOtnC3y/QhChgzI5TTcKJQIIfNBq",
+	"sVj+nEKj+c/vWr/cbJzF2OjRTphhwjGjEIcfsfMLOGmUy5lJtTSNQy5LWtagKusGcQuTKZpXfgC4nIf9",
+	"rG2bl5mJ9YyLLTUTVUlA9mTBoHggUSIo/feAK+IMzmLpTYCVEtMx983y5puvmwum4031xHTueC71pJYT",
+	"Z3RN2934V/Yjnbuxfn2XczPfB15DUa0e12BWL4rN3MZpgNY9uMBkRNBSMbyfY2z95SxNDBRHB4LBeCDF",
+	"pXjX0bEx8HFfe9i8vcTlF9Ztaw7k2jXEdB8vMjUfLm3NvMLiDDUfOBkAEjriGMGp3uqltpg5uWSS+5dD",
+	"J/nveELJfe9SSqoJhpLKmBITaSU3UtVJCPBSOk/osoRmgaBAFoJnzpenSvg7SjViSizgduNnJNcSdAP3",
+	"hvFPMewUvhPwNqbEfFgVU2IeXICNQ7pdkaZuyUwJwFF/1aT1LGXfYa+7fJ6FKUt1OR+ZDgvbrNs1kwZj",
+	"OJSLyQyRIyoxHsFOYIUyEWoXmvpkkCC5E0sBSLlBuXos7tQTMrC8M7USE4GFXST2/drVA37jxHdvviCJ",
+	"s2WHvSu7gatG+baGTcAypU1Q1yByj1jD1uC2zDfWrzdur7jyrvd1EsVn2easa0YDuVOaIuFXd/4+JVPM",
+	"HeWaybBE6Gbhk6Fx8XkMAxl18G5cEuDqqhufYvN5cvyzbprJKb9X/mn4m0MsicSv5nt/Svo6teuEvMds",
+	"FhKgPXuEZRDrDB86ClZsXBmAiHtu6Nr9cyCaQiCZuYxpIXUEL1CiANn9bCP1bXC2Y7Sw5vBbT+qNuVka",
+	"yU6lzpWt2gwWSqxz+Mit+bZGD89FIAenUJzgdirHdLHUSbAFUzBcdvx5IKWgPw/0YH2B150Rd/XhqVF4",
+	"bLQT8FuSX4Qf6vmrYAh5bFvfw4NQ1YEIxTULcfFkGNYb2zfuA7webK3MterX2kfs/U1N+bf454EUyHTM",
+	"y4Q6Rkc//IR4j5TBQmUCh5K4hgZlqFjE72YHp6onNc8/R7DodYh45MUA876+hNLT35vu6unv7cV/+rp6",
+	"9if2p+HvfiXelUx19aaT/YmuRLwrkehKpbsSvV3prkSqK9HflU509aS7elJdyb6uRF86kepKJpTkfvxS",
+	"skeJy9D7b2oqM6HqE+03jG3SHx1MI+BJRAz/LogCuxsaj6f7x5P53niid3+fms0l44mUlk0n4ql4ur+v",
+	"ry9gTbp0PaPtToA7AKxRA3Yd1YzqtBOF7t6giwIKEvscw5AALyY7HaUn1ZVOBC09GJ6jewDQfE9eG8+m",
+	"05qaSI6nEqqW6Mvmx/u03kSPms+p/fJV9cjW04NCbpIwqZFI5RLpRO9EMtOfU9V8byKd7OtLZLR0b7JP",
+	"y6tJdTwAHD2ZasDUZp3feOPcBXJh4Z5nqhBkB75AMAjdp8GW5gozym3Y1iv2JQ0kDU6u2OBy+pYlB8vt",
+	"Lx5P5pV4PJVW4vH9WfwnpyRS8QT+k8R/Uko2noA/PUo2nhzHf7L4T17JxlNxJZtV+/Gf/Zl4PI5Hiav4",
+	"zzj+k1Pi8UQS/8E/JPrwH/xDMoX/wCf8SCql9PT07lfy+XgiE++JpxScdqLEe+IJJZ4m/0zjTwn8qVeJ",
+	"98aDwC+9SD2jKJyGipVz0MCHI0fA8y+xqOJnhIBea56wMrtm0vfq2zMrwL02UDyOAiJkhcOIJ+PxDIZ6",
+	"Rk33ZtPj+/enkul40BYDUGw0HMdGKZIxfCLcKQxF3FXRw0qlApZ06i2TT5lihFnhX8POCHU4GL2zABC8",
+	"uEx1b5YXCN3Wjedbdxe2amcZM78KNZBI4AGOw3Ys6+jj4YO7yHrGnJrjYYTSMbITIMYU9AktF+gzYA/8",
+	"xtlwNAdWHnPP/bgbWz8/hwxWEpdFRH+ut0rSGtbszNeEOpCz98E3enpBKHuMp+T5eW8T6A1exwvN6/+0",
+	"zVU++TQgOdAfryRbyFtHYPkKlyTH2x40/Eo3r8Tan/Qh13iy88AKls9Xb53/pfnY9GfKQWrgQFGrsmxA",
+	"N8jArJNIYr+ZG/T/Zfc1jjI92Hy63ry67hkCsbJsHiR8DYzqR5yFZd6hT4dVSBPRs6jqRoYzEEWyLY0a",
+	"WoVRGhI1qhWwIVHFm4lQfA02TRzNJeMt3jKkGXXSM5MGKeGDPDsvyAMbCFeq6u/p6UWbL65vL/w40J1A",
+	"zqOQpjs6NnB0TEH/OTA8lhk9TD8MDdEPOB8wM3iU+xf7+An9cGj4yPDo4aGDChr8+MiRocGxoYOdyGHK",
+	"iK+wQeb4YAh1EFmei1hDjddnbfMu1ifpSg6iDk7iP1guOc/QwHQPuvpQSChUcR47m4hLQBZOhxgjQdRr",
+	"JWIucSOg0aEjB539ImLdIRYNHqTO3j2CEt1XWypA8CD04r+72qzcFEFWwLEPR6X0hUR0QMQmCX8iWVc4",
+	"/Sq0qNBeGcqJTdCTmxtxMO7a+8chK5ze6dqm/YN6gxxDhwuKFd1lJVu8BvxbbqooD7QEDe4RxMt+Rw2C",
+	"5nLjh382vnju+Lcgv3KRfElKr2w+fQChQbTuiufqu+mAvghRu2aOHD2EuDnA4OR9Bi97vFjOngBmNDfb",
+	"OPcjeZLUDHFFlp0XfvhAmx6lAMEsICDS4/8nG/zvuPgscz+3O9SjzpMhAWBhA5BNHSLPvpMCqqH0lxdG",
+	"Ag24rMgQJ5s5jowIkn7NQqRiEnVvEHeFr2gqS/xag6TJ67b5APF7x1kTPGDtmpmn3BQHt/gpH+bdfirr",
+	"PusnvaC8CsWD1gIuvDRk83fNEyJEGYvBxdYiszQ4zpq1xtzs1vK9tyl8RRcErjFpdb295Vy5QpX49KMU",
+	"zvBYNnyYOe/xPsjCob2v8PEXGkRUMDlf6vB3EickSmC7OtBcvWvGNtsHurKrE4GtC6aR4GQDb+jx3tYB",
+	"enuSGqRcOyRwbLqioQ5Hgelprd0R1OqkLOUOfxOQwiYOLHdx0v0woh4uqbvIrDDd3Z1eYTGaDga1pffB",
+	"MndQZEHCc5igMZtOTfFfQ+7OlUs7sUBAPXiXcznKLa4O9xUJRsQhXWQfxFcqFAAJuTq/dpaJdqpSqGp6",
+	"RpUEkze/urD56rZtktV7CiSueWstmgutOz81751xbPI42WjuBU7XBLdj7AC2BmldOH5qj/NdCqWcJrN/",
+	"k5RxvyxAS0fHKa1dngcuPI9t8xgTwO/gKQJkbnCUUEqV680rP8RktzlcWSGLxM5865kTberRVKQKzR5q",
+	"BrsyMnnoCTkLeqU8mxfQTUpJDKNyWFNzsuLfh8fGRpDDCXxUgZFLlwz+h65VuwaOEwbpN3erxSnPC0ZR",
+	"7ypqarVUKB3vUiuFtrST0kgyVNB+ODuHuCEIfOVyzXm3FlQjoDsWQ95qFvONLaMJrJcuNC7NQXEDD3/e",
+	"gPf3JYEqCSHSnicS3Ql+ai5AWuNdbiuIRFei8MJG0bLskVuy4c0raIFxDYqAhS5BmHeJn5SU+nEBw6ZE",
+	"IDAQ8DgF6jky3BPsHBSxa7ycm24fjshCemX4NgFoHSGm0ZF1ahZ/PFx9L1bu2FyTAOxwWQ89IiQmwWMn",
+	"Nj8NGnQiVkNHyRbLUOpTXpMKEakEl6bA8TBraPAo9DL58JDbxKQx90vj9V3ibGhXvCxavotLPKQ0zpgo",
+	"5+SJrNitP0M6uARu+P2hMQmqCHLX+0Nj8lofxkT7Y8eM6kv8uWbtA0MS40pcnoCQxbz59Lz7krlCB5tZ",
+	"JaFe+8RBqEHLOoP+F9ZcWw+ft268wvFR/CE4wjV5OPhMAoG0r72rfZ8URPS+BtcGJjUaxJz4mhVIHbxU",
+	"iYkC4gB4k29wDUdGOJoXL7XuPxfuk5N+w8iLZYUNho2I58FxfWFndMw6w1ZRJ8t3Z6a1ZIHaryBWPoQ8",
+	"OWubdwPWcwdXVpRvjs8kWQmggp7lOESTnQhLkQBM8azqjes7YbMzMkxkJ89SV1o4s2JVKB3pJnf5VhaI",
+	"fz4QWYvurmtm2BqsRa4MtSzTgQ2KcwmSEj37dCD/D1KIaD82p1ipjLvVmRQQ7osI4k/CW2/LnzyDUP6E",
+	"OgiR31FKYiiJbk8FJAeHOjjUY1U4JpKi73pC2nCDlCqT7BjvcpZGylvPHOOBoLfHJbWRPOKhsx9nKhfM",
+	"CjkymdDoFdij+jfqCbbwGairJ/Wg9rXW7gh9Eshv6WRPL+rtTrU2avbMi/7uRGuj1hkoY3ucu363itQs",
+	"wWyFTiKOUVXz+UKW1fCQnBAUT4OpAiIcydxMe6qPOc93HVb1iaBmKEJVRQuS/2aukRxB2uOF0BW6seWg",
+	"dgChekU7IL1dHE1blQNjD86WCmgt5lZodFONyRowTFZ9Rw4pVrIrElrB0R85ksgFlqQMsHTxU8gR6lQy",
+	"nU7s33khlp2WQPEcAQGJsPgI9UoEG3W7cipwmWXJHBGvZKRMiCzEL2f0qYKh6Z/GP9sBNkvGr90XBo+f",
+	"wlGLu0dnb0vEQHeS98EA+tfT3dudkBHAeF8fSnWnOkWnL8SikkqRmWIhr2HjFYgYbuFXkHYWIrlyxMz/",
+	"PUzin1RPZTS1WiShdVABzg8h9wHn7i+37rs0ya6ZLI90mUSdbL64DjLkvD+tFBeNub/s5qvWLPqutSi8",
+	"KwpoQVTUsRAWSkZvj9SK9ls4Bsghy6Z1S/wG8RjSeIbU5kYjox9wHaPWLzl9OP0KfPA6MupxLaPmJNSj",
+	"PJ6f0rNQ1c19FCakyQXL/IqhxNHCtvnP5g0LOgKyHqSsQGCE0/BciXAQAbq4LQhRR2t5sdMJ8Qq6ZpkJ",
+	"kizs0JTeeE9/PL6T5ZWgXqCkZCFzq3vqfNF4QTgtFhnCUtJo4wAsH5izCEbeTSSsF4DsGykRHKlqwNNz",
+	"H2jTH+fzMuuoS+x8ZZy8ZVidy88phEsURVdcYxquSqdVXSzaNv/ZWpnnfVndye5EojOgBHk2q8ldjp6w",
+	"b09KjG+1gBz4BrNj2TafNs9/E7noDtlGJqsWs1PFSH6iEf3Ee/DSIPeOU2XIkAhVvutds8RdUpeu0IpC",
+	"uCDLUKIjIlmSXvc2V9DbCuMBWZSHFuBCTUv2zCq+oKRthUB54FbQfsVLEWmF1z3AgBi0DcVFHenhBdwP",
+	"XTMCUy2FGzF8EHHVs8HO7it7Rlo2vyCg8/sfebnJPylJC0Oj+GckVvJ5B5XOiVIVJRABn+1V+O6Bbf3S",
+	"GeA7HpyoluX+Ob57JjGmSjaPi4tmpzOSZ9uBYu86BYrHKUkE4FeC2VBAeVXB+7HGdxOQ1oZGcJLYk+En",
+	"YRs+Z2mdNaZbJknY2zgKZIHZ5Ggyk7gCscPMSoiBLLrNXNpII3LR+iDIOZZLYc2B2NaVkGc14aoGNK8h",
+	"M6nKHLziTcViOHevqYhq1iHYt10JV5/KGpDVISyqUHqbRf1wcReLSoRUtI0Wj+StZyvMIT0KudJGSY87",
+	"u+IhjXKa0bbiF6HmerDdNJxek0qnWw+WfYSboGV03YuylXYZYWxY6V5ksoRUfogobnVjf24fsc9hLQPS",
+	"zZ2Shn4Bgs8s9JiMYUKJn/2jgcEOFsGF6ZeCPGY4PLF4BstCpJ+zFb7bYYh6Q7m8tLbtQa1aOKl1jYKA",
+	"3kFUWNYY4hiW5Olsx2L434R7+ibgX5Ns+IODh7qGThlVNWt0xBUsZ0pH4WESOEpFLeW6PlTHtWKHuy28",
+	"NPY2W6iCsIWymzQA6Hwr46eLMi47M+utWz+B65WEkiw35r6wrfNbD38gMVU+5cBjMm17+QVYCmfngZB/",
+	"A+xp6U1xY51dZiKjZ3I+Klp7RkY/EE083tc4yRgjs6x7BbbryPl8ZKejJ6U5sPvMCkQfh/XXYOqnpKXG",
+	"IvbnejRpb3eitlEWwfXDSDQ1cBAiPWIH8wIaGxxB4IDGkGID+nvSuNIMe8brSkacVcqVhv2mKrFRB7bA",
+	"peMJ1OEpgNVJ9no5rHcekhuqxLIzZOOhKlsdSaxu0FYsYn6f+6ofzx1DHB8Q5Dkc1NF49rh59QswpfDn",
+	"4R4ne3sjqPabI2qitwWvvNcUX1cvQoM9aXgjaQrjPYPgm2xbiwMjw+Jpr7GCftekd8yLKuKNDVnVdo0F",
+	"dlrzyGd6FtP23OIEXlequZBoLS9CT5uH2+ZZ6HkjerrD6xGSoEPS/C+4WuOKkMPCDKKs5uACFA09D4nH",
+	"8rqGrns9sKhiKFZwxhjxBCR+3hc0p98HqWWYgFqqPd2IcEkr55j9KGIuU13LWtx6+ADM9U6YEmek9SBa",
+	"8+oPMNwZ8dZvkNJJvn5ILqaJgVC+xXgDsGixJuFtDmXI97DCFWdq7n2xoyjfNckfetObT2TjWp+6fzyZ",
+	"68mmtP58n9o7ns725FJaMp+IR+u96EtKisykndr64IL1hD8tCx2n5MEQkoQZv0NM+zwj8r+oNeE3n647",
+	"HCg6QfLd/qiqd5BnSxYYoZ+IoKt4TMQs0yyCDTbwwBw6dvY7Uu+GbwtKyoRtreK+pjitnfp2Itpl25GC",
+	"HdF94fo7Rzez5rbN80XbCVVgfZdZ0uQzXEJziCS5pKSoHaZZHDUXi816bq5lBdprxeLC4c60Y6W2EjzD",
+	"CkV6V2SyuZDzstc15yEXTPzGqWBHap3MvCAZfnxqmM/EzmdZucDyjbwRrWjh7rPt3G+dIrTRB6Ix4wHj",
+	"7UHaHlFAmLmmomXb+2kG4R1i3R6taFlPZQyhh4bEic4VJ6gDj8etdW3zpqczqyiBbT5fbF681awRfQxY",
+	"okkjLX2trkPEDrdzkGg3Dq1HG1z1f3cZbpCWlaniEnFRMYNzkXEDCWleux4hw3KgIg6Di1n4h9pJiqWv",
+	"fgY3XKBBk1sB1o6oL5DKhXto6D0dSAZ9uw/pe1VHnnei1Xh6ty0XPgvfWuC2hPKZrq7uq3jYGeoxa18H",
+	"jPPvIsGZ1v4sEwH2cX4BgeFmEVch95uNfTiaGRgazSSS/Zn3Bz/KjB4eSKZ75Rl3v6f2H048YJQ0Mjeu",
+	"8LQS7OrbgVdQGrxBn+aKwAcN6D6S0bIT5cgOdPp6IJERf49GQ1LyChRQ2b4NstXRUXgORY8Kffdd2TBJ",
+	"L2pZrrZYpHaJTnCJhzTvIV32B9N4ztOBuwyXRGIQk9EGJRRh23ivArlaBDbBvYU6oKtfp0/O9bbxc2N2",
+	"rAVigqU2PFlFY9q7H6yEpHSpn1bjasEZeVb70KDH0es+jDqI6R0DMJeB74UDTcluOvdwaLtEGnW1R0HH",
+	"3KwBvCB86p0GI4e1bgw/298TMWA9Zf3zCF083EIq5N/g3vA2H3CxlCDhzlcBOcWyo9thG1sBX3riqSiT",
+	"BiBNcClgGbpo2ZyuYjdaJZnurSYwD5bKC1J6x91R/mTk5AjIV7AzPeFTkW/a5n1Wu2A+qBmUxx4jCXX+",
+	"vdQVKOSiWCiHDwrHk8on1f3ZuJYe78v1JLR+tTebzMdz+8f7tT41kW3PmAq59tnuY5puEMIfVjQDGpNZ",
+	"a/bMGqg3s6ydlUs4eOfnCqd3U39cp1ss0XdMai5XlXbNlIT22GadPu4J7BkeAaw/R4z2YjhJsq873h3v",
+	"lkvlopHFu3HJTnF0xBerjSePyGXjKvUfHDqqoIhUhBIsdcqYyOykXzjLs+Q6hfvckuDlka58A/kNPKIN",
+	"BcpBer04Lu+2FrcemM1HFm+VdH81N1grHMciwmdQe9OwuvDuu4vlrFqcKOvS5Css2kVFDPysByvAeYOL",
+	"pZNyG/wa+nt6pPJAdaqEay9ERoaFrZ+fNOaviE24BCuU9wHeR18zN19chTakpENQuxwvwXgdgjVyCHGv",
+	"+ADlIpRwUiGn42ULFHBSKsPKs0oFUMhrxj9zkUa9tEiRp1oy/653KBjiIPcVt4+euOysufcD4OgdM0Dz",
+	"Fht7YT4o1fhOasWAKT7Ev6GOBPp39Dkp/qGgJPp3lFcNtdi+WBMMHbYFMr588TBH2+MlqxemEgAYk4Az",
+	"ABW8mOnv/xXIEoRCBDwRZKYgkQ+E1An4E3cZ/uS2AJJVkojOTfjCI/KgCj6WAlZK4gYfAYN1HEs0uuK/",
+	"Xs7hzGtPtvF/vTyHSNSFXbPE/G3wzxJXckjpBG/BCLTPKcJyrHqshKt3HECff/55N32qO1uexD+4ZTkO",
+	"kNob+Ev8/3bmLj28pgLaSbg65Ho4gerIz/3IT6TmR0iljE9dUx18SLIPqdhnnHXLr7lNFYvqOB4BuxUD",
+	"6/ztxHkDb3CMmRsm2Hgjian1mnBE5SKnqblxTcuz/3Z3d/8WIfZhpTswbFEH1po7nazGdmcYj4ef1y6j",
+	"9kEBkYbsB1fCEaMvohTBUZC/965jFVWQq/2pxePlasGYmNT5VxzLF2lo7gcaCui0t0Erf9EleoLnzoPX",
+	"9yYOQnuKne3Na9/CY3UnHk6gZixcBCIZs6RRtqdqkLSwjnucLJWYXu/YacX5hpd2uK/hNHNaTkyi45+o",
+	"alzJp0yhlC8Lw3rALoydzUCz0AzR8XRxObzHmv8lWu2pmFtpQofCHpj8ONU9PuNngqoNjpeO/0WCFd7f",
+	"sZGJ89pi/VQ31MkK/5xrfue+lAeKykHH9X1wPh74lB2i4tpRqZEatsetmd7jOFQ0oaMz0iIQUm52P4ZU",
+	"1FwO7vxne5x3wrPYKMVFjjpH5YJWQmyYMwNxBj6/xaj53ZXG7Gzz1o+tJzcbly7sJfejtkn40Lc7plcp",
+	"lDCq6ZUTBWZFCmrfgvEIihty7/A1DteEaB9r3jUy16zRKRAjR8CY+YE2PVzKl0Ht7iQG7MMDXcl0L4YK",
+	"Gld1rbcH+TIRuOgNmvAItW3IkOgDbRqNFECPgRErhVIX2Q9yYt8ar75tvLzY6adesZ6+g0N/qfyt//B7",
+	"o+q/7RsbnvzPf0v/eVCb+ot29MRk+shHlT//5/95PzUxOnXoP/59Z0xLrgaLwrCg6YZw2Z6eVBRBl+jH",
+	"k+qpwiSuPdSbTqfSSmyyUCL/TsjUkEpADqRYQMtNAJQmzrCeNCCYYlZCRiXGfnzXOz1cVazC5xvPK12u",
+	"ONzLX2wJCVJrKFfW5WwZkV45gFxCySrJRqH2v1Cqq2ZGKjboG8rtF/TiHvTbPM8VyfLEFnu8YCSgn3Io",
+	"5m3k+wXKgLrA0rbBfr5xpnHrR9QhlUcWvFIPMQ3GWZ57ojsJEd2SxQbKLN7VhMX870YeaZMa6IVaGyVH",
+	"Bvua9SfCHP/k2E87wYBKvk45X2MgSfb52t8cxjEshaWIfAm3/od2IqhXAgqSROVSmIL8spcnYKvdUXPB",
+	"8FQbCASBtDDokqz+ngf9OUwPRXPfi3WC+GJdOXnBweBUzrdpxMD3nlFi1XJZUDf19qw3O6Ub5ckMflMX",
+	"eC+01nvJ9kt8jXOchXtk6CNMfbfufwEx/3XeMLxdu9f6+ZIn5SSk1LncWkV8EegINncPl3KsLG3H6JHh",
+	"Tkbl1rZW1qWWy/bmnp1YejwWEPlWdhAfQjyMNZPkBKeSjg80TCfmHsPknfReDlg936PNLxkCVwqtHRpP",
+	"JFM96d6+/v3qeDan5Xf673DHJS/wS13QX7O+pq5T1i8R+4RY4jCNn4qn2Yf+eM/upFmfUhhhvbycjjoc",
+	"2f59PMC7E9rph372Yf/uNs7RifAwAT6Yg7h8mlefNR9fCTPu6NO6oU3Kinw6v2yAg5fUD4RedRISJHXD",
+	"EBcLGD43SJEEjjJZpCoTbonmIXwbyEc8sYDgvjtjYn8znuUx5srerBfq7BIC9IUnZPkImBXt0IyNOqhB",
+	"HBrCSaYVdakNYd3uOm8RY47YAKbOnyUX0SMoaBlXCRKb5H8FqV1OO0FuSYWSrmWnQOJyvIJrXKsAkm0g",
+	"78YAHeS/xE3haD8dWiNXluDlNCEBJILABPeMY4JqGlNibFWxz7h75b4b7gLxCXwiL/Mabf2lCHyERbAQ",
+	"BBDLACfKUS1bruZksRRQsw/89HwXFbeq0uabr7fWn3C+tnR3EtfcrJlcFU78DWjVWw8ft376ISCc1tBO",
+	"GQHVWtnEXHU01pF+yZ65CSxtnTwTVL7M1xiGE+7FDjE454V4RLhSAxLTdsnA5yMPLJOvGuuP5DXc7ETs",
+	"Ed2ucc0GMZuiZAp1eD1DXGtHwbsn9UnzCw8KHY6w+gD/n3dpMtDtrt0QD5QovYaE5/eu0ZA48EIAeglJ",
+	"JFLPu+vtpU0rJBICpjrVDH9wIHnzCf6u2A1loZ2sQhZFEKnHMG0qJooJ/pyqnZmbJgLaX0iRDHWkHRE1",
+	"KPyFc5/2Yb0WN5qXxkn5oBbUfBryIOuN+tL2zVuhl5RqkcN45JGiim/VKVJKnR6K7BZKnez+xQXcxbYr",
+	"bBdFIANNQB1BEacpbacWZesioUoDQwMHbbMeXC9QTDtfwWlWgxNqMt41Ui5OJ1LxNMhKn1dxdHLhJAKe",
+	"/wQw8BHJjaZGR7P+14+PClUv6mhgaLTr/cGPfCMg7RQmOwWD1jHkNEivjRYvb/C9QXvmW2ef5O5C6eT7",
+	"TAxf2RG/iJy5qDDPQobUSQmrewjYBniCKJ4wLKtjdLBIy+GXwPm+JHXQAYBYl96+8gSvRoTe4HuDiNs3",
+	"jC8uB0GdXkdHdGuQBo1vob2FE2//ZferXcv4SOyfLycphemKb5MbQEbfeIIdJGr736e0UlbLlKYmx7Xq",
+	"290q+RWoWbuH7luUNOQ7qFHq7ZF3ZFKElOAGEzqeuSq8BMifvExc/UQtFnIgYAwFtRbzpkZTMZbFxbn9",
+	"xPySKNVWQxMp8bSD5RzNm5SXv3Zr2dWhHsW8Mzn68+jHR9AINoVqVdSx+XoeHYvtE6T7fYljsU5/TQEn",
+	"4F3aSF9sSuIZT4a4k0F5HNHbrXkQB6DHYOJOID1FaFin5QYnVGnNObl9ACp8+vR4VhqqLtW1w1Kho5dO",
+	"+2s6vt8TVRNaQ02YRAYA74A+EPy1Ox3fj3iFevPpC9tcpYhUs1gO+ZqsZx6m7FVdy0D7PWCRXHtqVwMP",
+	"LPbtj5Ys6XBxRZC1lf78s/p3KkY9i+7XqIkUhUqGmjV2usKCnslKYuveU/VCFstZulHF5EgnzaQGgFXw",
+	"xFgM0ZXK+AVdnyKcQSKJGRk1b4T8Oq7ly1VN+jN3xCFQxRdDhiqtS7Oty4/CE3xc24HYWW5o8ODoQIA9",
+	"vqAWOV4oZLOqyfFUNqJlVzJhV3ASami0QERXfwfx80ukB6n1ak1aTU26ODK/5Aw9VENyW/y04zSoD3lJ",
+	"e+LG+oPWm0Ww5deJi9CnTuOCUq4czDmzdQW5hQEUxIzOOutvZi1ufbHauvxIrCXywBX1A8xv2A93d0FW",
+	"PMwoGCzTGH1IuxSigZFhrgDngVgCx8diGJYrWkmtFHAqS3eiOx4jbcjginv7DuLvKmVZh0LemwcrZ86f",
+	"ZZfhmCtBW4F9nMEW17lLjfNLpHpO27hb0Un5wFsIymeIjMFeq7CT4RwuplnWDRzzzm2QYI2mG+/RNklU",
+	"vsIfOUDs+5tOoEG4WDsex83ghB2JGIot/fAFye4B6Cfj8XezAjIHWYIkzr8d3AnQyVGBMd9bgwtjVc8e",
+	"Lh7kwrBl+xojerIIFzafXmiu3yPrSv166+IcCAve5CR2P7ZWHjUubvi1EFhsIvUbApG2AAPwkXpvdbeL",
+	"h7W49ctZKO9OeTWsN7n/V1xvYBk7bw0n4gUPKlqHF56OJ369hQeX0AR+UDODSjA2Hy5tzbzCNlFKbak9",
+	"m+zgt8Fr8EdBNgP5sh2sgWlPTqrVaUpugMVFJvWYu6nHIRh17MPR2Gd4PD5yKJhD0ZJ4Am9CtOz/SkBk",
+	"S50ly38Df5fb8i+huLEYp77iIQCNN7db65ehpJWnQxItadXcuLz1coZKlMTD5mNpIB3fhQBwaK8Yvfe3",
+	"ZXG1AFgv6dfXWA/ypcbs2UYdV1PklsECGDhgcTXLhbAut7D7ilgdPyzdRcqdB+nBvhvO7JQ++Y34Mt1d",
+	"6GWDAyAY8Adn/YOz/qtx1n9BvtSWLQTwAXJVA3mU6zl613oUCSEI5BpBlRpIN962zNjcEIND21Dww5zD",
+	"7L8jDefmb6NZyeD+hx71B7X/F6L2NdOJfhPq4goFa81lTzVyFDaJWGElnFAfK/2hru01W3zrI5XUit/x",
+	"Ycr1wVCTZyB/5br2HA9p8rbW+vmMbb7Zev3SF8vjJj2hDpJWoqBDhaqWL59S0KiaV6sFBRU+HlXQUO64",
+	"pqBUb1xBf/kLNeV2ynoqetMVaBuOKJ3EhFQ28DGcg0pjGILefgQslhXtrNMWH3Eo5nzRyT3bCVCBPX1P",
+	"nESn7TuzjecX8Z4xRtwDEFyBFuQwuQgaBnKUiPeizRffbd+44MKVNNm4ilVUJ4/ZfIxxikyBldxzLKmK",
+	"N4aLIsn7GpZIaN+o2DsUCrytqaSMIqAblXgpfKl4V1goxfe0ytzMKiOpXmwjA0KJcAy6wJtDr7m+S8F0",
+	"jRAVkiMXdIm3r843ludpnDM2OpxtPlyihypSD0g4u92cuyRgutDeoQCdJpGwiX3/KORO79MNrRKA7dFK",
+	"jZPYBlLizOQiH1dBCDmHbzG7N26VPFz4HlyCrH0BvVus7zz1p8v78rsR2uJ18neGwMkp4CRhBdKuOX06",
+	"N5/WGvWbrEknKwaAo1jSjblZfJUWSB9PN+WEL5rGot5ty9q+es42rzOLEFuMU7pk883XzQUzUsORmilj",
+	"FiuyzpNiSH2n0/bC14Uk5JJTvWOUIfXvSe3YO8HFW69PQmG42+Nti/CHbvGHbvE71i32Sl/4Q1t4h9rC",
+	"7jUAj6izNxVGHbGhrbgDkgKRdYqavLSkXIbZfLrevLpO5RzeMGfWGftcpOzTfOBfHuO4IbEJB2FFHBcb",
+	"zkFkhluN7VPvYoWtgcYWTP5ZHdNCCWqyGLjlIq3QAvVIRTamcLjljbP5zMfietpXURVAsERofs+vSZa8",
+	"hWMb69cbt1ecfEiSANO8fa5x/lnz9hIRPKA/5kIQ7gZtMRoSgrgaLH3Tas/m7eb6t/z9CVSUaRctjJ+s",
+	"rVEkoZcvFUXbg4KcS288nwCEf369QHqm2eZj3xId6TXIKVlf2Hw+6w7N+yjxuBsoVy6RfBzaBZClpNZM",
+	"GgCLQiPfQ4XC4dwohvi/yJV6B8ZqvP2wO0KpLvM9//e4owHXxct3HI4TeHkNTTe6SFppoLnJ12KSmlRo",
+	"oUjrBW1X6KtMu5vqzS739rl9mPkE4y4N6d2HE1338QVn5WWKL1ATxcwGAIhU4riGIyi8DXFp58RbwZyN",
+	"mGDcitbv0gojqZstlarlBaRpJWwvDrlltoNe5A8hyPLilNggdGeqWowdgCJmB/btc8r5HuiP98djpz87",
+	"/f8GAMdmY4zFAAEA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -1,17 +1,131 @@
 package server
 
 import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	utls "github.com/refraction-networking/utls"
 	"github.com/refraction-networking/utls/server/handler"
 	"github.com/refraction-networking/utls/server/openapi"
+	"github.com/refraction-networking/utls/server/testserver"
+	"golang.org/x/time/rate"
 )
 
-func Run() {
+func Run(opts Options) {
 	e := echo.New()
+	var err error
+	if e.IPExtractor, err = ipExtractor(opts.TrustedProxies); err != nil {
+		e.Logger.Fatal(err)
+	}
 	e.Use(middleware.Logger())
-	server := handler.Server{}
+	if opts.RateLimit > 0 {
+		e.Use(rateLimiter(opts))
+	}
+	if opts.RequestTimeout > 0 {
+		// ハンドラーはリクエストのcontextの期限までに接続とハンドシェイクを終える
+		e.Use(middleware.ContextTimeout(time.Duration(opts.RequestTimeout)))
+	}
+	validator, err := handler.NewRequestValidator()
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	e.Use(validator)
+
+	if opts.Fingerprints != "" {
+		ids, err := utls.LoadClientHelloDefinitions(os.DirFS(opts.Fingerprints))
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		slog.Info("ClientHello definitions loaded", "directory", opts.Fingerprints, "count", len(ids))
+	}
+
+	sessions := handler.NewSessionStore(handler.DefaultSessionTTL)
+	defer sessions.Close()
+	sessions.MaxSessions = opts.MaxSessions
+	sessions.MaxSessionsPerClient = opts.MaxSessionsPerClient
+	tickets := handler.NewTicketStore(handler.DefaultTicketTTL)
+	tickets.MaxSessions = opts.MaxSavedSessions
+	tickets.MaxSessionsPerClient = opts.MaxSavedSessionsPerClient
+	server := handler.Server{
+		Sessions: sessions,
+		Tickets:  tickets,
+	}
+	outbound := handler.OutboundConfig{
+		MaxConcurrentConnections: opts.MaxConcurrentConnections,
+		Allow:                    opts.Allow,
+		Deny:                     opts.Deny,
+		DenyPrivate:              opts.DenyPrivate,
+	}
+	if opts.TestServerAddr != "" {
+		ts, err := testserver.Start(opts.TestServerAddr)
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		defer ts.Close()
+		slog.Info("TLS 1.3 test server started", "address", ts.Addr().String())
+		server.TestServer = ts
+		outbound.Exempt = append(outbound.Exempt, ts.Addr().AddrPort())
+		if ts.Addr().IP.IsUnspecified() {
+			// 全てのアドレスで待ち受けている場合は、ループバックアドレスで接続される
+			port := uint16(ts.Addr().Port)
+			outbound.Exempt = append(outbound.Exempt, netip.AddrPortFrom(netip.IPv6Loopback(), port), netip.AddrPortFrom(netip.MustParseAddr("127.0.0.1"), port))
+		}
+	}
+	if server.Outbound, err = handler.NewOutbound(outbound); err != nil {
+		e.Logger.Fatal(err)
+	}
+
 	e.Static("/static", "out/")
 	openapi.RegisterHandlers(e, server)
-	e.Logger.Fatal(e.Start(":80"))
+	e.Logger.Fatal(e.Start(opts.Addr))
+}
+
+// ipExtractor は、リクエストのクライアントのIPアドレスを決める方法を返す。
+// X-Forwarded-For ヘッダーは、trustedProxies から届いたリクエストの場合だけ使う。
+func ipExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, v := range trustedProxies {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(v)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		_, ipNet, err := net.ParseCIDR(prefix.Masked().String())
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// rateLimiter は、クライアントのIPアドレスごとにリクエスト数を制限するミドルウェアを返す
+func rateLimiter(opts Options) echo.MiddlewareFunc {
+	store := middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+		Rate:      rate.Limit(opts.RateLimit),
+		Burst:     opts.RateBurst,
+		ExpiresIn: 3 * time.Minute,
+	})
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: store,
+		DenyHandler: func(ctx echo.Context, _ string, err error) error {
+			return ctx.JSON(http.StatusTooManyRequests, openapi.ErrorResponse{
+				Code:    openapi.ErrorCodeRateLimited,
+				Message: "too many requests",
+			})
+		},
+	})
 }
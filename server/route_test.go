@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+		expectingErr   bool
+	}{
+		{
+			name:         "正常系：プロキシを指定しない場合は X-Forwarded-For を使わない",
+			remoteAddr:   "127.0.0.1:1234",
+			forwardedFor: "203.0.113.1",
+			want:         "127.0.0.1",
+		},
+		{
+			name:           "正常系：信頼するプロキシからのリクエストは X-Forwarded-For を使う",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.1.2.3:1234",
+			forwardedFor:   "203.0.113.1",
+			want:           "203.0.113.1",
+		},
+		{
+			name:           "正常系：信頼するプロキシが付け足す前の値は使わない",
+			trustedProxies: []string{"10.1.2.3"},
+			remoteAddr:     "10.1.2.3:1234",
+			forwardedFor:   "198.51.100.1, 203.0.113.1",
+			want:           "203.0.113.1",
+		},
+		{
+			name:           "正常系：信頼しないアドレスからのリクエストは X-Forwarded-For を使わない",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "192.168.0.1:1234",
+			forwardedFor:   "203.0.113.1",
+			want:           "192.168.0.1",
+		},
+		{
+			name:           "異常系：不正なプロキシのアドレス",
+			trustedProxies: []string{"proxy.example.com"},
+			expectingErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := ipExtractor(tt.trustedProxies)
+			if (err != nil) != tt.expectingErr {
+				t.Fatalf("ipExtractor() error = %v, expectingErr %v", err, tt.expectingErr)
+			}
+			if tt.expectingErr {
+				return
+			}
+			req := httptest.NewRequest(http.MethodPost, "/tls/handshake", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			if got := extract(req); got != tt.want {
+				t.Errorf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
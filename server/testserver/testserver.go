@@ -0,0 +1,212 @@
+// Package testserver は、学習用APIのハンドシェイクをネットワークなしで試すための
+// ローカルTLSサーバーを提供します。
+package testserver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"golang.org/x/net/http2"
+)
+
+// ServerName は、テストサーバーの証明書に含まれるホスト名です。
+// ハンドシェイク時はこの値をSNIに指定します。
+const ServerName = "localhost"
+
+// ClientAuthServerName は、クライアント証明書を要求させるときにSNIに指定するホスト名です。
+// このホスト名で接続すると、サーバーは CertificateRequest を送信します。
+// 送られてきた証明書は検証しません。
+const ClientAuthServerName = "client-auth.localhost"
+
+// readTimeout は、ハンドシェイク後にアプリケーションデータを待つ時間です。
+const readTimeout = 5 * time.Second
+
+// responseBody は、アプリケーションデータを受け取ったときに返すHTTPレスポンスの本文です。
+const responseBody = "Hello from the TLS learning test server\n"
+
+// Server は、このパッケージの utls.Server を使ってTLS 1.3とTLS 1.2で応答するローカルサーバーです。
+// HTTPは HTTP/1.1 と、ALPNで合意した場合の HTTP/2 に対応しています。
+// 証明書は起動時に自己署名で生成されます。
+type Server struct {
+	listener    net.Listener
+	config      *utls.Config
+	certificate *x509.Certificate
+
+	wg sync.WaitGroup
+}
+
+// Start は、addr ("127.0.0.1:8443" など) で待ち受けるテストサーバーを起動します。
+// ポートに0を指定した場合は空いているポートが使用されます。
+func Start(addr string) (*Server, error) {
+	cert, err := generateCertificate([]string{ServerName, ClientAuthServerName, "127.0.0.1", "::1"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	s := &Server{
+		listener:    listener,
+		certificate: cert.Leaf,
+		config: &utls.Config{
+			Certificates: []utls.Certificate{cert},
+			MinVersion:   utls.VersionTLS12,
+			MaxVersion:   utls.VersionTLS13,
+			NextProtos:   []string{"h2", "http/1.1"},
+		},
+	}
+	clientAuthConfig := s.config.Clone()
+	clientAuthConfig.ClientAuth = utls.RequireAnyClientCert
+	s.config.GetConfigForClient = func(hello *utls.ClientHelloInfo) (*utls.Config, error) {
+		if hello.ServerName == ClientAuthServerName {
+			return clientAuthConfig, nil
+		}
+		return nil, nil
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr は、サーバーが待ち受けているアドレスを返します。
+func (s *Server) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Certificate は、サーバーが提示する自己署名証明書を返します。
+func (s *Server) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// Close は、待ち受けを停止し、処理中の接続が終わるまで待ちます。
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := s.handle(conn); err != nil {
+				slog.Debug("test server connection failed", "remote", conn.RemoteAddr(), "error", err)
+			}
+		}()
+	}
+}
+
+// handle は、ハンドシェイクを行い、クライアントがHTTPリクエストを送ってきた場合は
+// 固定のレスポンスを返します。ALPNで h2 が合意された場合は HTTP/2 で応答します。
+func (s *Server) handle(conn net.Conn) error {
+	conn.SetDeadline(time.Now().Add(readTimeout))
+
+	tlsConn := utls.Server(conn, s.config)
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	if tlsConn.ConnectionState().NegotiatedProtocol == "h2" {
+		(&http2.Server{}).ServeConn(tlsConn, &http2.ServeConnOpts{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				io.WriteString(w, responseBody)
+			}),
+		})
+		return nil
+	}
+
+	// リクエストヘッダの終わり (空行) まで読み込む
+	reader := bufio.NewReader(tlsConn)
+	var request []byte
+	for !bytes.HasSuffix(request, []byte("\r\n\r\n")) {
+		line, err := reader.ReadBytes('\n')
+		request = append(request, line...)
+		if err != nil {
+			// アプリケーションデータを送らずに切断された
+			return nil
+		}
+	}
+
+	response := fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s",
+		len(responseBody), responseBody)
+	if _, err := tlsConn.Write([]byte(response)); err != nil {
+		return err
+	}
+	return tlsConn.Close()
+}
+
+// generateCertificate は、hostsに対する自己署名証明書を生成します。
+// 生成方法は generate_cert.go の ECDSA P-256 の場合と同じです。
+func generateCertificate(hosts []string) (utls.Certificate, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(365 * 24 * time.Hour)
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"TLS Learning Test Server"},
+		},
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return utls.Certificate{}, err
+	}
+	return utls.Certificate{
+		Certificate: [][]byte{derBytes},
+		PrivateKey:  priv,
+		Leaf:        leaf,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package testserver
+
+import (
+	"crypto/x509"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+	"golang.org/x/net/http2"
+)
+
+func TestServer(t *testing.T) {
+	s, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer s.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(s.Certificate())
+
+	t.Run("証明書を検証してTLS 1.3でハンドシェイクできる", func(t *testing.T) {
+		conn, err := utls.Dial("tcp", s.Addr().String(), &utls.Config{
+			ServerName: ServerName,
+			RootCAs:    roots,
+		})
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+
+		if v := conn.ConnectionState().Version; v != utls.VersionTLS13 {
+			t.Errorf("negotiated version = %x, want %x", v, utls.VersionTLS13)
+		}
+
+		if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		response, err := io.ReadAll(conn)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		if !strings.HasPrefix(string(response), "HTTP/1.1 200 OK\r\n") || !strings.HasSuffix(string(response), responseBody) {
+			t.Errorf("unexpected response %q", response)
+		}
+	})
+
+	t.Run("TLS 1.2のみのクライアントともハンドシェイクできる", func(t *testing.T) {
+		conn, err := utls.Dial("tcp", s.Addr().String(), &utls.Config{
+			ServerName: ServerName,
+			RootCAs:    roots,
+			MaxVersion: utls.VersionTLS12,
+		})
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		if v := conn.ConnectionState().Version; v != utls.VersionTLS12 {
+			t.Errorf("negotiated version = %x, want %x", v, utls.VersionTLS12)
+		}
+	})
+
+	t.Run("ALPNでh2を合意した場合はHTTP/2で応答する", func(t *testing.T) {
+		conn, err := utls.Dial("tcp", s.Addr().String(), &utls.Config{
+			ServerName: ServerName,
+			RootCAs:    roots,
+			NextProtos: []string{"h2"},
+		})
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		if p := conn.ConnectionState().NegotiatedProtocol; p != "h2" {
+			t.Fatalf("negotiated protocol = %q, want h2", p)
+		}
+
+		cc, err := (&http2.Transport{}).NewClientConn(conn)
+		if err != nil {
+			t.Fatalf("NewClientConn() error = %v", err)
+		}
+		req, _ := http.NewRequest("GET", "https://"+ServerName+"/", nil)
+		resp, err := cc.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != responseBody {
+			t.Errorf("unexpected response %d %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("ClientAuthServerNameではクライアント証明書を要求する", func(t *testing.T) {
+		clientCert, err := generateCertificate([]string{"client"})
+		if err != nil {
+			t.Fatalf("generateCertificate() error = %v", err)
+		}
+		requested := false
+		conn, err := utls.Dial("tcp", s.Addr().String(), &utls.Config{
+			ServerName: ClientAuthServerName,
+			RootCAs:    roots,
+			GetClientCertificate: func(*utls.CertificateRequestInfo) (*utls.Certificate, error) {
+				requested = true
+				return &clientCert, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		if !requested {
+			t.Error("the server did not request a client certificate")
+		}
+
+		// サーバーが証明書を受け入れていればレスポンスが返る
+		if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		response, err := io.ReadAll(conn)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		if !strings.HasSuffix(string(response), responseBody) {
+			t.Errorf("unexpected response %q", response)
+		}
+	})
+
+	t.Run("TLS 1.1のみのクライアントは拒否される", func(t *testing.T) {
+		_, err := utls.Dial("tcp", s.Addr().String(), &utls.Config{
+			ServerName: ServerName,
+			RootCAs:    roots,
+			MaxVersion: utls.VersionTLS11,
+		})
+		if err == nil {
+			t.Fatal("Dial() succeeded with TLS 1.1")
+		}
+	})
+}
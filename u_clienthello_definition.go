@@ -0,0 +1,607 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/refraction-networking/utls/dicttls"
+)
+
+// ClientHelloDefinitionFormatVersion is the format_version of the
+// ClientHelloDefinition files read and written by this version of uTLS.
+const ClientHelloDefinitionFormatVersion = 1
+
+var ErrUnsupportedDefinitionFormat = errors.New("tls: unsupported ClientHello definition format_version")
+
+// ClientHelloDefinition is a declarative description of the ClientHello of a
+// mimicked client, registered as the ClientHelloSpec of ClientHelloID
+// {Client, Version}.
+//
+// It is stored as JSON in the format of ClientHelloSpecJSONUnmarshaler, with
+// the ClientHelloID, the TLS versions by name and the per-connection rules
+// that a ClientHelloSpec can not express as data:
+//
+//	{
+//		"format_version": 1,
+//		"client": "Chrome",
+//		"version": "133",
+//		"min_vers": "TLS 1.2",
+//		"max_vers": "TLS 1.3",
+//		"shuffle": "chrome",
+//		"cipher_suites": ["GREASE", "TLS_AES_128_GCM_SHA256", ...],
+//		"compression_methods": ["NULL"],
+//		"extensions": [
+//			{"name": "GREASE"},
+//			{"name": "key_share", "client_shares": [{"group": "GREASE", "key_exchange": [0]}, {"group": "X25519MLKEM768"}]},
+//			{"name": "encrypted_client_hello", "candidate_cipher_suites": [{"kdf_id": "HKDF_SHA256", "aead_id": "AES-128-GCM"}], "candidate_payload_lens": [128]},
+//			{"name": "padding", "len": 0},
+//			...
+//		]
+//	}
+//
+// The extensions are listed in the order they are sent. A "GREASE" extension
+// or cipher suite, curve, version or key share is replaced by a GREASE value
+// for each connection, "padding" with len 0 uses BoringPaddingStyle and
+// "pre_shared_key" is a UtlsPreSharedKeyExtension sent when a session is
+// resumed.
+type ClientHelloDefinition struct {
+	FormatVersion int    `json:"format_version"`
+	Client        string `json:"client"`
+	Version       string `json:"version"`
+
+	// TLSVersMin and TLSVersMax are names such as "TLS 1.2", see
+	// ClientHelloSpec.
+	TLSVersMin string `json:"min_vers,omitempty"`
+	TLSVersMax string `json:"max_vers,omitempty"`
+
+	// SessionID is how the legacy session ID is derived from a session
+	// ticket: "" for a random session ID and "sha256" for its SHA-256 hash.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Shuffle is how the extensions are reordered for each connection: ""
+	// keeps the order and "chrome" applies ShuffleChromeTLSExtensions, which
+	// keeps GREASE, padding and pre_shared_key in place.
+	Shuffle string `json:"shuffle,omitempty"`
+
+	CipherSuites       []string          `json:"cipher_suites"`
+	CompressionMethods []string          `json:"compression_methods"`
+	Extensions         []json.RawMessage `json:"extensions"`
+}
+
+// ParseClientHelloDefinition parses and validates a JSON encoded
+// ClientHelloDefinition.
+func ParseClientHelloDefinition(data []byte) (*ClientHelloDefinition, error) {
+	def, _, err := parseClientHelloDefinition(data)
+	return def, err
+}
+
+// parseClientHelloDefinition parses and validates a JSON encoded
+// ClientHelloDefinition and returns it along with its decoded form.
+func parseClientHelloDefinition(data []byte) (*ClientHelloDefinition, *clientHelloTemplate, error) {
+	var version struct {
+		FormatVersion int `json:"format_version"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, nil, err
+	}
+	if version.FormatVersion != ClientHelloDefinitionFormatVersion {
+		return nil, nil, fmt.Errorf("%w: %d", ErrUnsupportedDefinitionFormat, version.FormatVersion)
+	}
+
+	def := &ClientHelloDefinition{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(def); err != nil {
+		return nil, nil, err
+	}
+	t, err := def.validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	return def, t, nil
+}
+
+// ID returns the ClientHelloID the definition is registered as.
+func (def *ClientHelloDefinition) ID() ClientHelloID {
+	return ClientHelloID{def.Client, def.Version, nil, nil}
+}
+
+// ClientHelloSpec returns a new ClientHelloSpec for the definition, with the
+// extensions shuffled if the definition says so. Each call returns new
+// extensions, so the spec may be used for a single connection.
+//
+// The definition is decoded on each call. UTLSIdToSpec uses the form decoded
+// when the definition was registered instead.
+func (def *ClientHelloDefinition) ClientHelloSpec() (ClientHelloSpec, error) {
+	t, err := def.decode()
+	if err != nil {
+		return ClientHelloSpec{}, err
+	}
+	return t.clientHelloSpec(), nil
+}
+
+// clientHelloSpec returns the ClientHelloSpec of the definition with the
+// extensions in the order they are defined.
+func (def *ClientHelloDefinition) clientHelloSpec() (ClientHelloSpec, error) {
+	t, err := def.decode()
+	if err != nil {
+		return ClientHelloSpec{}, err
+	}
+	return t.orderedSpec(), nil
+}
+
+// clientHelloTemplate is a decoded ClientHelloDefinition, from which
+// ClientHelloSpecs are built without decoding the definition again.
+type clientHelloTemplate struct {
+	spec       ClientHelloSpec       // without extensions
+	extensions []func() TLSExtension // new instances of the extensions, in order
+	shuffle    func([]TLSExtension) []TLSExtension
+}
+
+// orderedSpec returns a ClientHelloSpec with new extensions in the order they
+// are defined.
+func (t *clientHelloTemplate) orderedSpec() ClientHelloSpec {
+	spec := t.spec
+	spec.CipherSuites = slices.Clone(t.spec.CipherSuites)
+	spec.CompressionMethods = slices.Clone(t.spec.CompressionMethods)
+	spec.Extensions = make([]TLSExtension, len(t.extensions))
+	for i, newExtension := range t.extensions {
+		spec.Extensions[i] = newExtension()
+	}
+	return spec
+}
+
+// clientHelloSpec returns a ClientHelloSpec with new extensions, shuffled if
+// the definition says so.
+func (t *clientHelloTemplate) clientHelloSpec() ClientHelloSpec {
+	spec := t.orderedSpec()
+	if t.shuffle != nil {
+		spec.Extensions = t.shuffle(spec.Extensions)
+	}
+	return spec
+}
+
+// decode decodes the definition, which does not need to be valid otherwise.
+func (def *ClientHelloDefinition) decode() (*clientHelloTemplate, error) {
+	t := &clientHelloTemplate{}
+	var err error
+	if t.spec.TLSVersMin, err = versionFromName(def.TLSVersMin); err != nil {
+		return nil, err
+	}
+	if t.spec.TLSVersMax, err = versionFromName(def.TLSVersMax); err != nil {
+		return nil, err
+	}
+	if t.spec.CipherSuites, err = cipherSuitesFromNames(def.CipherSuites); err != nil {
+		return nil, err
+	}
+	if t.spec.CompressionMethods, err = compressionMethodsFromNames(def.CompressionMethods); err != nil {
+		return nil, err
+	}
+
+	extensions, err := json.Marshal(def.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	exts := TLSExtensionsJSONUnmarshaler{UseRealPSK: true}
+	if err := json.Unmarshal(extensions, &exts); err != nil {
+		return nil, err
+	}
+	for _, ext := range exts.Extensions() {
+		newExtension, err := extensionBuilder(ext)
+		if err != nil {
+			return nil, err
+		}
+		t.extensions = append(t.extensions, newExtension)
+	}
+
+	switch def.SessionID {
+	case "":
+	case "sha256":
+		t.spec.GetSessionID = sha256.Sum256
+	default:
+		return nil, fmt.Errorf("tls: unknown session_id %q", def.SessionID)
+	}
+	switch def.Shuffle {
+	case "":
+	case "chrome":
+		t.shuffle = ShuffleChromeTLSExtensions
+	default:
+		return nil, fmt.Errorf("tls: unknown shuffle %q", def.Shuffle)
+	}
+	return t, nil
+}
+
+// validate checks the definition and returns it decoded.
+func (def *ClientHelloDefinition) validate() (*clientHelloTemplate, error) {
+	if def.FormatVersion != ClientHelloDefinitionFormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedDefinitionFormat, def.FormatVersion)
+	}
+	if def.Client == "" || def.Version == "" {
+		return nil, errors.New("tls: ClientHello definition without client or version")
+	}
+	switch def.Client {
+	case helloGolang, helloCustom, helloRandomized, helloRandomizedALPN, helloRandomizedNoALPN:
+		return nil, fmt.Errorf("tls: ClientHello definition of reserved client %s", def.Client)
+	}
+	t, err := def.decode()
+	if err != nil {
+		id := def.ID()
+		return nil, fmt.Errorf("tls: invalid ClientHello definition %s: %w", id.Str(), err)
+	}
+	return t, nil
+}
+
+// extensionBuilder returns a function that returns a new copy of ext, an
+// extension decoded from a definition. The copies share no state with ext or
+// with each other, as extensions are modified by the connection using them.
+func extensionBuilder(ext TLSExtension) (func() TLSExtension, error) {
+	var build func() TLSExtension
+	switch e := ext.(type) {
+	case *UtlsGREASEExtension:
+		build = func() TLSExtension { return &UtlsGREASEExtension{Value: e.Value, Body: slices.Clone(e.Body)} }
+	case *SNIExtension:
+		build = func() TLSExtension { return &SNIExtension{ServerName: e.ServerName} }
+	case *StatusRequestExtension:
+		build = func() TLSExtension { return &StatusRequestExtension{} }
+	case *SupportedCurvesExtension:
+		build = func() TLSExtension { return &SupportedCurvesExtension{Curves: slices.Clone(e.Curves)} }
+	case *SupportedPointsExtension:
+		build = func() TLSExtension {
+			return &SupportedPointsExtension{SupportedPoints: slices.Clone(e.SupportedPoints)}
+		}
+	case *SignatureAlgorithmsExtension:
+		build = func() TLSExtension {
+			return &SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: slices.Clone(e.SupportedSignatureAlgorithms)}
+		}
+	case *SignatureAlgorithmsCertExtension:
+		build = func() TLSExtension {
+			return &SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: slices.Clone(e.SupportedSignatureAlgorithms)}
+		}
+	case *ALPNExtension:
+		build = func() TLSExtension { return &ALPNExtension{AlpnProtocols: slices.Clone(e.AlpnProtocols)} }
+	case *SCTExtension:
+		build = func() TLSExtension { return &SCTExtension{} }
+	case *UtlsPaddingExtension:
+		build = func() TLSExtension {
+			return &UtlsPaddingExtension{PaddingLen: e.PaddingLen, WillPad: e.WillPad, GetPaddingLen: e.GetPaddingLen}
+		}
+	case *ExtendedMasterSecretExtension:
+		build = func() TLSExtension { return &ExtendedMasterSecretExtension{} }
+	case *UtlsCompressCertExtension:
+		build = func() TLSExtension { return &UtlsCompressCertExtension{Algorithms: slices.Clone(e.Algorithms)} }
+	case *FakeRecordSizeLimitExtension:
+		build = func() TLSExtension { return &FakeRecordSizeLimitExtension{Limit: e.Limit} }
+	case *FakeDelegatedCredentialsExtension:
+		build = func() TLSExtension {
+			return &FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: slices.Clone(e.SupportedSignatureAlgorithms)}
+		}
+	case *SessionTicketExtension:
+		build = func() TLSExtension { return &SessionTicketExtension{} }
+	case *UtlsPreSharedKeyExtension:
+		build = func() TLSExtension { return &UtlsPreSharedKeyExtension{} }
+	case *SupportedVersionsExtension:
+		build = func() TLSExtension { return &SupportedVersionsExtension{Versions: slices.Clone(e.Versions)} }
+	case *PSKKeyExchangeModesExtension:
+		build = func() TLSExtension { return &PSKKeyExchangeModesExtension{Modes: slices.Clone(e.Modes)} }
+	case *KeyShareExtension:
+		build = func() TLSExtension {
+			shares := make([]KeyShare, len(e.KeyShares))
+			for i, ks := range e.KeyShares {
+				shares[i] = KeyShare{Group: ks.Group, Data: slices.Clone(ks.Data)}
+			}
+			return &KeyShareExtension{KeyShares: shares}
+		}
+	case *NPNExtension:
+		build = func() TLSExtension { return &NPNExtension{NextProtos: slices.Clone(e.NextProtos)} }
+	case *ApplicationSettingsExtension:
+		build = func() TLSExtension {
+			return &ApplicationSettingsExtension{SupportedProtocols: slices.Clone(e.SupportedProtocols)}
+		}
+	case *ApplicationSettingsExtensionNew:
+		build = func() TLSExtension {
+			return &ApplicationSettingsExtensionNew{SupportedProtocols: slices.Clone(e.SupportedProtocols)}
+		}
+	case *FakeChannelIDExtension:
+		build = func() TLSExtension { return &FakeChannelIDExtension{OldExtensionID: e.OldExtensionID} }
+	case *GREASEEncryptedClientHelloExtension:
+		build = func() TLSExtension {
+			return &GREASEEncryptedClientHelloExtension{
+				CandidateCipherSuites: slices.Clone(e.CandidateCipherSuites),
+				CandidateConfigIds:    slices.Clone(e.CandidateConfigIds),
+				EncapsulatedKey:       slices.Clone(e.EncapsulatedKey),
+				CandidatePayloadLens:  slices.Clone(e.CandidatePayloadLens),
+			}
+		}
+	case *RenegotiationInfoExtension:
+		build = func() TLSExtension {
+			return &RenegotiationInfoExtension{Renegotiation: e.Renegotiation, RenegotiatedConnection: slices.Clone(e.RenegotiatedConnection)}
+		}
+	case *CookieExtension:
+		build = func() TLSExtension { return &CookieExtension{Cookie: slices.Clone(e.Cookie)} }
+	case *FakeTokenBindingExtension:
+		build = func() TLSExtension {
+			return &FakeTokenBindingExtension{MajorVersion: e.MajorVersion, MinorVersion: e.MinorVersion, KeyParameters: slices.Clone(e.KeyParameters)}
+		}
+	default:
+		return nil, fmt.Errorf("tls: extension %T can not be defined", ext)
+	}
+	return build, nil
+}
+
+// NewClientHelloDefinition returns the definition of spec as the ClientHello
+// of id, e.g. to store a spec built in Go as a file. The order of spec's
+// extensions is kept; set Shuffle for clients that shuffle them.
+//
+// Only extensions that can be unmarshaled from JSON with their settings are
+// supported, and the values must be known to package dicttls.
+func NewClientHelloDefinition(id ClientHelloID, spec *ClientHelloSpec) (*ClientHelloDefinition, error) {
+	def := &ClientHelloDefinition{
+		FormatVersion: ClientHelloDefinitionFormatVersion,
+		Client:        id.Client,
+		Version:       id.Version,
+	}
+	var err error
+	if def.TLSVersMin, err = versionName(spec.TLSVersMin); err != nil {
+		return nil, err
+	}
+	if def.TLSVersMax, err = versionName(spec.TLSVersMax); err != nil {
+		return nil, err
+	}
+	if spec.GetSessionID != nil {
+		if reflect.ValueOf(spec.GetSessionID).Pointer() != reflect.ValueOf(sha256.Sum256).Pointer() {
+			return nil, errors.New("tls: GetSessionID other than sha256.Sum256 can not be defined")
+		}
+		def.SessionID = "sha256"
+	}
+
+	for _, suite := range spec.CipherSuites {
+		name, err := definitionName(suite, dicttls.DictCipherSuiteValueIndexed)
+		if err != nil {
+			return nil, fmt.Errorf("tls: cipher suite: %w", err)
+		}
+		def.CipherSuites = append(def.CipherSuites, name)
+	}
+	for _, method := range spec.CompressionMethods {
+		name, ok := dicttls.DictCompMethValueIndexed[method]
+		if !ok {
+			return nil, fmt.Errorf("tls: unknown compression method %d", method)
+		}
+		def.CompressionMethods = append(def.CompressionMethods, name)
+	}
+	for _, ext := range spec.Extensions {
+		raw, err := extensionDefinition(ext)
+		if err != nil {
+			return nil, err
+		}
+		def.Extensions = append(def.Extensions, raw)
+	}
+	return def, nil
+}
+
+// extensionDefinition returns the JSON of ext in the format of
+// TLSExtensionsJSONUnmarshaler.
+func extensionDefinition(ext TLSExtension) (json.RawMessage, error) {
+	var name string
+	fields := map[string]any{}
+	var err error
+	switch e := ext.(type) {
+	case *UtlsGREASEExtension:
+		if e.Value != 0 || len(e.Body) != 0 {
+			return nil, errors.New("tls: GREASE extension with a fixed value or body can not be defined")
+		}
+		name = "GREASE"
+	case *SNIExtension:
+		name = "server_name"
+	case *StatusRequestExtension:
+		name = "status_request"
+	case *SupportedCurvesExtension:
+		name = "supported_groups"
+		fields["named_group_list"], err = definitionNames(e.Curves, dicttls.DictSupportedGroupsValueIndexed)
+	case *SupportedPointsExtension:
+		name = "ec_point_formats"
+		fields["ec_point_format_list"], err = definitionNames(e.SupportedPoints, dicttls.DictECPointFormatValueIndexed)
+	case *SignatureAlgorithmsExtension:
+		name = "signature_algorithms"
+		fields["supported_signature_algorithms"], err = definitionNames(e.SupportedSignatureAlgorithms, dicttls.DictSignatureSchemeValueIndexed)
+	case *SignatureAlgorithmsCertExtension:
+		name = "signature_algorithms_cert"
+		fields["supported_signature_algorithms"], err = definitionNames(e.SupportedSignatureAlgorithms, dicttls.DictSignatureSchemeValueIndexed)
+	case *ALPNExtension:
+		name = "application_layer_protocol_negotiation"
+		fields["protocol_name_list"] = e.AlpnProtocols
+	case *SCTExtension:
+		name = "signed_certificate_timestamp"
+	case *UtlsPaddingExtension:
+		name = "padding"
+		switch {
+		case e.GetPaddingLen != nil && reflect.ValueOf(e.GetPaddingLen).Pointer() == reflect.ValueOf(BoringPaddingStyle).Pointer():
+			fields["len"] = 0
+		case e.GetPaddingLen == nil && e.WillPad && e.PaddingLen > 0:
+			fields["len"] = e.PaddingLen
+		default:
+			return nil, errors.New("tls: padding other than BoringPaddingStyle or a fixed length can not be defined")
+		}
+	case *ExtendedMasterSecretExtension:
+		name = "extended_master_secret"
+	case *UtlsCompressCertExtension:
+		name = "compress_certificate"
+		fields["algorithms"], err = definitionNames(e.Algorithms, dicttls.DictCertificateCompressionAlgorithmValueIndexed)
+	case *FakeRecordSizeLimitExtension:
+		name = "record_size_limit"
+		fields["record_size_limit"] = e.Limit
+	case *FakeDelegatedCredentialsExtension:
+		name = "delegated_credentials"
+		fields["supported_signature_algorithms"], err = definitionNames(e.SupportedSignatureAlgorithms, dicttls.DictSignatureSchemeValueIndexed)
+	case *SessionTicketExtension:
+		name = "session_ticket"
+	case *UtlsPreSharedKeyExtension:
+		name = "pre_shared_key"
+	case *SupportedVersionsExtension:
+		name = "supported_versions"
+		var versions []string
+		for _, vers := range e.Versions {
+			if isGREASEUint16(vers) {
+				versions = append(versions, "GREASE")
+				continue
+			}
+			v, err := versionName(vers)
+			if err != nil {
+				return nil, err
+			}
+			versions = append(versions, v)
+		}
+		fields["versions"] = versions
+	case *PSKKeyExchangeModesExtension:
+		name = "psk_key_exchange_modes"
+		fields["ke_modes"], err = definitionNames(e.Modes, dicttls.DictPSKKeyExchangeModeValueIndexed)
+	case *KeyShareExtension:
+		name = "key_share"
+		type clientShare struct {
+			Group       string `json:"group"`
+			KeyExchange []int  `json:"key_exchange,omitempty"`
+		}
+		var shares []clientShare
+		for _, ks := range e.KeyShares {
+			group, err := definitionName(ks.Group, dicttls.DictSupportedGroupsValueIndexed)
+			if err != nil {
+				return nil, fmt.Errorf("tls: key share: %w", err)
+			}
+			share := clientShare{Group: group}
+			for _, b := range ks.Data {
+				share.KeyExchange = append(share.KeyExchange, int(b))
+			}
+			shares = append(shares, share)
+		}
+		fields["client_shares"] = shares
+	case *NPNExtension:
+		if len(e.NextProtos) != 0 {
+			return nil, errors.New("tls: NPN extension with protocols can not be defined")
+		}
+		name = "next_protocol_negotiation"
+	case *ApplicationSettingsExtension:
+		name = "application_settings"
+		fields["supported_protocols"] = e.SupportedProtocols
+	case *ApplicationSettingsExtensionNew:
+		name = "application_settings_new"
+		fields["supported_protocols"] = e.SupportedProtocols
+	case *FakeChannelIDExtension:
+		name = "channel_id"
+		if e.OldExtensionID {
+			name = "channel_id_old"
+		}
+	case *GREASEEncryptedClientHelloExtension:
+		if len(e.EncapsulatedKey) != 0 {
+			return nil, errors.New("tls: GREASE ECH extension with a fixed encapsulated key can not be defined")
+		}
+		name = "encrypted_client_hello"
+		type cipherSuite struct {
+			KdfId  string `json:"kdf_id"`
+			AeadId string `json:"aead_id"`
+		}
+		var suites []cipherSuite
+		for _, suite := range e.CandidateCipherSuites {
+			kdf, ok := dicttls.DictKDFIdentifierValueIndexed[suite.KdfId]
+			if !ok {
+				return nil, fmt.Errorf("tls: unknown HPKE KDF %d", suite.KdfId)
+			}
+			aead, ok := dicttls.DictAEADIdentifierValueIndexed[suite.AeadId]
+			if !ok {
+				return nil, fmt.Errorf("tls: unknown HPKE AEAD %d", suite.AeadId)
+			}
+			suites = append(suites, cipherSuite{kdf, aead})
+		}
+		fields["candidate_cipher_suites"] = suites
+		if len(e.CandidateConfigIds) != 0 {
+			fields["candidate_config_ids"] = e.CandidateConfigIds
+		}
+		fields["candidate_payload_lens"] = e.CandidatePayloadLens
+	case *RenegotiationInfoExtension:
+		if len(e.RenegotiatedConnection) != 0 {
+			return nil, errors.New("tls: renegotiation_info of a renegotiation can not be defined")
+		}
+		name = "renegotiation_info"
+		switch e.Renegotiation {
+		case RenegotiateOnceAsClient:
+		case RenegotiateFreelyAsClient:
+			fields["renegotiation"] = "freely"
+		case RenegotiateNever:
+			fields["renegotiation"] = "never"
+		default:
+			return nil, fmt.Errorf("tls: unknown renegotiation %d", e.Renegotiation)
+		}
+	default:
+		return nil, fmt.Errorf("tls: extension %T can not be defined", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tls: %s: %w", name, err)
+	}
+
+	// "name" is written first for readability
+	raw, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return raw, nil
+	}
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	raw = append(raw[:len(raw)-1], ',')
+	return append(raw, rest[1:]...), nil
+}
+
+// definitionNames returns the dicttls names of values, with "GREASE" for
+// GREASE values.
+func definitionNames[T ~uint8 | ~uint16, K uint8 | uint16](values []T, dict map[K]string) ([]string, error) {
+	var names []string
+	for _, v := range values {
+		name, err := definitionName(v, dict)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func definitionName[T ~uint8 | ~uint16, K uint8 | uint16](v T, dict map[K]string) (string, error) {
+	if isGREASEUint16(uint16(v)) {
+		return "GREASE", nil
+	}
+	name, ok := dict[K(v)]
+	if !ok {
+		return "", fmt.Errorf("no name for value %d", v)
+	}
+	return name, nil
+}
+
+// versionName returns the name of a TLS version, or "" for 0.
+func versionName(vers uint16) (string, error) {
+	if vers == 0 {
+		return "", nil
+	}
+	if _, err := versionFromName(VersionName(vers)); err != nil {
+		return "", fmt.Errorf("tls: unsupported version %#04x", vers)
+	}
+	return VersionName(vers), nil
+}
+
+// versionFromName returns the TLS version named by VersionName, or 0 for "".
+func versionFromName(name string) (uint16, error) {
+	if name == "" {
+		return 0, nil
+	}
+	for _, vers := range []uint16{VersionTLS10, VersionTLS11, VersionTLS12, VersionTLS13} {
+		if VersionName(vers) == name {
+			return vers, nil
+		}
+	}
+	return 0, fmt.Errorf("tls: unknown version %q", name)
+}
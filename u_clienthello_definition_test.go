@@ -0,0 +1,291 @@
+package tls
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// unregisterClientHelloDefinition removes the definition of id registered by
+// a test.
+func unregisterClientHelloDefinition(t *testing.T, id ClientHelloID) {
+	t.Cleanup(func() {
+		clientHelloDefinitions.Lock()
+		defer clientHelloDefinitions.Unlock()
+		delete(clientHelloDefinitions.byID, id.Str())
+		clientHelloDefinitions.ids = slices.DeleteFunc(clientHelloDefinitions.ids, func(other ClientHelloID) bool {
+			return other.Str() == id.Str()
+		})
+	})
+}
+
+func TestBuiltinClientHelloDefinitions(t *testing.T) {
+	// init skips the built-in definitions that fail to parse
+	names, err := fs.Glob(builtinClientHelloDefinitions, "parrots/*.json")
+	if err != nil || len(names) == 0 {
+		t.Fatalf("no built-in definitions: %v", err)
+	}
+	for _, name := range names {
+		data, err := builtinClientHelloDefinitions.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		def, err := ParseClientHelloDefinition(data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		id := def.ID()
+		if _, ok := lookupClientHelloTemplate(id); !ok {
+			t.Errorf("%s: %s is not registered", name, id.Str())
+		}
+	}
+
+	ids := ClientHelloDefinitionIDs()
+	for _, id := range []ClientHelloID{HelloChrome_Auto, HelloFirefox_Auto, HelloIOS_Auto, HelloEdge_Auto, HelloSafari_Auto, HelloChrome_115_PQ_PSK} {
+		if !slices.Contains(ids, id) {
+			t.Errorf("no built-in definition of %s", id.Str())
+		}
+	}
+
+	for _, id := range ids {
+		def, _ := LookupClientHelloDefinition(id)
+		spec, err := def.clientHelloSpec()
+		if err != nil {
+			t.Fatalf("%s: %v", id.Str(), err)
+		}
+		// the definition written for the spec is the definition itself
+		written, err := NewClientHelloDefinition(id, &spec)
+		if err != nil {
+			t.Fatalf("%s: %v", id.Str(), err)
+		}
+		written.Shuffle = def.Shuffle
+		got, _ := json.Marshal(written)
+		want, _ := json.Marshal(def)
+		if string(got) != string(want) {
+			t.Errorf("%s: definition of the spec is\n%s\nwant\n%s", id.Str(), got, want)
+		}
+	}
+}
+
+func TestClientHelloDefinitionSpecsAreIndependent(t *testing.T) {
+	spec, err := UTLSIdToSpec(HelloChrome_133)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ext := range spec.Extensions {
+		switch e := ext.(type) {
+		case *KeyShareExtension:
+			e.KeyShares[0].Group = 0
+		case *SupportedCurvesExtension:
+			e.Curves[0] = 0
+		}
+	}
+	spec.CipherSuites[0] = 0
+
+	other, err := UTLSIdToSpec(HelloChrome_133)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.CipherSuites[0] == 0 {
+		t.Error("specs share their cipher suites")
+	}
+	for _, ext := range other.Extensions {
+		switch e := ext.(type) {
+		case *KeyShareExtension:
+			if e.KeyShares[0].Group == 0 {
+				t.Error("specs share their key shares")
+			}
+		case *SupportedCurvesExtension:
+			if e.Curves[0] == 0 {
+				t.Error("specs share their supported curves")
+			}
+		}
+	}
+}
+
+func TestClientHelloDefinitionShuffle(t *testing.T) {
+	def, _ := LookupClientHelloDefinition(HelloChrome_133)
+	ordered, err := def.clientHelloSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		spec, err := def.ClientHelloSpec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(spec.Extensions) != len(ordered.Extensions) {
+			t.Fatalf("got %d extensions, want %d", len(spec.Extensions), len(ordered.Extensions))
+		}
+		for j, ext := range ordered.Extensions {
+			if _, ok := ext.(*UtlsGREASEExtension); ok {
+				if _, ok := spec.Extensions[j].(*UtlsGREASEExtension); !ok {
+					t.Errorf("extension %d is %T, want the GREASE extension kept in place", j, spec.Extensions[j])
+				}
+			}
+		}
+		if ordered.Extensions[1] == spec.Extensions[1] {
+			t.Fatal("the spec shares extensions with another spec")
+		}
+	}
+}
+
+const testClientHelloDefinition = `{
+	"format_version": 1,
+	"client": "Example",
+	"version": "1",
+	"min_vers": "TLS 1.2",
+	"max_vers": "TLS 1.3",
+	"cipher_suites": ["GREASE", "TLS_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"],
+	"compression_methods": ["NULL"],
+	"extensions": [
+		{"name": "GREASE"},
+		{"name": "server_name"},
+		{"name": "supported_groups", "named_group_list": ["GREASE", "x25519", "secp256r1"]},
+		{"name": "ec_point_formats", "ec_point_format_list": ["uncompressed"]},
+		{"name": "application_layer_protocol_negotiation", "protocol_name_list": ["h2"]},
+		{"name": "signature_algorithms", "supported_signature_algorithms": ["ecdsa_secp256r1_sha256", "rsa_pss_rsae_sha256"]},
+		{"name": "key_share", "client_shares": [{"group": "GREASE", "key_exchange": [0]}, {"group": "x25519"}]},
+		{"name": "psk_key_exchange_modes", "ke_modes": ["psk_dhe_ke"]},
+		{"name": "supported_versions", "versions": ["GREASE", "TLS 1.3", "TLS 1.2"]},
+		{"name": "application_settings_new", "supported_protocols": ["h2"]},
+		{"name": "encrypted_client_hello", "candidate_cipher_suites": [{"kdf_id": "HKDF_SHA256", "aead_id": "AES-128-GCM"}], "candidate_payload_lens": [128]},
+		{"name": "GREASE"},
+		{"name": "padding", "len": 0}
+	]
+}`
+
+func TestLoadClientHelloDefinitions(t *testing.T) {
+	id := ClientHelloID{Client: "Example", Version: "1"}
+	unregisterClientHelloDefinition(t, id)
+	ids, err := LoadClientHelloDefinitions(fstest.MapFS{
+		"example.json": {Data: []byte(testClientHelloDefinition)},
+		"README.md":    {Data: []byte("not a definition")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != id {
+		t.Fatalf("loaded %v, want %v", ids, id)
+	}
+	if !slices.Contains(ClientHelloDefinitionIDs(), id) {
+		t.Errorf("%s is not registered", id.Str())
+	}
+
+	uconn := UClient(&net.TCPConn{}, &Config{ServerName: "example.com"}, id)
+	if err := uconn.BuildHandshakeState(); err != nil {
+		t.Fatal(err)
+	}
+	hello := uconn.HandshakeState.Hello
+	if !isGREASEUint16(hello.CipherSuites[0]) || !slices.Equal(hello.CipherSuites[1:], []uint16{TLS_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}) {
+		t.Errorf("cipher suites = %x", hello.CipherSuites)
+	}
+	if len(uconn.Extensions) != 13 {
+		t.Fatalf("got %d extensions, want 13", len(uconn.Extensions))
+	}
+	if _, ok := uconn.Extensions[10].(*GREASEEncryptedClientHelloExtension); !ok {
+		t.Errorf("extension 10 is %T, want GREASE ECH", uconn.Extensions[10])
+	}
+	if hello.ServerName != "example.com" || !slices.Equal(hello.AlpnProtocols, []string{"h2"}) {
+		t.Errorf("ServerName %q, ALPN %q", hello.ServerName, hello.AlpnProtocols)
+	}
+
+	// a definition with the same ClientHelloID replaces the registered one
+	def, err := ParseClientHelloDefinition([]byte(strings.Replace(testClientHelloDefinition, `"protocol_name_list": ["h2"]`, `"protocol_name_list": ["http/1.1"]`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterClientHelloDefinition(def); err != nil {
+		t.Fatal(err)
+	}
+	if registered, _ := LookupClientHelloDefinition(id); registered != def {
+		t.Error("the definition was not replaced")
+	}
+	if n := len(ClientHelloDefinitionIDs()) - len(slices.DeleteFunc(ClientHelloDefinitionIDs(), func(other ClientHelloID) bool { return other == id })); n != 1 {
+		t.Errorf("%s is registered %d times", id.Str(), n)
+	}
+}
+
+func TestLoadClientHelloDefinitionsFailure(t *testing.T) {
+	id := ClientHelloID{Client: "Example", Version: "1"}
+	unregisterClientHelloDefinition(t, id)
+	_, err := LoadClientHelloDefinitions(fstest.MapFS{
+		"a.json": {Data: []byte(testClientHelloDefinition)},
+		"b.json": {Data: []byte(strings.Replace(testClientHelloDefinition, `"TLS_AES_128_GCM_SHA256"`, `"TLS_AES_999"`, 1))},
+	})
+	if err == nil || !strings.Contains(err.Error(), "b.json") {
+		t.Fatalf("err = %v, want an error for b.json", err)
+	}
+	if _, ok := LookupClientHelloDefinition(id); ok {
+		t.Error("a.json was registered although b.json failed")
+	}
+
+	_, err = LoadClientHelloDefinitions(fstest.MapFS{
+		"a.json": {Data: []byte(testClientHelloDefinition)},
+		"b.json": {Data: []byte(testClientHelloDefinition)},
+	})
+	if err == nil {
+		t.Error("loaded two definitions of the same ClientHelloID")
+	}
+}
+
+func TestParseClientHelloDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr error
+	}{
+		{"format_version", `"format_version": 1`, `"format_version": 2`, ErrUnsupportedDefinitionFormat},
+		{"unknown field", `"client"`, `"shufle": "chrome", "client"`, nil},
+		{"reserved client", `"client": "Example"`, `"client": "Golang"`, nil},
+		{"no version", `"version": "1"`, `"version": ""`, nil},
+		{"shuffle", `"client"`, `"shuffle": "firefox", "client"`, nil},
+		{"session_id", `"client"`, `"session_id": "md5", "client"`, nil},
+		{"min_vers", `"TLS 1.2"`, `"SSL 3.0"`, nil},
+		{"extension", `{"name": "server_name"}`, `{"name": "server_nam"}`, ErrUnknownExtension},
+		{"curve", `"secp256r1"`, `"secp256r2"`, nil},
+		{"ECH AEAD", `"AES-128-GCM"`, `"AES-128-CCM"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Replace(testClientHelloDefinition, tt.old, tt.new, 1)
+			if data == testClientHelloDefinition {
+				t.Fatalf("%q is not in the definition", tt.old)
+			}
+			_, err := ParseClientHelloDefinition([]byte(data))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientHelloDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  TLSExtension
+	}{
+		{"custom padding", &UtlsPaddingExtension{GetPaddingLen: AlwaysPadToLen(512)}},
+		{"fake PSK", &FakePreSharedKeyExtension{}},
+		{"generic", &GenericExtension{Id: 0x1234}},
+		{"unnamed curve", &SupportedCurvesExtension{[]CurveID{0x1234}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &ClientHelloSpec{Extensions: []TLSExtension{tt.ext}}
+			if _, err := NewClientHelloDefinition(ClientHelloID{Client: "Example", Version: "1"}, spec); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
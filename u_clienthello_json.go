@@ -39,20 +39,29 @@ func (c *CipherSuitesJSONUnmarshaler) UnmarshalJSON(jsonStr []byte) error {
 		return err
 	}
 
-	for _, name := range cipherSuiteNames {
+	cipherSuites, err := cipherSuitesFromNames(cipherSuiteNames)
+	if err != nil {
+		return err
+	}
+	c.cipherSuites = append(c.cipherSuites, cipherSuites...)
+	return nil
+}
+
+func cipherSuitesFromNames(names []string) ([]uint16, error) {
+	var cipherSuites []uint16
+	for _, name := range names {
 		if name == "GREASE" {
-			c.cipherSuites = append(c.cipherSuites, GREASE_PLACEHOLDER)
+			cipherSuites = append(cipherSuites, GREASE_PLACEHOLDER)
 			continue
 		}
 
 		if id, ok := dicttls.DictCipherSuiteNameIndexed[name]; ok {
-			c.cipherSuites = append(c.cipherSuites, id)
+			cipherSuites = append(cipherSuites, id)
 		} else {
-			return fmt.Errorf("unknown cipher suite name: %s", name)
+			return nil, fmt.Errorf("unknown cipher suite name: %s", name)
 		}
 	}
-
-	return nil
+	return cipherSuites, nil
 }
 
 func (c *CipherSuitesJSONUnmarshaler) CipherSuites() []uint16 {
@@ -69,15 +78,24 @@ func (c *CompressionMethodsJSONUnmarshaler) UnmarshalJSON(jsonStr []byte) error
 		return err
 	}
 
-	for _, name := range compressionMethodNames {
+	compressionMethods, err := compressionMethodsFromNames(compressionMethodNames)
+	if err != nil {
+		return err
+	}
+	c.compressionMethods = append(c.compressionMethods, compressionMethods...)
+	return nil
+}
+
+func compressionMethodsFromNames(names []string) ([]uint8, error) {
+	var compressionMethods []uint8
+	for _, name := range names {
 		if id, ok := dicttls.DictCompMethNameIndexed[name]; ok {
-			c.compressionMethods = append(c.compressionMethods, id)
+			compressionMethods = append(compressionMethods, id)
 		} else {
-			return fmt.Errorf("unknown compression method name: %s", name)
+			return nil, fmt.Errorf("unknown compression method name: %s", name)
 		}
 	}
-
-	return nil
+	return compressionMethods, nil
 }
 
 func (c *CompressionMethodsJSONUnmarshaler) CompressionMethods() []uint8 {
@@ -0,0 +1,146 @@
+package tls
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sync"
+)
+
+// builtinClientHelloDefinitions are the definitions of the ClientHelloIDs
+// declared in this package, one file per ClientHelloID.
+//
+//go:embed parrots/*.json
+var builtinClientHelloDefinitions embed.FS
+
+// clientHelloDefinitions is the registry of ClientHelloDefinitions that
+// utlsIdToSpec looks up. ClientHelloIDs are matched by Client and Version.
+var clientHelloDefinitions = struct {
+	sync.RWMutex
+	byID map[string]registeredClientHelloDefinition
+	ids  []ClientHelloID // in registration order
+}{
+	byID: map[string]registeredClientHelloDefinition{},
+}
+
+// registeredClientHelloDefinition is a registered definition along with its
+// form decoded at registration, so that utlsIdToSpec does not decode it for
+// every connection.
+type registeredClientHelloDefinition struct {
+	def      *ClientHelloDefinition
+	template *clientHelloTemplate
+}
+
+func init() {
+	// A built-in definition that fails to load is left out, and its
+	// ClientHelloID is unknown. TestBuiltinClientHelloDefinitions checks
+	// that they all load.
+	registerClientHelloDefinitions(parseBuiltinClientHelloDefinitions())
+}
+
+// parseBuiltinClientHelloDefinitions returns the built-in definitions that
+// parse, skipping the others.
+func parseBuiltinClientHelloDefinitions() []registeredClientHelloDefinition {
+	names, _ := fs.Glob(builtinClientHelloDefinitions, "parrots/*.json")
+	var defs []registeredClientHelloDefinition
+	for _, name := range names {
+		data, err := builtinClientHelloDefinitions.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		def, t, err := parseClientHelloDefinition(data)
+		if err != nil {
+			continue
+		}
+		defs = append(defs, registeredClientHelloDefinition{def, t})
+	}
+	return defs
+}
+
+// RegisterClientHelloDefinition validates def and registers it as the
+// ClientHelloSpec of def.ID(), replacing any definition of the same
+// ClientHelloID, including the built-in ones. The ClientHelloID can then be
+// used with UClient and UTLSIdToSpec. def must not be modified after it is
+// registered.
+func RegisterClientHelloDefinition(def *ClientHelloDefinition) error {
+	t, err := def.validate()
+	if err != nil {
+		return err
+	}
+	registerClientHelloDefinitions([]registeredClientHelloDefinition{{def, t}})
+	return nil
+}
+
+// LoadClientHelloDefinitions parses the ClientHelloDefinition in each .json
+// file at the root of fsys and registers them all, or none of them if any
+// fails to parse. It returns the ClientHelloIDs of the loaded definitions.
+//
+// Use os.DirFS to load the definitions in a directory.
+func LoadClientHelloDefinitions(fsys fs.FS) ([]ClientHelloID, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]registeredClientHelloDefinition, 0, len(names))
+	ids := make([]ClientHelloID, 0, len(names))
+	seen := map[string]string{}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		def, t, err := parseClientHelloDefinition(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(name), err)
+		}
+		id := def.ID()
+		if other, ok := seen[id.Str()]; ok {
+			return nil, fmt.Errorf("%s: ClientHelloID %s is already defined in %s", name, id.Str(), other)
+		}
+		seen[id.Str()] = name
+		defs = append(defs, registeredClientHelloDefinition{def, t})
+		ids = append(ids, id)
+	}
+	registerClientHelloDefinitions(defs)
+	return ids, nil
+}
+
+func registerClientHelloDefinitions(defs []registeredClientHelloDefinition) {
+	clientHelloDefinitions.Lock()
+	defer clientHelloDefinitions.Unlock()
+	for _, r := range defs {
+		id := r.def.ID()
+		if _, ok := clientHelloDefinitions.byID[id.Str()]; !ok {
+			clientHelloDefinitions.ids = append(clientHelloDefinitions.ids, id)
+		}
+		clientHelloDefinitions.byID[id.Str()] = r
+	}
+}
+
+// ClientHelloDefinitionIDs returns the ClientHelloIDs of the registered
+// definitions, the built-in ones first, in the order they were registered.
+func ClientHelloDefinitionIDs() []ClientHelloID {
+	clientHelloDefinitions.RLock()
+	defer clientHelloDefinitions.RUnlock()
+	return append([]ClientHelloID(nil), clientHelloDefinitions.ids...)
+}
+
+// LookupClientHelloDefinition returns the registered definition of id, which
+// must not be modified.
+func LookupClientHelloDefinition(id ClientHelloID) (*ClientHelloDefinition, bool) {
+	clientHelloDefinitions.RLock()
+	defer clientHelloDefinitions.RUnlock()
+	r, ok := clientHelloDefinitions.byID[id.Str()]
+	return r.def, ok
+}
+
+// lookupClientHelloTemplate returns the decoded form of the registered
+// definition of id.
+func lookupClientHelloTemplate(id ClientHelloID) (*clientHelloTemplate, bool) {
+	clientHelloDefinitions.RLock()
+	defer clientHelloDefinitions.RUnlock()
+	r, ok := clientHelloDefinitions.byID[id.Str()]
+	return r.template, ok
+}
@@ -378,10 +378,14 @@ func (c *UConn) handshakeContext(ctx context.Context) (ret error) {
 	c.handshakeErr = c.handshakeFn(handshakeCtx)
 	if c.handshakeErr == nil {
 		c.handshakes++
+		c.utlsServerFingerprints()
 	} else {
 		// If an error occurred during the hadshake try to flush the
 		// alert that might be left in the buffer.
 		c.flush()
+		if c.utls.trackFailure {
+			c.utls.handshakeFailure = c.newHandshakeFailure(c.handshakeErr)
+		}
 	}
 
 	if c.handshakeErr == nil && !c.isHandshakeComplete.Load() {
@@ -589,8 +593,6 @@ func (uconn *UConn) MarshalClientHello() error {
 
 }
 
-var ClientHelloRaw = []byte{}
-
 // MarshalClientHelloNoECH marshals ClientHello as if there was no
 // ECH extension present.
 func (uconn *UConn) MarshalClientHelloNoECH() error {
@@ -669,7 +671,6 @@ func (uconn *UConn) MarshalClientHelloNoECH() error {
 	}
 
 	hello.Raw = helloBuffer.Bytes()
-	ClientHelloRaw = hello.Raw
 	return nil
 }
 
@@ -840,6 +841,9 @@ func (c *Conn) utlsHandshakeMessageType(msgType byte) (handshakeMessage, error)
 // Extending (*Conn).connectionStateLocked()
 func (c *Conn) utlsConnectionStateLocked(state *ConnectionState) {
 	state.PeerApplicationSettings = c.utls.peerApplicationSettings
+	state.JA3S = c.utls.ja3s
+	state.JA4S = c.utls.ja4s
+	state.JA4X = c.utls.ja4x
 }
 
 type utlsConnExtraFields struct {
@@ -849,6 +853,35 @@ type utlsConnExtraFields struct {
 	applicationSettingsCodepoint uint16
 
 	sessionController *sessionController
+
+	// recorder captures records and handshake messages, if set
+	recorder *HandshakeRecorder
+
+	// keyScheduleObserver is notified of TLS 1.3 key derivations and of the
+	// TLS 1.2 master secret and key block, if set
+	keyScheduleObserver KeyScheduleObserver
+
+	// trackFailure enables handshakeFailure. lastMessage, receivedAlert and
+	// sentAlert describe the handshake so far; lastMessage is only kept if
+	// trackFailure is set. sentAlert is guarded by Conn.out.
+	trackFailure     bool
+	handshakeFailure *HandshakeFailure
+	lastMessage      *RecordedMessage
+	receivedAlert    *HandshakeAlert
+	sentAlert        *HandshakeAlert
+
+	// receivedHelloRetryRequest is set once a client receives a
+	// HelloRetryRequest, even if it then fails to send its second
+	// ClientHello.
+	receivedHelloRetryRequest bool
+
+	// serverHello is the ServerHello message received by a client, not
+	// counting a HelloRetryRequest. It is dropped once ja3s, ja4s and ja4x
+	// are computed at the end of the handshake.
+	serverHello []byte
+	ja3s        JA3S
+	ja4s        JA4S
+	ja4x        []JA4X
 }
 
 // Read reads data from the connection.
@@ -947,6 +980,7 @@ func (c *UConn) handleRenegotiation() error {
 	// [uTLS section ends]
 	if c.handshakeErr = c.clientHandshake(context.Background()); c.handshakeErr == nil {
 		c.handshakes++
+		c.utlsServerFingerprints()
 	}
 	return c.handshakeErr
 }
@@ -0,0 +1,496 @@
+package tls
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// HandshakeExtension is a single extension as it appears inside a
+// handshake message, before any interpretation of its body.
+type HandshakeExtension struct {
+	Type uint16
+	Data []byte
+}
+
+// DecodedServerHello is a ServerHello (or HelloRetryRequest) broken down
+// into its fields.
+type DecodedServerHello struct {
+	Raw []byte
+
+	// Hello holds the fields understood by crypto/tls.
+	Hello *PubServerHelloMsg
+
+	// IsHelloRetryRequest is true if the random is the special
+	// HelloRetryRequest value from RFC 8446, Section 4.1.3.
+	IsHelloRetryRequest bool
+
+	Extensions []HandshakeExtension
+}
+
+// DecodedEncryptedExtensions is an EncryptedExtensions message broken down
+// into its extension list.
+type DecodedEncryptedExtensions struct {
+	Raw        []byte
+	Extensions []HandshakeExtension
+}
+
+// DecodedCertificateRequest is a CertificateRequest message. TLS 1.3
+// messages carry RequestContext and Extensions, TLS 1.2 messages the
+// remaining fields (RFC 5246, Section 7.4.4).
+type DecodedCertificateRequest struct {
+	Raw            []byte
+	RequestContext []byte
+	Extensions     []HandshakeExtension
+
+	CertificateTypes       []byte
+	SignatureSchemes       []SignatureScheme
+	CertificateAuthorities [][]byte
+}
+
+// DecodedCertificateEntry is a single CertificateEntry of a TLS 1.3
+// Certificate message.
+type DecodedCertificateEntry struct {
+	// Data is the DER encoding of the certificate.
+	Data       []byte
+	Extensions []HandshakeExtension
+
+	// Certificate is the parsed form of Data, or nil if it could not be
+	// parsed. ParseError is set in the latter case.
+	Certificate *x509.Certificate
+	ParseError  error
+}
+
+// DecodedCertificate is a Certificate message. If the server sent a
+// CompressedCertificate, the message is decoded after decompression and
+// CompressionAlgorithm records the algorithm used. TLS 1.2 messages have no
+// RequestContext and no per-entry extensions.
+type DecodedCertificate struct {
+	Raw                  []byte
+	CompressionAlgorithm CertCompressionAlgo
+	RequestContext       []byte
+	Entries              []DecodedCertificateEntry
+}
+
+// DecodedCertificateVerify is a CertificateVerify message.
+type DecodedCertificateVerify struct {
+	Raw             []byte
+	SignatureScheme SignatureScheme
+	Signature       []byte
+}
+
+// DecodedFinished is a Finished message.
+type DecodedFinished struct {
+	Raw        []byte
+	VerifyData []byte
+}
+
+// DecodedNewSessionTicket is a NewSessionTicket message. TLS 1.2 messages
+// (RFC 5077, Section 3.3) only carry Lifetime and Ticket.
+type DecodedNewSessionTicket struct {
+	Raw []byte
+
+	// Lifetime is ticket_lifetime in seconds, or ticket_lifetime_hint in
+	// TLS 1.2.
+	Lifetime   uint32
+	AgeAdd     uint32
+	Nonce      []byte
+	Ticket     []byte
+	Extensions []HandshakeExtension
+
+	// MaxEarlyData is max_early_data_size from the early_data extension,
+	// or zero if the server does not accept 0-RTT data with this ticket.
+	MaxEarlyData uint32
+}
+
+// DecodedServerKeyExchange is a TLS 1.2 ServerKeyExchange message of an
+// ECDHE cipher suite (RFC 8422, Section 5.4).
+type DecodedServerKeyExchange struct {
+	Raw []byte
+
+	// CurveType is the ECParameters curve_type. Only named_curve (3) is
+	// supported, other types leave NamedCurve and PublicKey empty.
+	CurveType  uint8
+	NamedCurve CurveID
+	PublicKey  []byte
+
+	// SignatureScheme is zero before TLS 1.2, where the signature algorithm
+	// is implied by the server certificate.
+	SignatureScheme SignatureScheme
+	Signature       []byte
+}
+
+// DecodedServerHelloDone is a TLS 1.2 ServerHelloDone message.
+type DecodedServerHelloDone struct {
+	Raw []byte
+}
+
+// DecodedClientKeyExchange is a TLS 1.2 ClientKeyExchange message. Exactly
+// one of PublicKey (ECDHE) and EncryptedPreMasterSecret (RSA) is set.
+type DecodedClientKeyExchange struct {
+	Raw                      []byte
+	PublicKey                []byte
+	EncryptedPreMasterSecret []byte
+}
+
+// DecodedServerFlight is the typed, per-message breakdown of the handshake
+// messages a server sends in reply to a ClientHello. Messages the server did
+// not send are nil.
+type DecodedServerFlight struct {
+	// Version is the protocol version selected by the ServerHello.
+	Version uint16
+
+	HelloRetryRequest   *DecodedServerHello
+	ServerHello         *DecodedServerHello
+	EncryptedExtensions *DecodedEncryptedExtensions
+	CertificateRequest  *DecodedCertificateRequest
+	Certificate         *DecodedCertificate
+	CertificateVerify   *DecodedCertificateVerify
+	Finished            *DecodedFinished
+
+	// TLS 1.2 only.
+	ServerKeyExchange *DecodedServerKeyExchange
+	ServerHelloDone   *DecodedServerHelloDone
+}
+
+// DecodeServerFlight decodes the server's handshake messages found in msgs,
+// typically the result of HandshakeRecorder.Messages, up to and including
+// the server Finished. Messages sent by the local side are ignored.
+func DecodeServerFlight(msgs []RecordedMessage) (*DecodedServerFlight, error) {
+	flight := &DecodedServerFlight{}
+	for _, m := range msgs {
+		if m.Direction != RecordReceived {
+			continue
+		}
+
+		var err error
+		switch m.Type {
+		case typeServerHello:
+			var hello *DecodedServerHello
+			if hello, err = DecodeServerHello(m.Raw); err != nil {
+				break
+			}
+			if hello.IsHelloRetryRequest {
+				flight.HelloRetryRequest = hello
+			} else {
+				flight.ServerHello = hello
+				flight.Version = hello.Hello.Vers
+				if hello.Hello.SupportedVersion != 0 {
+					flight.Version = hello.Hello.SupportedVersion
+				}
+			}
+		case typeEncryptedExtensions:
+			flight.EncryptedExtensions, err = DecodeEncryptedExtensions(m.Raw)
+		case typeCertificateRequest:
+			if flight.Version == VersionTLS13 {
+				flight.CertificateRequest, err = DecodeCertificateRequest(m.Raw)
+			} else {
+				flight.CertificateRequest, err = DecodeCertificateRequestTLS12(m.Raw)
+			}
+		case typeCertificate, utlsTypeCompressedCertificate:
+			if flight.Version == VersionTLS13 {
+				flight.Certificate, err = DecodeCertificate(m.Raw)
+			} else {
+				flight.Certificate, err = DecodeCertificateTLS12(m.Raw)
+			}
+		case typeServerKeyExchange:
+			flight.ServerKeyExchange, err = DecodeServerKeyExchange(m.Raw, flight.Version)
+		case typeServerHelloDone:
+			flight.ServerHelloDone = &DecodedServerHelloDone{Raw: m.Raw}
+		case typeCertificateVerify:
+			flight.CertificateVerify, err = DecodeCertificateVerify(m.Raw)
+		case typeFinished:
+			flight.Finished, err = DecodeFinished(m.Raw)
+		}
+		if err != nil {
+			return flight, err
+		}
+		if flight.Finished != nil {
+			break
+		}
+	}
+	if flight.ServerHello == nil && flight.HelloRetryRequest == nil {
+		return flight, errors.New("tls: no ServerHello recorded")
+	}
+	return flight, nil
+}
+
+// DecodeServerHello decodes a raw ServerHello message, including its 4-byte
+// header.
+func DecodeServerHello(raw []byte) (*DecodedServerHello, error) {
+	m := new(serverHelloMsg)
+	if !m.unmarshal(raw) {
+		return nil, errors.New("tls: failed to parse ServerHello")
+	}
+	s := cryptobyte.String(raw)
+	var random []byte
+	if !s.Skip(4+2) || !s.ReadBytes(&random, 32) || !skipUint8LengthPrefixed(&s) || !s.Skip(2+1) {
+		return nil, errors.New("tls: failed to parse ServerHello")
+	}
+	exts, err := readHandshakeExtensions(&s)
+	if err != nil {
+		return nil, fmt.Errorf("tls: failed to parse ServerHello extensions: %w", err)
+	}
+	return &DecodedServerHello{
+		Raw:                 raw,
+		Hello:               m.getPublicPtr(),
+		IsHelloRetryRequest: string(random) == string(helloRetryRequestRandom),
+		Extensions:          exts,
+	}, nil
+}
+
+// DecodeEncryptedExtensions decodes a raw EncryptedExtensions message.
+func DecodeEncryptedExtensions(raw []byte) (*DecodedEncryptedExtensions, error) {
+	s := cryptobyte.String(raw)
+	if !s.Skip(4) || s.Empty() {
+		return nil, errors.New("tls: failed to parse EncryptedExtensions")
+	}
+	exts, err := readHandshakeExtensions(&s)
+	if err != nil {
+		return nil, fmt.Errorf("tls: failed to parse EncryptedExtensions: %w", err)
+	}
+	return &DecodedEncryptedExtensions{Raw: raw, Extensions: exts}, nil
+}
+
+// DecodeCertificateRequest decodes a raw TLS 1.3 CertificateRequest message.
+func DecodeCertificateRequest(raw []byte) (*DecodedCertificateRequest, error) {
+	m := &DecodedCertificateRequest{Raw: raw}
+	s := cryptobyte.String(raw)
+	if !s.Skip(4) || !readUint8LengthPrefixed(&s, &m.RequestContext) {
+		return nil, errors.New("tls: failed to parse CertificateRequest")
+	}
+	var err error
+	if m.Extensions, err = readHandshakeExtensions(&s); err != nil {
+		return nil, fmt.Errorf("tls: failed to parse CertificateRequest extensions: %w", err)
+	}
+	return m, nil
+}
+
+// DecodeCertificate decodes a raw TLS 1.3 Certificate message. A
+// CompressedCertificate message (RFC 8879) is decompressed first.
+func DecodeCertificate(raw []byte) (*DecodedCertificate, error) {
+	m := &DecodedCertificate{Raw: raw}
+	if len(raw) > 0 && raw[0] == utlsTypeCompressedCertificate {
+		compressed := new(utlsCompressedCertificateMsg)
+		if !compressed.unmarshal(raw) {
+			return nil, errors.New("tls: failed to parse CompressedCertificate")
+		}
+		var err error
+		if raw, err = decompressCertificateMsg(compressed); err != nil {
+			return nil, fmt.Errorf("tls: failed to decompress certificate message: %w", err)
+		}
+		m.CompressionAlgorithm = CertCompressionAlgo(compressed.algorithm)
+	}
+
+	s := cryptobyte.String(raw)
+	var entries cryptobyte.String
+	if !s.Skip(4) || !readUint8LengthPrefixed(&s, &m.RequestContext) ||
+		!s.ReadUint24LengthPrefixed(&entries) || !s.Empty() {
+		return nil, errors.New("tls: failed to parse Certificate")
+	}
+	for !entries.Empty() {
+		var entry DecodedCertificateEntry
+		var extensions cryptobyte.String
+		if !readUint24LengthPrefixed(&entries, &entry.Data) ||
+			!entries.ReadUint16LengthPrefixed(&extensions) {
+			return nil, errors.New("tls: failed to parse Certificate entry")
+		}
+		for !extensions.Empty() {
+			var ext HandshakeExtension
+			if !extensions.ReadUint16(&ext.Type) || !readUint16LengthPrefixed(&extensions, &ext.Data) {
+				return nil, errors.New("tls: failed to parse Certificate entry extensions")
+			}
+			entry.Extensions = append(entry.Extensions, ext)
+		}
+		entry.Certificate, entry.ParseError = x509.ParseCertificate(entry.Data)
+		m.Entries = append(m.Entries, entry)
+	}
+	return m, nil
+}
+
+// DecodeCertificateRequestTLS12 decodes a raw TLS 1.2 CertificateRequest
+// message.
+func DecodeCertificateRequestTLS12(raw []byte) (*DecodedCertificateRequest, error) {
+	m := &DecodedCertificateRequest{Raw: raw}
+	s := cryptobyte.String(raw)
+	var sigAndHashes, authorities cryptobyte.String
+	if !s.Skip(4) || !readUint8LengthPrefixed(&s, &m.CertificateTypes) ||
+		!s.ReadUint16LengthPrefixed(&sigAndHashes) ||
+		!s.ReadUint16LengthPrefixed(&authorities) || !s.Empty() {
+		return nil, errors.New("tls: failed to parse CertificateRequest")
+	}
+	for !sigAndHashes.Empty() {
+		var scheme uint16
+		if !sigAndHashes.ReadUint16(&scheme) {
+			return nil, errors.New("tls: failed to parse CertificateRequest signature algorithms")
+		}
+		m.SignatureSchemes = append(m.SignatureSchemes, SignatureScheme(scheme))
+	}
+	for !authorities.Empty() {
+		var ca []byte
+		if !readUint16LengthPrefixed(&authorities, &ca) {
+			return nil, errors.New("tls: failed to parse CertificateRequest authorities")
+		}
+		m.CertificateAuthorities = append(m.CertificateAuthorities, ca)
+	}
+	return m, nil
+}
+
+// DecodeCertificateTLS12 decodes a raw TLS 1.2 Certificate message.
+func DecodeCertificateTLS12(raw []byte) (*DecodedCertificate, error) {
+	m := &DecodedCertificate{Raw: raw}
+	s := cryptobyte.String(raw)
+	var certs cryptobyte.String
+	if !s.Skip(4) || !s.ReadUint24LengthPrefixed(&certs) || !s.Empty() {
+		return nil, errors.New("tls: failed to parse Certificate")
+	}
+	for !certs.Empty() {
+		var entry DecodedCertificateEntry
+		if !readUint24LengthPrefixed(&certs, &entry.Data) {
+			return nil, errors.New("tls: failed to parse Certificate entry")
+		}
+		entry.Certificate, entry.ParseError = x509.ParseCertificate(entry.Data)
+		m.Entries = append(m.Entries, entry)
+	}
+	return m, nil
+}
+
+// DecodeServerKeyExchange decodes a raw ServerKeyExchange message of an
+// ECDHE cipher suite. vers is the negotiated protocol version, which decides
+// whether the signature is preceded by its algorithm.
+func DecodeServerKeyExchange(raw []byte, vers uint16) (*DecodedServerKeyExchange, error) {
+	m := &DecodedServerKeyExchange{Raw: raw}
+	s := cryptobyte.String(raw)
+	if !s.Skip(4) || !s.ReadUint8(&m.CurveType) {
+		return nil, errors.New("tls: failed to parse ServerKeyExchange")
+	}
+	if m.CurveType != 3 { // named_curve
+		return m, nil
+	}
+	var curve uint16
+	if !s.ReadUint16(&curve) || !readUint8LengthPrefixed(&s, &m.PublicKey) {
+		return nil, errors.New("tls: failed to parse ServerKeyExchange parameters")
+	}
+	m.NamedCurve = CurveID(curve)
+	if vers >= VersionTLS12 {
+		var scheme uint16
+		if !s.ReadUint16(&scheme) {
+			return nil, errors.New("tls: failed to parse ServerKeyExchange signature")
+		}
+		m.SignatureScheme = SignatureScheme(scheme)
+	}
+	if !readUint16LengthPrefixed(&s, &m.Signature) || !s.Empty() {
+		return nil, errors.New("tls: failed to parse ServerKeyExchange signature")
+	}
+	return m, nil
+}
+
+// DecodeClientKeyExchange decodes a raw ClientKeyExchange message sent for
+// the given TLS 1.2 cipher suite.
+func DecodeClientKeyExchange(raw []byte, cipherSuite uint16) (*DecodedClientKeyExchange, error) {
+	suite := cipherSuiteByID(cipherSuite)
+	if suite == nil {
+		return nil, fmt.Errorf("tls: unknown TLS 1.2 cipher suite %#04x", cipherSuite)
+	}
+	m := &DecodedClientKeyExchange{Raw: raw}
+	s := cryptobyte.String(raw)
+	if !s.Skip(4) {
+		return nil, errors.New("tls: failed to parse ClientKeyExchange")
+	}
+	var ok bool
+	if suite.flags&suiteECDHE != 0 {
+		ok = readUint8LengthPrefixed(&s, &m.PublicKey)
+	} else {
+		ok = readUint16LengthPrefixed(&s, &m.EncryptedPreMasterSecret)
+	}
+	if !ok || !s.Empty() {
+		return nil, errors.New("tls: failed to parse ClientKeyExchange")
+	}
+	return m, nil
+}
+
+// DecodeCertificateVerify decodes a raw TLS 1.2 or TLS 1.3
+// CertificateVerify message that carries a signature algorithm.
+func DecodeCertificateVerify(raw []byte) (*DecodedCertificateVerify, error) {
+	m := &certificateVerifyMsg{hasSignatureAlgorithm: true}
+	if !m.unmarshal(raw) {
+		return nil, errors.New("tls: failed to parse CertificateVerify")
+	}
+	return &DecodedCertificateVerify{
+		Raw:             raw,
+		SignatureScheme: m.signatureAlgorithm,
+		Signature:       m.signature,
+	}, nil
+}
+
+// DecodeFinished decodes a raw Finished message.
+func DecodeFinished(raw []byte) (*DecodedFinished, error) {
+	m := new(finishedMsg)
+	if !m.unmarshal(raw) {
+		return nil, errors.New("tls: failed to parse Finished")
+	}
+	return &DecodedFinished{Raw: raw, VerifyData: m.verifyData}, nil
+}
+
+// DecodeNewSessionTicket decodes a raw NewSessionTicket message. vers is
+// the negotiated protocol version, which decides the message format.
+func DecodeNewSessionTicket(raw []byte, vers uint16) (*DecodedNewSessionTicket, error) {
+	m := &DecodedNewSessionTicket{Raw: raw}
+	s := cryptobyte.String(raw)
+	if !s.Skip(4) || !s.ReadUint32(&m.Lifetime) {
+		return nil, errors.New("tls: failed to parse NewSessionTicket")
+	}
+	if vers != VersionTLS13 {
+		if !readUint16LengthPrefixed(&s, &m.Ticket) || !s.Empty() {
+			return nil, errors.New("tls: failed to parse NewSessionTicket")
+		}
+		return m, nil
+	}
+
+	if !s.ReadUint32(&m.AgeAdd) || !readUint8LengthPrefixed(&s, &m.Nonce) ||
+		!readUint16LengthPrefixed(&s, &m.Ticket) {
+		return nil, errors.New("tls: failed to parse NewSessionTicket")
+	}
+	var err error
+	if m.Extensions, err = readHandshakeExtensions(&s); err != nil {
+		return nil, fmt.Errorf("tls: failed to parse NewSessionTicket: %w", err)
+	}
+	for _, ext := range m.Extensions {
+		if ext.Type != extensionEarlyData {
+			continue
+		}
+		data := cryptobyte.String(ext.Data)
+		if !data.ReadUint32(&m.MaxEarlyData) || !data.Empty() {
+			return nil, errors.New("tls: failed to parse NewSessionTicket early_data extension")
+		}
+	}
+	return m, nil
+}
+
+// DecodeServerFlight decodes the server's handshake messages recorded so far.
+func (r *HandshakeRecorder) DecodeServerFlight() (*DecodedServerFlight, error) {
+	return DecodeServerFlight(r.Messages())
+}
+
+// readHandshakeExtensions reads an optional uint16-prefixed extension list
+// that must be the last field of s.
+func readHandshakeExtensions(s *cryptobyte.String) ([]HandshakeExtension, error) {
+	if s.Empty() {
+		return nil, nil
+	}
+	var extensions cryptobyte.String
+	if !s.ReadUint16LengthPrefixed(&extensions) || !s.Empty() {
+		return nil, errors.New("malformed extension list")
+	}
+	exts := []HandshakeExtension{}
+	for !extensions.Empty() {
+		var ext HandshakeExtension
+		if !extensions.ReadUint16(&ext.Type) || !readUint16LengthPrefixed(&extensions, &ext.Data) {
+			return nil, errors.New("malformed extension")
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
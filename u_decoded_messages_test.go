@@ -0,0 +1,123 @@
+package tls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUTLSDecodeServerFlight(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.MinVersion = VersionTLS13
+	clientConfig.MaxVersion = VersionTLS13
+	serverConfig := testConfig.Clone()
+
+	recorder := recordHandshake(t, clientConfig, serverConfig, HelloGolang)
+	flight, err := recorder.DecodeServerFlight()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flight.HelloRetryRequest != nil {
+		t.Errorf("unexpected HelloRetryRequest")
+	}
+	if flight.ServerHello == nil || flight.ServerHello.Hello.SupportedVersion != VersionTLS13 {
+		t.Fatalf("ServerHello did not select TLS 1.3")
+	}
+	var sawSupportedVersions bool
+	for _, ext := range flight.ServerHello.Extensions {
+		if ext.Type == extensionSupportedVersions {
+			sawSupportedVersions = true
+			if !bytes.Equal(ext.Data, []byte{0x03, 0x04}) {
+				t.Errorf("supported_versions = %x, want 0304", ext.Data)
+			}
+		}
+	}
+	if !sawSupportedVersions {
+		t.Errorf("ServerHello extensions lack supported_versions")
+	}
+
+	if flight.EncryptedExtensions == nil {
+		t.Errorf("missing EncryptedExtensions")
+	}
+	if flight.CertificateRequest != nil {
+		t.Errorf("unexpected CertificateRequest")
+	}
+	if flight.Certificate == nil || len(flight.Certificate.Entries) != len(serverConfig.Certificates[0].Certificate) {
+		t.Fatalf("Certificate does not carry the server chain")
+	}
+	for i, entry := range flight.Certificate.Entries {
+		if !bytes.Equal(entry.Data, serverConfig.Certificates[0].Certificate[i]) {
+			t.Errorf("certificate entry %d does not match the server certificate", i)
+		}
+		if entry.Certificate == nil {
+			t.Errorf("certificate entry %d was not parsed: %v", i, entry.ParseError)
+		}
+	}
+	if flight.CertificateVerify == nil || len(flight.CertificateVerify.Signature) == 0 {
+		t.Errorf("missing CertificateVerify signature")
+	}
+	if flight.Finished == nil || len(flight.Finished.VerifyData) != 32 {
+		t.Errorf("missing or malformed server Finished")
+	}
+}
+
+func TestUTLSDecodeServerFlightMalformed(t *testing.T) {
+	_, err := DecodeServerFlight([]RecordedMessage{
+		{Direction: RecordReceived, Type: typeServerHello, Raw: []byte{typeServerHello, 0, 0, 2, 3, 3}},
+	})
+	if err == nil {
+		t.Errorf("expected an error for a truncated ServerHello")
+	}
+	if _, err := DecodeServerFlight(nil); err == nil {
+		t.Errorf("expected an error when no ServerHello was recorded")
+	}
+}
+
+func TestUTLSDecodeServerFlightTLS12(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.MinVersion = VersionTLS12
+	clientConfig.MaxVersion = VersionTLS12
+	clientConfig.CipherSuites = []uint16{TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+	serverConfig := testConfig.Clone()
+	serverConfig.ClientAuth = RequestClientCert
+
+	recorder := recordHandshake(t, clientConfig, serverConfig, HelloGolang)
+	flight, err := recorder.DecodeServerFlight()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flight.Version != VersionTLS12 {
+		t.Fatalf("Version = %#04x, want TLS 1.2", flight.Version)
+	}
+	if flight.EncryptedExtensions != nil || flight.CertificateVerify != nil {
+		t.Errorf("unexpected TLS 1.3 messages in a TLS 1.2 flight")
+	}
+	if flight.Certificate == nil || len(flight.Certificate.Entries) != len(serverConfig.Certificates[0].Certificate) {
+		t.Fatalf("Certificate does not carry the server chain")
+	}
+	if !bytes.Equal(flight.Certificate.Entries[0].Data, serverConfig.Certificates[0].Certificate[0]) {
+		t.Errorf("certificate entry does not match the server certificate")
+	}
+	skx := flight.ServerKeyExchange
+	if skx == nil || skx.CurveType != 3 || len(skx.PublicKey) == 0 || skx.SignatureScheme == 0 || len(skx.Signature) == 0 {
+		t.Fatalf("malformed ServerKeyExchange: %+v", skx)
+	}
+	if flight.CertificateRequest == nil || len(flight.CertificateRequest.SignatureSchemes) == 0 {
+		t.Errorf("missing TLS 1.2 CertificateRequest")
+	}
+	if flight.ServerHelloDone == nil {
+		t.Errorf("missing ServerHelloDone")
+	}
+	if flight.Finished == nil || len(flight.Finished.VerifyData) != 12 {
+		t.Errorf("missing or malformed server Finished")
+	}
+
+	ckx, err := DecodeClientKeyExchange(recorder.Message(RecordSent, typeClientKeyExchange), flight.ServerHello.Hello.CipherSuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skx.NamedCurve == X25519 && len(ckx.PublicKey) != 32 {
+		t.Errorf("ClientKeyExchange public key length = %d, want 32", len(ckx.PublicKey))
+	}
+}
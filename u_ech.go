@@ -2,6 +2,7 @@ package tls
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -261,6 +262,38 @@ func (g *GREASEEncryptedClientHelloExtension) Write(b []byte) (int, error) {
 	return fullLen, nil
 }
 
+// UnmarshalJSON implements TLSExtensionJSON. Only the candidates are read,
+// the config_id, encapsulated key and payload are generated for each
+// ClientHello.
+func (g *GREASEEncryptedClientHelloExtension) UnmarshalJSON(b []byte) error {
+	var greaseECH struct {
+		CandidateCipherSuites []struct {
+			KdfId  string `json:"kdf_id"`
+			AeadId string `json:"aead_id"`
+		} `json:"candidate_cipher_suites"`
+		CandidateConfigIds   []uint8  `json:"candidate_config_ids"`
+		CandidatePayloadLens []uint16 `json:"candidate_payload_lens"`
+	}
+	if err := json.Unmarshal(b, &greaseECH); err != nil {
+		return err
+	}
+
+	for _, suite := range greaseECH.CandidateCipherSuites {
+		kdfId, ok := dicttls.DictKDFIdentifierNameIndexed[suite.KdfId]
+		if !ok {
+			return fmt.Errorf("unknown HPKE KDF %s", suite.KdfId)
+		}
+		aeadId, ok := dicttls.DictAEADIdentifierNameIndexed[suite.AeadId]
+		if !ok {
+			return fmt.Errorf("unknown HPKE AEAD %s", suite.AeadId)
+		}
+		g.CandidateCipherSuites = append(g.CandidateCipherSuites, HPKESymmetricCipherSuite{KdfId: kdfId, AeadId: aeadId})
+	}
+	g.CandidateConfigIds = greaseECH.CandidateConfigIds
+	g.CandidatePayloadLens = greaseECH.CandidatePayloadLens
+	return nil
+}
+
 // UnimplementedECHExtension is a placeholder for an ECH extension that is not implemented.
 // All implementations of EncryptedClientHelloExtension should embed this struct to ensure
 // forward compatibility.
@@ -0,0 +1,433 @@
+package tls
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// fingerprintCapture is a ClientHello captured from a real browser in
+// testdata/fingerprints, together with the ClientHelloID that parrots it.
+type fingerprintCapture struct {
+	// Client and Version name the ClientHelloID.
+	Client  string `json:"client"`
+	Version string `json:"version"`
+	// Source describes where and how the ClientHello was captured.
+	Source string `json:"source"`
+	// ShuffledExtensions is set if the browser shuffles the extensions, so
+	// only the positions of GREASE and pre_shared_key are compared.
+	ShuffledExtensions bool `json:"shuffled_extensions"`
+	// ClientHello is the hex encoded TLS record of the ClientHello.
+	ClientHello string `json:"client_hello"`
+	// KnownDrift lists the differences reported by clientHelloDrift that
+	// are known and accepted. The test fails on any other difference, and
+	// also when a known difference is gone, so the list stays current.
+	KnownDrift []string `json:"known_drift"`
+}
+
+// driftHello is a ClientHello reduced to what clientHelloDrift compares.
+type driftHello struct {
+	vers         uint16
+	sessionIDLen int
+	cipherSuites []uint16
+	compression  []byte
+	extensions   []uint16
+	data         map[uint16][]byte
+	serverName   string
+	// unpaddedLen is the length of the handshake message without the
+	// padding extension, as passed to UtlsPaddingExtension.GetPaddingLen
+	unpaddedLen int
+	paddingLen  int
+	hasPadding  bool
+}
+
+func parseDriftHello(record []byte) (*driftHello, error) {
+	s := cryptobyte.String(record)
+	var recordBody, body cryptobyte.String
+	var msgType uint8
+	if !s.Skip(3) || !s.ReadUint16LengthPrefixed(&recordBody) {
+		return nil, fmt.Errorf("not a TLS record")
+	}
+	if !recordBody.ReadUint8(&msgType) || msgType != typeClientHello || !recordBody.ReadUint24LengthPrefixed(&body) {
+		return nil, fmt.Errorf("not a ClientHello")
+	}
+	h := &driftHello{data: map[uint16][]byte{}, unpaddedLen: 4 + len(body)}
+
+	var random, sessionID, suites, compression, exts cryptobyte.String
+	if !body.ReadUint16(&h.vers) || !body.ReadBytes((*[]byte)(&random), 32) ||
+		!body.ReadUint8LengthPrefixed(&sessionID) || !body.ReadUint16LengthPrefixed(&suites) ||
+		!body.ReadUint8LengthPrefixed(&compression) || !body.ReadUint16LengthPrefixed(&exts) {
+		return nil, fmt.Errorf("malformed ClientHello")
+	}
+	h.sessionIDLen = len(sessionID)
+	h.compression = compression
+	for !suites.Empty() {
+		var suite uint16
+		if !suites.ReadUint16(&suite) {
+			return nil, fmt.Errorf("malformed cipher suites")
+		}
+		h.cipherSuites = append(h.cipherSuites, suite)
+	}
+	for !exts.Empty() {
+		var id uint16
+		var data cryptobyte.String
+		if !exts.ReadUint16(&id) || !exts.ReadUint16LengthPrefixed(&data) {
+			return nil, fmt.Errorf("malformed extensions")
+		}
+		if isGREASEUint16(id) {
+			id = GREASE_PLACEHOLDER
+		}
+		h.extensions = append(h.extensions, id)
+		if id == utlsExtensionPadding {
+			h.hasPadding = true
+			h.paddingLen = len(data)
+			h.unpaddedLen -= 4 + len(data)
+		}
+		if id == extensionServerName {
+			var list, name cryptobyte.String
+			var nameType uint8
+			if data.ReadUint16LengthPrefixed(&list) && list.ReadUint8(&nameType) && list.ReadUint16LengthPrefixed(&name) {
+				h.serverName = string(name)
+			}
+		}
+		if _, ok := h.data[id]; !ok || id != GREASE_PLACEHOLDER {
+			h.data[id] = normalizeDriftExtension(id, data)
+		}
+	}
+	return h, nil
+}
+
+// normalizeDriftExtension replaces the GREASE values in the extension data
+// with GREASE_PLACEHOLDER and the values that change with every connection
+// with their lengths.
+func normalizeDriftExtension(id uint16, data cryptobyte.String) []byte {
+	b := cryptobyte.NewBuilder(nil)
+	switch id {
+	case extensionSupportedCurves, extensionSupportedVersions:
+		// lists of uint16 values with a length prefix
+		b.AddBytes(normalizeGREASE(data))
+		return b.BytesOrPanic()
+	case extensionKeyShare:
+		var shares cryptobyte.String
+		if !data.ReadUint16LengthPrefixed(&shares) {
+			break
+		}
+		for !shares.Empty() {
+			var group uint16
+			var key cryptobyte.String
+			if !shares.ReadUint16(&group) || !shares.ReadUint16LengthPrefixed(&key) {
+				return []byte("malformed")
+			}
+			if isGREASEUint16(group) {
+				group = GREASE_PLACEHOLDER
+			}
+			b.AddUint16(group)
+			b.AddUint16(uint16(len(key)))
+		}
+		return b.BytesOrPanic()
+	case extensionServerName, utlsExtensionPadding, extensionSessionTicket, extensionPreSharedKey, extensionEarlyData:
+		// the server name and the session are compared separately, and
+		// padding by clientHelloDrift's padding rule
+		return nil
+	case extensionEncryptedClientHello:
+		// GREASE ECH picks a random config ID, AEAD and payload length
+		var typ uint8
+		var kdf, aead uint16
+		var configID uint8
+		var enc cryptobyte.String
+		if !data.ReadUint8(&typ) || !data.ReadUint16(&kdf) || !data.ReadUint16(&aead) || !data.ReadUint8(&configID) ||
+			!data.ReadUint16LengthPrefixed(&enc) {
+			break
+		}
+		b.AddUint8(typ)
+		b.AddUint16(kdf)
+		b.AddUint16(uint16(len(enc)))
+		return b.BytesOrPanic()
+	}
+	return data
+}
+
+// normalizeGREASE replaces GREASE values in a list of uint16 values, skipping
+// a length prefix of 1 or 2 bytes.
+func normalizeGREASE(data []byte) []byte {
+	out := slices.Clone(data)
+	start := len(out) % 2
+	for i := start; i+1 < len(out); i += 2 {
+		if v := uint16(out[i])<<8 | uint16(out[i+1]); isGREASEUint16(v) {
+			out[i], out[i+1] = GREASE_PLACEHOLDER>>8, GREASE_PLACEHOLDER&0xff
+		}
+	}
+	return out
+}
+
+// clientHelloDrift returns the structural differences between a captured
+// ClientHello and the one generated by a preset. Extension order is compared
+// modulo shuffling if shuffled is set, values modulo GREASE and randomness,
+// and padding by the rule each ClientHello follows at its own length. pre_shared_key and early_data depend on a resumed session and
+// are only compared if the generated ClientHello has them.
+func clientHelloDrift(capture, preset *driftHello, shuffled bool) []string {
+	var drift []string
+	add := func(format string, args ...any) {
+		drift = append(drift, fmt.Sprintf(format, args...))
+	}
+	hexList := func(values []uint16) string {
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = fmt.Sprintf("%04x", v)
+		}
+		return strings.Join(list, ",")
+	}
+
+	if capture.vers != preset.vers {
+		add("legacy_version: capture %04x, preset %04x", capture.vers, preset.vers)
+	}
+	if capture.sessionIDLen != preset.sessionIDLen {
+		add("session_id length: capture %d, preset %d", capture.sessionIDLen, preset.sessionIDLen)
+	}
+	if c, p := hexList(normalizeUint16s(capture.cipherSuites)), hexList(normalizeUint16s(preset.cipherSuites)); c != p {
+		add("cipher_suites: capture %s, preset %s", c, p)
+	}
+	if !slices.Equal(capture.compression, preset.compression) {
+		add("compression_methods: capture %x, preset %x", capture.compression, preset.compression)
+	}
+
+	// extensions that depend on the session or on the length of the hello
+	resumption := []uint16{extensionPreSharedKey, extensionEarlyData}
+	compared := func(h *driftHello) []uint16 {
+		return slices.DeleteFunc(slices.Clone(h.extensions), func(id uint16) bool {
+			return id == utlsExtensionPadding || slices.Contains(resumption, id) && !slices.Contains(preset.extensions, id)
+		})
+	}
+	captureExts, presetExts := compared(capture), compared(preset)
+	for _, id := range captureExts {
+		if !slices.Contains(presetExts, id) {
+			add("extension %04x: missing from preset", id)
+		}
+	}
+	for _, id := range presetExts {
+		if !slices.Contains(captureExts, id) {
+			add("extension %04x: not in capture", id)
+		}
+	}
+	if shuffled {
+		// shuffling keeps GREASE and pre_shared_key in place
+		for i, id := range captureExts {
+			pinned := id == GREASE_PLACEHOLDER || id == extensionPreSharedKey
+			if pinned && (i >= len(presetExts) || presetExts[i] != id) {
+				add("extension %04x: at position %d in capture, not in preset", id, i)
+			}
+		}
+	} else if slices.Equal(sortedCopy(captureExts), sortedCopy(presetExts)) && !slices.Equal(captureExts, presetExts) {
+		add("extension order: capture %s, preset %s", hexList(captureExts), hexList(presetExts))
+	}
+	for _, id := range captureExts {
+		if p, ok := preset.data[id]; ok && !slices.Equal(capture.data[id], p) {
+			add("extension %04x data: capture %x, preset %x", id, capture.data[id], p)
+		}
+	}
+
+	// the padding rule can only be told apart at lengths that BoringSSL pads
+	if c, p := paddingStyle(capture), paddingStyle(preset); c != "" && p != "" && c != p {
+		add("padding: capture %s, preset %s", c, p)
+	}
+	return drift
+}
+
+// paddingStyle describes the padding of h, or returns "" if h has no padding
+// and BoringPaddingStyle would not pad it either.
+func paddingStyle(h *driftHello) string {
+	length, pad := BoringPaddingStyle(h.unpaddedLen)
+	switch {
+	case h.hasPadding && pad && length == h.paddingLen:
+		return "BoringPaddingStyle"
+	case h.hasPadding:
+		return fmt.Sprintf("%d bytes of padding at unpadded length %d", h.paddingLen, h.unpaddedLen)
+	case pad:
+		return fmt.Sprintf("no padding at unpadded length %d", h.unpaddedLen)
+	}
+	return ""
+}
+
+func normalizeUint16s(values []uint16) []uint16 {
+	out := slices.Clone(values)
+	for i, v := range out {
+		if isGREASEUint16(v) {
+			out[i] = GREASE_PLACEHOLDER
+		}
+	}
+	return out
+}
+
+func sortedCopy(values []uint16) []uint16 {
+	out := slices.Clone(values)
+	slices.Sort(out)
+	return out
+}
+
+// driftPresets are the presets that must have a capture in
+// testdata/fingerprints. A preset is added here together with its capture.
+var driftPresets = []ClientHelloID{}
+
+// TestFingerprintDrift checks the presets against the browser captures in
+// testdata/fingerprints, and that each of driftPresets has one.
+func TestFingerprintDrift(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "fingerprints", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	captures := map[string]fingerprintCapture{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c fingerprintCapture
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		captures[file] = c
+	}
+
+	for _, id := range driftPresets {
+		t.Run("capture of "+id.Str(), func(t *testing.T) {
+			for _, c := range captures {
+				if c.Client == id.Client && c.Version == id.Version {
+					return
+				}
+			}
+			t.Errorf("no capture of %s in testdata/fingerprints", id.Str())
+		})
+	}
+	if len(files) == 0 {
+		t.Skip("no captures in testdata/fingerprints")
+	}
+
+	for _, file := range files {
+		c := captures[file]
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			if c.Source == "" {
+				t.Errorf("%s does not say which browser produced it", file)
+			}
+			record, err := hex.DecodeString(c.ClientHello)
+			if err != nil {
+				t.Fatal(err)
+			}
+			capture, err := parseDriftHello(record)
+			if err != nil {
+				t.Fatalf("capture: %v", err)
+			}
+
+			// the capture's server name makes both ClientHellos the same
+			// length if nothing else differs
+			id := ClientHelloID{Client: c.Client, Version: c.Version}
+			// there is no session to resume, see clientHelloDrift
+			config := &Config{ServerName: capture.serverName, OmitEmptyPsk: true}
+			uconn := UClient(&net.TCPConn{}, config, id)
+			if err := uconn.BuildHandshakeState(); err != nil {
+				t.Fatalf("%s: %v", id.Str(), err)
+			}
+			preset, err := parseDriftHello(prependRecordHeader(uconn.HandshakeState.Hello.Raw, VersionTLS10))
+			if err != nil {
+				t.Fatalf("preset: %v", err)
+			}
+
+			drift := clientHelloDrift(capture, preset, c.ShuffledExtensions)
+			for _, d := range drift {
+				if !slices.Contains(c.KnownDrift, d) {
+					t.Errorf("%s drifted from the capture: %s", id.Str(), d)
+				}
+			}
+			for _, d := range c.KnownDrift {
+				if !slices.Contains(drift, d) {
+					t.Errorf("known drift of %s is gone, remove it from %s: %s", id.Str(), file, d)
+				}
+			}
+		})
+	}
+}
+
+// TestClientHelloDriftSamePreset checks that two ClientHellos of the same
+// preset differ only in values that clientHelloDrift ignores.
+func TestClientHelloDriftSamePreset(t *testing.T) {
+	tests := []struct {
+		id       ClientHelloID
+		shuffled bool
+	}{
+		{HelloChrome_100, false},
+		{HelloChrome_120, true},
+		{HelloChrome_133, true},
+		{HelloFirefox_120, false},
+		{HelloSafari_16_0, false},
+		{HelloIOS_14, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id.Str(), func(t *testing.T) {
+			var hellos []*driftHello
+			for i := 0; i < 2; i++ {
+				uconn := UClient(&net.TCPConn{}, &Config{ServerName: "example.com"}, tt.id)
+				if err := uconn.BuildHandshakeState(); err != nil {
+					t.Fatal(err)
+				}
+				h, err := parseDriftHello(prependRecordHeader(uconn.HandshakeState.Hello.Raw, VersionTLS10))
+				if err != nil {
+					t.Fatal(err)
+				}
+				hellos = append(hellos, h)
+			}
+			if drift := clientHelloDrift(hellos[0], hellos[1], tt.shuffled); len(drift) > 0 {
+				t.Errorf("drift between two ClientHellos of the same preset: %q", drift)
+			}
+		})
+	}
+}
+
+func TestClientHelloDrift(t *testing.T) {
+	build := func(id ClientHelloID, serverName string) *driftHello {
+		uconn := UClient(&net.TCPConn{}, &Config{ServerName: serverName}, id)
+		if err := uconn.BuildHandshakeState(); err != nil {
+			t.Fatal(err)
+		}
+		h, err := parseDriftHello(prependRecordHeader(uconn.HandshakeState.Hello.Raw, VersionTLS10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	// Chrome 83 predates ALPS
+	drift := clientHelloDrift(build(HelloChrome_83, "example.com"), build(HelloChrome_100, "example.com"), false)
+	if !slices.Contains(drift, "extension 4469: not in capture") {
+		t.Errorf("ALPS was not reported: %q", drift)
+	}
+
+	// the same extensions in another order
+	capture, preset := build(HelloChrome_100, "example.com"), build(HelloChrome_100, "example.com")
+	preset.extensions[1], preset.extensions[2] = preset.extensions[2], preset.extensions[1]
+	drift = clientHelloDrift(capture, preset, false)
+	if len(drift) != 1 || !strings.HasPrefix(drift[0], "extension order:") {
+		t.Errorf("drift = %q, want the extension order", drift)
+	}
+	if drift := clientHelloDrift(capture, preset, true); len(drift) != 0 {
+		t.Errorf("drift of shuffled extensions = %q, want none", drift)
+	}
+
+	// a preset that stopped padding
+	capture, preset = build(HelloChrome_100, "a.example"), build(HelloChrome_100, "a.example")
+	if !capture.hasPadding {
+		t.Fatal("ClientHello is not padded")
+	}
+	preset.hasPadding = false
+	preset.extensions = slices.DeleteFunc(preset.extensions, func(id uint16) bool { return id == utlsExtensionPadding })
+	drift = clientHelloDrift(capture, preset, false)
+	if len(drift) != 1 || !strings.HasPrefix(drift[0], "padding: capture BoringPaddingStyle, preset no padding") {
+		t.Errorf("drift = %q, want the padding", drift)
+	}
+}
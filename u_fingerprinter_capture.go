@@ -0,0 +1,341 @@
+package tls
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// maxCapturedClientHelloLen bounds the data buffered for a connection while
+// looking for its ClientHello.
+const maxCapturedClientHelloLen = 1 << 16
+
+// CapturedClientHello is a ClientHello found in a packet capture.
+type CapturedClientHello struct {
+	// Time is the timestamp of the packet that completed the ClientHello.
+	Time time.Time
+
+	// Client and Server are the addresses the ClientHello was sent from and
+	// to.
+	Client, Server netip.AddrPort
+
+	// QUICVersion is the version of the QUIC Initial packets that carried the
+	// ClientHello, or 0 if it was sent over TCP.
+	QUICVersion uint32
+
+	// Raw is the ClientHello as a single TLS record, as passed to
+	// Fingerprinter.RawClientHello. A ClientHello fragmented over several
+	// records, or carried in QUIC CRYPTO frames, is put into one record.
+	Raw []byte
+
+	// Spec is the ClientHelloSpec fingerprinted from Raw, or nil if Err is set.
+	Spec *ClientHelloSpec
+	Err  error
+}
+
+// CapturedClientHellos reads a pcap or pcapng capture and fingerprints the
+// ClientHello of each connection in it, in the order their last packet was
+// captured.
+//
+// TCP streams are reassembled from their SYN, or from the first segment that
+// starts a ClientHello if the capture starts after the SYN. The Initial
+// packets of QUIC versions 1, 2 and draft-29 are decrypted with the keys
+// derived from the Destination Connection ID of the connection, and the
+// CRYPTO frames are reassembled. IP fragments are not reassembled.
+//
+// If the capture is malformed or truncated, it returns the ClientHellos found
+// before the error along with the error.
+func (f *Fingerprinter) CapturedClientHellos(capture io.Reader) ([]CapturedClientHello, error) {
+	r, err := newCaptureReader(capture)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &clientHelloCapture{
+		tcp:  map[captureFlow]*tcpClientHelloStream{},
+		quic: map[captureFlow]*quicClientHelloStream{},
+	}
+	for {
+		pkt, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return f.fingerprintCaptured(c.hellos), err
+		}
+		c.packet(pkt)
+	}
+	return f.fingerprintCaptured(c.hellos), nil
+}
+
+func (f *Fingerprinter) fingerprintCaptured(hellos []CapturedClientHello) []CapturedClientHello {
+	for i := range hellos {
+		hellos[i].Spec, hellos[i].Err = f.RawClientHello(hellos[i].Raw)
+	}
+	return hellos
+}
+
+// captureFlow is one direction of a connection.
+type captureFlow struct {
+	src, dst netip.AddrPort
+}
+
+// clientHelloCapture finds the ClientHellos in the packets of a capture.
+type clientHelloCapture struct {
+	tcp    map[captureFlow]*tcpClientHelloStream
+	quic   map[captureFlow]*quicClientHelloStream
+	hellos []CapturedClientHello
+}
+
+func (c *clientHelloCapture) packet(pkt *capturedPacket) {
+	ip, ok := decodeIPPacket(pkt.linkType, pkt.data)
+	if !ok {
+		return
+	}
+	switch ip.protocol {
+	case ipProtocolTCP:
+		seg, ok := decodeTCP(ip.payload)
+		if !ok {
+			return
+		}
+		flow := captureFlow{netip.AddrPortFrom(ip.src, seg.srcPort), netip.AddrPortFrom(ip.dst, seg.dstPort)}
+		s := c.tcp[flow]
+		if s == nil || seg.syn {
+			// a SYN starts a new connection over the same addresses
+			s = &tcpClientHelloStream{}
+			c.tcp[flow] = s
+		}
+		if hello := s.segment(seg); hello != nil {
+			c.hellos = append(c.hellos, CapturedClientHello{
+				Time:   pkt.time,
+				Client: flow.src,
+				Server: flow.dst,
+				Raw:    hello,
+			})
+		}
+	case ipProtocolUDP:
+		dgram, ok := decodeUDP(ip.payload)
+		if !ok {
+			return
+		}
+		flow := captureFlow{netip.AddrPortFrom(ip.src, dgram.srcPort), netip.AddrPortFrom(ip.dst, dgram.dstPort)}
+		for _, p := range parseQUICInitialPackets(dgram.payload) {
+			s := c.quic[flow]
+			if s == nil {
+				s = &quicClientHelloStream{}
+				c.quic[flow] = s
+			}
+			if hello := s.packet(p); hello != nil {
+				c.hellos = append(c.hellos, CapturedClientHello{
+					Time:        pkt.time,
+					Client:      flow.src,
+					Server:      flow.dst,
+					QUICVersion: p.version,
+					Raw:         hello,
+				})
+			}
+		}
+	}
+}
+
+// tcpClientHelloStream reassembles one direction of a TCP connection until it
+// holds a ClientHello, or until it is clear that it does not start with one.
+type tcpClientHelloStream struct {
+	started bool
+	next    uint32 // sequence number of the byte following data
+	data    []byte
+	// pending holds the segments after a gap, sorted by sequence number, and
+	// pendingLen the length of their payloads
+	pending    []tcpSegment
+	pendingLen int
+	done       bool
+}
+
+// segment adds a segment to the stream and returns the ClientHello record once
+// the stream holds it.
+func (s *tcpClientHelloStream) segment(seg tcpSegment) []byte {
+	if s.done {
+		return nil
+	}
+	if seg.syn {
+		// the data of a SYN, as sent with TCP Fast Open, follows the SYN
+		seg.seq++
+		s.started, s.next = true, seg.seq
+	}
+	if len(seg.payload) == 0 {
+		return nil
+	}
+	if !s.started {
+		// the capture started after the SYN, so look for the first record
+		// of a ClientHello
+		if len(seg.payload) < 6 || seg.payload[0] != byte(recordTypeHandshake) || seg.payload[5] != typeClientHello {
+			return nil
+		}
+		s.started, s.next = true, seg.seq
+	}
+
+	// Sequence numbers are compared relative to next, in sequence number
+	// arithmetic so that it works across wraparound. A segment that starts
+	// beyond the longest ClientHello can not be part of it.
+	if int32(seg.seq-s.next) > maxCapturedClientHelloLen {
+		return nil
+	}
+	i, _ := slices.BinarySearchFunc(s.pending, seg.seq, func(p tcpSegment, seq uint32) int {
+		return cmp.Compare(int32(p.seq-s.next), int32(seq-s.next))
+	})
+	s.pending = slices.Insert(s.pending, i, tcpSegment{seq: seg.seq, payload: seg.payload})
+	s.pendingLen += len(seg.payload)
+	for len(s.pending) > 0 {
+		p := s.pending[0]
+		// offset of the next byte in the segment
+		off := int32(s.next - p.seq)
+		if off < 0 { // after a gap
+			break
+		}
+		if int(off) < len(p.payload) {
+			s.data = append(s.data, p.payload[off:]...)
+			s.next += uint32(len(p.payload)) - uint32(off)
+		}
+		s.pending = s.pending[1:]
+		s.pendingLen -= len(p.payload)
+	}
+
+	hello, ok := clientHelloFromRecords(s.data)
+	if hello != nil || !ok || len(s.data) > maxCapturedClientHelloLen || s.pendingLen > maxCapturedClientHelloLen {
+		s.done, s.data, s.pending, s.pendingLen = true, nil, nil, 0
+	}
+	return hello
+}
+
+// clientHelloFromRecords returns the ClientHello at the start of the TLS
+// records in data as a single record. It returns false if data does not start
+// with a ClientHello, and nil and true if data holds only a part of it.
+func clientHelloFromRecords(data []byte) ([]byte, bool) {
+	var msg []byte
+	var version []byte
+	for len(data) >= 5 {
+		if data[0] != byte(recordTypeHandshake) {
+			return nil, false
+		}
+		if version == nil {
+			version = data[1:3]
+		}
+		n := int(data[3])<<8 | int(data[4])
+		if len(data) < 5+n {
+			break
+		}
+		msg = append(msg, data[5:5+n]...)
+		data = data[5+n:]
+
+		if len(msg) > 0 && msg[0] != typeClientHello {
+			return nil, false
+		}
+		if hello := handshakeMessageRecord(msg, version); hello != nil {
+			return hello, true
+		}
+	}
+	if len(data) > 0 && data[0] != byte(recordTypeHandshake) {
+		return nil, false
+	}
+	return nil, true
+}
+
+// handshakeMessageRecord returns the first handshake message in msg in a
+// handshake record of the given version, or nil if msg holds only a part of
+// it.
+func handshakeMessageRecord(msg []byte, version []byte) []byte {
+	if len(msg) < 4 {
+		return nil
+	}
+	n := 4 + (int(msg[1])<<16 | int(msg[2])<<8 | int(msg[3]))
+	if len(msg) < n || n > 0xffff {
+		return nil
+	}
+	record := []byte{byte(recordTypeHandshake), version[0], version[1], byte(n >> 8), byte(n)}
+	return append(record, msg[:n]...)
+}
+
+// quicClientHelloStream reassembles the CRYPTO frames of the Initial packets
+// sent by a QUIC client until they hold a ClientHello.
+type quicClientHelloStream struct {
+	keys   *quicInitialKeys
+	frames []quicCryptoFrame
+	size   int
+	done   bool
+}
+
+// packet adds an Initial packet to the stream and returns the ClientHello
+// record once the stream holds it.
+func (s *quicClientHelloStream) packet(p quicInitialPacket) []byte {
+	payload, err := s.open(p)
+	if err != nil || s.done {
+		return nil
+	}
+
+	frames, err := parseQUICCryptoFrames(payload)
+	if err != nil {
+		return nil
+	}
+	for _, frame := range frames {
+		s.frames = append(s.frames, frame)
+		s.size += len(frame.data)
+	}
+	msg, err := s.cryptoData()
+	if err != nil || s.size > maxCapturedClientHelloLen {
+		s.done, s.frames = true, nil
+		return nil
+	}
+	// QUIC carries no TLS records, so the record version is the one TLS 1.3
+	// clients send in their first record
+	if hello := handshakeMessageRecord(msg, []byte{0x03, 0x01}); hello != nil {
+		s.done, s.frames = true, nil
+		return hello
+	}
+	return nil
+}
+
+// open removes the protection of an Initial packet with the keys of the
+// connection. The keys are derived from the Destination Connection ID of the
+// first Initial packet, so the packets of the server, which use other keys,
+// are rejected. A packet that only the keys derived from its own Destination
+// Connection ID decrypt starts a new connection, as after a Retry.
+func (s *quicClientHelloStream) open(p quicInitialPacket) ([]byte, error) {
+	if s.keys != nil {
+		if payload, err := s.keys.open(p); err == nil {
+			return payload, nil
+		}
+	}
+	keys, err := newQUICInitialKeys(p.version, p.dcid)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := keys.open(p)
+	if err != nil {
+		return nil, err
+	}
+	*s = quicClientHelloStream{keys: keys}
+	return payload, nil
+}
+
+// cryptoData returns the contiguous start of the CRYPTO stream.
+func (s *quicClientHelloStream) cryptoData() ([]byte, error) {
+	slices.SortStableFunc(s.frames, func(a, b quicCryptoFrame) int {
+		return cmp.Compare(a.offset, b.offset)
+	})
+	var data []byte
+	for _, frame := range s.frames {
+		if frame.offset > uint64(len(data)) {
+			break
+		}
+		if end := frame.offset + uint64(len(frame.data)); end > uint64(len(data)) {
+			data = append(data, frame.data[uint64(len(data))-frame.offset:]...)
+		}
+	}
+	if len(data) > 0 && data[0] != typeClientHello {
+		return nil, errors.New("tls: QUIC CRYPTO stream does not start with a ClientHello")
+	}
+	return data, nil
+}
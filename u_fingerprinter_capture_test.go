@@ -0,0 +1,380 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/refraction-networking/utls/internal/quicvarint"
+)
+
+func TestQUICInitialSecrets(t *testing.T) {
+	// RFC 9001, Appendix A.1 and RFC 9369, Appendix A.1
+	dcid, _ := hex.DecodeString("8394c8f03e515708")
+	tests := []struct {
+		name        string
+		version     uint32
+		key, iv, hp string
+	}{
+		{"v1", quicVersion1, "1f369613dd76d5467730efcbe3b1a22d", "fa044b2f42a3fd3b46fb255c", "9f50449e04a0e810283a1e9933adedd2"},
+		{"v2", quicVersion2, "8b1a0bc121284290a29e0971b5cd045d", "91f73e2351d8fa91660e909f", "45b95e15235d6f45a6b19cbcb0294ba9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, iv, hp, err := quicInitialSecrets(tt.version, dcid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(key) != tt.key || hex.EncodeToString(iv) != tt.iv || hex.EncodeToString(hp) != tt.hp {
+				t.Errorf("key %x, iv %x, hp %x\nwant key %s, iv %s, hp %s", key, iv, hp, tt.key, tt.iv, tt.hp)
+			}
+		})
+	}
+}
+
+// testCapturedPacket is a packet of a test capture.
+type testCapturedPacket struct {
+	time time.Time
+	data []byte
+}
+
+// testPcap returns a little-endian pcap capture of the packets.
+func testPcap(linkType uint16, packets []testCapturedPacket) []byte {
+	le := binary.LittleEndian
+	b := le.AppendUint32(nil, 0xa1b2c3d4)
+	b = le.AppendUint16(b, 2)
+	b = le.AppendUint16(b, 4)
+	b = append(b, make([]byte, 8)...)
+	b = le.AppendUint32(b, 65535)
+	b = le.AppendUint32(b, uint32(linkType))
+	for _, p := range packets {
+		b = le.AppendUint32(b, uint32(p.time.Unix()))
+		b = le.AppendUint32(b, uint32(p.time.Nanosecond()/1000))
+		b = le.AppendUint32(b, uint32(len(p.data)))
+		b = le.AppendUint32(b, uint32(len(p.data)))
+		b = append(b, p.data...)
+	}
+	return b
+}
+
+// testPcapng returns a big-endian pcapng capture of the packets, captured on
+// an interface with nanosecond timestamps.
+func testPcapng(linkType uint16, packets []testCapturedPacket) []byte {
+	be := binary.BigEndian
+	block := func(b []byte, typ uint32, body []byte) []byte {
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+		b = be.AppendUint32(b, typ)
+		b = be.AppendUint32(b, uint32(12+len(body)))
+		b = append(b, body...)
+		return be.AppendUint32(b, uint32(12+len(body)))
+	}
+	shb := be.AppendUint32(nil, pcapngByteOrderMagic)
+	shb = append(shb, 0, 1, 0, 0)
+	shb = be.AppendUint64(shb, ^uint64(0))
+	b := block(nil, pcapngBlockSectionHeader, shb)
+
+	idb := be.AppendUint16(nil, linkType)
+	idb = append(idb, 0, 0)
+	idb = be.AppendUint32(idb, 0)
+	idb = append(idb, 0, pcapngOptionIfTsresol, 0, 1, 9, 0, 0, 0) // nanoseconds
+	idb = append(idb, 0, 0, 0, 0)
+	b = block(b, pcapngBlockInterface, idb)
+
+	for _, p := range packets {
+		ts := uint64(p.time.UnixNano())
+		epb := be.AppendUint32(nil, 0)
+		epb = be.AppendUint32(epb, uint32(ts>>32))
+		epb = be.AppendUint32(epb, uint32(ts))
+		epb = be.AppendUint32(epb, uint32(len(p.data)))
+		epb = be.AppendUint32(epb, uint32(len(p.data)))
+		b = block(b, pcapngBlockEnhancedPacket, append(epb, p.data...))
+	}
+	return b
+}
+
+// testEthernetTCP returns an Ethernet frame of an IPv4 TCP segment.
+func testEthernetTCP(src, dst netip.AddrPort, seq uint32, flags byte, payload []byte) []byte {
+	b := make([]byte, 12, 14+20+20+len(payload))
+	b = append(b, 0x08, 0x00)
+
+	b = append(b, 0x45, 0)
+	b = binary.BigEndian.AppendUint16(b, uint16(20+20+len(payload)))
+	b = append(b, 0, 0, 0x40, 0, 64, ipProtocolTCP, 0, 0) // don't fragment
+	b = append(b, src.Addr().AsSlice()...)
+	b = append(b, dst.Addr().AsSlice()...)
+
+	b = binary.BigEndian.AppendUint16(b, src.Port())
+	b = binary.BigEndian.AppendUint16(b, dst.Port())
+	b = binary.BigEndian.AppendUint32(b, seq)
+	b = append(b, 0, 0, 0, 0, 5<<4, flags, 0xff, 0xff, 0, 0, 0, 0)
+	return append(b, payload...)
+}
+
+// testIPv6UDP returns an IPv6 packet of a UDP datagram.
+func testIPv6UDP(src, dst netip.AddrPort, payload []byte) []byte {
+	b := []byte{0x60, 0, 0, 0}
+	b = binary.BigEndian.AppendUint16(b, uint16(8+len(payload)))
+	b = append(b, ipProtocolUDP, 64)
+	b = append(b, src.Addr().AsSlice()...)
+	b = append(b, dst.Addr().AsSlice()...)
+
+	b = binary.BigEndian.AppendUint16(b, src.Port())
+	b = binary.BigEndian.AppendUint16(b, dst.Port())
+	b = binary.BigEndian.AppendUint16(b, uint16(8+len(payload)))
+	b = append(b, 0, 0)
+	return append(b, payload...)
+}
+
+// testQUICInitial returns an Initial packet of the frames, protected with the
+// client Initial keys of dcid and padded to at least 1200 bytes.
+func testQUICInitial(t *testing.T, version uint32, dcid []byte, pn uint16, frames []byte) []byte {
+	keys, err := newQUICInitialKeys(version, dcid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pnLen = 2
+	header := []byte{0xc0 | quicInitialProtections[version].packetType<<4 | (pnLen - 1)}
+	header = binary.BigEndian.AppendUint32(header, version)
+	header = append(header, byte(len(dcid)))
+	header = append(header, dcid...)
+	header = append(header, 4, 0xc1, 0x1e, 0x47, 0x1d) // Source Connection ID
+	header = append(header, 0)                         // Token Length
+	lengthOffset := len(header)
+	header = append(header, 0, 0) // Length, set below
+	pnOffset := len(header)
+	header = binary.BigEndian.AppendUint16(header, pn)
+
+	payloadLen := max(1200-len(header)-keys.aead.Overhead(), len(frames))
+	frames = append(frames, make([]byte, payloadLen-len(frames))...) // PADDING
+	binary.BigEndian.PutUint16(header[lengthOffset:], uint16(pnLen+payloadLen+keys.aead.Overhead())|0x4000)
+
+	nonce := bytes.Clone(keys.iv)
+	nonce[len(nonce)-2] ^= byte(pn >> 8)
+	nonce[len(nonce)-1] ^= byte(pn)
+	packet := keys.aead.Seal(header, nonce, frames, header)
+
+	mask := make([]byte, 16)
+	keys.hp.Encrypt(mask, packet[pnOffset+4:])
+	packet[0] ^= mask[0] & 0x0f
+	packet[pnOffset] ^= mask[1]
+	packet[pnOffset+1] ^= mask[2]
+	return packet
+}
+
+// testCryptoFrame returns a CRYPTO frame.
+func testCryptoFrame(offset int, data []byte) []byte {
+	b := []byte{0x06}
+	b = quicvarint.Append(b, uint64(offset))
+	b = quicvarint.Append(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func TestCapturedClientHellosTCP(t *testing.T) {
+	uconn := UClient(&net.TCPConn{}, &Config{ServerName: "example.com"}, HelloChrome_133)
+	if err := uconn.BuildHandshakeState(); err != nil {
+		t.Fatal(err)
+	}
+	msg := uconn.HandshakeState.Hello.Raw
+	record := prependRecordHeader(msg, VersionTLS10)
+
+	client := netip.MustParseAddrPort("192.0.2.1:50000")
+	server := netip.MustParseAddrPort("198.51.100.1:443")
+	client2 := netip.MustParseAddrPort("192.0.2.2:50001")
+	start := time.Unix(1700000000, 123456000)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Millisecond) }
+
+	var isn uint32 = 0xfffffff0 // the sequence numbers wrap around
+	// the ClientHello is fragmented over two records
+	fragmented := append(prependRecordHeader(msg[:200], VersionTLS10), prependRecordHeader(msg[200:], VersionTLS12)...)
+	packets := []testCapturedPacket{
+		{at(0), testEthernetTCP(client, server, isn, tcpFlagSYN, nil)},
+		{at(1), testEthernetTCP(server, client, 1000, tcpFlagSYN|0x10, nil)},
+		// out of order, retransmitted and overlapping segments
+		{at(2), testEthernetTCP(client, server, isn+1+100, 0x10, record[100:300])},
+		{at(3), testEthernetTCP(client, server, isn+1, 0x10, record[:150])},
+		{at(4), testEthernetTCP(client, server, isn+1, 0x10, record[:150])},
+		// a connection whose SYN was not captured
+		{at(5), testEthernetTCP(client2, server, 77, 0x10, fragmented[:400])},
+		{at(6), testEthernetTCP(client, server, isn+1+300, 0x10, record[300:])},
+		{at(7), testEthernetTCP(server, client, 1001, 0x10, []byte{0x16, 0x03, 0x03, 0x00, 0x04, 0x02, 0, 0, 0})},
+		{at(8), testEthernetTCP(client2, server, 77+400, 0x10, fragmented[400:])},
+	}
+
+	f := &Fingerprinter{}
+	want, err := f.RawClientHello(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	capture := testPcap(linkTypeEthernet, packets)
+	hellos, err := f.CapturedClientHellos(bytes.NewReader(capture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hellos) != 2 {
+		t.Fatalf("got %d ClientHellos, want 2", len(hellos))
+	}
+	for i, w := range []struct {
+		time   time.Time
+		client netip.AddrPort
+	}{{at(6), client}, {at(8), client2}} {
+		h := hellos[i]
+		if !h.Time.Equal(w.time) || h.Client != w.client || h.Server != server || h.QUICVersion != 0 {
+			t.Errorf("ClientHello %d: time %v, %v -> %v, QUIC version %d", i, h.Time, h.Client, h.Server, h.QUICVersion)
+		}
+		if !bytes.Equal(h.Raw, record) {
+			t.Errorf("ClientHello %d: raw\n%x\nwant\n%x", i, h.Raw, record)
+		}
+		if h.Err != nil {
+			t.Fatalf("ClientHello %d: %v", i, h.Err)
+		}
+		if !reflect.DeepEqual(h.Spec.CipherSuites, want.CipherSuites) || len(h.Spec.Extensions) != len(want.Extensions) {
+			t.Errorf("ClientHello %d: spec differs from the spec of the record", i)
+		}
+	}
+
+	// a truncated capture returns the ClientHellos before the truncation
+	hellos, err = f.CapturedClientHellos(bytes.NewReader(capture[:len(capture)-10]))
+	if err == nil || len(hellos) != 1 {
+		t.Errorf("truncated capture: %d ClientHellos, err = %v", len(hellos), err)
+	}
+}
+
+func TestTCPClientHelloStreamGap(t *testing.T) {
+	isn := uint32(0xffffff00) // the sequence numbers wrap around
+	payload := bytes.Repeat([]byte{0x16}, 1000)
+
+	// the first segment after the SYN is never captured, so nothing after
+	// it can be reassembled
+	s := &tcpClientHelloStream{}
+	s.segment(tcpSegment{seq: isn, syn: true})
+	seq := isn + 1 + 100
+	for i := 0; i < 1000 && !s.done; i++ {
+		s.segment(tcpSegment{seq: seq, payload: payload})
+		seq += uint32(len(payload))
+		if s.pendingLen > maxCapturedClientHelloLen+len(payload) {
+			t.Fatalf("%d bytes pending after segment %d", s.pendingLen, i)
+		}
+	}
+	if !s.done || s.pending != nil || s.pendingLen != 0 {
+		t.Fatalf("stream with a permanent gap is not done: done %v, %d segments pending", s.done, len(s.pending))
+	}
+
+	// a segment beyond the longest ClientHello is dropped
+	s = &tcpClientHelloStream{}
+	s.segment(tcpSegment{seq: isn, syn: true})
+	s.segment(tcpSegment{seq: isn + 1 + maxCapturedClientHelloLen + 1, payload: payload})
+	if len(s.pending) != 0 {
+		t.Errorf("segment beyond the ClientHello is pending")
+	}
+
+	// segments after a gap are reassembled once it is filled, in any order
+	record := bytes.Repeat([]byte{0xab}, 300)
+	record = append([]byte{0x16, 0x03, 0x01, 0x01, 0x2b, typeClientHello, 0, 1, 0x27}, record[:295]...)
+	s = &tcpClientHelloStream{}
+	s.segment(tcpSegment{seq: isn, syn: true})
+	for _, start := range []int{200, 100, 250} {
+		s.segment(tcpSegment{seq: isn + 1 + uint32(start), payload: record[start:min(start+100, len(record))]})
+	}
+	if hello := s.segment(tcpSegment{seq: isn + 1, payload: record[:100]}); !bytes.Equal(hello, record) {
+		t.Errorf("reassembled ClientHello\n%x\nwant\n%x", hello, record)
+	}
+}
+
+func TestCapturedClientHellosQUIC(t *testing.T) {
+	spec, err := UTLSIdToSpec(HelloChrome_133)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.Extensions = append([]TLSExtension{&QUICTransportParametersExtension{
+		TransportParameters: TransportParameters{
+			InitialMaxData(0x1800000),
+			MaxIdleTimeout(30000),
+			&GREASETransportParameter{Length: 2},
+			InitialSourceConnectionID{0xc1, 0x1e, 0x47, 0x1d},
+			&VersionInformation{ChoosenVersion: VERSION_1, AvailableVersions: []uint32{VERSION_GREASE, VERSION_1}},
+			&GREASEQUICBit{},
+			&FakeQUICTransportParameter{Id: 0x4752, Val: []byte{1}},
+		},
+	}}, spec.Extensions...)
+	uconn := UClient(&net.TCPConn{}, &Config{ServerName: "example.com"}, HelloCustom)
+	if err := uconn.ApplyPreset(&spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconn.BuildHandshakeState(); err != nil {
+		t.Fatal(err)
+	}
+	msg := uconn.HandshakeState.Hello.Raw
+	record := prependRecordHeader(msg, VersionTLS10)
+
+	client := netip.MustParseAddrPort("[2001:db8::1]:50000")
+	server := netip.MustParseAddrPort("[2001:db8::2]:443")
+	client2 := netip.MustParseAddrPort("[2001:db8::3]:50001")
+	start := time.Unix(1700000000, 123456789)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Millisecond) }
+
+	dcid := []byte{0x83, 0x94, 0xc8, 0xf0, 0x3e, 0x51, 0x57, 0x08}
+	ack := []byte{0x02, 0, 0, 0, 0}
+	packets := []testCapturedPacket{
+		// the CRYPTO frames are out of order across the packets
+		{at(0), testIPv6UDP(client, server, testQUICInitial(t, quicVersion1, dcid, 0, testCryptoFrame(600, msg[600:])))},
+		{at(1), testIPv6UDP(server, client, testQUICInitial(t, quicVersion1, []byte{0xc1, 0x1e, 0x47, 0x1d}, 0, ack))},
+		{at(2), testIPv6UDP(client, server, []byte("not a QUIC packet"))},
+		{at(3), testIPv6UDP(client, server, testQUICInitial(t, quicVersion1, dcid, 1, append(append(ack, 0x01), testCryptoFrame(0, msg[:700])...)))},
+		{at(4), testIPv6UDP(client2, server, testQUICInitial(t, quicVersion2, dcid, 0, testCryptoFrame(0, msg)))},
+	}
+
+	hellos, err := (&Fingerprinter{}).CapturedClientHellos(bytes.NewReader(testPcapng(linkTypeRaw, packets)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hellos) != 2 {
+		t.Fatalf("got %d ClientHellos, want 2", len(hellos))
+	}
+	for i, w := range []struct {
+		time    time.Time
+		client  netip.AddrPort
+		version uint32
+	}{{at(3), client, quicVersion1}, {at(4), client2, quicVersion2}} {
+		h := hellos[i]
+		if !h.Time.Equal(w.time) || h.Client != w.client || h.Server != server || h.QUICVersion != w.version {
+			t.Errorf("ClientHello %d: time %v, %v -> %v, QUIC version %#x", i, h.Time, h.Client, h.Server, h.QUICVersion)
+		}
+		if !bytes.Equal(h.Raw, record) {
+			t.Errorf("ClientHello %d: raw\n%x\nwant\n%x", i, h.Raw, record)
+		}
+		if h.Err != nil {
+			t.Fatalf("ClientHello %d: %v", i, h.Err)
+		}
+		tp, ok := h.Spec.Extensions[0].(*QUICTransportParametersExtension)
+		if !ok {
+			t.Fatalf("ClientHello %d: extension 0 is %T", i, h.Spec.Extensions[0])
+		}
+		wantParams := TransportParameters{
+			InitialMaxData(0x1800000),
+			MaxIdleTimeout(30000),
+			&GREASETransportParameter{Length: 2},
+			InitialSourceConnectionID{},
+			&VersionInformation{ChoosenVersion: VERSION_1, AvailableVersions: []uint32{VERSION_GREASE, VERSION_1}},
+			&GREASEQUICBit{},
+			&FakeQUICTransportParameter{Id: 0x4752, Val: []byte{1}},
+		}
+		if !reflect.DeepEqual(tp.TransportParameters, wantParams) {
+			t.Errorf("ClientHello %d: transport parameters %#v", i, tp.TransportParameters)
+		}
+	}
+}
+
+func TestCapturedClientHellosUnknownFormat(t *testing.T) {
+	_, err := (&Fingerprinter{}).CapturedClientHellos(bytes.NewReader([]byte("GET / HTTP/1.1\r\n")))
+	if !errors.Is(err, ErrUnknownCaptureFormat) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownCaptureFormat)
+	}
+}
@@ -290,6 +290,58 @@ func TestUTLSFingerprintClientHelloBluntMimicry(t *testing.T) {
 	t.Errorf("generated ClientHelloSpec with BluntMimicry did not correctly carry over generic extension")
 }
 
+func TestUTLSFingerprintClientHelloQUICTransportParameters(t *testing.T) {
+	params := TransportParameters{
+		InitialMaxData(0x1800000),
+		MaxIdleTimeout(30000),
+		&GREASEQUICBit{},
+		&FakeQUICTransportParameter{Id: 0x4752, Val: []byte{1}},
+	}
+	spec, err := utlsIdToSpec(HelloChrome_133)
+	if err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+	spec.Extensions = append(spec.Extensions, &QUICTransportParametersExtension{TransportParameters: params})
+
+	uconn := UClient(&net.TCPConn{}, &Config{ServerName: "foobar"}, HelloCustom)
+	if err := uconn.ApplyPreset(&spec); err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+	if err := uconn.BuildHandshakeState(); err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+
+	// quic_transport_parameters is a known extension, so no blunt mimicry
+	f := &Fingerprinter{}
+	generatedSpec, err := f.FingerprintClientHello(prependRecordHeader(uconn.HandshakeState.Hello.Raw, createMinTLSVersion(uconn.vers)))
+	if err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+	var ext *QUICTransportParametersExtension
+	for _, e := range generatedSpec.Extensions {
+		if e, ok := e.(*QUICTransportParametersExtension); ok {
+			ext = e
+		}
+	}
+	if ext == nil {
+		t.Fatalf("generated ClientHelloSpec has no QUICTransportParametersExtension")
+	}
+	if !reflect.DeepEqual(ext.TransportParameters, params) {
+		t.Errorf("transport parameters %#v, want %#v", ext.TransportParameters, params)
+	}
+
+	generatedUConn := UClient(&net.TCPConn{}, &Config{ServerName: "foobar"}, HelloCustom)
+	if err := generatedUConn.ApplyPreset(generatedSpec); err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+	if err := generatedUConn.BuildHandshakeState(); err != nil {
+		t.Fatalf("got error: %v; expected to succeed", err)
+	}
+	if len(generatedUConn.HandshakeState.Hello.Raw) != len(uconn.HandshakeState.Hello.Raw) {
+		t.Errorf("UConn from fingerprint has %d length, should have %d", len(generatedUConn.HandshakeState.Hello.Raw), len(uconn.HandshakeState.Hello.Raw))
+	}
+}
+
 func TestUTLSFingerprintClientHelloAlwaysAddPadding(t *testing.T) {
 	serverName := "foobar"
 
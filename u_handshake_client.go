@@ -49,11 +49,7 @@ func (hs *clientHandshakeStateTLS13) utlsReadServerCertificate(msg any) (process
 
 // called by (*clientHandshakeStateTLS13).utlsReadServerCertificate() when UtlsCompressCertExtension is used
 func (hs *clientHandshakeStateTLS13) decompressCert(m utlsCompressedCertificateMsg) (*certificateMsgTLS13, error) {
-	var (
-		decompressed io.Reader
-		compressed   = bytes.NewReader(m.compressedCertificateMessage)
-		c            = hs.c
-	)
+	c := hs.c
 
 	// Check to see if the peer responded with an algorithm we advertised.
 	supportedAlg := false
@@ -67,6 +63,26 @@ func (hs *clientHandshakeStateTLS13) decompressCert(m utlsCompressedCertificateM
 		return nil, fmt.Errorf("unadvertised algorithm (%d)", m.algorithm)
 	}
 
+	rawMsg, err := decompressCertificateMsg(&m)
+	if err != nil {
+		c.sendAlert(alertBadCertificate)
+		return nil, err
+	}
+	certMsg := new(certificateMsgTLS13)
+	if !certMsg.unmarshal(rawMsg) {
+		return nil, c.sendAlert(alertUnexpectedMessage)
+	}
+	return certMsg, nil
+}
+
+// decompressCertificateMsg decompresses a CompressedCertificate message and
+// returns the raw Certificate message it carries, header included.
+func decompressCertificateMsg(m *utlsCompressedCertificateMsg) ([]byte, error) {
+	var (
+		decompressed io.Reader
+		compressed   = bytes.NewReader(m.compressedCertificateMessage)
+	)
+
 	switch CertCompressionAlgo(m.algorithm) {
 	case CertCompressionBrotli:
 		decompressed = brotli.NewReader(compressed)
@@ -74,7 +90,6 @@ func (hs *clientHandshakeStateTLS13) decompressCert(m utlsCompressedCertificateM
 	case CertCompressionZlib:
 		rc, err := zlib.NewReader(compressed)
 		if err != nil {
-			c.sendAlert(alertBadCertificate)
 			return nil, fmt.Errorf("failed to open zlib reader: %w", err)
 		}
 		defer rc.Close()
@@ -83,14 +98,12 @@ func (hs *clientHandshakeStateTLS13) decompressCert(m utlsCompressedCertificateM
 	case CertCompressionZstd:
 		rc, err := zstd.NewReader(compressed)
 		if err != nil {
-			c.sendAlert(alertBadCertificate)
 			return nil, fmt.Errorf("failed to open zstd reader: %w", err)
 		}
 		defer rc.Close()
 		decompressed = rc
 
 	default:
-		c.sendAlert(alertBadCertificate)
 		return nil, fmt.Errorf("unsupported algorithm (%d)", m.algorithm)
 	}
 
@@ -102,21 +115,15 @@ func (hs *clientHandshakeStateTLS13) decompressCert(m utlsCompressedCertificateM
 
 	n, err := decompressed.Read(rawMsg[4:])
 	if err != nil && !errors.Is(err, io.EOF) {
-		c.sendAlert(alertBadCertificate)
 		return nil, err
 	}
 	if n < len(rawMsg)-4 {
 		// If, after decompression, the specified length does not match the actual length, the party
 		// receiving the invalid message MUST abort the connection with the "bad_certificate" alert.
 		// https://datatracker.ietf.org/doc/html/rfc8879#section-4
-		c.sendAlert(alertBadCertificate)
 		return nil, fmt.Errorf("decompressed len (%d) does not match specified len (%d)", n, m.uncompressedLength)
 	}
-	certMsg := new(certificateMsgTLS13)
-	if !certMsg.unmarshal(rawMsg) {
-		return nil, c.sendAlert(alertUnexpectedMessage)
-	}
-	return certMsg, nil
+	return rawMsg, nil
 }
 
 // to be called in (*clientHandshakeStateTLS13).handshake(),
@@ -0,0 +1,180 @@
+package tls
+
+import "bytes"
+
+// TLS alert levels, RFC 5246, Section 7.2.
+const (
+	AlertLevelWarning uint8 = alertLevelWarning
+	AlertLevelFatal   uint8 = alertLevelError
+)
+
+// Handshake states reported by HandshakeFailure. The TLS 1.3 names follow
+// the client state machine of RFC 8446, Appendix A.1. TLS 1.2 has no
+// normative state names, so the same style is used for its additional
+// states.
+const (
+	HandshakeStateStart                 = "START"
+	HandshakeStateWaitServerHello       = "WAIT_SH"
+	HandshakeStateWaitEncryptedExts     = "WAIT_EE"
+	HandshakeStateWaitCertOrCertRequest = "WAIT_CERT_CR"
+	HandshakeStateWaitCert              = "WAIT_CERT"
+	HandshakeStateWaitCertVerify        = "WAIT_CV"
+	HandshakeStateWaitFinished          = "WAIT_FINISHED"
+	HandshakeStateConnected             = "CONNECTED"
+
+	// HandshakeStateSendFinished means the server's flight is complete and
+	// the client failed while sending its own, up to its Finished.
+	HandshakeStateSendFinished = "SEND_FINISHED"
+
+	// TLS 1.2 only.
+	HandshakeStateWaitServerKeyExchange = "WAIT_SKE"
+	HandshakeStateWaitServerHelloDone   = "WAIT_SHD"
+)
+
+// HandshakeAlert is a TLS alert sent or received during a handshake.
+type HandshakeAlert struct {
+	Level       uint8
+	Description uint8
+}
+
+// String returns the alert description as in the alert error messages,
+// e.g. "tls: handshake failure".
+func (a HandshakeAlert) String() string {
+	return alert(a.Description).String()
+}
+
+// HandshakeFailure describes how far a failed handshake got, see
+// UConn.HandshakeFailure. It wraps the error the handshake returned, so
+// errors.Is and errors.As see through it.
+type HandshakeFailure struct {
+	Err error
+
+	// State is the handshake state the client was in when it failed, see
+	// the HandshakeState* constants.
+	State string
+
+	// LastMessage is the last handshake message sent or received, or nil
+	// if the failure happened before the ClientHello was written.
+	LastMessage *RecordedMessage
+
+	// ReceivedAlert is the last alert received from the server, if any.
+	ReceivedAlert *HandshakeAlert
+
+	// SentAlert is the alert raised locally, if any. It may not have
+	// reached the server if the connection was already broken.
+	SentAlert *HandshakeAlert
+}
+
+func (f *HandshakeFailure) Error() string {
+	return f.Err.Error()
+}
+
+func (f *HandshakeFailure) Unwrap() error {
+	return f.Err
+}
+
+// SetHandshakeFailureTracking enables HandshakeFailure. It must be called
+// before the handshake starts. The error returned by Handshake is the same
+// either way.
+func (uconn *UConn) SetHandshakeFailureTracking(enabled bool) {
+	uconn.utls.trackFailure = enabled
+}
+
+// HandshakeFailure returns how far the handshake got if it failed and
+// SetHandshakeFailureTracking was enabled, or nil otherwise. It must not be
+// called concurrently with the handshake.
+func (uconn *UConn) HandshakeFailure() *HandshakeFailure {
+	return uconn.utls.handshakeFailure
+}
+
+// newHandshakeFailure wraps err with the state tracked on c. c.out must not
+// be locked.
+func (c *Conn) newHandshakeFailure(err error) *HandshakeFailure {
+	failure := &HandshakeFailure{
+		Err:           err,
+		ReceivedAlert: c.utls.receivedAlert,
+	}
+	c.out.Lock()
+	failure.SentAlert = c.utls.sentAlert
+	c.out.Unlock()
+	if m := c.utls.lastMessage; m != nil {
+		lastMessage := *m
+		failure.LastMessage = &lastMessage
+	}
+	failure.State = handshakeState(c.vers, failure.LastMessage)
+	return failure
+}
+
+// handshakeState returns the state the client is in after m was sent or
+// received.
+func handshakeState(vers uint16, m *RecordedMessage) string {
+	if m == nil {
+		return HandshakeStateStart
+	}
+	if m.Direction == RecordSent {
+		switch m.Type {
+		case typeClientHello:
+			return HandshakeStateWaitServerHello
+		case typeFinished:
+			if vers == VersionTLS13 {
+				return HandshakeStateConnected
+			}
+			return HandshakeStateWaitFinished
+		}
+		return HandshakeStateSendFinished
+	}
+
+	if vers == VersionTLS13 {
+		switch m.Type {
+		case typeServerHello:
+			if len(m.Raw) >= 38 && bytes.Equal(m.Raw[6:38], helloRetryRequestRandom) {
+				// A second ClientHello has to be sent.
+				return HandshakeStateStart
+			}
+			return HandshakeStateWaitEncryptedExts
+		case typeEncryptedExtensions:
+			return HandshakeStateWaitCertOrCertRequest
+		case typeCertificateRequest:
+			return HandshakeStateWaitCert
+		case typeCertificate, utlsTypeCompressedCertificate:
+			return HandshakeStateWaitCertVerify
+		case typeCertificateVerify:
+			return HandshakeStateWaitFinished
+		case typeFinished:
+			return HandshakeStateSendFinished
+		}
+		return HandshakeStateConnected
+	}
+
+	switch m.Type {
+	case typeServerHello:
+		return HandshakeStateWaitCert
+	case typeCertificate, typeCertificateStatus:
+		return HandshakeStateWaitServerKeyExchange
+	case typeServerKeyExchange, typeCertificateRequest:
+		return HandshakeStateWaitServerHelloDone
+	case typeServerHelloDone:
+		return HandshakeStateSendFinished
+	case typeNewSessionTicket:
+		return HandshakeStateWaitFinished
+	}
+	return HandshakeStateConnected
+}
+
+// addMessage notes m as the last handshake message if failures are tracked,
+// keeps a ServerHello for the server fingerprints and passes m on to the
+// recorder, if any.
+func (f *utlsConnExtraFields) addMessage(dir RecordDirection, data []byte, encrypted bool) {
+	if f.trackFailure && len(data) > 0 {
+		f.lastMessage = &RecordedMessage{
+			Direction: dir,
+			Type:      data[0],
+			Raw:       append([]byte(nil), data...),
+			Encrypted: encrypted,
+		}
+	}
+	if dir == RecordReceived && len(data) >= 38 && data[0] == typeServerHello && !bytes.Equal(data[6:38], helloRetryRequestRandom) {
+		f.serverHello = append([]byte(nil), data...)
+	}
+	f.recorder.addMessage(dir, data, encrypted)
+}
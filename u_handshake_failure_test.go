@@ -0,0 +1,126 @@
+package tls
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUTLSHandshakeFailure(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		modify        func(client, server *Config)
+		state         string
+		lastMessage   uint8
+		lastDirection RecordDirection
+		receivedAlert *HandshakeAlert
+		sentAlert     *HandshakeAlert
+	}{
+		{
+			name: "ServerRejectsCipherSuites",
+			modify: func(client, server *Config) {
+				client.MaxVersion = VersionTLS12
+				client.CipherSuites = []uint16{TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+				server.CipherSuites = []uint16{TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}
+			},
+			state:         HandshakeStateWaitServerHello,
+			lastMessage:   HandshakeTypeClientHello,
+			lastDirection: RecordSent,
+			receivedAlert: &HandshakeAlert{Level: AlertLevelFatal, Description: uint8(alertHandshakeFailure)},
+		},
+		{
+			name: "ClientRejectsCertificate",
+			modify: func(client, server *Config) {
+				client.InsecureSkipVerify = false
+				client.ServerName = "wrong.example.com"
+			},
+			state:         HandshakeStateWaitCertVerify,
+			lastMessage:   HandshakeTypeCertificate,
+			lastDirection: RecordReceived,
+			sentAlert:     &HandshakeAlert{Level: AlertLevelFatal, Description: uint8(alertBadCertificate)},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig := testConfig.Clone()
+			serverConfig := testConfig.Clone()
+			tt.modify(clientConfig, serverConfig)
+
+			c, s := localPipe(t)
+			go func() {
+				defer s.Close()
+				Server(s, serverConfig).Handshake()
+			}()
+			uconn := UClient(c, clientConfig, HelloGolang)
+			uconn.SetHandshakeFailureTracking(true)
+			err := uconn.Handshake()
+			c.Close()
+
+			// The error itself is left as it is.
+			var wrapped *HandshakeFailure
+			if err == nil || errors.As(err, &wrapped) {
+				t.Fatalf("Handshake error = %v (%T), want the unwrapped error", err, err)
+			}
+			failure := uconn.HandshakeFailure()
+			if failure == nil {
+				t.Fatal("HandshakeFailure() = nil")
+			}
+			if failure.Err != err || !errors.Is(failure, err) {
+				t.Errorf("HandshakeFailure().Err = %v, want %v", failure.Err, err)
+			}
+			var certErr *CertificateVerificationError
+			if errors.As(failure, &certErr) != errors.As(err, &certErr) {
+				t.Errorf("errors.As(HandshakeFailure(), *CertificateVerificationError) differs from the handshake error")
+			}
+			if failure.Error() != err.Error() {
+				t.Errorf("Error() = %q, want the wrapped error %q", failure.Error(), err.Error())
+			}
+			if failure.State != tt.state {
+				t.Errorf("State = %s, want %s", failure.State, tt.state)
+			}
+			if m := failure.LastMessage; m == nil || m.Type != tt.lastMessage || m.Direction != tt.lastDirection {
+				t.Errorf("LastMessage = %+v, want type %d %s", m, tt.lastMessage, tt.lastDirection)
+			}
+			checkAlert(t, "ReceivedAlert", failure.ReceivedAlert, tt.receivedAlert)
+			checkAlert(t, "SentAlert", failure.SentAlert, tt.sentAlert)
+
+			// The failure is sticky, like the handshake error itself.
+			if again := uconn.Handshake(); again != err {
+				t.Errorf("second Handshake = %v, want the same error", again)
+			}
+			if again := uconn.HandshakeFailure(); again != failure {
+				t.Errorf("second HandshakeFailure = %v, want the same failure", again)
+			}
+		})
+	}
+}
+
+func TestUTLSHandshakeFailureDisabled(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.InsecureSkipVerify = false
+	clientConfig.ServerName = "wrong.example.com"
+
+	c, s := localPipe(t)
+	go func() {
+		defer s.Close()
+		Server(s, testConfig).Handshake()
+	}()
+	uconn := UClient(c, clientConfig, HelloGolang)
+	err := uconn.Handshake()
+	c.Close()
+
+	if err == nil {
+		t.Fatal("Handshake succeeded")
+	}
+	if failure := uconn.HandshakeFailure(); failure != nil {
+		t.Errorf("HandshakeFailure() = %+v, want nil", failure)
+	}
+	if uconn.utls.lastMessage != nil {
+		t.Errorf("last message kept without tracking: %+v", uconn.utls.lastMessage)
+	}
+}
+
+func checkAlert(t *testing.T, name string, got, want *HandshakeAlert) {
+	t.Helper()
+	if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+		t.Errorf("%s = %+v, want %+v", name, got, want)
+	}
+}
@@ -0,0 +1,264 @@
+package tls
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// TLS handshake message types, exported for inspecting recorded messages.
+const (
+	HandshakeTypeClientHello         uint8 = typeClientHello
+	HandshakeTypeServerHello         uint8 = typeServerHello
+	HandshakeTypeNewSessionTicket    uint8 = typeNewSessionTicket
+	HandshakeTypeEndOfEarlyData      uint8 = typeEndOfEarlyData
+	HandshakeTypeEncryptedExtensions uint8 = typeEncryptedExtensions
+	HandshakeTypeCertificate         uint8 = typeCertificate
+	HandshakeTypeServerKeyExchange   uint8 = typeServerKeyExchange
+	HandshakeTypeCertificateRequest  uint8 = typeCertificateRequest
+	HandshakeTypeServerHelloDone     uint8 = typeServerHelloDone
+	HandshakeTypeCertificateVerify   uint8 = typeCertificateVerify
+	HandshakeTypeClientKeyExchange   uint8 = typeClientKeyExchange
+	HandshakeTypeFinished            uint8 = typeFinished
+	HandshakeTypeCertificateStatus   uint8 = typeCertificateStatus
+	HandshakeTypeKeyUpdate           uint8 = typeKeyUpdate
+	HandshakeTypeCompressedCert      uint8 = utlsTypeCompressedCertificate
+	HandshakeTypeMessageHash         uint8 = typeMessageHash
+)
+
+// TLS record content types, exported for inspecting recorded records.
+const (
+	RecordTypeChangeCipherSpec uint8 = uint8(recordTypeChangeCipherSpec)
+	RecordTypeAlert            uint8 = uint8(recordTypeAlert)
+	RecordTypeHandshake        uint8 = uint8(recordTypeHandshake)
+	RecordTypeApplicationData  uint8 = uint8(recordTypeApplicationData)
+)
+
+// RecordDirection tells whether a recorded record or message was written
+// by the local side of the connection or read from the peer.
+type RecordDirection int
+
+const (
+	RecordSent     RecordDirection = 0
+	RecordReceived RecordDirection = 1
+)
+
+// String returns "sent" or "received".
+func (d RecordDirection) String() string {
+	if d == RecordSent {
+		return "sent"
+	}
+	return "received"
+}
+
+// RecordedRecord is a single TLS record as it crossed the wire.
+type RecordedRecord struct {
+	Direction RecordDirection
+
+	// ContentType is the content type of the plaintext. For TLS 1.3
+	// protected records it is the inner type, not the outer
+	// application_data type found in Raw.
+	ContentType uint8
+
+	// Raw is the record exactly as written to or read from the
+	// network, including the 5-byte header. Once record protection is
+	// active this is ciphertext.
+	Raw []byte
+
+	// Plaintext is the record payload before encryption (sent) or after
+	// decryption (received), without the TLS 1.3 inner content type
+	// and padding.
+	Plaintext []byte
+
+	// Encrypted is true if the record was protected. The remaining fields
+	// are only set for protected records.
+	Encrypted bool
+
+	// SequenceNumber is the implicit record sequence number the record
+	// was protected with (RFC 8446, Section 5.3; RFC 5246, Section 6.1).
+	SequenceNumber uint64
+
+	// Nonce is the per-record AEAD nonce. It is nil for CBC cipher suites.
+	Nonce []byte
+
+	// PaddingLength is the length of the zero padding following the TLS 1.3
+	// inner content type, or of the TLS 1.0-1.2 CBC padding including its
+	// length byte.
+	PaddingLength int
+}
+
+// RecordedMessage is a single reassembled handshake message.
+type RecordedMessage struct {
+	Direction RecordDirection
+
+	// Type is the handshake message type, see the HandshakeType* constants.
+	Type uint8
+
+	// Raw is the complete message including its 4-byte header, as it
+	// was written to the transcript.
+	Raw []byte
+
+	// Encrypted is true if the message was carried in protected records.
+	Encrypted bool
+}
+
+// HandshakeRecorder captures the records and handshake messages exchanged
+// on a single connection. Attach one to a UConn with SetHandshakeRecorder
+// before the handshake starts. The zero value is ready to use.
+//
+// Recording continues after the handshake, so records carrying application
+// data and post-handshake messages are captured as well.
+type HandshakeRecorder struct {
+	mu       sync.Mutex
+	records  []RecordedRecord
+	messages []RecordedMessage
+
+	// onMessage, if set, is called after each message is recorded, without
+	// holding mu. It is used by HandshakeStepper to pause the handshake.
+	onMessage func(RecordedMessage)
+}
+
+// NewHandshakeRecorder returns an empty HandshakeRecorder.
+func NewHandshakeRecorder() *HandshakeRecorder {
+	return &HandshakeRecorder{}
+}
+
+// addRecord takes ownership of raw; plaintext is copied.
+func (r *HandshakeRecorder) addRecord(dir RecordDirection, typ recordType, raw, plaintext []byte, protection recordProtection) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, RecordedRecord{
+		Direction:      dir,
+		ContentType:    uint8(typ),
+		Raw:            raw,
+		Plaintext:      append([]byte(nil), plaintext...),
+		Encrypted:      protection.encrypted,
+		SequenceNumber: protection.seq,
+		Nonce:          protection.nonce,
+		PaddingLength:  protection.padding,
+	})
+}
+
+// recordProtection describes how a single record was protected, see the
+// fields of RecordedRecord.
+type recordProtection struct {
+	encrypted bool
+	seq       uint64
+	nonce     []byte
+	padding   int
+}
+
+// recordProtection returns how raw was protected by hc, given the sequence
+// number hc had before the record was processed and the plaintext length.
+func (hc *halfConn) recordProtection(seq [8]byte, raw []byte, plaintextLen int) recordProtection {
+	if hc.cipher == nil || len(raw) < recordHeaderLen ||
+		(hc.version == VersionTLS13 && recordType(raw[0]) == recordTypeChangeCipherSpec) {
+		return recordProtection{}
+	}
+	p := recordProtection{encrypted: true, seq: binary.BigEndian.Uint64(seq[:])}
+	payloadLen := len(raw) - recordHeaderLen
+
+	switch c := hc.cipher.(type) {
+	case *xorNonceAEAD:
+		nonce := c.nonceMask
+		for i, b := range seq {
+			nonce[4+i] ^= b
+		}
+		p.nonce = nonce[:]
+	case *prefixNonceAEAD:
+		nonce := c.nonce
+		if payloadLen >= c.explicitNonceLen() {
+			copy(nonce[noncePrefixLength:], raw[recordHeaderLen:recordHeaderLen+c.explicitNonceLen()])
+			p.nonce = nonce[:]
+		}
+	case cbcMode:
+		p.padding = payloadLen - hc.explicitNonceLen() - hc.mac.Size() - plaintextLen
+	}
+	if c, ok := hc.cipher.(aead); ok && hc.version == VersionTLS13 {
+		// The inner content type byte precedes the padding.
+		p.padding = payloadLen - c.Overhead() - plaintextLen - 1
+	}
+	return p
+}
+
+func (r *HandshakeRecorder) addMessage(dir RecordDirection, data []byte, encrypted bool) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+	msg := RecordedMessage{
+		Direction: dir,
+		Type:      data[0],
+		Raw:       append([]byte(nil), data...),
+		Encrypted: encrypted,
+	}
+	r.mu.Lock()
+	r.messages = append(r.messages, msg)
+	onMessage := r.onMessage
+	r.mu.Unlock()
+	if onMessage != nil {
+		onMessage(msg)
+	}
+}
+
+func (r *HandshakeRecorder) setOnMessage(f func(RecordedMessage)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onMessage = f
+}
+
+// Records returns a copy of every record recorded so far, in the order
+// they were sent or received.
+func (r *HandshakeRecorder) Records() []RecordedRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRecord(nil), r.records...)
+}
+
+// Messages returns a copy of every handshake message recorded so far, in
+// the order they were sent or received.
+func (r *HandshakeRecorder) Messages() []RecordedMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedMessage(nil), r.messages...)
+}
+
+// Message returns the first recorded handshake message of the given type
+// and direction, or nil if there is none.
+func (r *HandshakeRecorder) Message(dir RecordDirection, typ uint8) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.messages {
+		if m.Direction == dir && m.Type == typ {
+			return m.Raw
+		}
+	}
+	return nil
+}
+
+// RawRecords returns the concatenated wire bytes of all records recorded
+// in the given direction.
+func (r *HandshakeRecorder) RawRecords(dir RecordDirection) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []byte
+	for _, rec := range r.records {
+		if rec.Direction == dir {
+			out = append(out, rec.Raw...)
+		}
+	}
+	return out
+}
+
+// SetHandshakeRecorder attaches r to the connection. It must be called
+// before the handshake starts. Passing nil disables recording.
+func (uconn *UConn) SetHandshakeRecorder(r *HandshakeRecorder) {
+	uconn.utls.recorder = r
+}
+
+// HandshakeRecorder returns the recorder attached with SetHandshakeRecorder,
+// or nil if there is none.
+func (uconn *UConn) HandshakeRecorder() *HandshakeRecorder {
+	return uconn.utls.recorder
+}
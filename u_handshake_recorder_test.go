@@ -0,0 +1,203 @@
+package tls
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestUTLSHandshakeRecorder(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.MinVersion = VersionTLS13
+	clientConfig.MaxVersion = VersionTLS13
+	clientConfig.Rand = nil // testConfig's zero source would make both ClientHellos identical
+	serverConfig := testConfig.Clone()
+
+	// Run two handshakes at once to make sure recordings don't leak
+	// between connections.
+	recorders := []*HandshakeRecorder{NewHandshakeRecorder(), NewHandshakeRecorder()}
+	var wg sync.WaitGroup
+	for _, recorder := range recorders {
+		c, s := localPipe(t)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			defer s.Close()
+			if err := Server(s, serverConfig).Handshake(); err != nil {
+				t.Errorf("server: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			defer c.Close()
+			uconn := UClient(c, clientConfig, HelloGolang)
+			uconn.SetHandshakeRecorder(recorder)
+			if err := uconn.Handshake(); err != nil {
+				t.Errorf("client: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	for _, recorder := range recorders {
+		var got []uint8
+		for _, m := range recorder.Messages() {
+			got = append(got, m.Type)
+		}
+		want := []uint8{
+			HandshakeTypeClientHello,
+			HandshakeTypeServerHello,
+			HandshakeTypeEncryptedExtensions,
+			HandshakeTypeCertificate,
+			HandshakeTypeCertificateVerify,
+			HandshakeTypeFinished,
+			HandshakeTypeFinished,
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("recorded message types = %v, want %v", got, want)
+		}
+
+		for _, m := range recorder.Messages() {
+			encrypted := m.Type != HandshakeTypeClientHello && m.Type != HandshakeTypeServerHello
+			if m.Encrypted != encrypted {
+				t.Errorf("message type %d: Encrypted = %v, want %v", m.Type, m.Encrypted, encrypted)
+			}
+		}
+
+		records := recorder.Records()
+		if len(records) == 0 || records[0].Direction != RecordSent {
+			t.Fatalf("first record was not sent by the client")
+		}
+		hello := recorder.Message(RecordSent, HandshakeTypeClientHello)
+		if !bytes.Equal(records[0].Raw[recordHeaderLen:], hello) {
+			t.Errorf("first sent record does not carry the ClientHello")
+		}
+		if !bytes.Equal(records[0].Plaintext, hello) {
+			t.Errorf("plaintext of the first record does not match the ClientHello")
+		}
+		for _, rec := range records {
+			if rec.Raw[0] == RecordTypeApplicationData && rec.ContentType == RecordTypeHandshake && bytes.Contains(rec.Raw, rec.Plaintext) {
+				t.Errorf("protected handshake record contains its plaintext")
+			}
+		}
+	}
+
+	if bytes.Equal(recorders[0].Message(RecordSent, HandshakeTypeClientHello), recorders[1].Message(RecordSent, HandshakeTypeClientHello)) {
+		t.Errorf("both connections recorded the same ClientHello")
+	}
+}
+
+// recordHandshake runs a handshake between a UClient and a local server and
+// returns what the client recorded.
+func recordHandshake(t *testing.T, clientConfig, serverConfig *Config, helloID ClientHelloID) *HandshakeRecorder {
+	t.Helper()
+	recorder := NewHandshakeRecorder()
+	c, s := localPipe(t)
+	errChan := make(chan error, 1)
+	go func() {
+		defer s.Close()
+		errChan <- Server(s, serverConfig).Handshake()
+	}()
+	uconn := UClient(c, clientConfig, helloID)
+	uconn.SetHandshakeRecorder(recorder)
+	err := uconn.Handshake()
+	c.Close()
+	if serverErr := <-errChan; serverErr != nil {
+		t.Fatalf("server: %v", serverErr)
+	}
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	return recorder
+}
+
+func TestUTLSHandshakeRecorderProtection(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		version     uint16
+		cipherSuite uint16
+		writeIV     string
+	}{
+		{"TLS13_AES_GCM", VersionTLS13, TLS_AES_128_GCM_SHA256, KeyScheduleClientHandshakeWriteIV},
+		{"TLS12_AES_GCM", VersionTLS12, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, KeyScheduleClientWriteIV},
+		{"TLS12_CHACHA20", VersionTLS12, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256, KeyScheduleClientWriteIV},
+		{"TLS12_AES_CBC", VersionTLS12, TLS_RSA_WITH_AES_128_CBC_SHA, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig := testConfig.Clone()
+			clientConfig.MinVersion = tt.version
+			clientConfig.MaxVersion = tt.version
+			clientConfig.CipherSuites = []uint16{tt.cipherSuite}
+			serverConfig := testConfig.Clone()
+			serverConfig.CipherSuites = []uint16{tt.cipherSuite}
+
+			recorder := NewHandshakeRecorder()
+			trace := NewKeyScheduleTrace()
+			c, s := localPipe(t)
+			errChan := make(chan error, 1)
+			go func() {
+				defer s.Close()
+				errChan <- Server(s, serverConfig).Handshake()
+			}()
+			uconn := UClient(c, clientConfig, HelloGolang)
+			uconn.SetHandshakeRecorder(recorder)
+			uconn.SetKeyScheduleObserver(trace)
+			err := uconn.Handshake()
+			c.Close()
+			if serverErr := <-errChan; serverErr != nil {
+				t.Fatalf("server: %v", serverErr)
+			}
+			if err != nil {
+				t.Fatalf("client: %v", err)
+			}
+
+			var first *RecordedRecord
+			for _, rec := range recorder.Records() {
+				if rec.Direction == RecordSent && rec.Encrypted {
+					first = &rec
+					break
+				}
+				if !rec.Encrypted && (rec.SequenceNumber != 0 || rec.Nonce != nil || rec.PaddingLength != 0) {
+					t.Errorf("unprotected record has protection details: %+v", rec)
+				}
+			}
+			if first == nil {
+				t.Fatal("no protected record was sent")
+			}
+			// The client Finished is the first record under new keys.
+			if first.ContentType != RecordTypeHandshake || first.SequenceNumber != 0 {
+				t.Errorf("first protected record: type %d, sequence number %d", first.ContentType, first.SequenceNumber)
+			}
+
+			if tt.writeIV == "" {
+				// CBC: explicit IV, ciphertext, MAC and padding fill the payload.
+				if first.Nonce != nil {
+					t.Errorf("Nonce = %x, want nil for CBC", first.Nonce)
+				}
+				payloadLen := len(first.Raw) - recordHeaderLen
+				if first.PaddingLength < 1 || first.PaddingLength > 16 || (payloadLen-first.PaddingLength-len(first.Plaintext)-20)%16 != 0 {
+					t.Errorf("PaddingLength = %d for a %d byte payload", first.PaddingLength, payloadLen)
+				}
+				return
+			}
+
+			iv := trace.Step(tt.writeIV)
+			if tt.cipherSuite == TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+				// RFC 5288, Section 3: salt || explicit nonce
+				want := append(append([]byte(nil), iv...), first.Raw[recordHeaderLen:recordHeaderLen+8]...)
+				if !bytes.Equal(first.Nonce, want) {
+					t.Errorf("Nonce = %x, want %x", first.Nonce, want)
+				}
+			} else if !bytes.Equal(first.Nonce, iv) {
+				// RFC 8446, Section 5.3: iv XOR sequence number 0
+				t.Errorf("Nonce = %x, want %x", first.Nonce, iv)
+			}
+			if first.PaddingLength != 0 {
+				t.Errorf("PaddingLength = %d, want 0", first.PaddingLength)
+			}
+		})
+	}
+}
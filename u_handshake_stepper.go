@@ -0,0 +1,155 @@
+package tls
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+)
+
+// HandshakeStep is a single handshake message reported by HandshakeStepper.
+// The handshake is paused right after a received message was read, or right
+// before a message is written to the network.
+type HandshakeStep struct {
+	Message RecordedMessage
+
+	// KeySchedule holds every key schedule step derived so far, in order.
+	KeySchedule []KeyScheduleStep
+}
+
+// HandshakeStepper drives the client handshake of a UConn one handshake
+// message at a time, so that each message and the key schedule state at
+// that point can be inspected before the handshake continues.
+//
+// The stepper records the connection with its own HandshakeRecorder and
+// KeyScheduleTrace, replacing any already attached to the UConn.
+type HandshakeStepper struct {
+	uconn    *UConn
+	recorder *HandshakeRecorder
+	trace    *KeyScheduleTrace
+
+	mu      sync.Mutex // serializes Next
+	started bool
+	paused  bool
+
+	steps  chan HandshakeStep
+	resume chan struct{}
+
+	done chan struct{} // closed when the handshake returns
+	err  error         // valid after done is closed
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewHandshakeStepper returns a stepper for uconn. The handshake does not
+// start until the first call to Next.
+func NewHandshakeStepper(uconn *UConn) *HandshakeStepper {
+	s := &HandshakeStepper{
+		uconn:    uconn,
+		recorder: NewHandshakeRecorder(),
+		trace:    NewKeyScheduleTrace(),
+		steps:    make(chan HandshakeStep),
+		resume:   make(chan struct{}),
+		done:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	s.recorder.setOnMessage(s.pause)
+	uconn.SetHandshakeRecorder(s.recorder)
+	uconn.SetKeyScheduleObserver(s.trace)
+	return s
+}
+
+// Next resumes the handshake and blocks until the next handshake message is
+// sent or received. It returns io.EOF once the handshake has completed, or
+// the error the handshake failed with.
+func (s *HandshakeStepper) Next(ctx context.Context) (*HandshakeStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		s.started = true
+		go s.run()
+	}
+	if s.paused {
+		select {
+		case s.resume <- struct{}{}:
+			s.paused = false
+		case <-s.closed:
+			return nil, net.ErrClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	select {
+	case step := <-s.steps:
+		s.paused = true
+		return &step, nil
+	case <-s.done:
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	case <-s.closed:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close aborts the handshake if it is still running and closes the
+// underlying connection.
+func (s *HandshakeStepper) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.uconn.NetConn().Close()
+	})
+	return err
+}
+
+// HandshakeFailure returns UConn.HandshakeFailure once the handshake has
+// returned, or nil while it is still running.
+func (s *HandshakeStepper) HandshakeFailure() *HandshakeFailure {
+	select {
+	case <-s.done:
+		return s.uconn.HandshakeFailure()
+	default:
+		return nil
+	}
+}
+
+// Recorder returns the recorder capturing the handshake.
+func (s *HandshakeStepper) Recorder() *HandshakeRecorder {
+	return s.recorder
+}
+
+// Trace returns the key schedule trace of the handshake.
+func (s *HandshakeStepper) Trace() *KeyScheduleTrace {
+	return s.trace
+}
+
+func (s *HandshakeStepper) run() {
+	err := s.uconn.Handshake()
+	// Messages exchanged after the handshake, such as NewSessionTicket,
+	// must not block application reads.
+	s.recorder.setOnMessage(nil)
+	s.err = err
+	close(s.done)
+}
+
+// pause is called by the recorder for every handshake message and blocks
+// the handshake until Next is called again.
+func (s *HandshakeStepper) pause(msg RecordedMessage) {
+	step := HandshakeStep{Message: msg, KeySchedule: s.trace.Steps()}
+	select {
+	case s.steps <- step:
+	case <-s.closed:
+		return
+	}
+	select {
+	case <-s.resume:
+	case <-s.closed:
+	}
+}
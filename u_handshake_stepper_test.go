@@ -0,0 +1,125 @@
+package tls
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestUTLSHandshakeStepper(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.MinVersion = VersionTLS13
+
+	c, s := localPipe(t)
+	errChan := make(chan error, 1)
+	go func() {
+		defer s.Close()
+		errChan <- Server(s, testConfig.Clone()).Handshake()
+	}()
+
+	stepper := NewHandshakeStepper(UClient(c, clientConfig, HelloGolang))
+	defer stepper.Close()
+
+	var got []string
+	var keySchedule []KeyScheduleStep
+	for {
+		step, err := stepper.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if len(step.KeySchedule) < len(keySchedule) {
+			t.Errorf("key schedule shrank from %d to %d steps", len(keySchedule), len(step.KeySchedule))
+		}
+		keySchedule = step.KeySchedule
+		got = append(got, step.Message.Direction.String()+" "+handshakeTypeName(step.Message.Type))
+
+		// Handshake keys are installed before EncryptedExtensions is read.
+		if step.Message.Type == HandshakeTypeEncryptedExtensions && !hasKeyScheduleStep(step.KeySchedule, KeyScheduleServerHandshakeTrafficSecret) {
+			t.Errorf("%s missing at EncryptedExtensions", KeyScheduleServerHandshakeTrafficSecret)
+		}
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+
+	want := []string{
+		"sent client_hello",
+		"received server_hello",
+		"received encrypted_extensions",
+		"received certificate",
+		"received certificate_verify",
+		"received finished",
+		"sent finished",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %q, want %q", got, want)
+	}
+	if !hasKeyScheduleStep(keySchedule, KeyScheduleClientApplicationTrafficSecret) {
+		t.Errorf("%s missing at the last step", KeyScheduleClientApplicationTrafficSecret)
+	}
+	if n := len(stepper.Recorder().Messages()); n != len(want) {
+		t.Errorf("recorded %d messages, want %d", n, len(want))
+	}
+
+	// Once complete, Next keeps reporting io.EOF.
+	if _, err := stepper.Next(context.Background()); err != io.EOF {
+		t.Errorf("Next after completion: %v, want io.EOF", err)
+	}
+}
+
+func TestUTLSHandshakeStepperClose(t *testing.T) {
+	c, s := localPipe(t)
+	go func() {
+		defer s.Close()
+		Server(s, testConfig.Clone()).Handshake()
+	}()
+
+	stepper := NewHandshakeStepper(UClient(c, testConfig.Clone(), HelloGolang))
+	step, err := stepper.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if step.Message.Type != HandshakeTypeClientHello {
+		t.Fatalf("first step is type %d, want ClientHello", step.Message.Type)
+	}
+
+	if err := stepper.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := stepper.Next(context.Background()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Next after Close: %v, want net.ErrClosed", err)
+	}
+}
+
+func hasKeyScheduleStep(steps []KeyScheduleStep, name string) bool {
+	for _, step := range steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func handshakeTypeName(typ uint8) string {
+	switch typ {
+	case HandshakeTypeClientHello:
+		return "client_hello"
+	case HandshakeTypeServerHello:
+		return "server_hello"
+	case HandshakeTypeEncryptedExtensions:
+		return "encrypted_extensions"
+	case HandshakeTypeCertificate:
+		return "certificate"
+	case HandshakeTypeCertificateVerify:
+		return "certificate_verify"
+	case HandshakeTypeFinished:
+		return "finished"
+	}
+	return "unknown"
+}
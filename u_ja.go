@@ -0,0 +1,400 @@
+package tls
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// JA3 is the JA3 fingerprint of a ClientHello, see
+// https://github.com/salesforce/ja3.
+type JA3 struct {
+	// String is the fingerprint string
+	// "SSLVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats"
+	// with decimal values and GREASE values removed.
+	String string
+	// Hash is the hex encoded MD5 hash of String.
+	Hash string
+}
+
+// JA4 is the JA4 fingerprint of a ClientHello, see
+// https://github.com/FoxIO-LLC/ja4/blob/main/technical_details/JA4.md.
+type JA4 struct {
+	// Fingerprint is JA4, e.g. "t13d1516h2_8daaf6152771_e5627efa2ab1".
+	Fingerprint string
+	// Raw is JA4_r, the sorted cipher suites, extensions and signature
+	// algorithms that Fingerprint hashes.
+	Raw string
+}
+
+// jaClientHello holds the fields of a ClientHello that JA3 and JA4 are
+// computed from. GREASE values are kept and skipped by the fingerprints.
+type jaClientHello struct {
+	vers                uint16
+	cipherSuites        []uint16
+	extensions          []uint16
+	curves              []uint16
+	points              []uint8
+	signatureAlgorithms []uint16
+	supportedVersions   []uint16
+	alpn                []byte
+}
+
+// JA3 computes the JA3 fingerprint of the ClientHello in chm.Raw, or of
+// chm marshaled if Raw is empty.
+func (chm *PubClientHelloMsg) JA3() (JA3, error) {
+	hello, err := chm.jaClientHello()
+	if err != nil {
+		return JA3{}, err
+	}
+	return hello.ja3(), nil
+}
+
+// JA4 computes the JA4 and JA4_r fingerprints of the ClientHello in chm.Raw,
+// or of chm marshaled if Raw is empty.
+func (chm *PubClientHelloMsg) JA4() (JA4, error) {
+	hello, err := chm.jaClientHello()
+	if err != nil {
+		return JA4{}, err
+	}
+	return hello.ja4(), nil
+}
+
+func (chm *PubClientHelloMsg) jaClientHello() (*jaClientHello, error) {
+	raw := chm.Raw
+	if len(raw) == 0 {
+		var err error
+		if raw, err = chm.Marshal(); err != nil {
+			return nil, err
+		}
+	}
+	return parseJAClientHello(raw)
+}
+
+// JA3 computes the JA3 fingerprint of a ClientHello built from chs.
+//
+// The fingerprint is computed from the spec without applying it, so an
+// UtlsPaddingExtension is always counted even though BoringPaddingStyle
+// omits it from ClientHellos that need no padding. Use
+// UConn.HandshakeState.Hello after BuildHandshakeState for the fingerprint
+// of the exact ClientHello.
+func (chs *ClientHelloSpec) JA3() (JA3, error) {
+	hello, err := chs.jaClientHello()
+	if err != nil {
+		return JA3{}, err
+	}
+	return hello.ja3(), nil
+}
+
+// JA4 computes the JA4 and JA4_r fingerprints of a ClientHello built from
+// chs, with the same caveat about padding as JA3.
+func (chs *ClientHelloSpec) JA4() (JA4, error) {
+	hello, err := chs.jaClientHello()
+	if err != nil {
+		return JA4{}, err
+	}
+	return hello.ja4(), nil
+}
+
+func (chs *ClientHelloSpec) jaClientHello() (*jaClientHello, error) {
+	hello := &jaClientHello{cipherSuites: chs.CipherSuites}
+	maxVers := chs.TLSVersMax
+	for _, ext := range chs.Extensions {
+		id, ok := specExtensionID(ext)
+		if !ok {
+			return nil, fmt.Errorf("tls: unable to determine the type of extension %T", ext)
+		}
+		hello.extensions = append(hello.extensions, id)
+		switch e := ext.(type) {
+		case *SupportedCurvesExtension:
+			for _, c := range e.Curves {
+				hello.curves = append(hello.curves, uint16(c))
+			}
+		case *SupportedPointsExtension:
+			hello.points = e.SupportedPoints
+		case *SignatureAlgorithmsExtension:
+			for _, s := range e.SupportedSignatureAlgorithms {
+				hello.signatureAlgorithms = append(hello.signatureAlgorithms, uint16(s))
+			}
+		case *SupportedVersionsExtension:
+			hello.supportedVersions = e.Versions
+			for _, v := range e.Versions {
+				if !isGREASEUint16(v) && v > maxVers {
+					maxVers = v
+				}
+			}
+		case *ALPNExtension:
+			if len(e.AlpnProtocols) > 0 {
+				hello.alpn = []byte(e.AlpnProtocols[0])
+			}
+		}
+	}
+	// legacy_version is capped at TLS 1.2, as UConn does
+	hello.vers = VersionTLS12
+	if maxVers != 0 && maxVers < VersionTLS12 {
+		hello.vers = maxVers
+	}
+	return hello, nil
+}
+
+// specExtensionID returns the extension type that ext is marshaled with.
+// GREASE extensions return GREASE_PLACEHOLDER.
+func specExtensionID(ext TLSExtension) (uint16, bool) {
+	switch e := ext.(type) {
+	case *UtlsGREASEExtension:
+		return GREASE_PLACEHOLDER, true
+	case *GenericExtension:
+		return e.Id, true
+	case *SNIExtension:
+		// marshaled only with a server name, which UConn takes from Config
+		return extensionServerName, true
+	case *UtlsPaddingExtension:
+		return utlsExtensionPadding, true
+	case *GREASEEncryptedClientHelloExtension:
+		return utlsExtensionECH, true
+	case *UtlsPreSharedKeyExtension, *FakePreSharedKeyExtension:
+		return extensionPreSharedKey, true
+	case *FakeChannelIDExtension:
+		if e.OldExtensionID {
+			return fakeOldExtensionChannelID, true
+		}
+		return fakeExtensionChannelID, true
+	}
+	// the other extensions can be marshaled before the spec is applied
+	buf := make([]byte, ext.Len())
+	if len(buf) < 4 {
+		return 0, false
+	}
+	if _, err := ext.Read(buf); err != nil && !errors.Is(err, io.EOF) {
+		return 0, false
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), true
+}
+
+// parseJAClientHello parses a ClientHello handshake message or a TLS record
+// containing one.
+func parseJAClientHello(raw []byte) (*jaClientHello, error) {
+	if len(raw) > 5 && recordType(raw[0]) == recordTypeHandshake && raw[5] == typeClientHello {
+		raw = raw[5:]
+	}
+	s := cryptobyte.String(raw)
+	var msgType uint8
+	var body cryptobyte.String
+	if !s.ReadUint8(&msgType) || msgType != typeClientHello || !s.ReadUint24LengthPrefixed(&body) {
+		return nil, errors.New("tls: not a ClientHello handshake message")
+	}
+
+	hello := &jaClientHello{}
+	var random, sessionID, cipherSuites, compression cryptobyte.String
+	if !body.ReadUint16(&hello.vers) || !body.ReadBytes((*[]byte)(&random), 32) ||
+		!body.ReadUint8LengthPrefixed(&sessionID) || !body.ReadUint16LengthPrefixed(&cipherSuites) ||
+		!body.ReadUint8LengthPrefixed(&compression) {
+		return nil, errors.New("tls: malformed ClientHello")
+	}
+	for !cipherSuites.Empty() {
+		var suite uint16
+		if !cipherSuites.ReadUint16(&suite) {
+			return nil, errors.New("tls: malformed ClientHello cipher suites")
+		}
+		hello.cipherSuites = append(hello.cipherSuites, suite)
+	}
+	if body.Empty() {
+		return hello, nil
+	}
+
+	var extensions cryptobyte.String
+	if !body.ReadUint16LengthPrefixed(&extensions) {
+		return nil, errors.New("tls: malformed ClientHello extensions")
+	}
+	for !extensions.Empty() {
+		var id uint16
+		var data cryptobyte.String
+		if !extensions.ReadUint16(&id) || !extensions.ReadUint16LengthPrefixed(&data) {
+			return nil, errors.New("tls: malformed ClientHello extensions")
+		}
+		hello.extensions = append(hello.extensions, id)
+
+		var ok = true
+		switch id {
+		case extensionSupportedCurves:
+			hello.curves, ok = readJAUint16List(&data, 2)
+		case extensionSupportedPoints:
+			var points cryptobyte.String
+			ok = data.ReadUint8LengthPrefixed(&points)
+			hello.points = points
+		case extensionSignatureAlgorithms:
+			hello.signatureAlgorithms, ok = readJAUint16List(&data, 2)
+		case extensionSupportedVersions:
+			hello.supportedVersions, ok = readJAUint16List(&data, 1)
+		case extensionALPN:
+			var protocols, proto cryptobyte.String
+			ok = data.ReadUint16LengthPrefixed(&protocols) && (protocols.Empty() || protocols.ReadUint8LengthPrefixed(&proto))
+			hello.alpn = proto
+		}
+		if !ok {
+			return nil, fmt.Errorf("tls: malformed ClientHello extension %d", id)
+		}
+	}
+	return hello, nil
+}
+
+// readJAUint16List reads a list of uint16 values with a length prefix of
+// prefixLen bytes.
+func readJAUint16List(s *cryptobyte.String, prefixLen int) ([]uint16, bool) {
+	var list cryptobyte.String
+	if prefixLen == 1 && !s.ReadUint8LengthPrefixed(&list) || prefixLen == 2 && !s.ReadUint16LengthPrefixed(&list) {
+		return nil, false
+	}
+	var values []uint16
+	for !list.Empty() {
+		var v uint16
+		if !list.ReadUint16(&v) {
+			return nil, false
+		}
+		values = append(values, v)
+	}
+	return values, true
+}
+
+func (h *jaClientHello) ja3() JA3 {
+	points := make([]uint16, len(h.points))
+	for i, p := range h.points {
+		points[i] = uint16(p)
+	}
+	s := strings.Join([]string{
+		strconv.Itoa(int(h.vers)),
+		jaDecimalList(h.cipherSuites),
+		jaDecimalList(h.extensions),
+		jaDecimalList(h.curves),
+		jaDecimalList(points),
+	}, ",")
+	hash := md5.Sum([]byte(s))
+	return JA3{String: s, Hash: hex.EncodeToString(hash[:])}
+}
+
+func (h *jaClientHello) ja4() JA4 {
+	protocol := "t"
+	if slices.Contains(h.extensions, extensionQUICTransportParameters) {
+		protocol = "q"
+	}
+	vers := h.vers
+	for _, v := range h.supportedVersions {
+		if !isGREASEUint16(v) && (vers == h.vers || v > vers) {
+			vers = v
+		}
+	}
+	sni := "i"
+	if slices.Contains(h.extensions, extensionServerName) {
+		sni = "d"
+	}
+
+	ciphers := jaHexList(h.cipherSuites, true)
+	// server_name and ALPN are represented in the first part only
+	var exts []string
+	extCount := 0
+	for _, e := range h.extensions {
+		if isGREASEUint16(e) {
+			continue
+		}
+		extCount++
+		if e != extensionServerName && e != extensionALPN {
+			exts = append(exts, fmt.Sprintf("%04x", e))
+		}
+	}
+	slices.Sort(exts)
+
+	a := fmt.Sprintf("%s%s%s%02d%02d%s", protocol, ja4Version(vers), sni, min(len(ciphers), 99), min(extCount, 99), ja4ALPN(h.alpn))
+	b := strings.Join(ciphers, ",")
+	c := strings.Join(exts, ",")
+	if len(h.signatureAlgorithms) > 0 {
+		c += "_" + strings.Join(jaHexList(h.signatureAlgorithms, false), ",")
+	}
+	return JA4{
+		Fingerprint: a + "_" + ja4Hash(b, len(ciphers) == 0) + "_" + ja4Hash(c, len(exts) == 0),
+		Raw:         a + "_" + b + "_" + c,
+	}
+}
+
+// jaDecimalList returns values without GREASE in decimal, joined by "-".
+func jaDecimalList(values []uint16) string {
+	var parts []string
+	for _, v := range values {
+		if !isGREASEUint16(v) {
+			parts = append(parts, strconv.Itoa(int(v)))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// jaHexList returns values without GREASE as 4 digit hex strings, sorted if
+// sorted is set.
+func jaHexList(values []uint16, sorted bool) []string {
+	var list []string
+	for _, v := range values {
+		if !isGREASEUint16(v) {
+			list = append(list, fmt.Sprintf("%04x", v))
+		}
+	}
+	if sorted {
+		slices.Sort(list)
+	}
+	return list
+}
+
+func ja4Hash(s string, empty bool) string {
+	if empty {
+		return "000000000000"
+	}
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+func ja4Version(vers uint16) string {
+	switch vers {
+	case VersionTLS13:
+		return "13"
+	case VersionTLS12:
+		return "12"
+	case VersionTLS11:
+		return "11"
+	case VersionTLS10:
+		return "10"
+	case VersionSSL30:
+		return "s3"
+	case 0x0200:
+		return "s2"
+	case 0xfeff:
+		return "d1"
+	case 0xfefd:
+		return "d2"
+	case 0xfefc:
+		return "d3"
+	}
+	return "00"
+}
+
+// ja4ALPN returns the first and last characters of the first ALPN protocol,
+// or of its hex encoding if either is not alphanumeric.
+func ja4ALPN(alpn []byte) string {
+	if len(alpn) == 0 {
+		return "00"
+	}
+	first, last := alpn[0], alpn[len(alpn)-1]
+	if !isAlphanumeric(first) || !isAlphanumeric(last) {
+		h := hex.EncodeToString(alpn)
+		return h[:1] + h[len(h)-1:]
+	}
+	return string([]byte{first, last})
+}
+
+func isAlphanumeric(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
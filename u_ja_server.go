@@ -0,0 +1,259 @@
+package tls
+
+import (
+	"crypto/md5"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// JA3S is the JA3S fingerprint of a ServerHello, see
+// https://github.com/salesforce/ja3.
+type JA3S struct {
+	// String is the fingerprint string "SSLVersion,Cipher,Extensions" with
+	// decimal values.
+	String string
+	// Hash is the hex encoded MD5 hash of String.
+	Hash string
+}
+
+// JA4S is the JA4S fingerprint of a ServerHello, see
+// https://github.com/FoxIO-LLC/ja4/blob/main/technical_details/JA4S.md.
+type JA4S struct {
+	// Fingerprint is JA4S, e.g. "t130200_1301_234ea6891581".
+	Fingerprint string
+	// Raw is JA4S_r, with the extensions in the order the server sent them.
+	Raw string
+}
+
+// JA4X is the JA4X fingerprint of a certificate, see
+// https://github.com/FoxIO-LLC/ja4/blob/main/technical_details/JA4X.md.
+type JA4X struct {
+	// Fingerprint is JA4X, the hashes of the issuer attribute, subject
+	// attribute and extension OIDs, e.g. "a373a9f83c6b_2bab15409345_7bf9a7bf7029".
+	Fingerprint string
+	// Raw is JA4X_r, the hex encoded OIDs that Fingerprint hashes.
+	Raw string
+}
+
+// jaServerHello holds the fields of a ServerHello that JA3S and JA4S are
+// computed from.
+type jaServerHello struct {
+	vers             uint16
+	cipherSuite      uint16
+	extensions       []uint16
+	supportedVersion uint16
+	alpn             []byte
+}
+
+// JA3S computes the JA3S fingerprint of the ServerHello in shm.Raw, or of
+// shm marshaled if Raw is empty.
+func (shm *PubServerHelloMsg) JA3S() (JA3S, error) {
+	hello, err := shm.jaServerHello()
+	if err != nil {
+		return JA3S{}, err
+	}
+	return hello.ja3s(), nil
+}
+
+// JA4S computes the JA4S and JA4S_r fingerprints of the ServerHello in
+// shm.Raw, or of shm marshaled if Raw is empty. A ServerHello does not tell
+// whether it was sent over QUIC, so the protocol is always "t";
+// ConnectionState.JA4S reports "q" for QUIC connections.
+func (shm *PubServerHelloMsg) JA4S() (JA4S, error) {
+	hello, err := shm.jaServerHello()
+	if err != nil {
+		return JA4S{}, err
+	}
+	return hello.ja4s(false), nil
+}
+
+func (shm *PubServerHelloMsg) jaServerHello() (*jaServerHello, error) {
+	raw := shm.Raw
+	if len(raw) == 0 {
+		var err error
+		if raw, err = shm.getPrivatePtr().marshal(); err != nil {
+			return nil, err
+		}
+	}
+	return parseJAServerHello(raw)
+}
+
+// parseJAServerHello parses a ServerHello handshake message or a TLS record
+// containing one.
+func parseJAServerHello(raw []byte) (*jaServerHello, error) {
+	if len(raw) > 5 && recordType(raw[0]) == recordTypeHandshake && raw[5] == typeServerHello {
+		raw = raw[5:]
+	}
+	s := cryptobyte.String(raw)
+	var msgType uint8
+	var body cryptobyte.String
+	if !s.ReadUint8(&msgType) || msgType != typeServerHello || !s.ReadUint24LengthPrefixed(&body) {
+		return nil, errors.New("tls: not a ServerHello handshake message")
+	}
+
+	hello := &jaServerHello{}
+	var random, sessionID cryptobyte.String
+	var compression uint8
+	if !body.ReadUint16(&hello.vers) || !body.ReadBytes((*[]byte)(&random), 32) ||
+		!body.ReadUint8LengthPrefixed(&sessionID) || !body.ReadUint16(&hello.cipherSuite) ||
+		!body.ReadUint8(&compression) {
+		return nil, errors.New("tls: malformed ServerHello")
+	}
+	if body.Empty() {
+		return hello, nil
+	}
+
+	var extensions cryptobyte.String
+	if !body.ReadUint16LengthPrefixed(&extensions) {
+		return nil, errors.New("tls: malformed ServerHello extensions")
+	}
+	for !extensions.Empty() {
+		var id uint16
+		var data cryptobyte.String
+		if !extensions.ReadUint16(&id) || !extensions.ReadUint16LengthPrefixed(&data) {
+			return nil, errors.New("tls: malformed ServerHello extensions")
+		}
+		hello.extensions = append(hello.extensions, id)
+
+		var ok = true
+		switch id {
+		case extensionSupportedVersions:
+			ok = data.ReadUint16(&hello.supportedVersion)
+		case extensionALPN:
+			var protocols, proto cryptobyte.String
+			ok = data.ReadUint16LengthPrefixed(&protocols) && protocols.ReadUint8LengthPrefixed(&proto)
+			hello.alpn = proto
+		}
+		if !ok {
+			return nil, fmt.Errorf("tls: malformed ServerHello extension %d", id)
+		}
+	}
+	return hello, nil
+}
+
+func (h *jaServerHello) ja3s() JA3S {
+	s := strings.Join([]string{
+		strconv.Itoa(int(h.vers)),
+		strconv.Itoa(int(h.cipherSuite)),
+		jaDecimalList(h.extensions),
+	}, ",")
+	hash := md5.Sum([]byte(s))
+	return JA3S{String: s, Hash: hex.EncodeToString(hash[:])}
+}
+
+func (h *jaServerHello) ja4s(quic bool) JA4S {
+	protocol := "t"
+	if quic {
+		protocol = "q"
+	}
+	vers := h.vers
+	if h.supportedVersion != 0 {
+		vers = h.supportedVersion
+	}
+	exts := jaHexList(h.extensions, false)
+
+	a := fmt.Sprintf("%s%s%02d%s", protocol, ja4Version(vers), min(len(exts), 99), ja4ALPN(h.alpn))
+	b := fmt.Sprintf("%04x", h.cipherSuite)
+	c := strings.Join(exts, ",")
+	return JA4S{
+		Fingerprint: a + "_" + b + "_" + ja4Hash(c, len(exts) == 0),
+		Raw:         a + "_" + b + "_" + c,
+	}
+}
+
+// CertificateJA4X computes the JA4X fingerprint of cert from the OIDs of its
+// issuer and subject attributes and of its extensions, in the order they
+// appear in the certificate.
+func CertificateJA4X(cert *x509.Certificate) (JA4X, error) {
+	issuer, err := nameOIDs(cert.RawIssuer)
+	if err != nil {
+		return JA4X{}, fmt.Errorf("tls: malformed certificate issuer: %w", err)
+	}
+	subject, err := nameOIDs(cert.RawSubject)
+	if err != nil {
+		return JA4X{}, fmt.Errorf("tls: malformed certificate subject: %w", err)
+	}
+	var extensions []string
+	for _, ext := range cert.Extensions {
+		der, err := asn1.Marshal(ext.Id)
+		if err != nil {
+			return JA4X{}, err
+		}
+		// skip the tag and the length of the OBJECT IDENTIFIER
+		var oid cryptobyte.String
+		s := cryptobyte.String(der)
+		if !s.ReadASN1(&oid, cryptobyte_asn1.OBJECT_IDENTIFIER) {
+			return JA4X{}, errors.New("tls: malformed certificate extension")
+		}
+		extensions = append(extensions, hex.EncodeToString(oid))
+	}
+
+	parts := [][]string{issuer, subject, extensions}
+	var fingerprint, raw []string
+	for _, oids := range parts {
+		s := strings.Join(oids, ",")
+		fingerprint = append(fingerprint, ja4Hash(s, len(oids) == 0))
+		raw = append(raw, s)
+	}
+	return JA4X{
+		Fingerprint: strings.Join(fingerprint, "_"),
+		Raw:         strings.Join(raw, "_"),
+	}, nil
+}
+
+// nameOIDs returns the hex encoded attribute type OIDs of the DER encoded
+// Name, in order.
+func nameOIDs(der []byte) ([]string, error) {
+	var rdns cryptobyte.String
+	s := cryptobyte.String(der)
+	if !s.ReadASN1(&rdns, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("invalid Name")
+	}
+	var oids []string
+	for !rdns.Empty() {
+		var set cryptobyte.String
+		if !rdns.ReadASN1(&set, cryptobyte_asn1.SET) {
+			return nil, errors.New("invalid RelativeDistinguishedName")
+		}
+		for !set.Empty() {
+			var atv, oid cryptobyte.String
+			if !set.ReadASN1(&atv, cryptobyte_asn1.SEQUENCE) || !atv.ReadASN1(&oid, cryptobyte_asn1.OBJECT_IDENTIFIER) {
+				return nil, errors.New("invalid AttributeTypeAndValue")
+			}
+			oids = append(oids, hex.EncodeToString(oid))
+		}
+	}
+	return oids, nil
+}
+
+// utlsServerFingerprints computes the JA3S, JA4S and JA4X fingerprints of
+// the ServerHello and the certificates received by a client, once its
+// handshake has completed.
+func (c *Conn) utlsServerFingerprints() {
+	if !c.isClient || c.utls.serverHello == nil {
+		return
+	}
+	hello, err := parseJAServerHello(c.utls.serverHello)
+	c.utls.serverHello = nil
+	c.utls.ja3s, c.utls.ja4s, c.utls.ja4x = JA3S{}, JA4S{}, nil
+	if err == nil {
+		c.utls.ja3s = hello.ja3s()
+		c.utls.ja4s = hello.ja4s(c.quic != nil)
+	}
+	for _, cert := range c.peerCertificates {
+		ja4x, err := CertificateJA4X(cert)
+		if err != nil {
+			c.utls.ja4x = nil
+			return
+		}
+		c.utls.ja4x = append(c.utls.ja4x, ja4x)
+	}
+}
@@ -0,0 +1,131 @@
+package tls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerHelloJA(t *testing.T) {
+	hello := &PubServerHelloMsg{
+		Vers:             VersionTLS12,
+		Random:           make([]byte, 32),
+		CipherSuite:      TLS_AES_128_GCM_SHA256,
+		SupportedVersion: VersionTLS13,
+		ServerShare:      KeyShare{Group: X25519, Data: make([]byte, 32)},
+	}
+	ja3s, err := hello.JA3S()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "771,4865,43-51"; ja3s.String != want {
+		t.Errorf("JA3S = %q, want %q", ja3s.String, want)
+	}
+
+	ja4s, err := hello.JA4S()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "t130200_1301_a56c5b993250"; ja4s.Fingerprint != want {
+		t.Errorf("JA4S = %q, want %q", ja4s.Fingerprint, want)
+	}
+	if want := "t130200_1301_002b,0033"; ja4s.Raw != want {
+		t.Errorf("JA4S_r = %q, want %q", ja4s.Raw, want)
+	}
+
+	// JA4S of the example ServerHello in the JA4S technical details, with
+	// key_share sent before supported_versions
+	raw := []byte{typeServerHello, 0, 0, 0, 0x03, 0x03}
+	raw = append(raw, make([]byte, 32)...)
+	raw = append(raw, 0, 0x13, 0x01, 0, 0, 14, 0, 0x33, 0, 4, 0, 0x1d, 0, 0, 0, 0x2b, 0, 2, 0x03, 0x04)
+	raw[3] = byte(len(raw) - 4)
+	ja4s, err = (&PubServerHelloMsg{Raw: raw}).JA4S()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "t130200_1301_234ea6891581"; ja4s.Fingerprint != want {
+		t.Errorf("JA4S = %q, want %q", ja4s.Fingerprint, want)
+	}
+}
+
+func TestConnectionStateServerFingerprints(t *testing.T) {
+	for _, version := range []uint16{VersionTLS12, VersionTLS13} {
+		t.Run(VersionName(version), func(t *testing.T) {
+			clientConfig := testConfig.Clone()
+			clientConfig.MinVersion = version
+			clientConfig.MaxVersion = version
+			clientConfig.NextProtos = []string{"h2"}
+			serverConfig := testConfig.Clone()
+			serverConfig.NextProtos = []string{"h2"}
+
+			c, s := localPipe(t)
+			go func() {
+				defer s.Close()
+				Server(s, serverConfig).Handshake()
+			}()
+			defer c.Close()
+			uconn := UClient(c, clientConfig, HelloGolang)
+			if err := uconn.Handshake(); err != nil {
+				t.Fatal(err)
+			}
+			state := uconn.ConnectionState()
+			// The fingerprints are computed once, when the handshake completes.
+			if uconn.utls.serverHello != nil {
+				t.Error("the ServerHello is still kept after the handshake")
+			}
+			if again := uconn.ConnectionState(); again.JA3S != state.JA3S || again.JA4S != state.JA4S {
+				t.Errorf("second ConnectionState = %+v %+v, want %+v %+v", again.JA3S, again.JA4S, state.JA3S, state.JA4S)
+			}
+
+			want, err := uconn.HandshakeState.ServerHello.JA3S()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.JA3S != want {
+				t.Errorf("JA3S = %+v, want %+v", state.JA3S, want)
+			}
+			// TLS 1.3 servers send ALPN in EncryptedExtensions, which JA4S does not cover
+			prefix := map[uint16]string{VersionTLS12: "t12", VersionTLS13: "t13"}[version]
+			alpn := map[uint16]string{VersionTLS12: "h2", VersionTLS13: "00"}[version]
+			if !strings.HasPrefix(state.JA4S.Fingerprint, prefix) || state.JA4S.Fingerprint[5:7] != alpn {
+				t.Errorf("JA4S = %q, want %s with ALPN %s", state.JA4S.Fingerprint, prefix, alpn)
+			}
+
+			if len(state.JA4X) != len(state.PeerCertificates) {
+				t.Fatalf("got %d JA4X fingerprints for %d certificates", len(state.JA4X), len(state.PeerCertificates))
+			}
+			for i, cert := range state.PeerCertificates {
+				want, err := CertificateJA4X(cert)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if state.JA4X[i] != want {
+					t.Errorf("JA4X[%d] = %+v, want %+v", i, state.JA4X[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCertificateJA4X(t *testing.T) {
+	cert, err := testConfig.Certificates[0].leaf()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ja4x, err := CertificateJA4X(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(ja4x.Raw, "_")
+	if len(parts) != 3 {
+		t.Fatalf("JA4X_r = %q, want 3 parts", ja4x.Raw)
+	}
+	// O=Acme Co, CN=example.golang
+	if parts[1] != "55040a,550403" {
+		t.Errorf("subject OIDs = %q, want 55040a,550403", parts[1])
+	}
+	for i, part := range parts {
+		if got := ja4Hash(part, part == ""); got != strings.Split(ja4x.Fingerprint, "_")[i] {
+			t.Errorf("hash of %q = %s, want %s", part, got, ja4x.Fingerprint)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package tls
+
+import (
+	"net"
+	"testing"
+)
+
+// jaTestSpec is the Chrome ClientHello of the JA4 technical details, with
+// JA4 t13d1516h2_8daaf6152771_e5627efa2ab1.
+func jaTestSpec() *ClientHelloSpec {
+	return &ClientHelloSpec{
+		CipherSuites: []uint16{
+			GREASE_PLACEHOLDER,
+			TLS_AES_128_GCM_SHA256,
+			TLS_AES_256_GCM_SHA384,
+			TLS_CHACHA20_POLY1305_SHA256,
+			TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+			TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			TLS_RSA_WITH_AES_128_GCM_SHA256,
+			TLS_RSA_WITH_AES_256_GCM_SHA384,
+			TLS_RSA_WITH_AES_128_CBC_SHA,
+			TLS_RSA_WITH_AES_256_CBC_SHA,
+		},
+		CompressionMethods: []byte{compressionNone},
+		Extensions: []TLSExtension{
+			&UtlsGREASEExtension{},
+			&SNIExtension{},
+			&ExtendedMasterSecretExtension{},
+			&RenegotiationInfoExtension{Renegotiation: RenegotiateOnceAsClient},
+			&SupportedCurvesExtension{[]CurveID{GREASE_PLACEHOLDER, X25519, CurveP256, CurveP384}},
+			&SupportedPointsExtension{SupportedPoints: []byte{pointFormatUncompressed}},
+			&SessionTicketExtension{},
+			&ALPNExtension{AlpnProtocols: []string{"h2", "http/1.1"}},
+			&StatusRequestExtension{},
+			&SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: []SignatureScheme{
+				ECDSAWithP256AndSHA256,
+				PSSWithSHA256,
+				PKCS1WithSHA256,
+				ECDSAWithP384AndSHA384,
+				PSSWithSHA384,
+				PKCS1WithSHA384,
+				PSSWithSHA512,
+				PKCS1WithSHA512,
+			}},
+			&SCTExtension{},
+			&KeyShareExtension{[]KeyShare{
+				{Group: CurveID(GREASE_PLACEHOLDER), Data: []byte{0}},
+				{Group: X25519},
+			}},
+			&PSKKeyExchangeModesExtension{[]uint8{PskModeDHE}},
+			&SupportedVersionsExtension{[]uint16{GREASE_PLACEHOLDER, VersionTLS13, VersionTLS12}},
+			&UtlsCompressCertExtension{[]CertCompressionAlgo{CertCompressionBrotli}},
+			&ApplicationSettingsExtension{SupportedProtocols: []string{"h2"}},
+			&UtlsGREASEExtension{},
+			&UtlsPaddingExtension{GetPaddingLen: BoringPaddingStyle},
+		},
+	}
+}
+
+func TestClientHelloSpecJA(t *testing.T) {
+	spec := jaTestSpec()
+	ja3, err := spec.JA3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJA3 := "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53," +
+		"0-23-65281-10-11-35-16-5-13-18-51-45-43-27-17513-21,29-23-24,0"
+	if ja3.String != wantJA3 {
+		t.Errorf("JA3 = %q, want %q", ja3.String, wantJA3)
+	}
+	if len(ja3.Hash) != 32 {
+		t.Errorf("JA3 hash %q is not an MD5 hash", ja3.Hash)
+	}
+
+	ja4, err := spec.JA4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "t13d1516h2_8daaf6152771_e5627efa2ab1"; ja4.Fingerprint != want {
+		t.Errorf("JA4 = %q, want %q", ja4.Fingerprint, want)
+	}
+	wantRaw := "t13d1516h2_002f,0035,009c,009d,1301,1302,1303,c013,c014,c02b,c02c,c02f,c030,cca8,cca9_" +
+		"0005,000a,000b,000d,0012,0015,0017,001b,0023,002b,002d,0033,4469,ff01_0403,0804,0401,0503,0805,0501,0806,0601"
+	if ja4.Raw != wantRaw {
+		t.Errorf("JA4_r = %q, want %q", ja4.Raw, wantRaw)
+	}
+}
+
+func TestClientHelloMsgJA(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ClientHelloID
+	}{
+		{"Chrome 102", HelloChrome_102},
+		{"Chrome 120", HelloChrome_120},
+		{"Firefox 120", HelloFirefox_120},
+		{"iOS 14", HelloIOS_14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := UTLSIdToSpec(tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			uconn := UClient(&net.TCPConn{}, &Config{ServerName: "example.com"}, HelloCustom)
+			if err := uconn.ApplyPreset(&spec); err != nil {
+				t.Fatal(err)
+			}
+			if err := uconn.BuildHandshakeState(); err != nil {
+				t.Fatal(err)
+			}
+			hello := uconn.HandshakeState.Hello
+
+			// JA4 sorts the extensions, so it does not change with the extension shuffle
+			wantJA4, err := spec.JA4()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ja4, err := hello.JA4()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ja4 != wantJA4 {
+				t.Errorf("JA4 of the ClientHello = %+v, want %+v", ja4, wantJA4)
+			}
+
+			// without Raw, the fingerprints are computed from the marshaled message
+			parsed := UnmarshalClientHello(hello.Raw)
+			if parsed == nil {
+				t.Fatal("failed to unmarshal the ClientHello")
+			}
+			parsed.Raw = nil
+			parsedJA4, err := parsed.JA4()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsedJA4.Fingerprint[:4] != wantJA4.Fingerprint[:4] || parsedJA4.Fingerprint[11:23] != wantJA4.Fingerprint[11:23] {
+				t.Errorf("JA4 of the marshaled ClientHello = %s, want cipher suites of %s", parsedJA4.Fingerprint, wantJA4.Fingerprint)
+			}
+		})
+	}
+}
+
+func TestJA4ALPN(t *testing.T) {
+	tests := []struct {
+		alpn string
+		want string
+	}{
+		{"", "00"},
+		{"h2", "h2"},
+		{"http/1.1", "h1"},
+		{"h", "hh"},
+		{"\xab", "ab"},
+		{"h2\xff", "6f"},
+	}
+	for _, tt := range tests {
+		if got := ja4ALPN([]byte(tt.alpn)); got != tt.want {
+			t.Errorf("ja4ALPN(%q) = %q, want %q", tt.alpn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tls
+
+import (
+	"hash"
+	"sync"
+)
+
+// Names of the values reported to a KeyScheduleObserver, following the
+// terminology of RFC 8446, Section 7. TLS 1.2 connections report the values
+// of RFC 5246, Sections 6.3 and 8.1 instead.
+const (
+	// KeyScheduleSharedSecret is the (EC)DHE shared secret fed into the
+	// handshake secret. For hybrid post-quantum groups it is the
+	// concatenation of both shared secrets.
+	KeyScheduleSharedSecret = "shared_secret"
+
+	KeyScheduleEarlySecret     = "early_secret"
+	KeyScheduleBinderKey       = "binder_key"
+	KeyScheduleHandshakeSecret = "handshake_secret"
+	KeyScheduleMasterSecret    = "master_secret"
+
+	KeyScheduleClientHandshakeTrafficSecret = "client_handshake_traffic_secret"
+	KeyScheduleClientHandshakeWriteKey      = "client_handshake_write_key"
+	KeyScheduleClientHandshakeWriteIV       = "client_handshake_write_iv"
+	KeyScheduleServerHandshakeTrafficSecret = "server_handshake_traffic_secret"
+	KeyScheduleServerHandshakeWriteKey      = "server_handshake_write_key"
+	KeyScheduleServerHandshakeWriteIV       = "server_handshake_write_iv"
+
+	KeyScheduleServerFinishedKey = "server_finished_key"
+	KeyScheduleClientFinishedKey = "client_finished_key"
+
+	KeyScheduleClientApplicationTrafficSecret = "client_application_traffic_secret_0"
+	KeyScheduleClientApplicationWriteKey      = "client_application_write_key"
+	KeyScheduleClientApplicationWriteIV       = "client_application_write_iv"
+	KeyScheduleServerApplicationTrafficSecret = "server_application_traffic_secret_0"
+	KeyScheduleServerApplicationWriteKey      = "server_application_write_key"
+	KeyScheduleServerApplicationWriteIV       = "server_application_write_iv"
+
+	KeyScheduleExporterMasterSecret   = "exporter_master_secret"
+	KeyScheduleResumptionMasterSecret = "resumption_master_secret"
+
+	// KeySchedulePreMasterSecret is the TLS 1.2 pre_master_secret: the
+	// ECDHE shared secret, or the random value encrypted to the server's
+	// RSA key. TLS 1.2 reports KeyScheduleMasterSecret after it, with the
+	// session hash as TranscriptHash if extended_master_secret was
+	// negotiated (RFC 7627).
+	KeySchedulePreMasterSecret = "pre_master_secret"
+
+	// The TLS 1.2 key block, split as in RFC 5246, Section 6.3. The MAC keys
+	// are only reported for cipher suites that use them.
+	KeyScheduleClientWriteMACKey = "client_write_mac_key"
+	KeyScheduleServerWriteMACKey = "server_write_mac_key"
+	KeyScheduleClientWriteKey    = "client_write_key"
+	KeyScheduleServerWriteKey    = "server_write_key"
+	KeyScheduleClientWriteIV     = "client_write_iv"
+	KeyScheduleServerWriteIV     = "server_write_iv"
+)
+
+// KeyScheduleStep is a single value derived by the key schedule.
+type KeyScheduleStep struct {
+	// Name identifies the value, see the KeySchedule* constants.
+	Name string
+
+	// Value is the secret, key or IV itself.
+	Value []byte
+
+	// TranscriptHash is the hash of the handshake transcript at the point
+	// the value was derived, or nil if the derivation does not depend on
+	// the transcript.
+	TranscriptHash []byte
+}
+
+// KeyScheduleObserver is notified of every step of the key schedule
+// performed by a client connection, in the order the steps happen. Both TLS
+// 1.3 and TLS 1.2 handshakes are reported.
+//
+// The reported values allow decrypting the whole connection. Observers are
+// meant for debugging and teaching, and must never be attached to
+// connections carrying sensitive data.
+type KeyScheduleObserver interface {
+	ObserveKeySchedule(step KeyScheduleStep)
+}
+
+// KeyScheduleTrace is a KeyScheduleObserver that stores every step it is
+// notified of.
+type KeyScheduleTrace struct {
+	mu    sync.Mutex
+	steps []KeyScheduleStep
+}
+
+// NewKeyScheduleTrace returns an empty KeyScheduleTrace.
+func NewKeyScheduleTrace() *KeyScheduleTrace {
+	return &KeyScheduleTrace{}
+}
+
+// ObserveKeySchedule implements KeyScheduleObserver.
+func (t *KeyScheduleTrace) ObserveKeySchedule(step KeyScheduleStep) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, step)
+}
+
+// Steps returns a copy of the steps recorded so far.
+func (t *KeyScheduleTrace) Steps() []KeyScheduleStep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]KeyScheduleStep(nil), t.steps...)
+}
+
+// Step returns the value of the first recorded step with the given name, or
+// nil if there is none.
+func (t *KeyScheduleTrace) Step(name string) []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.steps {
+		if s.Name == name {
+			return s.Value
+		}
+	}
+	return nil
+}
+
+// SetKeyScheduleObserver attaches o to the connection. It must be called
+// before the handshake starts. Passing nil disables observation.
+func (uconn *UConn) SetKeyScheduleObserver(o KeyScheduleObserver) {
+	uconn.utls.keyScheduleObserver = o
+}
+
+// observeKeySchedule reports a key schedule step. transcript may be nil.
+func (c *Conn) observeKeySchedule(name string, value []byte, transcript hash.Hash) {
+	if c.utls.keyScheduleObserver == nil {
+		return
+	}
+	var transcriptHash []byte
+	if transcript != nil {
+		transcriptHash = transcript.Sum(nil)
+	}
+	c.observeKeyScheduleSum(name, value, transcriptHash)
+}
+
+// observeKeyScheduleSum is like observeKeySchedule, for callers that
+// already hold the transcript hash, such as the TLS 1.2 session hash.
+func (c *Conn) observeKeyScheduleSum(name string, value, transcriptHash []byte) {
+	if c.utls.keyScheduleObserver == nil {
+		return
+	}
+	c.utls.keyScheduleObserver.ObserveKeySchedule(KeyScheduleStep{
+		Name:           name,
+		Value:          append([]byte(nil), value...),
+		TranscriptHash: append([]byte(nil), transcriptHash...),
+	})
+}
+
+// observeTrafficSecret reports a traffic secret followed by the write key
+// and IV derived from it.
+func (c *Conn) observeTrafficSecret(suite *cipherSuiteTLS13, secretName, keyName, ivName string, secret []byte, transcript hash.Hash) {
+	if c.utls.keyScheduleObserver == nil {
+		return
+	}
+	c.observeKeySchedule(secretName, secret, transcript)
+	key, iv := suite.trafficKey(secret)
+	c.observeKeySchedule(keyName, key, nil)
+	c.observeKeySchedule(ivName, iv, nil)
+}
+
+// observeKeyBlock reports the TLS 1.2 keys derived from the master secret.
+func (c *Conn) observeKeyBlock(clientMAC, serverMAC, clientKey, serverKey, clientIV, serverIV []byte) {
+	if c.utls.keyScheduleObserver == nil {
+		return
+	}
+	if len(clientMAC) > 0 {
+		c.observeKeySchedule(KeyScheduleClientWriteMACKey, clientMAC, nil)
+		c.observeKeySchedule(KeyScheduleServerWriteMACKey, serverMAC, nil)
+	}
+	c.observeKeySchedule(KeyScheduleClientWriteKey, clientKey, nil)
+	c.observeKeySchedule(KeyScheduleServerWriteKey, serverKey, nil)
+	c.observeKeySchedule(KeyScheduleClientWriteIV, clientIV, nil)
+	c.observeKeySchedule(KeyScheduleServerWriteIV, serverIV, nil)
+}
@@ -0,0 +1,218 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func TestUTLSKeyScheduleObserver(t *testing.T) {
+	keyLog := &bytes.Buffer{}
+	clientConfig := testConfig.Clone()
+	clientConfig.MinVersion = VersionTLS13
+	clientConfig.MaxVersion = VersionTLS13
+	clientConfig.KeyLogWriter = keyLog
+	serverConfig := testConfig.Clone()
+
+	trace := NewKeyScheduleTrace()
+	c, s := localPipe(t)
+	errChan := make(chan error, 1)
+	go func() {
+		defer s.Close()
+		errChan <- Server(s, serverConfig).Handshake()
+	}()
+	uconn := UClient(c, clientConfig, HelloGolang)
+	uconn.SetKeyScheduleObserver(trace)
+	err := uconn.Handshake()
+	c.Close()
+	if serverErr := <-errChan; serverErr != nil {
+		t.Fatalf("server: %v", serverErr)
+	}
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+
+	var got []string
+	for _, step := range trace.Steps() {
+		got = append(got, step.Name)
+	}
+	want := []string{
+		KeyScheduleSharedSecret,
+		KeyScheduleEarlySecret,
+		KeyScheduleHandshakeSecret,
+		KeyScheduleClientHandshakeTrafficSecret,
+		KeyScheduleClientHandshakeWriteKey,
+		KeyScheduleClientHandshakeWriteIV,
+		KeyScheduleServerHandshakeTrafficSecret,
+		KeyScheduleServerHandshakeWriteKey,
+		KeyScheduleServerHandshakeWriteIV,
+		KeyScheduleMasterSecret,
+		KeyScheduleServerFinishedKey,
+		KeyScheduleClientApplicationTrafficSecret,
+		KeyScheduleClientApplicationWriteKey,
+		KeyScheduleClientApplicationWriteIV,
+		KeyScheduleServerApplicationTrafficSecret,
+		KeyScheduleServerApplicationWriteKey,
+		KeyScheduleServerApplicationWriteIV,
+		KeyScheduleExporterMasterSecret,
+		KeyScheduleClientFinishedKey,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("observed steps = %v, want %v", got, want)
+	}
+
+	// The traffic secrets must match what is written to the key log.
+	random := hex.EncodeToString(uconn.HandshakeState.Hello.Random)
+	for label, name := range map[string]string{
+		keyLogLabelClientHandshake: KeyScheduleClientHandshakeTrafficSecret,
+		keyLogLabelServerHandshake: KeyScheduleServerHandshakeTrafficSecret,
+		keyLogLabelClientTraffic:   KeyScheduleClientApplicationTrafficSecret,
+		keyLogLabelServerTraffic:   KeyScheduleServerApplicationTrafficSecret,
+	} {
+		line := fmt.Sprintf("%s %s %x\n", label, random, trace.Step(name))
+		if !bytes.Contains(keyLog.Bytes(), []byte(line)) {
+			t.Errorf("%s does not match the key log", name)
+		}
+	}
+
+	// The handshake traffic secrets are derived over ClientHello..ServerHello,
+	// the application ones over ClientHello..server Finished.
+	steps := trace.Steps()
+	if steps[3].TranscriptHash == nil || bytes.Equal(steps[3].TranscriptHash, steps[11].TranscriptHash) {
+		t.Errorf("handshake and application secrets report the same transcript hash")
+	}
+	if !bytes.Equal(steps[3].TranscriptHash, steps[6].TranscriptHash) {
+		t.Errorf("client and server handshake secrets report different transcript hashes")
+	}
+	if steps[4].TranscriptHash != nil {
+		t.Errorf("write key reports a transcript hash")
+	}
+}
+
+func TestUTLSKeyScheduleObserverTLS12(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		cipherSuite uint16
+		// noEMS sends a ClientHello without extended_master_secret
+		noEMS bool
+		want  []string
+	}{
+		{
+			name:        "ECDHE_AEAD",
+			cipherSuite: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			want: []string{
+				KeySchedulePreMasterSecret,
+				KeyScheduleMasterSecret,
+				KeyScheduleClientWriteKey,
+				KeyScheduleServerWriteKey,
+				KeyScheduleClientWriteIV,
+				KeyScheduleServerWriteIV,
+			},
+		},
+		{
+			name:        "RSA_CBC",
+			cipherSuite: TLS_RSA_WITH_AES_128_CBC_SHA,
+			want: []string{
+				KeySchedulePreMasterSecret,
+				KeyScheduleMasterSecret,
+				KeyScheduleClientWriteMACKey,
+				KeyScheduleServerWriteMACKey,
+				KeyScheduleClientWriteKey,
+				KeyScheduleServerWriteKey,
+				KeyScheduleClientWriteIV,
+				KeyScheduleServerWriteIV,
+			},
+		},
+		{
+			name:        "ECDHE_AEAD_without_EMS",
+			cipherSuite: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			noEMS:       true,
+			want: []string{
+				KeySchedulePreMasterSecret,
+				KeyScheduleMasterSecret,
+				KeyScheduleClientWriteKey,
+				KeyScheduleServerWriteKey,
+				KeyScheduleClientWriteIV,
+				KeyScheduleServerWriteIV,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			keyLog := &bytes.Buffer{}
+			clientConfig := testConfig.Clone()
+			clientConfig.MinVersion = VersionTLS12
+			clientConfig.MaxVersion = VersionTLS12
+			clientConfig.CipherSuites = []uint16{tt.cipherSuite}
+			clientConfig.KeyLogWriter = keyLog
+			serverConfig := testConfig.Clone()
+			serverConfig.CipherSuites = []uint16{tt.cipherSuite}
+
+			trace := NewKeyScheduleTrace()
+			c, s := localPipe(t)
+			errChan := make(chan error, 1)
+			go func() {
+				defer s.Close()
+				errChan <- Server(s, serverConfig).Handshake()
+			}()
+			id := HelloGolang
+			if tt.noEMS {
+				id = HelloCustom
+			}
+			uconn := UClient(c, clientConfig, id)
+			if tt.noEMS {
+				if err := uconn.ApplyPreset(&ClientHelloSpec{
+					TLSVersMin:         VersionTLS12,
+					TLSVersMax:         VersionTLS12,
+					CipherSuites:       []uint16{tt.cipherSuite},
+					CompressionMethods: []uint8{compressionNone},
+					Extensions: []TLSExtension{
+						&SNIExtension{},
+						&SupportedCurvesExtension{Curves: []CurveID{X25519}},
+						&SupportedPointsExtension{SupportedPoints: []uint8{pointFormatUncompressed}},
+						&SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: []SignatureScheme{PSSWithSHA256, PKCS1WithSHA256}},
+						&RenegotiationInfoExtension{},
+					},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			uconn.SetKeyScheduleObserver(trace)
+			err := uconn.Handshake()
+			c.Close()
+			if serverErr := <-errChan; serverErr != nil {
+				t.Fatalf("server: %v", serverErr)
+			}
+			if err != nil {
+				t.Fatalf("client: %v", err)
+			}
+			if uconn.ConnectionState().Version != VersionTLS12 {
+				t.Fatalf("negotiated %x, want TLS 1.2", uconn.ConnectionState().Version)
+			}
+
+			var got []string
+			for _, step := range trace.Steps() {
+				got = append(got, step.Name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("observed steps = %v, want %v", got, tt.want)
+			}
+			// Only the master secret depends on the transcript.
+			for i, step := range trace.Steps() {
+				if i != 1 && step.TranscriptHash != nil {
+					t.Errorf("%s reports a transcript hash", step.Name)
+				}
+			}
+
+			line := fmt.Sprintf("%s %x %x\n", keyLogLabelTLS12, uconn.HandshakeState.Hello.Random, trace.Step(KeyScheduleMasterSecret))
+			if !bytes.Contains(keyLog.Bytes(), []byte(line)) {
+				t.Errorf("master secret does not match the key log")
+			}
+			// With extended_master_secret the master secret is derived over
+			// the session hash, otherwise over the randoms only.
+			if hasHash := trace.Steps()[1].TranscriptHash != nil; hasHash == tt.noEMS {
+				t.Errorf("master secret reports a session hash: %v, want %v", hasHash, !tt.noEMS)
+			}
+		})
+	}
+}
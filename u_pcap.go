@@ -0,0 +1,436 @@
+package tls
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/netip"
+	"time"
+)
+
+// ErrUnknownCaptureFormat is returned when a capture is neither a pcap nor a
+// pcapng file.
+var ErrUnknownCaptureFormat = errors.New("tls: capture is not a pcap or pcapng file")
+
+// maxCapturedPacketLen bounds the packets read from a capture, so that a
+// corrupt length does not allocate arbitrary memory.
+const maxCapturedPacketLen = 1 << 20
+
+// Link types of the captures, see https://www.tcpdump.org/linktypes.html.
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLoop     = 108
+	linkTypeSLL      = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeSLL2     = 276
+
+	// DLT_RAW is 12 or 14 in the pcap files written on some platforms.
+	linkTypeRawBSD     = 12
+	linkTypeRawOpenBSD = 14
+)
+
+const (
+	ipProtocolTCP = 6
+	ipProtocolUDP = 17
+)
+
+// capturedPacket is a packet read from a pcap or pcapng capture.
+type capturedPacket struct {
+	time     time.Time
+	linkType uint16
+	data     []byte
+}
+
+// captureReader reads the packets of a capture. next returns io.EOF after the
+// last packet.
+type captureReader interface {
+	next() (*capturedPacket, error)
+}
+
+// newCaptureReader returns a captureReader for the pcap or pcapng capture r.
+func newCaptureReader(r io.Reader) (captureReader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("tls: reading the capture: %w", err)
+	}
+	switch binary.BigEndian.Uint32(magic) {
+	case pcapngBlockSectionHeader:
+		return &pcapngReader{r: br}, nil
+	case 0xa1b2c3d4, 0xd4c3b2a1, 0xa1b23c4d, 0x4d3cb2a1:
+		return newPcapReader(br)
+	}
+	return nil, ErrUnknownCaptureFormat
+}
+
+// pcapReader reads a pcap capture, see
+// https://datatracker.ietf.org/doc/draft-ietf-opsawg-pcap/.
+type pcapReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nanos    bool // timestamps are in nanoseconds rather than microseconds
+	linkType uint16
+}
+
+func newPcapReader(r io.Reader) (*pcapReader, error) {
+	var hdr [24]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("tls: reading the pcap header: %w", err)
+	}
+	p := &pcapReader{r: r, order: binary.BigEndian}
+	magic := binary.BigEndian.Uint32(hdr[:4])
+	if magic == 0xd4c3b2a1 || magic == 0x4d3cb2a1 {
+		p.order = binary.LittleEndian
+	}
+	p.nanos = magic == 0xa1b23c4d || magic == 0x4d3cb2a1
+	// the upper bits of the link type field hold the FCS length
+	p.linkType = uint16(p.order.Uint32(hdr[20:]))
+	return p, nil
+}
+
+func (p *pcapReader) next() (*capturedPacket, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(p.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("tls: the pcap capture is truncated")
+		}
+		return nil, err
+	}
+	sec, frac := p.order.Uint32(hdr[:]), p.order.Uint32(hdr[4:])
+	length := p.order.Uint32(hdr[8:])
+	if length > maxCapturedPacketLen {
+		return nil, fmt.Errorf("tls: pcap packet of %d bytes is too long", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(p.r, data); err != nil {
+		return nil, errors.New("tls: the pcap capture is truncated")
+	}
+	if !p.nanos {
+		frac *= 1000
+	}
+	return &capturedPacket{
+		time:     time.Unix(int64(sec), int64(frac)),
+		linkType: p.linkType,
+		data:     data,
+	}, nil
+}
+
+// pcapng block types, see https://datatracker.ietf.org/doc/draft-ietf-opsawg-pcapng/.
+const (
+	pcapngBlockSectionHeader  = 0x0a0d0d0a
+	pcapngBlockInterface      = 1
+	pcapngBlockSimplePacket   = 3
+	pcapngBlockEnhancedPacket = 6
+	pcapngByteOrderMagic      = 0x1a2b3c4d
+	pcapngOptionEndOfOpt      = 0
+	pcapngOptionIfTsresol     = 9
+)
+
+// pcapngReader reads a pcapng capture. Blocks other than the section header,
+// interface description and packet blocks are skipped.
+type pcapngReader struct {
+	r          *bufio.Reader
+	order      binary.ByteOrder
+	interfaces []pcapngInterface // of the current section
+}
+
+type pcapngInterface struct {
+	linkType uint16
+	tsresol  uint8 // if_tsresol option
+}
+
+// pcapngDefaultTsresol is the timestamp resolution of interfaces without an
+// if_tsresol option, microseconds.
+const pcapngDefaultTsresol uint8 = 6
+
+func (p *pcapngReader) next() (*capturedPacket, error) {
+	for {
+		typ, body, err := p.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case pcapngBlockSectionHeader:
+			p.interfaces = nil
+		case pcapngBlockInterface:
+			if len(body) < 8 {
+				return nil, errors.New("tls: invalid pcapng interface description block")
+			}
+			iface := pcapngInterface{linkType: p.order.Uint16(body), tsresol: pcapngDefaultTsresol}
+			for opts := body[8:]; len(opts) >= 4; {
+				code, length := p.order.Uint16(opts), int(p.order.Uint16(opts[2:]))
+				if code == pcapngOptionEndOfOpt || 4+length > len(opts) {
+					break
+				}
+				if code == pcapngOptionIfTsresol && length == 1 {
+					iface.tsresol = opts[4]
+				}
+				opts = opts[min(len(opts), 4+(length+3)&^3):]
+			}
+			p.interfaces = append(p.interfaces, iface)
+		case pcapngBlockEnhancedPacket:
+			if len(body) < 20 {
+				return nil, errors.New("tls: invalid pcapng enhanced packet block")
+			}
+			id := p.order.Uint32(body)
+			if id >= uint32(len(p.interfaces)) {
+				return nil, fmt.Errorf("tls: pcapng packet of undescribed interface %d", id)
+			}
+			length := p.order.Uint32(body[12:])
+			if uint64(length) > uint64(len(body)-20) {
+				return nil, errors.New("tls: invalid pcapng enhanced packet block")
+			}
+			iface := p.interfaces[id]
+			ts := uint64(p.order.Uint32(body[4:]))<<32 | uint64(p.order.Uint32(body[8:]))
+			return &capturedPacket{
+				time:     iface.time(ts),
+				linkType: iface.linkType,
+				data:     body[20 : 20+length],
+			}, nil
+		case pcapngBlockSimplePacket:
+			if len(body) < 4 || len(p.interfaces) == 0 {
+				return nil, errors.New("tls: invalid pcapng simple packet block")
+			}
+			length := min(p.order.Uint32(body), uint32(len(body)-4))
+			return &capturedPacket{
+				linkType: p.interfaces[0].linkType,
+				data:     body[4 : 4+length],
+			}, nil
+		}
+	}
+}
+
+// readBlock reads the next block and returns its type and body.
+func (p *pcapngReader) readBlock() (uint32, []byte, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(p.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, nil, errors.New("tls: the pcapng capture is truncated")
+		}
+		return 0, nil, err
+	}
+	// the section header block type reads the same in both byte orders, and
+	// its byte-order magic sets the byte order of the section
+	if binary.BigEndian.Uint32(hdr[:]) == pcapngBlockSectionHeader {
+		magic, err := p.r.Peek(4)
+		if err != nil {
+			return 0, nil, errors.New("tls: the pcapng capture is truncated")
+		}
+		switch {
+		case binary.BigEndian.Uint32(magic) == pcapngByteOrderMagic:
+			p.order = binary.BigEndian
+		case binary.LittleEndian.Uint32(magic) == pcapngByteOrderMagic:
+			p.order = binary.LittleEndian
+		default:
+			return 0, nil, errors.New("tls: invalid pcapng section header block")
+		}
+	} else if p.order == nil {
+		return 0, nil, errors.New("tls: pcapng capture does not start with a section header block")
+	}
+
+	length := p.order.Uint32(hdr[4:])
+	if length < 12 || length%4 != 0 || length > maxCapturedPacketLen {
+		return 0, nil, fmt.Errorf("tls: invalid pcapng block length %d", length)
+	}
+	block := make([]byte, length-8)
+	if _, err := io.ReadFull(p.r, block); err != nil {
+		return 0, nil, errors.New("tls: the pcapng capture is truncated")
+	}
+	// the body is followed by the block length again
+	return p.order.Uint32(hdr[:]), block[:len(block)-4], nil
+}
+
+// time converts a timestamp in the units set by the if_tsresol option.
+func (iface pcapngInterface) time(ts uint64) time.Time {
+	exp := uint64(iface.tsresol & 0x7f)
+	if iface.tsresol&0x80 != 0 {
+		// negative power of 2
+		if exp >= 64 {
+			return time.Unix(0, 0)
+		}
+		frac := float64(ts&(1<<exp-1)) / float64(uint64(1)<<exp)
+		return time.Unix(int64(ts>>exp), int64(frac*1e9))
+	}
+	// negative power of 10
+	if exp > 19 {
+		return time.Unix(0, 0)
+	}
+	units := uint64(math.Pow10(int(exp)))
+	frac := ts % units
+	if exp <= 9 {
+		frac *= uint64(math.Pow10(9 - int(exp)))
+	} else {
+		frac /= uint64(math.Pow10(int(exp) - 9))
+	}
+	return time.Unix(int64(ts/units), int64(frac))
+}
+
+// ipPacket is the part of an IP packet needed to find ClientHellos.
+type ipPacket struct {
+	src, dst netip.Addr
+	protocol uint8
+	payload  []byte
+}
+
+// decodeIPPacket returns the IP packet in a frame of the link type. It returns
+// false for frames that are not IP, and for IP fragments, which are not
+// reassembled.
+func decodeIPPacket(linkType uint16, frame []byte) (ipPacket, bool) {
+	switch linkType {
+	case linkTypeEthernet:
+		if len(frame) < 14 {
+			return ipPacket{}, false
+		}
+		etherType := binary.BigEndian.Uint16(frame[12:])
+		frame = frame[14:]
+		// 802.1Q and 802.1ad tags
+		for (etherType == 0x8100 || etherType == 0x88a8 || etherType == 0x9100) && len(frame) >= 4 {
+			etherType = binary.BigEndian.Uint16(frame[2:])
+			frame = frame[4:]
+		}
+		if etherType != 0x0800 && etherType != 0x86dd {
+			return ipPacket{}, false
+		}
+	case linkTypeRaw, linkTypeRawBSD, linkTypeRawOpenBSD, linkTypeIPv4, linkTypeIPv6:
+	case linkTypeNull, linkTypeLoop:
+		// the address family is in the byte order of the capturing host, so
+		// the IP version decides instead
+		if len(frame) < 4 {
+			return ipPacket{}, false
+		}
+		frame = frame[4:]
+	case linkTypeSLL:
+		if len(frame) < 16 {
+			return ipPacket{}, false
+		}
+		frame = frame[16:]
+	case linkTypeSLL2:
+		if len(frame) < 20 {
+			return ipPacket{}, false
+		}
+		frame = frame[20:]
+	default:
+		return ipPacket{}, false
+	}
+	if len(frame) == 0 {
+		return ipPacket{}, false
+	}
+	switch frame[0] >> 4 {
+	case 4:
+		return decodeIPv4(frame)
+	case 6:
+		return decodeIPv6(frame)
+	}
+	return ipPacket{}, false
+}
+
+func decodeIPv4(b []byte) (ipPacket, bool) {
+	if len(b) < 20 {
+		return ipPacket{}, false
+	}
+	headerLen := int(b[0]&0x0f) * 4
+	totalLen := int(binary.BigEndian.Uint16(b[2:]))
+	if headerLen < 20 || totalLen < headerLen || totalLen > len(b) {
+		return ipPacket{}, false
+	}
+	// more fragments flag or fragment offset
+	if binary.BigEndian.Uint16(b[6:])&0x3fff != 0 {
+		return ipPacket{}, false
+	}
+	return ipPacket{
+		src:      netip.AddrFrom4([4]byte(b[12:16])),
+		dst:      netip.AddrFrom4([4]byte(b[16:20])),
+		protocol: b[9],
+		payload:  b[headerLen:totalLen],
+	}, true
+}
+
+func decodeIPv6(b []byte) (ipPacket, bool) {
+	if len(b) < 40 {
+		return ipPacket{}, false
+	}
+	payloadLen := int(binary.BigEndian.Uint16(b[4:]))
+	if 40+payloadLen > len(b) {
+		return ipPacket{}, false
+	}
+	p := ipPacket{
+		src:      netip.AddrFrom16([16]byte(b[8:24])),
+		dst:      netip.AddrFrom16([16]byte(b[24:40])),
+		protocol: b[6],
+		payload:  b[40 : 40+payloadLen],
+	}
+	for {
+		switch p.protocol {
+		case 0, 43, 60: // hop-by-hop, routing and destination options
+			if len(p.payload) < 8 {
+				return ipPacket{}, false
+			}
+			n := (int(p.payload[1]) + 1) * 8
+			if n > len(p.payload) {
+				return ipPacket{}, false
+			}
+			p.protocol, p.payload = p.payload[0], p.payload[n:]
+		case 44: // fragment
+			return ipPacket{}, false
+		default:
+			return p, true
+		}
+	}
+}
+
+// tcpSegment is the part of a TCP segment needed to reassemble streams.
+type tcpSegment struct {
+	srcPort, dstPort uint16
+	seq              uint32
+	syn              bool
+	payload          []byte
+}
+
+const tcpFlagSYN = 0x02
+
+func decodeTCP(b []byte) (tcpSegment, bool) {
+	if len(b) < 20 {
+		return tcpSegment{}, false
+	}
+	dataOffset := int(b[12]>>4) * 4
+	if dataOffset < 20 || dataOffset > len(b) {
+		return tcpSegment{}, false
+	}
+	return tcpSegment{
+		srcPort: binary.BigEndian.Uint16(b),
+		dstPort: binary.BigEndian.Uint16(b[2:]),
+		seq:     binary.BigEndian.Uint32(b[4:]),
+		syn:     b[13]&tcpFlagSYN != 0,
+		payload: b[dataOffset:],
+	}, true
+}
+
+// udpDatagram is a UDP datagram.
+type udpDatagram struct {
+	srcPort, dstPort uint16
+	payload          []byte
+}
+
+func decodeUDP(b []byte) (udpDatagram, bool) {
+	if len(b) < 8 {
+		return udpDatagram{}, false
+	}
+	length := int(binary.BigEndian.Uint16(b[4:]))
+	if length < 8 || length > len(b) {
+		return udpDatagram{}, false
+	}
+	return udpDatagram{
+		srcPort: binary.BigEndian.Uint16(b),
+		dstPort: binary.BigEndian.Uint16(b[2:]),
+		payload: b[8:length],
+	}, true
+}
@@ -0,0 +1,79 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+)
+
+// PskBinderCalculation shows how the binder of the first PSK identity in a
+// pre_shared_key extension is derived, RFC 8446, Sections 4.2.11.2 and 7.1.
+type PskBinderCalculation struct {
+	// Identity is the offered PSK identity: the session ticket and its
+	// obfuscated age.
+	Identity PskIdentity
+
+	// EarlySecret is HKDF-Extract(0, PSK).
+	EarlySecret []byte
+	// BinderKey is Derive-Secret(EarlySecret, "res binder", "").
+	BinderKey []byte
+	// FinishedKey is HKDF-Expand-Label(BinderKey, "finished", "", Hash.length).
+	FinishedKey []byte
+	// TranscriptHash is the hash of the ClientHello truncated before the
+	// binders list.
+	TranscriptHash []byte
+	// Binder is the binder sent in the ClientHello, which is
+	// HMAC(FinishedKey, TranscriptHash).
+	Binder []byte
+}
+
+// PskBinderCalculation recomputes the binder of the PSK offered in the
+// ClientHello built for uconn. It returns nil if no PSK was offered, which
+// is the case when the spec has no UtlsPreSharedKeyExtension or no session
+// could be resumed.
+//
+// After a HelloRetryRequest the binder of the second ClientHello also covers
+// the first ClientHello and the HelloRetryRequest, RFC 8446, Section
+// 4.2.11.2. uTLS does not resend a PSK in that case, so PskBinderCalculation
+// returns an error once a HelloRetryRequest was received. It also returns an
+// error if the recomputed binder is not the one sent.
+func (uconn *UConn) PskBinderCalculation() (*PskBinderCalculation, error) {
+	var psk *UtlsPreSharedKeyExtension
+	for _, ext := range uconn.Extensions {
+		if e, ok := ext.(*UtlsPreSharedKeyExtension); ok {
+			psk = e
+		}
+	}
+	if psk == nil || !psk.IsInitialized() || len(psk.Identities) == 0 || len(psk.Binders) == 0 {
+		return nil, nil
+	}
+
+	if uconn.utls.receivedHelloRetryRequest {
+		return nil, errors.New("tls: PSK binder calculation after a HelloRetryRequest is not supported")
+	}
+
+	hello := uconn.HandshakeState.Hello.Raw
+	bindersLen := 2
+	for _, binder := range psk.Binders {
+		bindersLen += 1 + len(binder)
+	}
+	if len(hello) < bindersLen {
+		return nil, errors.New("tls: ClientHello is shorter than its binders")
+	}
+
+	suite := psk.cipherSuite
+	transcript := suite.hash.New()
+	transcript.Write(hello[:len(hello)-bindersLen])
+	calc := &PskBinderCalculation{
+		Identity:       psk.Identities[0],
+		EarlySecret:    psk.EarlySecret,
+		BinderKey:      psk.BinderKey,
+		FinishedKey:    suite.finishedKey(psk.BinderKey),
+		TranscriptHash: transcript.Sum(nil),
+		Binder:         bytes.Clone(psk.Binders[0]),
+	}
+	if !hmac.Equal(suite.finishedHash(psk.BinderKey, transcript), calc.Binder) {
+		return nil, errors.New("tls: recomputed PSK binder does not match the binder sent")
+	}
+	return calc, nil
+}
@@ -0,0 +1,158 @@
+package tls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func pskTestSpec() *ClientHelloSpec {
+	return &ClientHelloSpec{
+		CipherSuites: []uint16{TLS_AES_128_GCM_SHA256},
+		Extensions: []TLSExtension{
+			&SNIExtension{},
+			&SupportedCurvesExtension{Curves: []CurveID{X25519}},
+			&KeyShareExtension{KeyShares: []KeyShare{{Group: X25519}}},
+			&SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: []SignatureScheme{PSSWithSHA256, PKCS1WithSHA256}},
+			&SupportedVersionsExtension{Versions: []uint16{VersionTLS13}},
+			&PSKKeyExchangeModesExtension{Modes: []uint8{PskModeDHE}},
+			&UtlsPreSharedKeyExtension{},
+		},
+	}
+}
+
+// pskTestHandshake runs a TLS 1.3 handshake with pskTestSpec and reads the
+// server's reply, so that its NewSessionTicket reaches the cache.
+func pskTestHandshake(t *testing.T, clientConfig, serverConfig *Config) (*UConn, *HandshakeRecorder) {
+	t.Helper()
+	c, s := localPipe(t)
+	errChan := make(chan error, 1)
+	go func() {
+		defer s.Close()
+		server := Server(s, serverConfig)
+		if err := server.Handshake(); err != nil {
+			errChan <- err
+			return
+		}
+		_, err := server.Write([]byte("ok"))
+		errChan <- err
+	}()
+	defer c.Close()
+
+	recorder := NewHandshakeRecorder()
+	uconn := UClient(c, clientConfig, HelloCustom)
+	uconn.SetHandshakeRecorder(recorder)
+	if err := uconn.ApplyPreset(pskTestSpec()); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconn.Handshake(); err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	buf := make([]byte, 2)
+	if _, err := uconn.Read(buf); err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+	return uconn, recorder
+}
+
+func TestUTLSPskBinderCalculation(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.ServerName = "example.golang"
+	clientConfig.ClientSessionCache = NewLRUClientSessionCache(1)
+	clientConfig.OmitEmptyPsk = true
+	serverConfig := testConfig.Clone()
+
+	first, recorder := pskTestHandshake(t, clientConfig, serverConfig)
+	if first.ConnectionState().DidResume {
+		t.Fatal("first handshake resumed")
+	}
+	if calc, err := first.PskBinderCalculation(); err != nil || calc != nil {
+		t.Errorf("PskBinderCalculation() without a session = %+v, %v, want nil", calc, err)
+	}
+	raw := recorder.Message(RecordReceived, HandshakeTypeNewSessionTicket)
+	if raw == nil {
+		t.Fatal("no NewSessionTicket recorded")
+	}
+	ticket, err := DecodeNewSessionTicket(raw, VersionTLS13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.Lifetime == 0 || len(ticket.Ticket) == 0 || ticket.MaxEarlyData != 0 {
+		t.Errorf("unexpected NewSessionTicket %+v", ticket)
+	}
+
+	second, _ := pskTestHandshake(t, clientConfig, serverConfig)
+	if !second.ConnectionState().DidResume {
+		t.Fatal("second handshake did not resume")
+	}
+	calc, err := second.PskBinderCalculation()
+	if err != nil || calc == nil {
+		t.Fatalf("PskBinderCalculation() = %+v, %v", calc, err)
+	}
+	if !bytes.Equal(calc.Identity.Label, ticket.Ticket) {
+		t.Errorf("identity = %x, want the ticket %x", calc.Identity.Label, ticket.Ticket)
+	}
+	hello := second.HandshakeState.Hello.Raw
+	if !bytes.HasSuffix(hello, calc.Binder) {
+		t.Errorf("binder %x is not the one sent in the ClientHello", calc.Binder)
+	}
+	if len(calc.EarlySecret) == 0 || len(calc.BinderKey) == 0 || len(calc.FinishedKey) == 0 || len(calc.TranscriptHash) == 0 {
+		t.Errorf("incomplete calculation %+v", calc)
+	}
+}
+
+func TestUTLSPskBinderCalculationHelloRetryRequest(t *testing.T) {
+	clientConfig := testConfig.Clone()
+	clientConfig.ServerName = "example.golang"
+	clientConfig.ClientSessionCache = NewLRUClientSessionCache(1)
+	clientConfig.OmitEmptyPsk = true
+	pskTestHandshake(t, clientConfig, testConfig.Clone())
+
+	// The server only accepts P-256, so the X25519 key share of the
+	// ClientHello that offers the PSK triggers a HelloRetryRequest.
+	serverConfig := testConfig.Clone()
+	serverConfig.CurvePreferences = []CurveID{CurveP256}
+	c, s := localPipe(t)
+	go func() {
+		defer s.Close()
+		Server(s, serverConfig).Handshake()
+	}()
+	defer c.Close()
+
+	spec := pskTestSpec()
+	for _, ext := range spec.Extensions {
+		if e, ok := ext.(*SupportedCurvesExtension); ok {
+			e.Curves = []CurveID{X25519, CurveP256}
+		}
+	}
+	uconn := UClient(c, clientConfig, HelloCustom)
+	if err := uconn.ApplyPreset(spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := uconn.Handshake(); err == nil {
+		t.Fatal("uTLS resent a PSK after a HelloRetryRequest")
+	}
+	if !uconn.utls.receivedHelloRetryRequest {
+		t.Fatal("the server did not send a HelloRetryRequest")
+	}
+	if calc, err := uconn.PskBinderCalculation(); err == nil {
+		t.Errorf("PskBinderCalculation() after a HelloRetryRequest = %+v, want an error", calc)
+	}
+}
+
+func TestUTLSDecodeNewSessionTicketTLS12(t *testing.T) {
+	m := &newSessionTicketMsg{ticket: []byte("ticket")}
+	raw, err := m.marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket, err := DecodeNewSessionTicket(raw, VersionTLS12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ticket.Ticket, m.ticket) || ticket.Nonce != nil {
+		t.Errorf("unexpected NewSessionTicket %+v", ticket)
+	}
+}
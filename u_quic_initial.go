@@ -0,0 +1,255 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/refraction-networking/utls/internal/hkdf"
+	"github.com/refraction-networking/utls/internal/quicvarint"
+	"github.com/refraction-networking/utls/internal/tls13"
+)
+
+// QUIC versions whose Initial packets can be decrypted to read the ClientHello.
+const (
+	quicVersion1       uint32 = VERSION_1  // RFC 9000
+	quicVersion2       uint32 = VERSION_2  // RFC 9369
+	quicVersionDraft29 uint32 = 0xff00001d // draft-ietf-quic-transport-29
+)
+
+// quicInitialProtection is the version-specific protection of Initial packets.
+type quicInitialProtection struct {
+	packetType uint8 // long header packet type of Initial packets
+	salt       []byte
+	keyLabel   string
+	ivLabel    string
+	hpLabel    string
+}
+
+var quicInitialProtections = map[uint32]quicInitialProtection{
+	quicVersion1: {
+		// RFC 9001, Section 5.2
+		salt:     []byte{0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3, 0x4d, 0x17, 0x9a, 0xe6, 0xa4, 0xc8, 0x0c, 0xad, 0xcc, 0xbb, 0x7f, 0x0a},
+		keyLabel: "quic key",
+		ivLabel:  "quic iv",
+		hpLabel:  "quic hp",
+	},
+	quicVersion2: {
+		// RFC 9369, Section 3.3
+		packetType: 0b01,
+		salt:       []byte{0x0d, 0xed, 0xe3, 0xde, 0xf7, 0x00, 0xa6, 0xdb, 0x81, 0x93, 0x81, 0xbe, 0x6e, 0x26, 0x9d, 0xcb, 0xf9, 0xbd, 0x2e, 0xd9},
+		keyLabel:   "quicv2 key",
+		ivLabel:    "quicv2 iv",
+		hpLabel:    "quicv2 hp",
+	},
+	quicVersionDraft29: {
+		salt:     []byte{0xaf, 0xbf, 0xec, 0x28, 0x99, 0x93, 0xd2, 0x4c, 0x9e, 0x97, 0x86, 0xf1, 0x9c, 0x61, 0x11, 0xe0, 0x43, 0x90, 0xa8, 0x99},
+		keyLabel: "quic key",
+		ivLabel:  "quic iv",
+		hpLabel:  "quic hp",
+	},
+}
+
+// quicInitialKeys are the keys protecting the Initial packets sent by a
+// client.
+type quicInitialKeys struct {
+	aead cipher.AEAD
+	iv   []byte
+	hp   cipher.Block
+}
+
+// newQUICInitialKeys derives the client Initial keys from the Destination
+// Connection ID of the first Initial packet of the connection.
+func newQUICInitialKeys(version uint32, dcid []byte) (*quicInitialKeys, error) {
+	key, iv, hp, err := quicInitialSecrets(version, dcid)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	hpBlock, err := aes.NewCipher(hp)
+	if err != nil {
+		return nil, err
+	}
+	return &quicInitialKeys{aead: aead, iv: iv, hp: hpBlock}, nil
+}
+
+// quicInitialSecrets returns the client Initial packet protection key, IV and
+// header protection key, see RFC 9001, Section 5.2.
+func quicInitialSecrets(version uint32, dcid []byte) (key, iv, hp []byte, err error) {
+	p, ok := quicInitialProtections[version]
+	if !ok {
+		return nil, nil, nil, errors.New("tls: unsupported QUIC version")
+	}
+	initialSecret := hkdf.Extract(sha256.New, dcid, p.salt)
+	secret := tls13.ExpandLabel(sha256.New, initialSecret, "client in", nil, sha256.Size)
+	key = tls13.ExpandLabel(sha256.New, secret, p.keyLabel, nil, 16)
+	iv = tls13.ExpandLabel(sha256.New, secret, p.ivLabel, nil, aeadNonceLength)
+	hp = tls13.ExpandLabel(sha256.New, secret, p.hpLabel, nil, 16)
+	return key, iv, hp, nil
+}
+
+// quicInitialPacket is an Initial packet of a UDP datagram, still protected.
+type quicInitialPacket struct {
+	version  uint32
+	dcid     []byte
+	packet   []byte // the whole packet
+	pnOffset int    // offset of the packet number in packet
+}
+
+// parseQUICInitialPackets returns the Initial packets coalesced in a UDP
+// datagram. Other packets are skipped.
+func parseQUICInitialPackets(datagram []byte) []quicInitialPacket {
+	var packets []quicInitialPacket
+	for len(datagram) > 0 && datagram[0]&0x80 != 0 { // long header
+		if len(datagram) < 7 {
+			break
+		}
+		version := binary.BigEndian.Uint32(datagram[1:])
+		p, ok := quicInitialProtections[version]
+		if !ok {
+			break
+		}
+		r := bytes.NewReader(datagram[5:])
+		dcidLen, _ := r.ReadByte()
+		if dcidLen > 20 || int(dcidLen) > r.Len() {
+			break
+		}
+		dcid := datagram[6 : 6+dcidLen]
+		r.Seek(int64(dcidLen), io.SeekCurrent)
+		scidLen, err := r.ReadByte()
+		if err != nil || scidLen > 20 || int(scidLen) > r.Len() {
+			break
+		}
+		r.Seek(int64(scidLen), io.SeekCurrent)
+
+		isInitial := (datagram[0]>>4)&0b11 == p.packetType
+		if isInitial {
+			tokenLen, err := quicvarint.Read(r)
+			if err != nil || tokenLen > uint64(r.Len()) {
+				break
+			}
+			r.Seek(int64(tokenLen), io.SeekCurrent)
+		}
+		length, err := quicvarint.Read(r)
+		if err != nil || length > uint64(r.Len()) {
+			break
+		}
+		pnOffset := len(datagram) - r.Len()
+		end := pnOffset + int(length)
+		if isInitial {
+			packets = append(packets, quicInitialPacket{
+				version:  version,
+				dcid:     dcid,
+				packet:   datagram[:end],
+				pnOffset: pnOffset,
+			})
+		}
+		datagram = datagram[end:]
+	}
+	return packets
+}
+
+// open removes the protection of the packet and returns its payload.
+func (k *quicInitialKeys) open(p quicInitialPacket) ([]byte, error) {
+	// RFC 9001, Section 5.4.2
+	if p.pnOffset+4+16 > len(p.packet) {
+		return nil, errors.New("tls: QUIC packet is too short to sample")
+	}
+	mask := make([]byte, 16)
+	k.hp.Encrypt(mask, p.packet[p.pnOffset+4:p.pnOffset+4+16])
+
+	header := bytes.Clone(p.packet[:p.pnOffset+4])
+	header[0] ^= mask[0] & 0x0f
+	pnLen := int(header[0]&0b11) + 1
+	header = header[:p.pnOffset+pnLen]
+	var pn uint64
+	for i := 0; i < pnLen; i++ {
+		header[p.pnOffset+i] ^= mask[1+i]
+		pn = pn<<8 | uint64(header[p.pnOffset+i])
+	}
+
+	// Initial packet numbers are small, so the truncated packet number is
+	// taken as the full one.
+	nonce := bytes.Clone(k.iv)
+	for i := 0; i < 8; i++ {
+		nonce[len(nonce)-1-i] ^= byte(pn >> (8 * i))
+	}
+	return k.aead.Open(nil, nonce, p.packet[len(header):], header)
+}
+
+// quicCryptoFrame is a CRYPTO frame of an Initial packet.
+type quicCryptoFrame struct {
+	offset uint64
+	data   []byte
+}
+
+// parseQUICCryptoFrames returns the CRYPTO frames in the payload of an Initial
+// packet, skipping the other frames allowed in Initial packets.
+func parseQUICCryptoFrames(payload []byte) ([]quicCryptoFrame, error) {
+	errInvalid := errors.New("tls: invalid frame in QUIC Initial packet")
+	var frames []quicCryptoFrame
+	r := bytes.NewReader(payload)
+	// readVarints reads and returns the last of n variable-length integers.
+	readVarints := func(n int) (v uint64, err error) {
+		for i := 0; i < n && err == nil; i++ {
+			v, err = quicvarint.Read(r)
+		}
+		return v, err
+	}
+	for r.Len() > 0 {
+		typ, err := quicvarint.Read(r)
+		if err != nil {
+			return nil, errInvalid
+		}
+		switch typ {
+		case 0x00, 0x01: // PADDING, PING
+		case 0x02, 0x03: // ACK
+			rangeCount, err := readVarints(3)
+			if err != nil {
+				return nil, errInvalid
+			}
+			n := 1 + 2*rangeCount
+			if typ == 0x03 {
+				n += 3 // ECN counts
+			}
+			if rangeCount > uint64(r.Len()) {
+				return nil, errInvalid
+			}
+			if _, err := readVarints(int(n)); err != nil {
+				return nil, errInvalid
+			}
+		case 0x06: // CRYPTO
+			offset, err := quicvarint.Read(r)
+			if err != nil {
+				return nil, errInvalid
+			}
+			length, err := quicvarint.Read(r)
+			if err != nil || length > uint64(r.Len()) {
+				return nil, errInvalid
+			}
+			data := make([]byte, length)
+			r.Read(data)
+			frames = append(frames, quicCryptoFrame{offset, data})
+		case 0x1c: // CONNECTION_CLOSE
+			reasonLen, err := readVarints(3)
+			if err != nil || reasonLen > uint64(r.Len()) {
+				return nil, errInvalid
+			}
+			r.Seek(int64(reasonLen), io.SeekCurrent)
+		default:
+			return nil, errInvalid
+		}
+	}
+	return frames, nil
+}
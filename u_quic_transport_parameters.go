@@ -1,8 +1,11 @@
 package tls
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"math"
 	"math/big"
 
@@ -44,6 +47,105 @@ func (tps TransportParameters) Marshal() []byte {
 	return b
 }
 
+// parseTransportParameters parses the transport parameters of a
+// quic_transport_parameters extension. Parameters that are specific to the
+// connection or random, such as GREASE parameters, are parsed into ones that
+// generate new values.
+func parseTransportParameters(b []byte) (TransportParameters, error) {
+	r := bytes.NewReader(b)
+	var tps TransportParameters
+	for r.Len() > 0 {
+		id, err := quicvarint.Read(r)
+		if err != nil {
+			return nil, errors.New("unable to read transport parameter ID")
+		}
+		length, err := quicvarint.Read(r)
+		if err != nil || length > uint64(r.Len()) {
+			return nil, fmt.Errorf("unable to read transport parameter %#x", id)
+		}
+		val := make([]byte, length)
+		r.Read(val)
+
+		tp, err := parseTransportParameter(id, val)
+		if err != nil {
+			return nil, err
+		}
+		tps = append(tps, tp)
+	}
+	return tps, nil
+}
+
+func parseTransportParameter(id uint64, val []byte) (TransportParameter, error) {
+	switch id {
+	case max_idle_timeout, max_udp_payload_size, initial_max_data,
+		initial_max_stream_data_bidi_local, initial_max_stream_data_bidi_remote,
+		initial_max_stream_data_uni, initial_max_streams_bidi, initial_max_streams_uni,
+		max_ack_delay, active_connection_id_limit, max_datagram_frame_size:
+		r := bytes.NewReader(val)
+		v, err := quicvarint.Read(r)
+		if err != nil || r.Len() != 0 {
+			return nil, fmt.Errorf("invalid value of transport parameter %#x", id)
+		}
+		switch id {
+		case max_idle_timeout:
+			return MaxIdleTimeout(v), nil
+		case max_udp_payload_size:
+			return MaxUDPPayloadSize(v), nil
+		case initial_max_data:
+			return InitialMaxData(v), nil
+		case initial_max_stream_data_bidi_local:
+			return InitialMaxStreamDataBidiLocal(v), nil
+		case initial_max_stream_data_bidi_remote:
+			return InitialMaxStreamDataBidiRemote(v), nil
+		case initial_max_stream_data_uni:
+			return InitialMaxStreamDataUni(v), nil
+		case initial_max_streams_bidi:
+			return InitialMaxStreamsBidi(v), nil
+		case initial_max_streams_uni:
+			return InitialMaxStreamsUni(v), nil
+		case max_ack_delay:
+			return MaxAckDelay(v), nil
+		case active_connection_id_limit:
+			return ActiveConnectionIDLimit(v), nil
+		default:
+			return MaxDatagramFrameSize(v), nil
+		}
+	case disable_active_migration, grease_quic_bit:
+		if len(val) != 0 {
+			return nil, fmt.Errorf("invalid value of transport parameter %#x", id)
+		}
+		if id == disable_active_migration {
+			return &DisableActiveMigration{}, nil
+		}
+		return &GREASEQUICBit{}, nil
+	case initial_source_connection_id:
+		// the connection ID is specific to the connection
+		return InitialSourceConnectionID{}, nil
+	case version_information, version_information_legacy:
+		if len(val) == 0 || len(val)%4 != 0 {
+			return nil, fmt.Errorf("invalid value of transport parameter %#x", id)
+		}
+		v := &VersionInformation{
+			ChoosenVersion: binary.BigEndian.Uint32(val),
+			LegacyID:       id == version_information_legacy,
+		}
+		for val = val[4:]; len(val) > 0; val = val[4:] {
+			version := binary.BigEndian.Uint32(val)
+			if version&0x0f0f0f0f == VERSION_GREASE {
+				version = VERSION_GREASE
+			}
+			v.AvailableVersions = append(v.AvailableVersions, version)
+		}
+		return v, nil
+	case padding:
+		return PaddingTransportParameter(val), nil
+	}
+	if (GREASETransportParameter{}).IsGREASEID(id) {
+		return &GREASETransportParameter{Length: uint16(len(val))}, nil
+	}
+	return &FakeQUICTransportParameter{Id: id, Val: val}, nil
+}
+
 // TransportParameter represents a QUIC transport parameter.
 //
 // Caller will write the following to the wire:
@@ -268,7 +370,7 @@ func (*VersionInformation) GetGREASEVersion() uint32 {
 		return VERSION_GREASE
 	}
 
-	return uint32(randVal.Uint64()&math.MaxUint32) | 0x0a0a0a0a // all GREASE versions are in 0x?a?a?a?a
+	return uint32(randVal.Uint64()&0xf0f0f0f0) | 0x0a0a0a0a // all GREASE versions are in 0x?a?a?a?a
 }
 
 type PaddingTransportParameter []byte
@@ -2,6 +2,7 @@ package tls
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 )
 
@@ -15,6 +16,58 @@ func testTransportParametersFirefox(t *testing.T) {
 	}
 }
 
+func TestParseTransportParameters(t *testing.T) {
+	tps, err := parseTransportParameters(_truthTransportParametersFirefox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the connection ID and GREASE values are generated again
+	want := TransportParameters{
+		InitialMaxStreamDataBidiRemote(0x100000),
+		InitialMaxStreamsBidi(16),
+		MaxDatagramFrameSize(1200),
+		MaxIdleTimeout(30000),
+		ActiveConnectionIDLimit(8),
+		&GREASEQUICBit{},
+		&VersionInformation{
+			ChoosenVersion:    0x00000001,
+			AvailableVersions: []uint32{VERSION_GREASE, 0x00000001},
+			LegacyID:          true,
+		},
+		InitialMaxStreamsUni(16),
+		&GREASETransportParameter{Length: 2},
+		InitialMaxStreamDataBidiLocal(0xc00000),
+		InitialMaxStreamDataUni(0x100000),
+		InitialSourceConnectionID{},
+		MaxAckDelay(20),
+		InitialMaxData(0x1800000),
+		&DisableActiveMigration{},
+	}
+	if !reflect.DeepEqual(tps, want) {
+		t.Errorf("parseTransportParameters() = %#v, want %#v", tps, want)
+	}
+
+	for _, b := range [][]byte{
+		{0x04},                         // no length
+		{0x04, 0x04, 0x80, 0x00},       // value shorter than its length
+		{0x01, 0x03, 0x40, 0x01, 0x00}, // trailing byte after the varint
+		{0x0c, 0x01, 0x00},             // disable_active_migration with a value
+		{0x11, 0x03, 0x00, 0x00, 0x01}, // version_information not in versions
+	} {
+		if _, err := parseTransportParameters(b); err == nil {
+			t.Errorf("parseTransportParameters(%x) succeeded", b)
+		}
+	}
+}
+
+func TestGetGREASEVersion(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if v := (&VersionInformation{}).GetGREASEVersion(); v&0x0f0f0f0f != 0x0a0a0a0a {
+			t.Fatalf("GetGREASEVersion() = %#08x, want 0x?a?a?a?a", v)
+		}
+	}
+}
+
 var (
 	_inputTransportParametersFirefox = TransportParameters{
 		InitialMaxStreamDataBidiRemote(0x100000),
@@ -1334,9 +1334,10 @@ func (e *KeyShareExtension) UnmarshalJSON(b []byte) error {
 
 // QUICTransportParametersExtension implements quic_transport_parameters (57).
 //
-// Currently, it works as a fake extension and does not support parsing, since
-// the QUICConn provided by this package does not really understand these
-// parameters.
+// Currently, it works as a fake extension, since the QUICConn provided by this
+// package does not really understand these parameters. Parsing an extension
+// only recovers the TransportParameters a ClientHelloSpec needs to send them
+// again.
 type QUICTransportParametersExtension struct {
 	TransportParameters TransportParameters
 
@@ -1365,6 +1366,16 @@ func (e *QUICTransportParametersExtension) Read(b []byte) (int, error) {
 	return e.Len(), io.EOF
 }
 
+func (e *QUICTransportParametersExtension) Write(b []byte) (int, error) {
+	tps, err := parseTransportParameters(b)
+	if err != nil {
+		return 0, err
+	}
+	e.TransportParameters = tps
+	e.marshalResult = nil
+	return len(b), nil
+}
+
 func (e *QUICTransportParametersExtension) writeToUConn(*UConn) error {
 	// no need to set *UConn.quic.transportParams, since it is unused
 	return nil
@@ -1663,8 +1674,24 @@ func (e *RenegotiationInfoExtension) Read(b []byte) (int, error) {
 	return e.Len(), io.EOF
 }
 
-func (e *RenegotiationInfoExtension) UnmarshalJSON(_ []byte) error {
-	e.Renegotiation = RenegotiateOnceAsClient
+func (e *RenegotiationInfoExtension) UnmarshalJSON(b []byte) error {
+	var renegotiationInfo struct {
+		Renegotiation string `json:"renegotiation"` // optional, defaults to "once"
+	}
+	if err := json.Unmarshal(b, &renegotiationInfo); err != nil {
+		return err
+	}
+
+	switch renegotiationInfo.Renegotiation {
+	case "", "once":
+		e.Renegotiation = RenegotiateOnceAsClient
+	case "freely":
+		e.Renegotiation = RenegotiateFreelyAsClient
+	case "never":
+		e.Renegotiation = RenegotiateNever
+	default:
+		return fmt.Errorf("unknown renegotiation %s", renegotiationInfo.Renegotiation)
+	}
 	return nil
 }
 